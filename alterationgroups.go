@@ -0,0 +1,74 @@
+package permissivecsv
+
+import "fmt"
+
+// AlterationGroup describes a range of alterations that share the same Kind
+// and shape, which is to say that each altered record originally had
+// OriginalFieldCount fields (as parsed), and was altered to have
+// ResultingFieldCount fields. FirstRecordOrdinal and LastRecordOrdinal are the
+// ordinals of the first and last altered records in the range, and Count is
+// the number of alterations in the range. Records within the range that were
+// not altered are not counted.
+type AlterationGroup struct {
+	Kind                AlterationKind
+	FirstRecordOrdinal  int
+	LastRecordOrdinal   int
+	Count               int
+	OriginalFieldCount  int
+	ResultingFieldCount int
+}
+
+func (g *AlterationGroup) String() string {
+	records := fmt.Sprintf("record %d", g.FirstRecordOrdinal)
+	if g.LastRecordOrdinal != g.FirstRecordOrdinal {
+		records = fmt.Sprintf("records %d-%d", g.FirstRecordOrdinal, g.LastRecordOrdinal)
+	}
+	return fmt.Sprintf("%s: %s from %d to %d fields (%d altered)",
+		records, g.Kind, g.OriginalFieldCount, g.ResultingFieldCount, g.Count)
+}
+
+// WithDedupedAlterationGrouping instructs the Scanner to group consecutive
+// alterations that share the same kind and shape into ranges, which are
+// reported by ScanSummary.AlterationGroups. A file whose format drifted part
+// way through (for instance, by gaining a column) produces an alteration for
+// every subsequent record, which a single group describes. Every alteration is
+// grouped, including those that are not retained (see DroppedAlterationCount),
+// and the retained alterations remain available from Alterations in full
+// detail. Describe (and String) describe the groups rather than the individual
+// alterations.
+func WithDedupedAlterationGrouping() Option {
+	return func(o *options) {
+		o.alterationGrouping = true
+	}
+}
+
+// groupAlteration adds alteration, which altered a record that originally had
+// originalFieldCount fields so that it has resultingFieldCount fields, to the
+// summary's AlterationGroups.
+func (s *ScanSummary) groupAlteration(alteration *Alteration, originalFieldCount, resultingFieldCount int) {
+	n := len(s.AlterationGroups)
+	if n > 0 {
+		last := s.AlterationGroups[n-1]
+		if last.Kind == alteration.Kind &&
+			last.OriginalFieldCount == originalFieldCount &&
+			last.ResultingFieldCount == resultingFieldCount {
+			s.AlterationGroups[n-1] = &AlterationGroup{
+				Kind:                last.Kind,
+				FirstRecordOrdinal:  last.FirstRecordOrdinal,
+				LastRecordOrdinal:   alteration.RecordOrdinal,
+				Count:               last.Count + 1,
+				OriginalFieldCount:  originalFieldCount,
+				ResultingFieldCount: resultingFieldCount,
+			}
+			return
+		}
+	}
+	s.AlterationGroups = append(s.AlterationGroups, &AlterationGroup{
+		Kind:                alteration.Kind,
+		FirstRecordOrdinal:  alteration.RecordOrdinal,
+		LastRecordOrdinal:   alteration.RecordOrdinal,
+		Count:               1,
+		OriginalFieldCount:  originalFieldCount,
+		ResultingFieldCount: resultingFieldCount,
+	})
+}
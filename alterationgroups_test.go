@@ -0,0 +1,148 @@
+package permissivecsv_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithDedupedAlterationGrouping(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		opts      []permissivecsv.Option
+		expGroups []*permissivecsv.AlterationGroup
+	}{
+		{
+			name: "disabled",
+			data: "a,b,c\nd,e\nf,g\n",
+		},
+		{
+			name: "no alterations",
+			data: "a,b\nc,d\n",
+			opts: []permissivecsv.Option{permissivecsv.WithDedupedAlterationGrouping()},
+		},
+		{
+			name: "format drift",
+			data: "a,b,c\nd,e\nf,g\nh,i,j\nk,l\n",
+			opts: []permissivecsv.Option{permissivecsv.WithDedupedAlterationGrouping()},
+			expGroups: []*permissivecsv.AlterationGroup{
+				{
+					Kind:                permissivecsv.AlterationPaddedRecord,
+					FirstRecordOrdinal:  2,
+					LastRecordOrdinal:   5,
+					Count:               3,
+					OriginalFieldCount:  2,
+					ResultingFieldCount: 3,
+				},
+			},
+		},
+		{
+			name: "differing shapes and kinds",
+			data: "a,b,c\nd,e\nf\ng,h,i,j\nk,l,m,n\n",
+			opts: []permissivecsv.Option{permissivecsv.WithDedupedAlterationGrouping()},
+			expGroups: []*permissivecsv.AlterationGroup{
+				{
+					Kind:                permissivecsv.AlterationPaddedRecord,
+					FirstRecordOrdinal:  2,
+					LastRecordOrdinal:   2,
+					Count:               1,
+					OriginalFieldCount:  2,
+					ResultingFieldCount: 3,
+				},
+				{
+					Kind:                permissivecsv.AlterationPaddedRecord,
+					FirstRecordOrdinal:  3,
+					LastRecordOrdinal:   3,
+					Count:               1,
+					OriginalFieldCount:  1,
+					ResultingFieldCount: 3,
+				},
+				{
+					Kind:                permissivecsv.AlterationTruncatedRecord,
+					FirstRecordOrdinal:  4,
+					LastRecordOrdinal:   5,
+					Count:               2,
+					OriginalFieldCount:  4,
+					ResultingFieldCount: 3,
+				},
+			},
+		},
+		{
+			name: "alterations not retained",
+			data: "a,b,c\nd,e\nf,g\nh,i\n",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithDedupedAlterationGrouping(),
+				permissivecsv.WithAlterationSampling(map[permissivecsv.AlterationKind]int{
+					permissivecsv.AlterationPaddedRecord: 1,
+				}),
+			},
+			expGroups: []*permissivecsv.AlterationGroup{
+				{
+					Kind:                permissivecsv.AlterationPaddedRecord,
+					FirstRecordOrdinal:  2,
+					LastRecordOrdinal:   4,
+					Count:               3,
+					OriginalFieldCount:  2,
+					ResultingFieldCount: 3,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			for s.Scan() {
+			}
+			assert.Equal(t, test.expGroups, s.Summary().AlterationGroups)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_AlterationGroupDescribe(t *testing.T) {
+	data := "a,b,c\n" + strings.Repeat("d,e\n", 4999)
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithDedupedAlterationGrouping())
+	for s.Scan() {
+	}
+	summary := s.Summary()
+	assert.Len(t, summary.Alterations, 4999, "alterations are retained in full")
+	if assert.Len(t, summary.AlterationGroups, 1) {
+		assert.Equal(t, "records 2-5000: padded record from 2 to 3 fields (4999 altered)", summary.AlterationGroups[0].String())
+	}
+	exp := "Scan Summary\n" +
+		"---------------------------------------\n" +
+		"  Records Scanned:    5000\n" +
+		"  Alterations Made:   4999\n" +
+		"  EOF:                true\n" +
+		"  Err:                none\n" +
+		"  Alteration Groups:\n" +
+		"    records 2-5000: padded record from 2 to 3 fields (4999 altered)\n"
+	assert.Equal(t, exp, summary.String())
+}
+
+func Test_AlterationGroupMerge(t *testing.T) {
+	scan := func(data string) *permissivecsv.ScanSummary {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader,
+			permissivecsv.WithDedupedAlterationGrouping())
+		for s.Scan() {
+		}
+		return s.Summary()
+	}
+	merged := scan("a,b,c\nd,e\n")
+	merged.Merge(scan("f,g,h\ni,j\n"))
+	merged.Merge(scan("k,l,m\nn,o,p,q\n"))
+	groups := []string{}
+	for _, group := range merged.AlterationGroups {
+		groups = append(groups, fmt.Sprint(group))
+	}
+	assert.Equal(t, []string{
+		"records 2-4: padded record from 2 to 3 fields (2 altered)",
+		"record 6: truncated record from 4 to 3 fields (1 altered)",
+	}, groups)
+}
@@ -4,23 +4,31 @@ package permissivecsv
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/csv"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"hash"
+	"hash/crc32"
 	"io"
-	"io/ioutil"
+	"math/rand"
+	"reflect"
 	"strings"
-	"text/template"
+	"sync"
+	"time"
+	"unicode/utf8"
 
-	"github.com/eltorocorp/permissivecsv/internal/linesplit"
 	"github.com/eltorocorp/permissivecsv/internal/util"
+	"github.com/eltorocorp/permissivecsv/linesplit"
 )
 
 var (
 	// ErrReaderIsNil is returned in the Summary if Scan is called but the
 	// reader that the Scanner was initialized with is nil.
 	ErrReaderIsNil = fmt.Errorf("reader is nil")
+
+	// ErrInvalidPartitionSize is returned by Partition if the number of
+	// records per partition is negative.
+	ErrInvalidPartitionSize = fmt.Errorf("invalid partition size")
 )
 
 const (
@@ -35,8 +43,108 @@ const (
 
 	// AltPaddedRecord is the description for padded record alterations.
 	AltPaddedRecord = "padded record"
+
+	// AltMergedFields is the description for merged field alterations.
+	AltMergedFields = "merged fields"
+
+	// AltJoinedTrailingFields is the description for joined trailing field
+	// alterations.
+	AltJoinedTrailingFields = "joined trailing fields"
+
+	// AltSwitchedDelimiter is the description for switched delimiter
+	// alterations.
+	AltSwitchedDelimiter = "switched delimiter"
+
+	// AltDroppedRecord is the description for dropped record alterations.
+	AltDroppedRecord = "dropped record"
+
+	// AltFoldedContinuation is the description for folded continuation
+	// alterations.
+	AltFoldedContinuation = "folded continuation"
 )
 
+// AlterationKind identifies the type of alteration that was made to a record.
+type AlterationKind int
+
+const (
+	// AlterationNone indicates that a record was not altered.
+	AlterationNone AlterationKind = iota
+
+	// AlterationBareQuote indicates that a record's fields were discarded due
+	// to a bare quote.
+	AlterationBareQuote
+
+	// AlterationExtraneousQuote indicates that a record's fields were
+	// discarded due to an extraneous quote.
+	AlterationExtraneousQuote
+
+	// AlterationTruncatedRecord indicates that a record had more fields than
+	// expected, and was truncated.
+	AlterationTruncatedRecord
+
+	// AlterationPaddedRecord indicates that a record had fewer fields than
+	// expected, and was padded.
+	AlterationPaddedRecord
+
+	// AlterationMergedFields indicates that a record had one more field than
+	// expected, and two adjacent fields that appeared to be a single split
+	// value were merged (see RepairMergeSplitFields).
+	AlterationMergedFields
+
+	// AlterationJoinedTrailingFields indicates that a record had more fields
+	// than expected, and the extra trailing fields were joined into the last
+	// field (see RepairJoinTrailingFields).
+	AlterationJoinedTrailingFields
+
+	// AlterationSwitchedDelimiter indicates that a record had an unexpected
+	// number of fields, and was parsed with an alternate delimiter instead of
+	// a comma (see WithAlternateDelimiters).
+	AlterationSwitchedDelimiter
+
+	// AlterationDroppedRecord indicates that a record's field count was
+	// outside the tolerance set by WithFieldCountTolerance, and the record was
+	// dropped.
+	AlterationDroppedRecord
+
+	// AlterationFoldedContinuation indicates that a record was wrapped across
+	// more than one line, and the lines were folded back into a single record
+	// (see WithContinuationRule).
+	AlterationFoldedContinuation
+
+	// firstCustomAlterationKind is the first kind allocated by
+	// RegisterAlterationKind.
+	firstCustomAlterationKind
+)
+
+// String returns the alteration description associated with the kind (such
+// as AltBareQuote).
+func (k AlterationKind) String() string {
+	switch k {
+	case AlterationNone:
+		return "none"
+	case AlterationBareQuote:
+		return AltBareQuote
+	case AlterationExtraneousQuote:
+		return AltExtraneousQuote
+	case AlterationTruncatedRecord:
+		return AltTruncatedRecord
+	case AlterationPaddedRecord:
+		return AltPaddedRecord
+	case AlterationMergedFields:
+		return AltMergedFields
+	case AlterationJoinedTrailingFields:
+		return AltJoinedTrailingFields
+	case AlterationSwitchedDelimiter:
+		return AltSwitchedDelimiter
+	case AlterationDroppedRecord:
+		return AltDroppedRecord
+	case AlterationFoldedContinuation:
+		return AltFoldedContinuation
+	default:
+		return registeredAlterationKind(k)
+	}
+}
+
 // Scanner provides methods for permissively reading CSV input. Successive
 // calls to the Scan method will step through the records of a file.
 //
@@ -92,6 +200,9 @@ const (
 // unable to make any assumptions about the author's intentions. When such
 // replacements are made, the type of replacement, record number, and original
 // data are all immediately available via the Summary method.
+//
+// A Scanner is not safe for concurrent use. Use a SyncScanner to share a
+// single input between goroutines.
 type Scanner struct {
 	headerCheck        HeaderCheck
 	currentRecord      []string
@@ -101,7 +212,8 @@ type Scanner struct {
 	recordsScanned     int64
 	scanSummary        *ScanSummary
 	checkedForHeader   bool
-	splitter           *linesplit.Splitter
+	splitter           recordSplitter
+	opts               options
 
 	// bytesUnclaimed exists solely for the Partition method.
 	// It represents the number of bytes the scan method has ignored while
@@ -111,9 +223,222 @@ type Scanner struct {
 	// lengths.
 	bytesUnclaimed int64
 
+	// emptyRecordsUnclaimed is the number of empty records represented by
+	// bytesUnclaimed.
+	emptyRecordsUnclaimed int64
+
+	// leadingEmptyRecords and danglingEmptyRecords are the number of empty
+	// records that were ignored because they preceded the first record, or
+	// followed the last record (see WithKeepEmptyRecords). They exist solely
+	// for Segments.Validate.
+	leadingEmptyRecords  int
+	danglingEmptyRecords int
+
+	// segmentHash is non-nil while Partition is running, and accumulates the
+	// checksum of the bytes in the current segment. trailingHash accumulates
+	// the checksum of the unclaimed bytes that follow the most recent record
+	// (see WithEmptySegments).
+	segmentHash  hash.Hash32
+	trailingHash hash.Hash32
+
+	// headerSegment is the byte range of the header, if Partition excluded
+	// one.
+	headerSegment *Segment
+
+	// bytesConsumed is the number of bytes of input that have been processed
+	// by Scan, and recordOffset is the byte offset at which the current record
+	// begins.
+	bytesConsumed int64
+
+	// bytesRead is the number of bytes of input that have been read by the
+	// internal scanner, including any tokens that have been read ahead.
+	bytesRead    int64
+	recordOffset int64
+
+	// rawRecordLength is the length in bytes of the current record, including
+	// its terminator.
+	rawRecordLength int64
+
+	// currentRawFields is the current record as it appeared in the input
+	// (without its terminator), and currentParsedFieldCount is the number of
+	// fields that were parsed from it prior to any padding. These are retained
+	// so that CurrentFieldStates can be computed on demand.
+	currentRawFields        string
+	currentParsedFieldCount int
+
+	// currentMergedField is the index of the field into which the following
+	// field was merged (see WithMergeSplitFields), or -1 if no fields were
+	// merged.
+	currentMergedField int
+
+	// currentFieldShift is the number of fields that were removed from (if
+	// positive) or added to (if negative) the beginning of the current record
+	// (see WithTruncation and WithPadding).
+	currentFieldShift int
+
+	// currentDelimiter is the delimiter with which the fields of the current
+	// record were parsed (see WithAlternateDelimiters).
+	currentDelimiter rune
+
+	// recordsReturned is the number of records for which Scan has returned
+	// true (see WithMaxRecords). Unlike the summary's RecordCount, it excludes
+	// records that were dropped or skipped.
+	recordsReturned int
+
+	// state is the state of the Scanner (see State).
+	state ScannerState
+
+	// readBuffer is the buffer supplied to the internal scanner by a
+	// ScannerPool (if any), and recycledBuffers holds it for return to the
+	// pool.
+	readBuffer      []byte
+	recycledBuffers *scannerBuffers
+
+	// redactedColumns are the columns supplied to WithRedaction that have
+	// been matched to the header.
+	redactedColumns []redactedColumn
+
+	// jsonExpansions are the columns supplied to WithJSONColumn that have
+	// been matched to the header, and jsonColumnNames are the names of the
+	// columns flattened from them.
+	jsonColumnsResolved bool
+	jsonExpansions      []jsonExpansion
+	jsonColumnNames     []string
+
+	// alterationMemory is the approximate number of bytes used by the
+	// retained alterations (see WithMemoryBudget), and retainedAlterations is
+	// the number of retained alterations of each kind (see
+	// WithAlterationSampling).
+	alterationMemory    int64
+	retainedAlterations map[AlterationKind]int
+
+	// deadline is the time after which Scan will return false (see
+	// WithDeadline and WithTimeout). The zero value indicates no deadline.
+	deadline time.Time
+
+	// validating is true while Validate is scanning, in which case records that
+	// do not require alteration are not parsed into fields.
+	validating bool
+
+	// scanningRaw is true while ScanRaw is scanning, in which case records are
+	// not parsed into fields.
+	scanningRaw bool
+
+	// summaryMu guards the summary (see Snapshot), and summaryHeld is true
+	// while the Scanner holds it.
+	summaryMu   *sync.Mutex
+	summaryHeld bool
+
+	// closed is true once Close has been called.
+	closed bool
+
+	// sampler draws the records that are returned by Scan (see
+	// WithSampleRate).
+	sampler *rand.Rand
+
+	// currentTerminator is the terminator of the current record, and
+	// currentAlteration is the alteration (if any) that was made to it.
+	currentTerminator []byte
+	currentAlteration AlterationKind
+
+	// When empty records are being kept, any empty records that precede a
+	// non-empty record are queued (along with the non-empty record), so that
+	// dangling terminators can still be ignored.
+	pendingEmptyRecords []emptyRecord
+	pendingRawRecord    *rawToken
+
+	// lookahead holds tokens that have been read from the input by peekRecord
+	// (or Peek), but not yet processed by Scan.
+	lookahead []rawToken
+
 	// the value can only be non-nil the first time Scan is called
 	// and will be nil for all subsequent calls.
 	firstRecord []string
+
+	// header holds the column names once the first record has been
+	// identified as a header, and decodePlans caches the mapping of struct
+	// fields to columns used by Decode. leadingRecords retains the first two
+	// records, so that Decode can identify the header even if the first record
+	// was not checked while it was current. headerResolved is true once the
+	// header (if any) has been identified.
+	header         []string
+	headerResolved bool
+	leadingRecords [][]string
+	decodePlans    map[reflect.Type][]decodeTarget
+
+	// recordMapKeys caches the key of each column for CurrentRecordMap.
+	recordMapKeys map[int]string
+
+	// checkpointedRecords is the RecordCount of the last checkpoint (see
+	// WithCheckpointEvery), and checkpointedFinal is true once the final
+	// checkpoint has been emitted.
+	checkpointedRecords int
+	checkpointedFinal   bool
+
+	// uniqueIndexes are the indexes of the columns supplied to
+	// WithUniqueColumns, once they have been matched to the header, and
+	// uniqueKeys tracks the keys that have been seen.
+	uniqueIndexes []int
+	uniqueKeys    keyTracker
+
+	// repeatedHeader is the header as it appeared in the input, if it has been
+	// identified (see WithSkipRepeatedHeaders).
+	repeatedHeader *string
+
+	// strictTerminator is the first terminator encountered, against which
+	// subsequent terminators are compared (see WithStrictRFC4180).
+	strictTerminator []byte
+
+	// tokenQuoteForceClosed is true if a quote within the token most recently
+	// returned by nextToken was force closed, and currentQuoteForceClosed is
+	// true if a quote within the current record was force closed (see
+	// WithMaxQuotedFieldBytes).
+	tokenQuoteForceClosed   bool
+	currentQuoteForceClosed bool
+
+	// currentFolds is the number of continuation lines that were folded into
+	// the current record (see WithContinuationRule).
+	currentFolds int
+
+	// currentOriginalFields are the fields of the current record as they
+	// appeared in the input, from which the FieldChanges of its alterations
+	// are derived, or nil if the record was redacted.
+	currentOriginalFields []string
+
+	// currentDropped is true if the record most recently processed was
+	// dropped (see WithFieldCountTolerance), in which case Scan continues to
+	// the next record.
+	currentDropped bool
+
+	// span is the scan span while it is in progress, and spanStarted is true
+	// once it has been started (see WithTracer).
+	span        Span
+	spanStarted bool
+
+	// lastRecord is the record most recently returned by Scan, and done is
+	// true once Scan has returned false (see Done).
+	lastRecord []string
+	done       bool
+}
+
+// emptyRecord locates an empty record within the input. text is the record
+// as it appeared in the input, including its terminator.
+type emptyRecord struct {
+	offset     int64
+	length     int64
+	text       string
+	terminator []byte
+}
+
+// rawToken is an unparsed record and its terminator.
+type rawToken struct {
+	text             string
+	terminator       []byte
+	quoteForceClosed bool
+
+	// folds is the number of continuation lines that were folded into the
+	// token (see WithContinuationRule).
+	folds int
 }
 
 // HeaderCheck is a function that evaluates whether or not firstRecord is
@@ -122,130 +447,472 @@ type Scanner struct {
 //
 // firstRecord is the first record of the file.
 // firstRecord will be nil in the following conditions:
-//  - Scan has not been called.
-//  - The file is empty.
-//  - The Scanner has advanced beyond the first record.
-type HeaderCheck func(firstRecord []string) bool
+//   - Scan has not been called.
+//   - The file is empty.
+//   - The Scanner has advanced beyond the first record.
+//
+// secondRecord is the record that follows the first record, as parsed from
+// the input (prior to any padding or truncation). Comparing the two records is
+// often the most reliable way to identify a header (for instance, a header
+// rarely contains numbers, where the data beneath it often does). The Scanner
+// reads ahead in order to supply secondRecord, but this does not affect the
+// records returned by Scan.
+// secondRecord will be nil in the following conditions:
+//   - firstRecord is nil.
+//   - The file contains only one record.
+//   - The second record contains an ambiguous quote.
+//
+// Use FirstRecordOnly to adapt a function that only inspects the first
+// record.
+type HeaderCheck func(firstRecord, secondRecord []string) bool
+
+// FirstRecordOnly adapts check, which only inspects the first record, to a
+// HeaderCheck.
+func FirstRecordOnly(check func(firstRecord []string) bool) HeaderCheck {
+	return func(firstRecord, secondRecord []string) bool {
+		return check(firstRecord)
+	}
+}
 
 // HeaderCheckAssumeNoHeader is a HeaderCheck that instructs the RecordIsHeader
 // method to report that no header exists for the file being scanned.
-var HeaderCheckAssumeNoHeader HeaderCheck = func(firstRecord []string) bool {
+var HeaderCheckAssumeNoHeader HeaderCheck = func(firstRecord, secondRecord []string) bool {
 	return false
 }
 
 // HeaderCheckAssumeHeaderExists returns true unless firstRecord is nil.
-var HeaderCheckAssumeHeaderExists HeaderCheck = func(firstRecord []string) bool {
+var HeaderCheckAssumeHeaderExists HeaderCheck = func(firstRecord, secondRecord []string) bool {
 	return firstRecord != nil
 }
 
-// NewScanner returns a new Scanner to read from r.
-func NewScanner(r io.Reader, headerCheck HeaderCheck) *Scanner {
-	internalScanner := bufio.NewScanner(r)
+// NewScanner returns a new Scanner to read from r. Any supplied Options are
+// applied to the Scanner.
+func NewScanner(r io.Reader, headerCheck HeaderCheck, opts ...Option) *Scanner {
+	return newScanner(r, headerCheck, newOptions(opts))
+}
+
+func newScanner(r io.Reader, headerCheck HeaderCheck, o options) *Scanner {
 	s := &Scanner{
 		headerCheck: headerCheck,
 		reader:      r,
-		scanner:     internalScanner,
-		splitter:    new(linesplit.Splitter),
+		splitter: &linesplit.Splitter{
+			DisableInvertedDOS: o.disableInvertedDOS,
+			Priority:           o.terminatorPriority,
+			MaxQuotedBytes:     o.maxQuotedFieldBytes,
+		},
+		opts: o,
+	}
+	if o.rowStart != nil {
+		s.splitter = &rowBoundarySplitter{rowStart: o.rowStart}
 	}
-	internalScanner.Split(s.splitter.Split)
+	s.scanner = s.newInternalScanner(r)
 	return s
 }
 
 // Scan advances the scanner to the next non-empty record, which is then available
 // via the CurrentRecord method. Scan returns false when it reaches the end
 // of the file. Once scanning is complete, subsequent scans will continue to
-// return false until the Reset method is called.
+// return false until the Reset method is called, and CurrentRecord returns the
+// last record that Scan returned (see Done and WithClearRecordOnDone).
 //
 // Scan skips what it considers "empty records". An empty record occurs any time
-// one or more terminators are present with no surrounding data.
+// one or more terminators are present with no surrounding data. Empty records
+// are counted in the Summary, and can be emitted rather than skipped by
+// supplying the WithKeepEmptyRecords option.
 //
 // If the underlaying Reader is nil, Scan will return false on the first call.
 // In all other cases, Scan will return true on the first call. This is done
 // to allow the caller to explicitely inspect the resulting record (even if
 // said record is empty).
+//
+// If a callback supplied to the Scanner panics, the panic is recovered, Scan
+// returns false, and the panic is reported by the summary's Err (see
+// CallbackPanicError).
 func (s *Scanner) Scan() bool {
-	var (
-		extraneousQuoteEncountered = false
-		bareQuoteEncountered       = false
-		recordTruncated            = false
-		recordPadded               = false
-	)
+	defer s.holdSummary()()
+	s.startSpan()
+	s.checkpoint(false)
+	more := s.scan()
+	for more && (s.currentDropped || s.skipUnsampled()) {
+		s.currentDropped = false
+		more = s.scan()
+	}
+	if !more || s.state != ScannerStateScanning {
+		s.checkpoint(true)
+		s.endSpan()
+		s.finish()
+		return false
+	}
+	s.recordsReturned++
+	s.lastRecord = s.currentRecord
+	return true
+}
 
+func (s *Scanner) scan() bool {
 	if s.scanSummary == nil {
-		s.scanSummary = &ScanSummary{
-			Alterations: []*Alteration{},
+		s.scanSummary = s.newScanSummary()
+		s.deadline = s.opts.deadline
+		if s.opts.timeout > 0 {
+			timeoutDeadline := time.Now().Add(s.opts.timeout)
+			if s.deadline.IsZero() || timeoutDeadline.Before(s.deadline) {
+				s.deadline = timeoutDeadline
+			}
 		}
 	}
 
+	if s.state != ScannerStateScanning {
+		return false
+	}
+
 	if s.reader == nil {
-		s.scanSummary.Err = ErrReaderIsNil
+		s.scanSummary.addErr(ErrReaderIsNil)
 		s.scanSummary.RecordCount = -1
 		s.scanSummary.AlterationCount = -1
 		s.scanSummary.EOF = false
+		s.state = ScannerStateErrored
 		return false
 	}
 
-	var record []string
-	more := s.scanner.Scan()
+	if !s.deadline.IsZero() && !time.Now().Before(s.deadline) {
+		s.scanSummary.DeadlineExceeded = true
+		s.scanSummary.addErr(ErrDeadlineExceeded)
+		s.scanSummary.ResumeOffset = s.resumeOffset()
+		s.state = ScannerStateLimited
+		return false
+	}
+	if s.opts.maxRecords > 0 && s.recordsReturned >= s.opts.maxRecords && s.hasMoreRecords() {
+		s.scanSummary.LimitReached = true
+		s.scanSummary.addErr(ErrLimitReached)
+		s.scanSummary.ResumeOffset = s.resumeOffset()
+		s.state = ScannerStateLimited
+		return false
+	}
+
+	if len(s.pendingEmptyRecords) > 0 {
+		s.emitEmptyRecord()
+		return true
+	}
+
+	if s.pendingRawRecord != nil {
+		pending := s.pendingRawRecord
+		s.pendingRawRecord = nil
+		return s.processWithinLimits(*pending)
+	}
+
+	rawRecord, currentTerminator, more := s.nextToken()
 	if !more {
-		s.scanSummary.EOF = true
+		s.endScan()
 		return false
 	}
 
-	rawRecord := s.scanner.Text()
-	currentTerminator := s.splitter.CurrentTerminator()
-	for rawRecord == string(currentTerminator) && more {
-		s.bytesUnclaimed += int64(len(currentTerminator))
-		more = s.scanner.Scan()
-		rawRecord = s.scanner.Text()
-		currentTerminator = s.splitter.CurrentTerminator()
+	blankRun := 0
+	blankRunBytes := int64(0)
+	for more && s.isEmptyToken(rawRecord, currentTerminator) {
+		s.scanSummary.EmptyRecordCount++
+		kept := s.opts.keepEmptyRecords && s.recordsScanned > 0
+		if kept {
+			s.pendingEmptyRecords = append(s.pendingEmptyRecords, emptyRecord{
+				offset:     s.bytesConsumed,
+				length:     int64(len(rawRecord)),
+				text:       rawRecord,
+				terminator: currentTerminator,
+			})
+		} else {
+			if s.recordsScanned == 0 {
+				s.leadingEmptyRecords++
+			}
+			s.unclaim([]byte(rawRecord))
+		}
+		s.checkStrictEmptyRecord(currentTerminator, s.bytesConsumed, kept)
+		s.bytesConsumed += int64(len(rawRecord))
+		blankRun++
+		blankRunBytes += int64(len(rawRecord))
+		if s.opts.stopAtBlankRun > 0 && s.recordsScanned > 0 && blankRun >= s.opts.stopAtBlankRun {
+			s.pendingEmptyRecords = nil
+			s.scanSummary.StoppedAtBlankRun = true
+			s.scanSummary.IgnoredBytes = blankRunBytes
+			s.discardRemaining()
+			return false
+		}
+		rawRecord, currentTerminator, more = s.nextToken()
 		continue
 	}
 
 	if rawRecord == "" && len(currentTerminator) == 0 {
+		// Any pending empty records are dangling terminators, which are
+		// always ignored.
+		for _, empty := range s.pendingEmptyRecords {
+			s.unclaim([]byte(empty.text))
+		}
+		s.danglingEmptyRecords += len(s.pendingEmptyRecords)
+		s.pendingEmptyRecords = nil
+		s.endScan()
+		return false
+	}
+
+	if len(s.pendingEmptyRecords) > 0 {
+		s.pendingRawRecord = &rawToken{
+			text:             rawRecord,
+			terminator:       currentTerminator,
+			quoteForceClosed: s.tokenQuoteForceClosed,
+		}
+		s.emitEmptyRecord()
+		return true
+	}
+
+	return s.processWithinLimits(rawToken{
+		text:             rawRecord,
+		terminator:       currentTerminator,
+		quoteForceClosed: s.tokenQuoteForceClosed,
+	})
+}
+
+// processWithinLimits processes token, unless doing so would exceed the
+// limit set by WithMaxBytes. In that case, the token is held back, and the
+// summary reports that the limit was reached.
+func (s *Scanner) processWithinLimits(token rawToken) bool {
+	token = s.foldContinuations(token)
+	if s.opts.maxBytes > 0 && s.bytesConsumed+int64(len(token.text)) > s.opts.maxBytes {
+		s.pendingRawRecord = &token
+		s.scanSummary.LimitReached = true
+		s.scanSummary.addErr(ErrLimitReached)
+		s.scanSummary.ResumeOffset = s.resumeOffset()
+		s.state = ScannerStateLimited
 		return false
 	}
+	s.currentQuoteForceClosed = token.quoteForceClosed
+	s.currentFolds = token.folds
+	if token.quoteForceClosed {
+		s.scanSummary.ForceClosedQuoteCount++
+	}
+	return s.processRawRecord(token.text, token.terminator)
+}
+
+// unclaim accounts for the bytes of an empty record that Scan has skipped
+// (see bytesUnclaimed).
+func (s *Scanner) unclaim(raw []byte) {
+	s.unclaimBytes(raw)
+	s.emptyRecordsUnclaimed++
+}
+
+// unclaimBytes accounts for raw bytes that Scan has skipped, such as those of
+// a record that was dropped (see bytesUnclaimed).
+func (s *Scanner) unclaimBytes(raw []byte) {
+	s.bytesUnclaimed += int64(len(raw))
+	if s.segmentHash != nil {
+		s.segmentHash.Write(raw)
+		s.trailingHash.Write(raw)
+	}
+}
+
+// claim resets the accounting of unclaimed bytes once Partition has assigned
+// them to a segment.
+func (s *Scanner) claim() {
+	s.bytesUnclaimed = 0
+	s.emptyRecordsUnclaimed = 0
+	if s.segmentHash != nil {
+		s.segmentHash.Reset()
+	}
+}
+
+// resumeOffset returns the byte offset of the first record that has not yet
+// been returned by Scan.
+func (s *Scanner) resumeOffset() int64 {
+	if len(s.pendingEmptyRecords) > 0 {
+		return s.pendingEmptyRecords[0].offset
+	}
+	return s.bytesConsumed
+}
+
+// emitEmptyRecord makes the next pending empty record the current record.
+func (s *Scanner) emitEmptyRecord() {
+	empty := s.pendingEmptyRecords[0]
+	s.pendingEmptyRecords = s.pendingEmptyRecords[1:]
+	s.scanSummary.RecordCount++
+	s.scanSummary.TerminatorCounts[Terminator(empty.terminator)]++
+	s.recordOffset = empty.offset
+	s.rawRecordLength = empty.length
+	s.currentRecord = make([]string, s.expectedFieldCount)
+	s.currentRawFields = strings.TrimSuffix(empty.text, string(empty.terminator))
+	s.currentParsedFieldCount = 0
+	s.currentMergedField = -1
+	s.currentFieldShift = 0
+	s.currentDelimiter = ','
+	s.currentTerminator = empty.terminator
+	s.currentAlteration = AlterationNone
+	s.currentQuoteForceClosed = false
+	s.firstRecord = nil
+}
+
+// processRawRecord parses rawRecord into fields, makes any necessary
+// alterations, and makes the result the current record.
+func (s *Scanner) processRawRecord(rawRecord string, currentTerminator []byte) bool {
+	var (
+		extraneousQuoteEncountered = false
+		bareQuoteEncountered       = false
+		recordTruncated            = false
+		recordPadded               = false
+		recordRepaired             = false
+		repairKind                 AlterationKind
+		alternateRecord            []string
+		bestEffortRecord           []string
+	)
 
+	var record []string
 	var trimmedRawRecord string
 	s.scanSummary.RecordCount++
+	s.scanSummary.TerminatorCounts[Terminator(currentTerminator)]++
+	s.recordOffset = s.bytesConsumed
+	if !utf8.ValidString(rawRecord) {
+		s.scanSummary.InvalidUTF8Count++
+	}
+	s.rawRecordLength = int64(len(rawRecord))
+	s.bytesConsumed += int64(len(rawRecord))
 	if len(currentTerminator) > 0 && strings.HasSuffix(rawRecord, string(currentTerminator)) {
 		trimmedRawRecord = rawRecord[:len(rawRecord)-len(currentTerminator)]
 	} else {
 		trimmedRawRecord = rawRecord
 	}
+	s.checkStrictTerminator(currentTerminator, s.scanSummary.RecordCount, s.recordOffset)
+	if s.skipRepeatedHeader(rawRecord, trimmedRawRecord) {
+		return true
+	}
+
+	if s.scanningRaw {
+		s.recordsScanned++
+		s.currentRecord = nil
+		s.currentRawFields = trimmedRawRecord
+		s.currentParsedFieldCount = 0
+		s.currentMergedField = -1
+		s.currentFieldShift = 0
+		s.currentDelimiter = ','
+		s.currentTerminator = currentTerminator
+		s.currentAlteration = AlterationNone
+		s.firstRecord = nil
+		return true
+	}
+
+	if s.validating && s.isWellFormed(trimmedRawRecord) {
+		s.scanSummary.observeFieldCount(s.expectedFieldCount)
+		s.recordsScanned++
+		s.currentRecord = nil
+		s.currentRawFields = trimmedRawRecord
+		s.currentParsedFieldCount = s.expectedFieldCount
+		s.currentMergedField = -1
+		s.currentFieldShift = 0
+		s.currentDelimiter = ','
+		s.currentTerminator = currentTerminator
+		s.currentAlteration = AlterationNone
+		s.firstRecord = nil
+		return true
+	}
 
 	if trimmedRawRecord == "" {
 		record = []string{""}
 	} else {
-		// we want to leverage csv.Reader for its field parsing logic, but
-		// want to avoid its record parsing logic. So, we replace any instances
-		// of \n or \r with tokens to override the Readers standard record
-		// termination handling; then fix the tokens after the fact.
-		text := util.TokenizeTerminators(trimmedRawRecord)
-		c := csv.NewReader(strings.NewReader(text))
 		var err error
-		record, err = c.Read()
+		record, err = parseFields(trimmedRawRecord, ',', false)
 		if err != nil {
 			extraneousQuoteEncountered = util.IsExtraneousQuoteError(err)
 			bareQuoteEncountered = util.IsBareQuoteError(err)
 			record = []string{}
+			if s.opts.lazyQuoteRetry && (extraneousQuoteEncountered || bareQuoteEncountered) {
+				bestEffortRecord, _ = parseFields(trimmedRawRecord, ',', true)
+			}
+		}
+	}
+	s.checkStrictFields(len(record), extraneousQuoteEncountered, bareQuoteEncountered)
+	if !extraneousQuoteEncountered && !bareQuoteEncountered && s.skipEmptyFirstRecord(rawRecord, record) {
+		return true
+	}
+
+	// Records that do not have the expected number of fields (including those
+	// that could not be parsed at all) might use an alternate delimiter.
+	delimiter := ','
+	if len(s.opts.alternateDelimiters) > 0 &&
+		s.recordsScanned > 0 &&
+		len(record) != s.expectedFieldCount {
+		switched, switchedDelimiter, ok := s.switchDelimiter(trimmedRawRecord)
+		if ok {
+			if extraneousQuoteEncountered || bareQuoteEncountered {
+				alternateRecord = make([]string, s.expectedFieldCount)
+			} else {
+				alternateRecord, _ = fitRecord(record, s.expectedFieldCount, s.opts.truncation, s.opts.padding)
+			}
+			record = switched
+			delimiter = switchedDelimiter
+			extraneousQuoteEncountered = false
+			bareQuoteEncountered = false
+			bestEffortRecord = nil
 		}
-		record = util.ResetTerminatorTokens(record)
+	}
+	delimiterSwitched := delimiter != ','
+	if s.opts.preserveQuotes && !extraneousQuoteEncountered && !bareQuoteEncountered {
+		record = preserveQuotes(trimmedRawRecord, delimiter, record)
 	}
 
+	if !extraneousQuoteEncountered && !bareQuoteEncountered {
+		s.scanSummary.observeFieldCount(len(record))
+	}
+	s.currentRawFields = trimmedRawRecord
+	s.currentParsedFieldCount = len(record)
+	s.currentMergedField = -1
+	s.currentFieldShift = 0
+	s.currentDelimiter = delimiter
+
 	s.recordsScanned++
+	if bestEffortRecord != nil {
+		if s.recordsScanned == 1 {
+			s.expectedFieldCount = len(bestEffortRecord)
+		}
+		alternateRecord = make([]string, s.expectedFieldCount)
+		record = bestEffortRecord
+		s.currentParsedFieldCount = len(record)
+	}
 	if s.recordsScanned == 1 {
 		s.expectedFieldCount = len(record)
+		if s.opts.expectedHeader != nil {
+			s.expectedFieldCount = len(s.opts.expectedHeader)
+		}
+	}
+	parsedRecord := record
+
+	if len(s.opts.repairStrategies) > 0 &&
+		s.recordsScanned > 1 &&
+		len(record) != s.expectedFieldCount &&
+		!extraneousQuoteEncountered && !bareQuoteEncountered {
+		repaired, kind, ok := s.callRepairStrategies(trimmedRawRecord, record, s.expectedFieldCount)
+		if ok {
+			alternateRecord, _ = fitRecord(record, s.expectedFieldCount, s.opts.truncation, s.opts.padding)
+			s.currentMergedField = mergedFieldIndex(record, repaired)
+			record = repaired
+			repairKind = kind
+			recordRepaired = true
+		}
 	}
 
 	if len(record) > s.expectedFieldCount {
-		record = record[:s.expectedFieldCount]
 		recordTruncated = true
 	} else if len(record) < s.expectedFieldCount {
-		pad := make([]string, s.expectedFieldCount-len(record))
-		record = append(record, pad...)
 		recordPadded = true
 	}
+	s.currentOriginalFields = parsedRecord
+	if (extraneousQuoteEncountered || bareQuoteEncountered) && bestEffortRecord == nil {
+		s.currentOriginalFields, _ = parseFields(trimmedRawRecord, delimiter, true)
+	}
+	if s.recordsScanned > 1 && len(s.redactedColumns) > 0 {
+		s.currentOriginalFields = nil
+	}
+	plainlyFitted := (recordTruncated || recordPadded) && !recordRepaired && !delimiterSwitched &&
+		!extraneousQuoteEncountered && !bareQuoteEncountered
+	withinTolerance := plainlyFitted && s.fieldCountWithinTolerance(len(record))
+	if plainlyFitted && !withinTolerance && s.opts.tolerancePolicy == ToleranceDrop {
+		s.dropRecord(rawRecord, trimmedRawRecord)
+		return true
+	}
+	if recordTruncated || recordPadded {
+		record, s.currentFieldShift = fitRecord(record, s.expectedFieldCount, s.opts.truncation, s.opts.padding)
+	}
 
 	// In cases where the record (for any reason) ends up with zero capacity
 	// (nil), we return an empty slice with capacity 1 instead. This ensures the
@@ -254,6 +921,10 @@ func (s *Scanner) Scan() bool {
 	if cap(record) == 0 {
 		record = make([]string, 0, 1)
 	}
+	originalData := trimmedRawRecord
+	if s.recordsScanned > 1 && s.redact(record, alternateRecord) {
+		originalData = ""
+	}
 	s.currentRecord = record
 
 	if s.recordsScanned == 1 {
@@ -261,99 +932,516 @@ func (s *Scanner) Scan() bool {
 	} else {
 		s.firstRecord = nil
 	}
+	if s.recordsScanned <= 2 {
+		s.leadingRecords = append(s.leadingRecords, record)
+	}
+
+	if s.recordsScanned == 1 && s.opts.redactions != nil {
+		var header []string
+		if s.RecordIsHeader() {
+			header = record
+		}
+		s.resolveRedactions(header)
+	}
+	if s.recordsScanned == 1 && s.opts.skipRepeatedHeaders && s.RecordIsHeader() {
+		s.resolveRepeatedHeader(trimmedRawRecord)
+	}
+	if s.recordsScanned == 1 && s.opts.uniqueColumns != nil && s.RecordIsHeader() {
+		s.resolveUniqueColumns(record)
+	}
+	if s.recordsScanned > 1 && s.uniqueKeys != nil && !extraneousQuoteEncountered && !bareQuoteEncountered {
+		s.checkUniqueKey(record)
+	}
+	if s.recordsScanned == 1 && s.opts.expectedHeader != nil && s.RecordIsHeader() {
+		s.scanSummary.HeaderMismatch = compareHeader(parsedRecord, s.opts.expectedHeader)
+	}
+	if s.recordsScanned == 1 && s.opts.normalizeHeader && s.RecordIsHeader() {
+		s.currentRecord = NormalizeColumnNames(record)
+	}
 
+	s.currentTerminator = currentTerminator
+	s.currentAlteration = AlterationNone
+	if s.currentFolds > 0 {
+		s.appendAlteration(originalData, record, nil, AlterationFoldedContinuation)
+	}
 	if extraneousQuoteEncountered {
-		s.appendAlteration(trimmedRawRecord, record, AltExtraneousQuote)
+		alteration := s.appendAlteration(originalData, record, alternateRecord, AlterationExtraneousQuote)
+		alteration.BestEffort = bestEffortRecord != nil
 	} else if bareQuoteEncountered {
-		s.appendAlteration(trimmedRawRecord, record, AltBareQuote)
-	} else if recordTruncated {
-		s.appendAlteration(trimmedRawRecord, record, AltTruncatedRecord)
-	} else if recordPadded {
-		s.appendAlteration(trimmedRawRecord, record, AltPaddedRecord)
+		alteration := s.appendAlteration(originalData, record, alternateRecord, AlterationBareQuote)
+		alteration.BestEffort = bestEffortRecord != nil
+	} else if delimiterSwitched {
+		s.appendAlteration(originalData, record, alternateRecord, AlterationSwitchedDelimiter)
+	} else if recordRepaired {
+		s.appendAlteration(originalData, record, alternateRecord, repairKind)
+	} else if recordTruncated || recordPadded {
+		kind := AlterationPaddedRecord
+		if recordTruncated {
+			kind = AlterationTruncatedRecord
+		}
+		alteration := s.appendAlteration(originalData, record, nil, kind)
+		if withinTolerance {
+			alteration.Severity = SeverityInfo
+			s.scanSummary.InfoAlterationCount++
+		}
 	}
+	s.skipLoneHeader(rawRecord)
 
 	return true
 }
 
-func (s *Scanner) appendAlteration(originalText string, record []string, description string) {
-	s.scanSummary.AlterationCount++
-	s.scanSummary.Alterations = append(s.scanSummary.Alterations, &Alteration{
+// parseFields splits a record (without its terminator) into fields separated
+// by delimiter. If lazyQuotes is true, quotes are parsed as by csv.Reader's
+// LazyQuotes.
+func parseFields(trimmedRawRecord string, delimiter rune, lazyQuotes bool) ([]string, error) {
+	// we want to leverage csv.Reader for its field parsing logic, but
+	// want to avoid its record parsing logic. So, we replace any instances
+	// of \n or \r with tokens to override the Readers standard record
+	// termination handling; then fix the tokens after the fact.
+	text := util.TokenizeTerminators(trimmedRawRecord)
+	c := csv.NewReader(strings.NewReader(text))
+	c.Comma = delimiter
+	c.LazyQuotes = lazyQuotes
+	record, err := c.Read()
+	if err != nil {
+		return nil, err
+	}
+	return util.ResetTerminatorTokens(record), nil
+}
+
+// nextToken returns the next raw token from the input, along with its
+// terminator, and records whether a quote within it was force closed (see
+// WithMaxQuotedFieldBytes) in tokenQuoteForceClosed. Tokens that have been
+// read ahead by peekRecord are returned first. nextToken returns false once
+// the input is exhausted.
+func (s *Scanner) nextToken() (string, []byte, bool) {
+	if len(s.lookahead) > 0 {
+		token := s.lookahead[0]
+		s.lookahead = s.lookahead[1:]
+		s.tokenQuoteForceClosed = token.quoteForceClosed
+		return token.text, token.terminator, true
+	}
+	s.tokenQuoteForceClosed = false
+	if !s.readToken() {
+		return "", nil, false
+	}
+	s.tokenQuoteForceClosed = s.splitter.QuoteForceClosed()
+	return s.scanner.Text(), s.splitter.CurrentTerminator(), true
+}
+
+// peekRecord returns the fields of the next non-empty record without
+// advancing the Scanner. Any tokens that must be read from the input to locate
+// the record are retained for subsequent calls to Scan. peekRecord returns nil
+// if there are no more records, or if the record cannot be parsed.
+func (s *Scanner) peekRecord() []string {
+	if s.pendingRawRecord != nil {
+		return peekFields(*s.pendingRawRecord)
+	}
+	token, ok := s.peekToken()
+	if !ok {
+		return nil
+	}
+	return peekFields(token)
+}
+
+// hasMoreRecords reports whether a subsequent call to Scan would produce a
+// record (ignoring any limits).
+func (s *Scanner) hasMoreRecords() bool {
+	if len(s.pendingEmptyRecords) > 0 || s.pendingRawRecord != nil {
+		return true
+	}
+	_, ok := s.peekToken()
+	return ok
+}
+
+// peekToken reads ahead to the next non-empty token, retaining any tokens that
+// are read for subsequent calls to Scan. peekToken returns false if there are
+// no more non-empty tokens, or if a blank run (see WithStopAtBlankRun) is
+// encountered first.
+func (s *Scanner) peekToken() (rawToken, bool) {
+	token, _, ok := s.peekTokenFrom(0)
+	return token, ok
+}
+
+// peekTokenFrom is like peekToken, but ignores the first start tokens of the
+// lookahead. It also returns the index of the token within the lookahead.
+func (s *Scanner) peekTokenFrom(start int) (rawToken, int, bool) {
+	blankRun := 0
+	for i := start; ; i++ {
+		if i == len(s.lookahead) && !s.readAhead() {
+			return rawToken{}, i, false
+		}
+		token := s.lookahead[i]
+		if s.isEmptyToken(token.text, token.terminator) {
+			blankRun++
+			if s.opts.stopAtBlankRun > 0 && s.recordsScanned > 0 && blankRun >= s.opts.stopAtBlankRun {
+				return rawToken{}, i, false
+			}
+			continue
+		}
+		return token, i, token.text != ""
+	}
+}
+
+// readAhead reads the next token from the input into the lookahead, returning
+// false once the input is exhausted.
+func (s *Scanner) readAhead() bool {
+	if !s.readToken() {
+		return false
+	}
+	s.lookahead = append(s.lookahead, rawToken{
+		text:             s.scanner.Text(),
+		terminator:       s.splitter.CurrentTerminator(),
+		quoteForceClosed: s.splitter.QuoteForceClosed(),
+	})
+	return true
+}
+
+// peekFields parses the fields of token, returning nil if token is empty or
+// cannot be parsed.
+func peekFields(token rawToken) []string {
+	text := strings.TrimSuffix(token.text, string(token.terminator))
+	if text == "" {
+		return nil
+	}
+	record, err := parseFields(text, ',', false)
+	if err != nil {
+		return nil
+	}
+	return record
+}
+
+// endScan records the reason scanning stopped. If the underlaying reader
+// returned an error, that error is reported via the summary as a ReadError,
+// unless it is the panic of a callback used to locate records (see
+// WithRowBoundary). Otherwise, the scanner has reached the end of the file.
+func (s *Scanner) endScan() {
+	err := s.scanErr()
+	var panicErr *CallbackPanicError
+	if errors.As(err, &panicErr) {
+		s.abort(err)
+		return
+	}
+	if err != nil {
+		s.scanSummary.addErr(&ReadError{Err: err})
+		s.state = ScannerStateErrored
+		return
+	}
+	s.scanSummary.EOF = true
+	s.state = ScannerStateEOF
+	s.checkHeaderOnly()
+	s.reconcileTrailer()
+	s.validateEOF()
+}
+
+// discardRemaining reads (and ignores) the remainder of the input, recording
+// how much content was ignored in the summary.
+func (s *Scanner) discardRemaining() {
+	for {
+		token, terminator, more := s.nextToken()
+		if !more {
+			break
+		}
+		s.scanSummary.IgnoredBytes += int64(len(token))
+		if len(token) > 0 && !s.isEmptyToken(token, terminator) {
+			s.scanSummary.IgnoredRecords++
+		}
+	}
+	s.endScan()
+}
+
+// appendAlteration records an alteration to the current record, and returns
+// it. If retaining the alteration would exceed the sampling limit for its kind
+// (see WithAlterationSampling) or the memory budget (see WithMemoryBudget), it
+// is counted, but is not added to the summary's Alterations.
+func (s *Scanner) appendAlteration(originalText string, record, alternateRecord []string, kind AlterationKind) *Alteration {
+	alteration := &Alteration{
 		RecordOrdinal:         s.scanSummary.RecordCount,
+		ByteOffset:            s.recordOffset,
 		OriginalData:          originalText,
 		ResultingRecord:       record,
-		AlterationDescription: description,
-	})
+		AlternateRecord:       alternateRecord,
+		Kind:                  kind,
+		AlterationDescription: kind.String(),
+	}
+	s.currentAlteration = kind
+	s.scanSummary.AlterationCount++
+	s.scanSummary.AlterationKindCounts[kind]++
+	s.traceAlteration(alteration)
+	if s.opts.alterationGrouping {
+		s.scanSummary.groupAlteration(alteration, s.currentParsedFieldCount, len(record))
+	}
+	if !s.sampleAlteration(kind) {
+		s.scanSummary.DroppedAlterationCount++
+		return alteration
+	}
+	if s.currentOriginalFields != nil {
+		alteration.FieldChanges = diffFields(s.currentOriginalFields, record)
+	}
+	if !s.reserveAlterationMemory(alteration) {
+		s.scanSummary.DroppedAlterationCount++
+		return alteration
+	}
+	s.retainedAlterations[kind]++
+	s.scanSummary.Alterations = append(s.scanSummary.Alterations, alteration)
+	return alteration
 }
 
 // Reset sets the Scanner and clears any summary data that any previous calls to
-// Scan may have generated. Note that since Scanner is based on a Reader, it
-// is necessary for the consumer to verify the position in the byte stream
-// from which the Scanner will read.
+// Scan may have generated. Any Options that were supplied to NewScanner are
+// retained. Note that since Scanner is based on a Reader, it is necessary for
+// the consumer to verify the position in the byte stream from which the
+// Scanner will read.
 func (s *Scanner) Reset() {
-	s = NewScanner(s.reader, s.headerCheck)
+	s.endSpan()
+	readBuffer := s.readBuffer
+	*s = *newScanner(s.reader, s.headerCheck, s.opts)
+	s.useReadBuffer(readBuffer)
 }
 
 // CurrentRecord returns the most recent record generated by a call to Scan.
+// Each call to Scan produces a new slice, so records can be retained (for
+// instance, by appending them to a [][]string) without being modified by
+// subsequent calls to Scan. However, the record must not be modified unless
+// WithSafeRecords is supplied, in which case CurrentRecord returns a copy. See
+// also CopyCurrentRecord.
+//
+// CurrentRecord returns nil before the first call to Scan. Once Scan has
+// returned false, it returns the last record that Scan returned, or an empty
+// slice (see Done).
 func (s *Scanner) CurrentRecord() []string {
+	if s.opts.safeRecords {
+		return s.CopyCurrentRecord()
+	}
 	return s.currentRecord
 }
 
+// CopyCurrentRecord returns a copy of the current record, which the caller
+// owns, and may modify. CopyCurrentRecord returns nil if there is no current
+// record.
+func (s *Scanner) CopyCurrentRecord() []string {
+	if s.currentRecord == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(s.currentRecord)), s.currentRecord...)
+}
+
 // Alteration describes a change that the Scanner made to a record because the
-// record was in an unexpected format.
+// record was in an unexpected format. ByteOffset is the position in the input
+// at which the altered record begins. Kind identifies the type of alteration,
+// and AlterationDescription is its description (Kind.String()).
+//
+// For records that were repaired by a RepairStrategy (such as
+// AlterationMergedFields), AlternateRecord is the record as it would have been
+// had it simply been padded or truncated.
+//
+// BestEffort is true if a record that could not be parsed due to quote
+// ambiguities was parsed using lazy quotes instead (see WithLazyQuoteRetry).
+// In that case, ResultingRecord is the lazily parsed record, and
+// AlternateRecord is the record of blank fields that would otherwise have been
+// returned.
+//
+// Otherwise, AlternateRecord is nil.
+//
+// Severity is SeverityInfo for records that were padded or truncated within
+// the tolerance set by WithFieldCountTolerance, and SeverityError otherwise.
+//
+// FieldChanges lists each field that differs between the record as it
+// appeared in the input and ResultingRecord, so that tooling can highlight
+// exactly which cells were changed. The original record is parsed with the
+// delimiter that was used, and with lazy quotes if it could not otherwise be
+// parsed. FieldChanges is nil if the record was redacted (see WithRedaction),
+// since the original values of redacted fields are not retained.
 type Alteration struct {
 	RecordOrdinal         int
+	ByteOffset            int64
 	OriginalData          string
 	ResultingRecord       []string
+	AlternateRecord       []string
+	Kind                  AlterationKind
 	AlterationDescription string
+	BestEffort            bool
+	Severity              Severity
+	FieldChanges          []FieldChange
 }
 
 // ScanSummary contains information about assumptions or alterations that have
 // been made via any calls to Scan.
+//
+// FieldCountRuns describes the number of fields that were observed in each
+// record (prior to any padding or truncation), as a series of runs of
+// consecutive records that share the same field count. Records whose fields
+// could not be parsed due to quote ambiguities are not observed, and are
+// therefore included in whichever run surrounds them.
+//
+// EmptyRecordCount is the number of empty records that were encountered,
+// whether they were skipped or emitted (see WithKeepEmptyRecords). Empty
+// records that are emitted are also included in RecordCount.
+//
+// StoppedAtBlankRun is true if scanning stopped early because a run of empty
+// records was encountered (see WithStopAtBlankRun). In that case, IgnoredBytes
+// and IgnoredRecords describe the content from the start of the blank run to
+// the end of the file that was discarded.
+//
+// LimitReached is true if scanning stopped before the end of the input because
+// a limit set by WithMaxRecords or WithMaxBytes was reached, and
+// DeadlineExceeded is true if scanning stopped because the deadline set by
+// WithDeadline or WithTimeout passed. In either case, EOF is false, and
+// ResumeOffset is the byte offset (relative to where the Scanner started
+// reading) of the first record that was not returned, from which scanning can
+// be resumed.
+//
+// Err accumulates every error that stopped (or followed the end of) scanning,
+// joined by errors.Join if there is more than one. Reader errors are wrapped in
+// a ReadError, limits and deadlines are reported as ErrLimitReached and
+// ErrDeadlineExceeded, and failed callbacks as a CallbackPanicError or
+// EOFValidationError. Use ReadFailed, Limited, and CallbackFailed (or
+// errors.Is with ErrReadFailed, ErrScanLimited, and ErrCallbackFailed) to test
+// for each.
+//
+// TerminatorCounts is the number of records returned by Scan with each
+// terminator. Records that are not terminated (such as the last record of most
+// files) are counted under an empty Terminator. InvalidUTF8Count is the number
+// of records that contained invalid UTF-8, which usually indicates that the
+// file uses a different encoding.
+//
+// AlterationKindCounts is the number of alterations of each kind, including
+// any that were not retained. DroppedAlterationCount is the number of
+// alterations that are included in AlterationCount, but were not retained in
+// Alterations, because the sampling limit for their kind was reached (see
+// WithAlterationSampling), or because the memory budget was exhausted (see
+// WithMemoryBudget).
+//
+// InfoAlterationCount is the number of alterations (including any that were
+// not retained) whose Severity is SeverityInfo, and DroppedRecordCount is the
+// number of records that were dropped (see WithFieldCountTolerance). Dropped
+// records are included in RecordCount, so that record ordinals continue to
+// reflect the input, but are not returned by Scan.
+//
+// RepeatedHeaderCount is the number of records that were skipped because they
+// repeated the header (see WithSkipRepeatedHeaders). Like dropped records,
+// they are included in RecordCount.
+//
+// UnsampledRecordCount is the number of records that were not returned by Scan
+// because they were not drawn in the sample (see WithSampleRate). They are
+// also included in RecordCount.
+//
+// ForceClosedQuoteCount is the number of records that contained a quoted field
+// that was closed because it exceeded the limit set by
+// WithMaxQuotedFieldBytes.
+//
+// Trailer reports whether the number of records matched the count declared
+// by the file's trailer. It is nil unless WithTrailerReconciliation is
+// supplied, and the end of the input was reached.
+//
+// Deviations lists the ways in which the input deviates from RFC 4180, in the
+// order in which they were encountered. It is nil unless WithStrictRFC4180 is
+// supplied.
+//
+// DuplicateKeys lists the records whose key duplicated that of an earlier
+// record (see WithUniqueColumns). It is nil unless the columns supplied to
+// WithUniqueColumns were matched to the header.
+//
+// RedactionCounts is the number of fields redacted in each of the columns
+// supplied to WithRedaction. It is nil if no columns were supplied, or if no
+// records have been scanned.
+//
+// HeaderMismatch describes how the header differs from the column names
+// supplied to WithFieldsPerRecordFromHeaderNames. It is nil if the header
+// matches, if there is no header, or if no names were supplied.
+//
+// HeaderOnly is true if the end of the input was reached, and the input
+// contained a header (see RecordIsHeader), but no other records (see
+// WithSkipLoneHeader). Empty records are ignored.
+//
+// Warnings lists assumptions that the Scanner made which may not reflect the
+// author's intent, such as identifying a header that is wider or narrower
+// than the data, in the order in which they were made (see Warning). It is nil
+// if there are no warnings.
+//
+// AlterationGroups lists every alteration, grouped into ranges of consecutive
+// alterations that share the same kind and shape (see
+// WithDedupedAlterationGrouping). It is nil unless that option is supplied.
 type ScanSummary struct {
-	RecordCount     int
-	AlterationCount int
-	Alterations     []*Alteration
-	EOF             bool
-	Err             error
-}
-
-// String returns a prettified representation of the summary.
-func (s *ScanSummary) String() string {
-	const templateText = `Scan Summary
----------------------------------------
-  Records Scanned:    {{.RecordCount}}
-  Alterations Made:   {{.AlterationCount}}
-  EOF:                {{.EOF}}
-  Err:                {{if .Err}}{{.Err}}{{else}}none{{end}}
-  Alterations:{{range .Alterations}}
-    Record Number:    {{.RecordOrdinal}}
-    Alteration:       {{.AlterationDescription}}
-    Original Data:    {{.OriginalData}}
-    Resulting Record: {{json .ResultingRecord}}
-{{else}}        none{{end}}`
-
-	var recordToJSON = func(s []string) string {
-		record, err := json.Marshal(s)
-		util.Panic(err)
-		return string(record)
-	}
-	funcMap := template.FuncMap{"json": recordToJSON}
-	tmpl := template.Must(template.
-		New("summary").
-		Funcs(funcMap).
-		Parse(templateText))
-	buf := new(bytes.Buffer)
-	util.Panic(tmpl.Execute(buf, s))
-	result, err := ioutil.ReadAll(buf)
-	util.Panic(err)
-	return string(result)
+	RecordCount       int
+	AlterationCount   int
+	Alterations       []*Alteration
+	FieldCountRuns    []*FieldCountRun
+	EmptyRecordCount  int
+	EOF               bool
+	Err               error
+	StoppedAtBlankRun bool
+	IgnoredBytes      int64
+	IgnoredRecords    int
+	LimitReached      bool
+	DeadlineExceeded  bool
+	ResumeOffset      int64
+	TerminatorCounts  map[Terminator]int
+	InvalidUTF8Count  int
+	HeaderMismatch    *HeaderMismatch
+	HeaderOnly        bool
+
+	DroppedAlterationCount int
+	InfoAlterationCount    int
+	DroppedRecordCount     int
+	ForceClosedQuoteCount  int
+	RepeatedHeaderCount    int
+	UnsampledRecordCount   int
+	Trailer                *TrailerReconciliation
+	DuplicateKeys          []DuplicateKey
+	Deviations             []*Deviation
+	Warnings               []*Warning
+	AlterationKindCounts   map[AlterationKind]int
+	RedactionCounts        map[string]int
+	AlterationGroups       []*AlterationGroup
+
+	// mu guards the summary against concurrent calls to Snapshot while the
+	// Scanner updates it. It is nil if the summary was not produced by a
+	// Scanner.
+	mu *sync.Mutex
+}
+
+// FieldCountRun describes a range of consecutive records that all contained
+// FieldCount fields. A file with a consistent structure will have a single
+// run. Multiple runs usually indicate that the file's schema changed part way
+// through (or that a subset of records are malformed).
+type FieldCountRun struct {
+	FirstRecordOrdinal int
+	LastRecordOrdinal  int
+	FieldCount         int
+}
+
+func (s *ScanSummary) observeFieldCount(fieldCount int) {
+	n := len(s.FieldCountRuns)
+	if n > 0 && s.FieldCountRuns[n-1].FieldCount == fieldCount {
+		s.FieldCountRuns[n-1].LastRecordOrdinal = s.RecordCount
+		return
+	}
+	s.FieldCountRuns = append(s.FieldCountRuns, &FieldCountRun{
+		FirstRecordOrdinal: s.RecordCount,
+		LastRecordOrdinal:  s.RecordCount,
+		FieldCount:         fieldCount,
+	})
+}
+
+// FieldCountChangePoints returns the ordinals of the records at which the
+// observed field count changed from that of the preceding records. The result
+// is empty if every record had the same number of fields.
+func (s *ScanSummary) FieldCountChangePoints() []int {
+	points := []int{}
+	for i := 1; i < len(s.FieldCountRuns); i++ {
+		points = append(points, s.FieldCountRuns[i].FirstRecordOrdinal)
+	}
+	return points
 }
 
 // Summary returns a summary of information about the assumptions or alterations
 // that were made during the most recent Scan. If the Scan method has not been
 // called, or Reset was called after the last call to Scan, Summary will return
 // nil. Summary will continue to collect data each time Scan is called, and will
-// only reset after the Reset method has been called.
+// only reset after the Reset method has been called. Since the summary is
+// updated by Scan, use its Snapshot method to read it from another goroutine
+// while scanning continues.
 func (s *Scanner) Summary() *ScanSummary {
 	return s.scanSummary
 }
@@ -363,15 +1451,39 @@ func (s *Scanner) Summary() *ScanSummary {
 // calling the HeaderCheck callback which was supplied to NewScanner when the
 // Scanner was instantiated.
 func (s *Scanner) RecordIsHeader() bool {
-	return s.headerCheck(s.firstRecord)
+	defer s.holdSummary()()
+	var secondRecord []string
+	if s.firstRecord != nil {
+		secondRecord = s.peekRecord()
+	}
+	isHeader := s.callHeaderCheck(s.firstRecord, secondRecord)
+	if s.firstRecord != nil && !s.headerResolved {
+		if isHeader {
+			s.header = s.currentRecord
+			if secondRecord != nil {
+				s.checkHeaderWidth(s.firstRecord, len(secondRecord))
+			}
+		}
+		s.headerResolved = true
+		s.decodePlans = nil
+	}
+	return isHeader
 }
 
 // Segment represents a byte range within a file that contains a subset of
-// records.
+// records. Checksum is the CRC-32 (Castagnoli) checksum of the bytes in the
+// range, which allows a worker to verify that it has fetched the same bytes
+// that were partitioned (see Verify). SkippedEmptyRecords is the number of
+// empty records within the range that Scan skips (see WithKeepEmptyRecords),
+// which includes any leading or dangling terminators. RecordCount is the
+// number of records within the range that Scan returns (see Validate).
 type Segment struct {
-	Ordinal     int64
-	LowerOffset int64
-	Length      int64
+	Ordinal             int64
+	LowerOffset         int64
+	Length              int64
+	Checksum            uint32
+	SkippedEmptyRecords int64
+	RecordCount         int64
 }
 
 // Partition reads the full file and divides it into a series of partitions,
@@ -385,9 +1497,22 @@ type Segment struct {
 // lengh, which is the partition size in bytes. If the file being read is empty
 // (0 bytes), Partition will return an empty slice of segments.
 //
+// Segments are contiguous, and Ordinals are numbered from 1 without gaps.
+// Bytes that Scan skips (such as empty records, and records dropped by
+// WithFieldCountTolerance) belong to the segment of the record that precedes
+// them, or to the first segment if they precede the first record. Thus, bytes
+// that follow the last record (such as dangling terminators) belong to the
+// last segment, and dangling terminators only change the length (and
+// checksum) of that segment. If no records precede such bytes (for instance,
+// if the input contains only empty records, or a header followed by empty
+// records), they are not included in any segment. WithEmptySegments instead
+// places the bytes that follow the last record in a final segment with a
+// RecordCount of zero.
+//
 // If excludeHeader is true, Partition will check if a header exists. If a
 // header is detected, the first Segment will ignore the header, and the
 // LowerOffset value will be the first byte position after the header record.
+// The header's byte range is then available via HeaderSegment.
 //
 // If excludeHeader is false, the LowerOffset of the first segment will always
 // be 0 (regardless of whether the first record is a header or not).
@@ -396,25 +1521,86 @@ type Segment struct {
 // such as os.File.Seek or bufio.ReadSeeker.Discard in situations where files
 // need to be accessed in a concurrent manner.
 //
-// Before processing, Partition explicitly resets the underlaying reader to the
-// top of the file. Thus, using Partition in conjunction with Scan could have
-// undesired results.
-func (s *Scanner) Partition(n int, excludeHeader bool) []*Segment {
+// If the underlaying reader implements io.Seeker, Partition seeks to the top of
+// the file before processing, and once finished, seeks back to the top of the
+// file and resets the Scanner. Thus, the Scanner can be used to Scan the file
+// from the beginning after calling Partition. Otherwise, Partition consumes
+// the remainder of the reader, and subsequent calls to Scan will return false.
+//
+// If n is zero, each segment contains a single record. If n is negative,
+// Partition returns ErrInvalidPartitionSize without reading the input. If the
+// initial seek fails, Partition returns an empty slice of segments, and the
+// error (a ReadError), which is also reported via the summary. Otherwise, if
+// scanning stops before the end of the input (for instance, because the
+// reader returned an error, or the reader is nil), Partition returns the
+// segments of the input that was scanned, along with the summary's Err.
+func (s *Scanner) Partition(n int, excludeHeader bool) ([]*Segment, error) {
 	var (
 		ordinal     int64
 		lowerOffset int64
 	)
+	if n < 0 {
+		return []*Segment{}, fmt.Errorf("%w: %d records per partition", ErrInvalidPartitionSize, n)
+	}
+	if n == 0 {
+		n = 1
+	}
+	seeker, canSeek := s.reader.(io.Seeker)
+	if canSeek {
+		_, err := seeker.Seek(0, io.SeekStart)
+		if err != nil {
+			s.Reset()
+			s.scanSummary = s.newScanSummary()
+			readErr := &ReadError{Err: err}
+			s.scanSummary.addErr(readErr)
+			s.state = ScannerStateErrored
+			return []*Segment{}, readErr
+		}
+	}
 	s.Reset()
+	s.segmentHash = crc32.New(segmentChecksumTable)
+	s.trailingHash = crc32.New(segmentChecksumTable)
+	defer func() {
+		s.segmentHash = nil
+		s.trailingHash = nil
+		if canSeek {
+			_, err := seeker.Seek(0, io.SeekStart)
+			if err == nil {
+				headerSegment := s.headerSegment
+				s.Reset()
+				s.headerSegment = headerSegment
+			}
+		}
+	}()
 	segments := []*Segment{}
 	headerEvaluated := false
-	currentRawRecord := ""
+	currentLength := int64(0)
 	recordsInCurrentSegment := 0
+
+	// The unclaimed bytes and empty records that precede the most recent
+	// record, and the checksum of the current segment up to the end of that
+	// record, allow any bytes that follow the last record to be split into a
+	// segment of their own (see WithEmptySegments).
+	unclaimedBeforeRecord := int64(0)
+	emptyRecordsBeforeRecord := int64(0)
+	checksumThroughRecord := uint32(0)
 	for s.Scan() {
 		if !headerEvaluated {
 			headerEvaluated = true
 			if excludeHeader && s.RecordIsHeader() {
-				lowerOffset = int64(len(s.scanner.Text())) + s.bytesUnclaimed
-				s.bytesUnclaimed = 0
+				lowerOffset = s.rawRecordLength + s.bytesUnclaimed
+				s.segmentHash.Write([]byte(s.currentRawFields))
+				s.segmentHash.Write(s.currentTerminator)
+				s.headerSegment = &Segment{
+					Ordinal:             0,
+					LowerOffset:         0,
+					Length:              lowerOffset,
+					Checksum:            s.segmentHash.Sum32(),
+					SkippedEmptyRecords: s.emptyRecordsUnclaimed,
+					RecordCount:         1,
+				}
+				s.claim()
+				s.trailingHash.Reset()
 				continue
 			}
 			lowerOffset = 0
@@ -423,29 +1609,75 @@ func (s *Scanner) Partition(n int, excludeHeader bool) []*Segment {
 		if recordsInCurrentSegment == n {
 			ordinal++
 			segments = append(segments, &Segment{
-				Ordinal:     ordinal,
-				LowerOffset: lowerOffset,
-				Length:      int64(len(currentRawRecord)) + s.bytesUnclaimed,
+				Ordinal:             ordinal,
+				LowerOffset:         lowerOffset,
+				Length:              currentLength + s.bytesUnclaimed,
+				Checksum:            s.segmentHash.Sum32(),
+				SkippedEmptyRecords: s.emptyRecordsUnclaimed,
+				RecordCount:         int64(recordsInCurrentSegment),
 			})
-			lowerOffset += int64(len(currentRawRecord)) + s.bytesUnclaimed
+			lowerOffset += currentLength + s.bytesUnclaimed
 			recordsInCurrentSegment = 0
-			s.bytesUnclaimed = 0
-			currentRawRecord = ""
+			s.claim()
+			currentLength = 0
 		}
-		currentRawRecord += s.scanner.Text()
+		currentLength += s.rawRecordLength
 		recordsInCurrentSegment++
+		s.segmentHash.Write([]byte(s.currentRawFields))
+		s.segmentHash.Write(s.currentTerminator)
+		s.trailingHash.Reset()
+		unclaimedBeforeRecord = s.bytesUnclaimed
+		emptyRecordsBeforeRecord = s.emptyRecordsUnclaimed
+		checksumThroughRecord = s.segmentHash.Sum32()
 	}
 
+	if excludeHeader && !headerEvaluated && s.scanSummary.HeaderOnly {
+		// the header was skipped (see WithSkipLoneHeader).
+		s.headerSegment = &Segment{
+			Ordinal:             0,
+			LowerOffset:         0,
+			Length:              s.bytesUnclaimed,
+			Checksum:            s.segmentHash.Sum32(),
+			SkippedEmptyRecords: s.emptyRecordsUnclaimed,
+			RecordCount:         1,
+		}
+		s.claim()
+		s.trailingHash.Reset()
+	}
+	trailingBytes := s.bytesUnclaimed - unclaimedBeforeRecord
+	trailingEmptyRecords := s.emptyRecordsUnclaimed - emptyRecordsBeforeRecord
 	if recordsInCurrentSegment > 0 {
+		segment := &Segment{
+			LowerOffset:         lowerOffset,
+			Length:              currentLength + s.bytesUnclaimed,
+			Checksum:            s.segmentHash.Sum32(),
+			SkippedEmptyRecords: s.emptyRecordsUnclaimed,
+			RecordCount:         int64(recordsInCurrentSegment),
+		}
+		if s.opts.emptySegments && trailingBytes > 0 {
+			segment.Length -= trailingBytes
+			segment.Checksum = checksumThroughRecord
+			segment.SkippedEmptyRecords -= trailingEmptyRecords
+		}
 		ordinal++
-		segments = append(segments,
-			&Segment{
-				Ordinal:     ordinal,
-				LowerOffset: lowerOffset,
-				Length:      int64(len(currentRawRecord)) + s.bytesUnclaimed,
-			})
-		s.bytesUnclaimed = 0
+		segment.Ordinal = ordinal
+		segments = append(segments, segment)
+		lowerOffset += segment.Length
+	}
+	if s.opts.emptySegments && trailingBytes > 0 {
+		ordinal++
+		segments = append(segments, &Segment{
+			Ordinal:             ordinal,
+			LowerOffset:         lowerOffset,
+			Length:              trailingBytes,
+			Checksum:            s.trailingHash.Sum32(),
+			SkippedEmptyRecords: trailingEmptyRecords,
+		})
 	}
+	s.claim()
 
-	return segments
+	if s.state != ScannerStateEOF {
+		return segments, s.scanSummary.Err
+	}
+	return segments, nil
 }
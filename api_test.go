@@ -2,7 +2,9 @@ package permissivecsv_test
 
 import (
 	"errors"
+	"hash/crc32"
 	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
 
@@ -260,11 +262,14 @@ func Test_Summary(t *testing.T) {
 			data:      nil,
 			scanLimit: -1,
 			expSummary: &permissivecsv.ScanSummary{
-				RecordCount:     -1,
-				AlterationCount: -1,
-				EOF:             false,
-				Err:             permissivecsv.ErrReaderIsNil,
-				Alterations:     []*permissivecsv.Alteration{},
+				TerminatorCounts:     map[permissivecsv.Terminator]int{},
+				AlterationKindCounts: map[permissivecsv.AlterationKind]int{},
+				RecordCount:          -1,
+				AlterationCount:      -1,
+				FieldCountRuns:       []*permissivecsv.FieldCountRun{},
+				EOF:                  false,
+				Err:                  permissivecsv.ErrReaderIsNil,
+				Alterations:          []*permissivecsv.Alteration{},
 			},
 		},
 		{
@@ -272,16 +277,23 @@ func Test_Summary(t *testing.T) {
 			data:      strings.NewReader("\""),
 			scanLimit: -1,
 			expSummary: &permissivecsv.ScanSummary{
-				RecordCount:     1,
-				AlterationCount: 1,
-				EOF:             true,
-				Err:             nil,
+				TerminatorCounts:     map[permissivecsv.Terminator]int{"": 1},
+				AlterationKindCounts: map[permissivecsv.AlterationKind]int{permissivecsv.AlterationExtraneousQuote: 1},
+				RecordCount:          1,
+				AlterationCount:      1,
+				FieldCountRuns:       []*permissivecsv.FieldCountRun{},
+				EOF:                  true,
+				Err:                  nil,
 				Alterations: []*permissivecsv.Alteration{
 					&permissivecsv.Alteration{
 						RecordOrdinal:         1,
 						OriginalData:          "\"",
 						ResultingRecord:       []string{},
+						Kind:                  permissivecsv.AlterationExtraneousQuote,
 						AlterationDescription: permissivecsv.AltExtraneousQuote,
+						FieldChanges: []permissivecsv.FieldChange{
+							{Index: -1, OriginalIndex: 0},
+						},
 					},
 				},
 			},
@@ -291,16 +303,26 @@ func Test_Summary(t *testing.T) {
 			data:      strings.NewReader("a\nb\""),
 			scanLimit: -1,
 			expSummary: &permissivecsv.ScanSummary{
-				RecordCount:     2,
-				AlterationCount: 1,
-				EOF:             true,
-				Err:             nil,
+				TerminatorCounts:     map[permissivecsv.Terminator]int{"\n": 1, "": 1},
+				AlterationKindCounts: map[permissivecsv.AlterationKind]int{permissivecsv.AlterationBareQuote: 1},
+				RecordCount:          2,
+				AlterationCount:      1,
+				FieldCountRuns: []*permissivecsv.FieldCountRun{
+					&permissivecsv.FieldCountRun{FirstRecordOrdinal: 1, LastRecordOrdinal: 1, FieldCount: 1},
+				},
+				EOF: true,
+				Err: nil,
 				Alterations: []*permissivecsv.Alteration{
 					&permissivecsv.Alteration{
 						RecordOrdinal:         2,
+						ByteOffset:            2,
 						OriginalData:          "b\"",
 						ResultingRecord:       []string{""},
+						Kind:                  permissivecsv.AlterationBareQuote,
 						AlterationDescription: permissivecsv.AltBareQuote,
+						FieldChanges: []permissivecsv.FieldChange{
+							{Index: 0, OriginalIndex: 0, Original: "b\""},
+						},
 					},
 				},
 			},
@@ -310,16 +332,27 @@ func Test_Summary(t *testing.T) {
 			data:      strings.NewReader("a,b,c\nd,e,f,g"),
 			scanLimit: -1,
 			expSummary: &permissivecsv.ScanSummary{
-				RecordCount:     2,
-				AlterationCount: 1,
-				EOF:             true,
-				Err:             nil,
+				TerminatorCounts:     map[permissivecsv.Terminator]int{"\n": 1, "": 1},
+				AlterationKindCounts: map[permissivecsv.AlterationKind]int{permissivecsv.AlterationTruncatedRecord: 1},
+				RecordCount:          2,
+				AlterationCount:      1,
+				FieldCountRuns: []*permissivecsv.FieldCountRun{
+					&permissivecsv.FieldCountRun{FirstRecordOrdinal: 1, LastRecordOrdinal: 1, FieldCount: 3},
+					&permissivecsv.FieldCountRun{FirstRecordOrdinal: 2, LastRecordOrdinal: 2, FieldCount: 4},
+				},
+				EOF: true,
+				Err: nil,
 				Alterations: []*permissivecsv.Alteration{
 					&permissivecsv.Alteration{
 						RecordOrdinal:         2,
+						ByteOffset:            6,
 						OriginalData:          "d,e,f,g",
 						ResultingRecord:       []string{"d", "e", "f"},
+						Kind:                  permissivecsv.AlterationTruncatedRecord,
 						AlterationDescription: permissivecsv.AltTruncatedRecord,
+						FieldChanges: []permissivecsv.FieldChange{
+							{Index: -1, OriginalIndex: 3, Original: "g"},
+						},
 					},
 				},
 			},
@@ -329,16 +362,27 @@ func Test_Summary(t *testing.T) {
 			data:      strings.NewReader("a,b,c\nd,e"),
 			scanLimit: -1,
 			expSummary: &permissivecsv.ScanSummary{
-				RecordCount:     2,
-				AlterationCount: 1,
-				EOF:             true,
-				Err:             nil,
+				TerminatorCounts:     map[permissivecsv.Terminator]int{"\n": 1, "": 1},
+				AlterationKindCounts: map[permissivecsv.AlterationKind]int{permissivecsv.AlterationPaddedRecord: 1},
+				RecordCount:          2,
+				AlterationCount:      1,
+				FieldCountRuns: []*permissivecsv.FieldCountRun{
+					&permissivecsv.FieldCountRun{FirstRecordOrdinal: 1, LastRecordOrdinal: 1, FieldCount: 3},
+					&permissivecsv.FieldCountRun{FirstRecordOrdinal: 2, LastRecordOrdinal: 2, FieldCount: 2},
+				},
+				EOF: true,
+				Err: nil,
 				Alterations: []*permissivecsv.Alteration{
 					&permissivecsv.Alteration{
 						RecordOrdinal:         2,
+						ByteOffset:            6,
 						OriginalData:          "d,e",
 						ResultingRecord:       []string{"d", "e", ""},
+						Kind:                  permissivecsv.AlterationPaddedRecord,
 						AlterationDescription: permissivecsv.AltPaddedRecord,
+						FieldChanges: []permissivecsv.FieldChange{
+							{Index: 2, OriginalIndex: -1},
+						},
 					},
 				},
 			},
@@ -348,11 +392,16 @@ func Test_Summary(t *testing.T) {
 			data:      strings.NewReader("a\n\b\nc"),
 			scanLimit: 1,
 			expSummary: &permissivecsv.ScanSummary{
-				RecordCount:     1,
-				AlterationCount: 0,
-				EOF:             false,
-				Err:             nil,
-				Alterations:     []*permissivecsv.Alteration{},
+				TerminatorCounts:     map[permissivecsv.Terminator]int{"\n": 1},
+				AlterationKindCounts: map[permissivecsv.AlterationKind]int{},
+				RecordCount:          1,
+				AlterationCount:      0,
+				FieldCountRuns: []*permissivecsv.FieldCountRun{
+					&permissivecsv.FieldCountRun{FirstRecordOrdinal: 1, LastRecordOrdinal: 1, FieldCount: 1},
+				},
+				EOF:         false,
+				Err:         nil,
+				Alterations: []*permissivecsv.Alteration{},
 			},
 		},
 	}
@@ -383,6 +432,23 @@ func Test_Summary(t *testing.T) {
 	}
 }
 
+func Test_FieldCountRuns(t *testing.T) {
+	r := strings.NewReader("a,b\nc,d\ne,f,g\nh\"h\"h,i\nj,k,l\nm,n")
+	s := permissivecsv.NewScanner(r, permissivecsv.HeaderCheckAssumeNoHeader)
+	for s.Scan() {
+	}
+	expRuns := []*permissivecsv.FieldCountRun{
+		&permissivecsv.FieldCountRun{FirstRecordOrdinal: 1, LastRecordOrdinal: 2, FieldCount: 2},
+		&permissivecsv.FieldCountRun{FirstRecordOrdinal: 3, LastRecordOrdinal: 5, FieldCount: 3},
+		&permissivecsv.FieldCountRun{FirstRecordOrdinal: 6, LastRecordOrdinal: 6, FieldCount: 2},
+	}
+	diff := deep.Equal(expRuns, s.Summary().FieldCountRuns)
+	if diff != nil {
+		t.Error(diff)
+	}
+	assert.Equal(t, []int{3, 6}, s.Summary().FieldCountChangePoints())
+}
+
 func Test_HeaderCheckCallback(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -414,7 +480,7 @@ func Test_HeaderCheckCallback(t *testing.T) {
 	for _, test := range tests {
 		testFn := func(t *testing.T) {
 			var actualFirstRecord []string
-			headerCheck := func(firstRecord []string) bool {
+			headerCheck := func(firstRecord, secondRecord []string) bool {
 				actualFirstRecord = firstRecord
 				return false
 			}
@@ -443,6 +509,120 @@ func Test_HeaderCheckCallback(t *testing.T) {
 	}
 }
 
+func Test_HeaderCheckSecondRecord(t *testing.T) {
+	tests := []struct {
+		name            string
+		data            string
+		opts            []permissivecsv.Option
+		expSecondRecord []string
+		expRecords      [][]string
+	}{
+		{
+			name:            "second record",
+			data:            "a,b\nc,d,e\nf,g",
+			expSecondRecord: []string{"c", "d", "e"},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+				[]string{"f", "g"},
+			},
+		},
+		{
+			name:            "empty records are skipped",
+			data:            "a,b\n\r\n\nc,d",
+			expSecondRecord: []string{"c", "d"},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+		},
+		{
+			name:            "kept empty records are skipped",
+			data:            "a,b\n\nc,d",
+			opts:            []permissivecsv.Option{permissivecsv.WithKeepEmptyRecords()},
+			expSecondRecord: []string{"c", "d"},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"", ""},
+				[]string{"c", "d"},
+			},
+		},
+		{
+			name:            "single record",
+			data:            "a,b\n\n",
+			expSecondRecord: nil,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+			},
+		},
+		{
+			name:            "ambiguous quote",
+			data:            "a,b\nc\"c\"c,d",
+			expSecondRecord: nil,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"", ""},
+			},
+		},
+		{
+			name:            "blank run",
+			data:            "a,b\n\n\nc,d",
+			opts:            []permissivecsv.Option{permissivecsv.WithStopAtBlankRun(2)},
+			expSecondRecord: nil,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			var actualSecondRecord []string
+			calls := 0
+			headerCheck := func(firstRecord, secondRecord []string) bool {
+				if firstRecord != nil {
+					actualSecondRecord = secondRecord
+					calls++
+				}
+				return false
+			}
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), headerCheck, test.opts...)
+			records := [][]string{}
+			for s.Scan() {
+				s.RecordIsHeader()
+				s.RecordIsHeader()
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, 2, calls)
+			assert.Equal(t, test.expSecondRecord, actualSecondRecord)
+			assert.Equal(t, test.expRecords, records)
+
+			unpeeked := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			for unpeeked.Scan() {
+			}
+			diff := deep.Equal(unpeeked.Summary(), s.Summary())
+			if diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_FirstRecordOnly(t *testing.T) {
+	var actualFirstRecord []string
+	headerCheck := permissivecsv.FirstRecordOnly(func(firstRecord []string) bool {
+		actualFirstRecord = firstRecord
+		return firstRecord != nil
+	})
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\nc,d"), headerCheck)
+	s.Scan()
+	assert.True(t, s.RecordIsHeader())
+	assert.Equal(t, []string{"a", "b"}, actualFirstRecord)
+	s.Scan()
+	assert.False(t, s.RecordIsHeader())
+}
+
 func Test_Partition(t *testing.T) {
 	// The partition tests specifically target segment generation capabilities,
 	// and presume that the underlaying record splitter is properly identifying
@@ -478,16 +658,19 @@ func Test_Partition(t *testing.T) {
 					Ordinal:     1,
 					LowerOffset: 0,
 					Length:      8,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
 					LowerOffset: 8,
 					Length:      8,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     3,
 					LowerOffset: 16,
 					Length:      7,
+					RecordCount: 2,
 				},
 			},
 		},
@@ -501,16 +684,19 @@ func Test_Partition(t *testing.T) {
 					Ordinal:     1,
 					LowerOffset: 0,
 					Length:      10,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
 					LowerOffset: 10,
 					Length:      10,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     3,
 					LowerOffset: 20,
 					Length:      8,
+					RecordCount: 2,
 				},
 			},
 		},
@@ -524,21 +710,25 @@ func Test_Partition(t *testing.T) {
 					Ordinal:     1,
 					LowerOffset: 0,
 					Length:      8,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
 					LowerOffset: 8,
 					Length:      8,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     3,
 					LowerOffset: 16,
 					Length:      8,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     4,
 					LowerOffset: 24,
 					Length:      3,
+					RecordCount: 1,
 				},
 			},
 		},
@@ -552,21 +742,25 @@ func Test_Partition(t *testing.T) {
 					Ordinal:     1,
 					LowerOffset: 0,
 					Length:      10,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
 					LowerOffset: 10,
 					Length:      10,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     3,
 					LowerOffset: 20,
 					Length:      10,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     4,
 					LowerOffset: 30,
 					Length:      3,
+					RecordCount: 1,
 				},
 			},
 		},
@@ -580,21 +774,25 @@ func Test_Partition(t *testing.T) {
 					Ordinal:     1,
 					LowerOffset: 0,
 					Length:      9,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
 					LowerOffset: 9,
 					Length:      8,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     3,
 					LowerOffset: 17,
 					Length:      8,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     4,
 					LowerOffset: 25,
 					Length:      3,
+					RecordCount: 1,
 				},
 			},
 		},
@@ -608,11 +806,13 @@ func Test_Partition(t *testing.T) {
 					Ordinal:     1,
 					LowerOffset: 0,
 					Length:      9,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
 					LowerOffset: 9,
 					Length:      14,
+					RecordCount: 2,
 				},
 			},
 		},
@@ -626,16 +826,19 @@ func Test_Partition(t *testing.T) {
 					Ordinal:     1,
 					LowerOffset: 4,
 					Length:      8,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
 					LowerOffset: 12,
 					Length:      8,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     3,
 					LowerOffset: 20,
 					Length:      7,
+					RecordCount: 2,
 				},
 			},
 		},
@@ -649,16 +852,19 @@ func Test_Partition(t *testing.T) {
 					Ordinal:     1,
 					LowerOffset: 5,
 					Length:      10,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
 					LowerOffset: 15,
 					Length:      10,
+					RecordCount: 2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     3,
 					LowerOffset: 25,
 					Length:      8,
+					RecordCount: 2,
 				},
 			},
 		},
@@ -669,14 +875,17 @@ func Test_Partition(t *testing.T) {
 			excludeHeader:       false,
 			expPartitions: []*permissivecsv.Segment{
 				&permissivecsv.Segment{
-					Ordinal:     1,
-					LowerOffset: 0,
-					Length:      7,
+					Ordinal:             1,
+					LowerOffset:         0,
+					Length:              7,
+					SkippedEmptyRecords: 3,
+					RecordCount:         2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
 					LowerOffset: 7,
 					Length:      3,
+					RecordCount: 2,
 				},
 			},
 		},
@@ -687,9 +896,11 @@ func Test_Partition(t *testing.T) {
 			excludeHeader:       false,
 			expPartitions: []*permissivecsv.Segment{
 				&permissivecsv.Segment{
-					Ordinal:     1,
-					LowerOffset: 0,
-					Length:      6,
+					Ordinal:             1,
+					LowerOffset:         0,
+					Length:              6,
+					SkippedEmptyRecords: 2,
+					RecordCount:         2,
 				},
 			},
 		},
@@ -698,16 +909,61 @@ func Test_Partition(t *testing.T) {
 			data:                strings.NewReader("a\nb\n\n\nc"),
 			recordsPerPartition: 2,
 			excludeHeader:       false,
+			expPartitions: []*permissivecsv.Segment{
+				&permissivecsv.Segment{
+					Ordinal:             1,
+					LowerOffset:         0,
+					Length:              6,
+					SkippedEmptyRecords: 2,
+					RecordCount:         2,
+				},
+				&permissivecsv.Segment{
+					Ordinal:     2,
+					LowerOffset: 6,
+					Length:      1,
+					RecordCount: 1,
+				},
+			},
+		},
+		{
+			name:                "trailing terminators",
+			data:                strings.NewReader("a\r\nb\r\nc\r\n\r\n\r\n"),
+			recordsPerPartition: 2,
+			excludeHeader:       false,
 			expPartitions: []*permissivecsv.Segment{
 				&permissivecsv.Segment{
 					Ordinal:     1,
 					LowerOffset: 0,
 					Length:      6,
+					RecordCount: 2,
+				},
+				&permissivecsv.Segment{
+					Ordinal:             2,
+					LowerOffset:         6,
+					Length:              7,
+					SkippedEmptyRecords: 2,
+					RecordCount:         1,
+				},
+			},
+		},
+		{
+			name:                "empty records are respected",
+			data:                strings.NewReader("h\n\na\n\nb\n\nc"),
+			recordsPerPartition: 2,
+			excludeHeader:       true,
+			expPartitions: []*permissivecsv.Segment{
+				&permissivecsv.Segment{
+					Ordinal:             1,
+					LowerOffset:         2,
+					Length:              7,
+					SkippedEmptyRecords: 3,
+					RecordCount:         2,
 				},
 				&permissivecsv.Segment{
 					Ordinal:     2,
-					LowerOffset: 6,
+					LowerOffset: 9,
 					Length:      1,
+					RecordCount: 1,
 				},
 			},
 		},
@@ -715,7 +971,21 @@ func Test_Partition(t *testing.T) {
 	for _, test := range tests {
 		testFn := func(t *testing.T) {
 			s := permissivecsv.NewScanner(test.data, permissivecsv.HeaderCheckAssumeHeaderExists)
-			partitions := s.Partition(test.recordsPerPartition, test.excludeHeader)
+			partitions, err := s.Partition(test.recordsPerPartition, test.excludeHeader)
+			if test.data == nil {
+				assert.True(t, errors.Is(err, permissivecsv.ErrReaderIsNil))
+			} else {
+				assert.NoError(t, err)
+			}
+			if test.data != nil {
+				// each segment's checksum covers exactly the bytes in its range.
+				test.data.Seek(0, io.SeekStart)
+				data, _ := ioutil.ReadAll(test.data)
+				for _, segment := range test.expPartitions {
+					b := data[segment.LowerOffset : segment.LowerOffset+segment.Length]
+					segment.Checksum = crc32.Checksum(b, crc32.MakeTable(crc32.Castagnoli))
+				}
+			}
 			diff := deep.Equal(test.expPartitions, partitions)
 			if diff != nil {
 				for _, d := range diff {
@@ -727,3 +997,283 @@ func Test_Partition(t *testing.T) {
 		t.Run(test.name, testFn)
 	}
 }
+
+func Test_Reset(t *testing.T) {
+	r := strings.NewReader("a,b\nc\n")
+	s := permissivecsv.NewScanner(r, permissivecsv.HeaderCheckAssumeNoHeader, permissivecsv.WithMaxRecords(1))
+	assert.True(t, s.Scan())
+	assert.False(t, s.Scan())
+	assert.True(t, s.Summary().LimitReached)
+
+	r.Seek(0, io.SeekStart)
+	s.Reset()
+	assert.Nil(t, s.Summary())
+	records := [][]string{}
+	for s.Scan() {
+		records = append(records, s.CurrentRecord())
+	}
+	assert.Equal(t, [][]string{[]string{"a", "b"}}, records, "options are retained")
+	assert.True(t, s.Summary().LimitReached)
+}
+
+func Test_PartitionTrailingBytes(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		excludeHeader bool
+		opts          []permissivecsv.Option
+		expPartitions []*permissivecsv.Segment
+	}{
+		{
+			name: "dangling terminators",
+			data: "a\nb\nc\n\n\n",
+			expPartitions: []*permissivecsv.Segment{
+				{Ordinal: 1, LowerOffset: 0, Length: 4, RecordCount: 2},
+				{Ordinal: 2, LowerOffset: 4, Length: 4, RecordCount: 1, SkippedEmptyRecords: 2},
+			},
+		},
+		{
+			name: "dangling terminators with empty segments",
+			data: "a\nb\nc\n\n\n",
+			opts: []permissivecsv.Option{permissivecsv.WithEmptySegments()},
+			expPartitions: []*permissivecsv.Segment{
+				{Ordinal: 1, LowerOffset: 0, Length: 4, RecordCount: 2},
+				{Ordinal: 2, LowerOffset: 4, Length: 2, RecordCount: 1},
+				{Ordinal: 3, LowerOffset: 6, Length: 2, SkippedEmptyRecords: 2},
+			},
+		},
+		{
+			name: "interior empty records with empty segments",
+			data: "a\n\nb\n\nc\n",
+			opts: []permissivecsv.Option{permissivecsv.WithEmptySegments()},
+			expPartitions: []*permissivecsv.Segment{
+				{Ordinal: 1, LowerOffset: 0, Length: 6, RecordCount: 2, SkippedEmptyRecords: 2},
+				{Ordinal: 2, LowerOffset: 6, Length: 2, RecordCount: 1},
+			},
+		},
+		{
+			name: "no trailing bytes with empty segments",
+			data: "a\nb\nc",
+			opts: []permissivecsv.Option{permissivecsv.WithEmptySegments()},
+			expPartitions: []*permissivecsv.Segment{
+				{Ordinal: 1, LowerOffset: 0, Length: 4, RecordCount: 2},
+				{Ordinal: 2, LowerOffset: 4, Length: 1, RecordCount: 1},
+			},
+		},
+		{
+			name:          "only empty records",
+			data:          "\n\r\n\n",
+			expPartitions: []*permissivecsv.Segment{},
+		},
+		{
+			name: "only empty records with empty segments",
+			data: "\n\r\n\n",
+			opts: []permissivecsv.Option{permissivecsv.WithEmptySegments()},
+			expPartitions: []*permissivecsv.Segment{
+				{Ordinal: 1, LowerOffset: 0, Length: 4, SkippedEmptyRecords: 3},
+			},
+		},
+		{
+			name:          "header and empty records",
+			data:          "h\n\n\n",
+			excludeHeader: true,
+			expPartitions: []*permissivecsv.Segment{},
+		},
+		{
+			name:          "header and empty records with empty segments",
+			data:          "h\n\n\n",
+			excludeHeader: true,
+			opts:          []permissivecsv.Option{permissivecsv.WithEmptySegments()},
+			expPartitions: []*permissivecsv.Segment{
+				{Ordinal: 1, LowerOffset: 2, Length: 2, SkippedEmptyRecords: 2},
+			},
+		},
+		{
+			name: "dropped records",
+			data: "a,b\nc\nd,e\nf\n",
+			opts: []permissivecsv.Option{permissivecsv.WithFieldCountTolerance(0, permissivecsv.ToleranceDrop)},
+			expPartitions: []*permissivecsv.Segment{
+				{Ordinal: 1, LowerOffset: 0, Length: 12, RecordCount: 2},
+			},
+		},
+		{
+			name: "dropped records with empty segments",
+			data: "a,b\nc\nd,e\nf\n",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithFieldCountTolerance(0, permissivecsv.ToleranceDrop),
+				permissivecsv.WithEmptySegments(),
+			},
+			expPartitions: []*permissivecsv.Segment{
+				{Ordinal: 1, LowerOffset: 0, Length: 10, RecordCount: 2},
+				{Ordinal: 2, LowerOffset: 10, Length: 2},
+			},
+		},
+	}
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			for _, segment := range test.expPartitions {
+				b := test.data[segment.LowerOffset : segment.LowerOffset+segment.Length]
+				segment.Checksum = crc32.Checksum([]byte(b), crc32.MakeTable(crc32.Castagnoli))
+			}
+			for run := 0; run < 2; run++ {
+				s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists, test.opts...)
+				partitions, err := s.Partition(2, test.excludeHeader)
+				assert.NoError(t, err)
+				diff := deep.Equal(test.expPartitions, partitions)
+				if diff != nil {
+					t.Errorf("run %d: %v", run, diff)
+				}
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_PartitionSize(t *testing.T) {
+	data := "a\nb\nc"
+	t.Run("zero", func(t *testing.T) {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+		segments, err := s.Partition(0, false)
+		assert.NoError(t, err)
+		if assert.Len(t, segments, 3) {
+			for i, segment := range segments {
+				assert.Equal(t, int64(i+1), segment.Ordinal)
+				assert.Equal(t, int64(i*2), segment.LowerOffset)
+				assert.Equal(t, int64(1), segment.RecordCount)
+			}
+		}
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		r := strings.NewReader(data)
+		s := permissivecsv.NewScanner(r, permissivecsv.HeaderCheckAssumeNoHeader)
+		assert.True(t, s.Scan())
+		segments, err := s.Partition(-1, false)
+		assert.Empty(t, segments)
+		assert.True(t, errors.Is(err, permissivecsv.ErrInvalidPartitionSize))
+		assert.True(t, s.Scan(), "the Scanner is unaffected")
+		assert.Equal(t, []string{"b"}, s.CurrentRecord())
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		s := permissivecsv.NewScanner(BadReader(strings.NewReader(data)), permissivecsv.HeaderCheckAssumeNoHeader)
+		segments, err := s.Partition(2, false)
+		assert.Empty(t, segments)
+		assert.True(t, errors.Is(err, ErrReader))
+	})
+}
+
+func Test_PartitionLeavesScannerReusable(t *testing.T) {
+	data := "h\na\nb\nc"
+	tests := []struct {
+		name       string
+		reader     io.Reader
+		expRecords [][]string
+	}{
+		{
+			name:   "seeker",
+			reader: strings.NewReader(data),
+			expRecords: [][]string{
+				[]string{"h"},
+				[]string{"a"},
+				[]string{"b"},
+				[]string{"c"},
+			},
+		},
+		{
+			name:       "non-seeker",
+			reader:     &nonSeeker{strings.NewReader(data)},
+			expRecords: [][]string{},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(test.reader, permissivecsv.HeaderCheckAssumeHeaderExists)
+			// partitioning after scanning has started begins from the top.
+			if _, ok := test.reader.(io.Seeker); ok {
+				s.Scan()
+				s.Scan()
+			}
+			segments, err := s.Partition(2, true)
+			assert.NoError(t, err)
+			assert.Len(t, segments, 2)
+			assert.NotNil(t, s.HeaderSegment())
+
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, test.expRecords, records)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+// badSeeker returns ErrReader on every call to Seek.
+type badSeeker struct {
+	io.Reader
+}
+
+func (r *badSeeker) Seek(offset int64, whence int) (int64, error) {
+	return 0, ErrReader
+}
+
+func Test_PartitionSeekError(t *testing.T) {
+	s := permissivecsv.NewScanner(&badSeeker{strings.NewReader("a\nb")}, permissivecsv.HeaderCheckAssumeNoHeader)
+	segments, err := s.Partition(1, false)
+	assert.Empty(t, segments)
+	assert.True(t, errors.Is(err, ErrReader), "got %v", err)
+	assert.True(t, errors.Is(err, permissivecsv.ErrReadFailed))
+	assert.True(t, errors.Is(s.Summary().Err, ErrReader), "got %v", s.Summary().Err)
+	assert.True(t, s.Summary().ReadFailed())
+	assert.False(t, s.Scan())
+}
+
+func Test_RetainedRecordsAreNotModified(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		opts []permissivecsv.Option
+	}{
+		{
+			name: "default",
+			data: "a,b\n\nc\nd,e,f\n\"g\"x,h\ni,j",
+		},
+		{
+			name: "empty records and alternate delimiters",
+			data: "a,b\n\nc\nd;e\n\n\ni,j",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithKeepEmptyRecords(),
+				permissivecsv.WithAlternateDelimiters(),
+				permissivecsv.WithNormalizedHeader(),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists, test.opts...)
+			retained := [][]string{}
+			copies := [][]string{}
+			for s.Scan() {
+				retained = append(retained, s.CurrentRecord())
+				copies = append(copies, s.CopyCurrentRecord())
+			}
+			assert.Equal(t, copies, retained)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_CopyCurrentRecord(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\nc"), permissivecsv.HeaderCheckAssumeNoHeader)
+	assert.Nil(t, s.CopyCurrentRecord())
+	s.Scan()
+	s.Scan()
+	record := s.CopyCurrentRecord()
+	assert.Equal(t, []string{"c", ""}, record)
+	record[0] = "modified"
+	assert.Equal(t, []string{"c", ""}, s.CurrentRecord())
+	assert.Equal(t, []string{"c", ""}, s.Summary().Alterations[0].ResultingRecord)
+}
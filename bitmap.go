@@ -0,0 +1,112 @@
+package permissivecsv
+
+import (
+	"strings"
+
+	"github.com/eltorocorp/permissivecsv/internal/util"
+)
+
+// FieldBitmap is a compact set of field indexes, in which bit i%64 of word i/64
+// is set if field i is a member.
+type FieldBitmap []uint64
+
+// IsSet reports whether field i is a member of the set.
+func (b FieldBitmap) IsSet(i int) bool {
+	if i < 0 || i/64 >= len(b) {
+		return false
+	}
+	return b[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Count returns the number of fields in the set.
+func (b FieldBitmap) Count() int {
+	n := 0
+	for _, word := range b {
+		for ; word != 0; word &= word - 1 {
+			n++
+		}
+	}
+	return n
+}
+
+func (b FieldBitmap) set(i int) {
+	b[i/64] |= 1 << uint(i%64)
+}
+
+func newFieldBitmap(n int) FieldBitmap {
+	return make(FieldBitmap, (n+63)/64)
+}
+
+// CurrentQuotedFields returns the set of fields of the current record that
+// were enclosed in double quotes in the input (see CurrentFieldStates). This
+// allows loaders that need to know the original representation of a field to
+// retain it in a compact form. The bitmap is computed on demand, so there is
+// no cost to records for which it is not requested.
+//
+// CurrentQuotedFields returns nil if Scan has not been called, or if there is
+// no current record.
+func (s *Scanner) CurrentQuotedFields() FieldBitmap {
+	states := s.CurrentFieldStates()
+	if states == nil {
+		return nil
+	}
+	bitmap := newFieldBitmap(len(states))
+	for i, state := range states {
+		if state == FieldQuoted {
+			bitmap.set(i)
+		}
+	}
+	return bitmap
+}
+
+// CurrentUnescapedFields returns the set of fields of the current record that
+// contained doubled quotes ("") in the input, which were unescaped to single
+// quotes in CurrentRecord. Like CurrentQuotedFields, the bitmap is computed on
+// demand.
+//
+// CurrentUnescapedFields returns nil if Scan has not been called, or if there
+// is no current record.
+func (s *Scanner) CurrentUnescapedFields() FieldBitmap {
+	if s.currentRecord == nil {
+		return nil
+	}
+	parsed := make([]bool, s.currentParsedFieldCount)
+	markEscapedFields(s.currentRawFields, s.currentDelimiter, parsed)
+	if s.currentMergedField >= 0 {
+		parsed = append(parsed[:s.currentMergedField+1], parsed[s.currentMergedField+2:]...)
+	}
+	bitmap := newFieldBitmap(len(s.currentRecord))
+	for i := range s.currentRecord {
+		if j := i + s.currentFieldShift; j >= 0 && j < len(parsed) && parsed[j] {
+			bitmap.set(i)
+		}
+	}
+	return bitmap
+}
+
+// markEscapedFields sets escaped[i] for each field of raw (separated by
+// delimiter) that contains a doubled quote within quotes.
+func markEscapedFields(raw string, delimiter rune, escaped []bool) {
+	if len(escaped) == 0 {
+		return
+	}
+	delim := string(delimiter)
+	field := 0
+	inQuotes := false
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == util.QuoteChar && inQuotes && i+1 < len(raw) && raw[i+1] == util.QuoteChar:
+			escaped[field] = true
+			i++
+		case c == util.QuoteChar:
+			inQuotes = !inQuotes
+		case !inQuotes && c == delim[0] && strings.HasPrefix(raw[i:], delim):
+			i += len(delim) - 1
+			field++
+			if field >= len(escaped) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CurrentQuotedAndUnescapedFields(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		opts         []permissivecsv.Option
+		expQuoted    [][]int
+		expUnescaped [][]int
+	}{
+		{
+			name: "quoted and escaped fields",
+			data: "a,\"b\",\"c\"\"d\"\n\"\"\"\",\"\",f",
+			expQuoted: [][]int{
+				[]int{1, 2},
+				[]int{0, 1},
+			},
+			expUnescaped: [][]int{
+				[]int{2},
+				[]int{0},
+			},
+		},
+		{
+			name: "padded record",
+			data: "a,b,c\n\"x\"\"\"",
+			expQuoted: [][]int{
+				[]int{},
+				[]int{0},
+			},
+			expUnescaped: [][]int{
+				[]int{},
+				[]int{0},
+			},
+		},
+		{
+			name: "merged fields",
+			data: "name,note\nSmith, Jr,\"say \"\"hi\"\"\"",
+			opts: []permissivecsv.Option{permissivecsv.WithMergeSplitFields()},
+			expQuoted: [][]int{
+				[]int{},
+				[]int{1},
+			},
+			expUnescaped: [][]int{
+				[]int{},
+				[]int{1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			assert.Nil(t, s.CurrentQuotedFields())
+			assert.Nil(t, s.CurrentUnescapedFields())
+			quoted := [][]int{}
+			unescaped := [][]int{}
+			for s.Scan() {
+				n := len(s.CurrentRecord())
+				quoted = append(quoted, bitmapMembers(s.CurrentQuotedFields(), n))
+				unescaped = append(unescaped, bitmapMembers(s.CurrentUnescapedFields(), n))
+			}
+			assert.Equal(t, test.expQuoted, quoted)
+			assert.Equal(t, test.expUnescaped, unescaped)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_FieldBitmap(t *testing.T) {
+	data := strings.Repeat("a,", 69) + "\"b\""
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	s.Scan()
+	bitmap := s.CurrentQuotedFields()
+	assert.Len(t, bitmap, 2)
+	assert.True(t, bitmap.IsSet(69))
+	assert.False(t, bitmap.IsSet(5))
+	assert.False(t, bitmap.IsSet(-1))
+	assert.False(t, bitmap.IsSet(128))
+	assert.Equal(t, 1, bitmap.Count())
+}
+
+func bitmapMembers(bitmap permissivecsv.FieldBitmap, n int) []int {
+	members := []int{}
+	for i := 0; i < n; i++ {
+		if bitmap.IsSet(i) {
+			members = append(members, i)
+		}
+	}
+	return members
+}
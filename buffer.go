@@ -0,0 +1,79 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// RecordTooLongError is reported by the summary's Err (wrapped in a ReadError)
+// if the Scanner stops because a record is longer than it can buffer (see
+// WithAutoGrowBuffer and WithMemoryBudget). Offset is the byte offset at which
+// the record begins, and Limit is the length of the longest record (including
+// its terminator) that could have been read. RecordTooLongError wraps
+// bufio.ErrTooLong, so errors.Is(err, bufio.ErrTooLong) reports true.
+type RecordTooLongError struct {
+	Offset int64
+	Limit  int
+}
+
+func (e *RecordTooLongError) Error() string {
+	return fmt.Sprintf("record at offset %d is longer than %d bytes: %v", e.Offset, e.Limit, bufio.ErrTooLong)
+}
+
+// Unwrap returns bufio.ErrTooLong.
+func (e *RecordTooLongError) Unwrap() error {
+	return bufio.ErrTooLong
+}
+
+// WithAutoGrowBuffer allows the buffer that the Scanner reads records into to
+// grow to limit bytes, rather than to bufio.MaxScanTokenSize (64KiB), so that
+// files with very long records (such as large embedded JSON documents) can be
+// read. The buffer starts small and only grows as long records are
+// encountered. A record that is longer than limit stops the scan with a
+// RecordTooLongError. If a memory budget is also set (see WithMemoryBudget),
+// the buffer is limited to whichever is smaller. A limit less than
+// bufio.MaxScanTokenSize leaves the default in place.
+func WithAutoGrowBuffer(limit int) Option {
+	return func(o *options) {
+		o.bufferLimit = limit
+	}
+}
+
+// newInternalScanner returns a bufio.Scanner that reads tokens from r using
+// the Scanner's splitter, with a buffer limited to the longest record
+// permitted by the options.
+func (s *Scanner) newInternalScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(s.splitter.Split)
+	if s.opts.memoryBudget > 0 || s.opts.bufferLimit > bufio.MaxScanTokenSize {
+		scanner.Buffer(nil, s.opts.maxRecordSize())
+	}
+	return scanner
+}
+
+// readToken advances the internal scanner to the next token, keeping count of
+// the bytes read so that an overly long record can be located. Once the
+// internal scanner has failed, it is not advanced again, since a bufio.Scanner
+// that stopped with bufio.ErrTooLong would otherwise return the truncated
+// contents of its buffer as a final token.
+func (s *Scanner) readToken() bool {
+	defer s.releaseSummary()()
+	if s.scanner.Err() != nil || !s.scanner.Scan() {
+		return false
+	}
+	s.bytesRead += int64(len(s.scanner.Bytes()))
+	return true
+}
+
+// scanErr returns the error (if any) that stopped the internal scanner.
+func (s *Scanner) scanErr() error {
+	err := s.scanner.Err()
+	if err == bufio.ErrTooLong {
+		return &RecordTooLongError{
+			Offset: s.bytesRead,
+			Limit:  s.opts.maxRecordSize(),
+		}
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package permissivecsv_test
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RecordTooLong(t *testing.T) {
+	long := strings.Repeat("x", bufio.MaxScanTokenSize)
+	tests := []struct {
+		name           string
+		data           string
+		opts           []permissivecsv.Option
+		expRecordCount int
+		expErr         *permissivecsv.RecordTooLongError
+	}{
+		{
+			name:           "default limit",
+			data:           "a,b\r\nc,d\n" + long + "\ne,f",
+			expRecordCount: 2,
+			expErr: &permissivecsv.RecordTooLongError{
+				Offset: 9,
+				Limit:  bufio.MaxScanTokenSize,
+			},
+		},
+		{
+			name:           "auto grow",
+			data:           "a,b\r\nc,d\n" + long + "\ne,f",
+			opts:           []permissivecsv.Option{permissivecsv.WithAutoGrowBuffer(4 * bufio.MaxScanTokenSize)},
+			expRecordCount: 4,
+		},
+		{
+			name:           "record exceeds grown buffer",
+			data:           "a,b\n" + strings.Repeat(long, 2) + "\ne,f",
+			opts:           []permissivecsv.Option{permissivecsv.WithAutoGrowBuffer(bufio.MaxScanTokenSize + 100)},
+			expRecordCount: 1,
+			expErr: &permissivecsv.RecordTooLongError{
+				Offset: 4,
+				Limit:  bufio.MaxScanTokenSize + 100,
+			},
+		},
+		{
+			name:           "limit below default",
+			data:           "a,b\n" + strings.Repeat("x", 1000),
+			opts:           []permissivecsv.Option{permissivecsv.WithAutoGrowBuffer(10)},
+			expRecordCount: 2,
+		},
+		{
+			name: "memory budget is smaller",
+			data: "a,b\n" + strings.Repeat("x", 1000),
+			opts: []permissivecsv.Option{
+				permissivecsv.WithAutoGrowBuffer(4 * bufio.MaxScanTokenSize),
+				permissivecsv.WithMemoryBudget(1000),
+			},
+			expRecordCount: 1,
+			expErr: &permissivecsv.RecordTooLongError{
+				Offset: 4,
+				Limit:  500,
+			},
+		},
+		{
+			name:           "record read ahead to identify the header",
+			data:           "a,b\n" + long + "\ne,f",
+			opts:           []permissivecsv.Option{permissivecsv.WithFieldsPerRecordFromHeaderNames("a", "b")},
+			expRecordCount: 1,
+			expErr: &permissivecsv.RecordTooLongError{
+				Offset: 4,
+				Limit:  bufio.MaxScanTokenSize,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists, test.opts...)
+			for s.Scan() {
+			}
+			summary := s.Summary()
+			assert.Equal(t, test.expRecordCount, summary.RecordCount)
+			if test.expErr == nil {
+				assert.NoError(t, summary.Err)
+				assert.True(t, summary.EOF)
+				return
+			}
+			var tooLong *permissivecsv.RecordTooLongError
+			assert.True(t, errors.As(summary.Err, &tooLong))
+			assert.Equal(t, test.expErr, tooLong)
+			assert.True(t, summary.ReadFailed())
+			assert.True(t, errors.Is(summary.Err, bufio.ErrTooLong))
+			assert.False(t, summary.EOF)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_RecordTooLongErrorMessage(t *testing.T) {
+	err := &permissivecsv.RecordTooLongError{Offset: 12, Limit: 100}
+	assert.Equal(t, "record at offset 12 is longer than 100 bytes: bufio.Scanner: token too long", err.Error())
+}
@@ -0,0 +1,85 @@
+package permissivecsv
+
+// Checkpoint identifies a position in the input from which scanning can be
+// resumed, once the records that precede it have been processed.
+//
+// RecordOrdinal is the ordinal of the last record that precedes the checkpoint
+// (records are numbered from 1, as in Alteration.RecordOrdinal). Offset is the
+// byte offset (relative to where the Scanner started reading) of the first
+// record that follows the checkpoint, from which a new Scanner can resume.
+// Header is a copy of the header, if one has been identified (see
+// RecordIsHeader), since a Scanner that resumes part way through the input
+// cannot identify it. Final is true for the checkpoint emitted once scanning
+// has stopped.
+type Checkpoint struct {
+	RecordOrdinal int
+	Offset        int64
+	Header        []string
+	Final         bool
+}
+
+// WithCheckpointEvery instructs the Scanner to call fn with a Checkpoint and a
+// copy of the summary of the records scanned so far after every n records.
+// This allows a long scan to be resumed after a failure, and allows
+// downstream systems to commit their work in batches: once fn has committed
+// the work done for the records up to the checkpoint, along with its Offset,
+// a consumer that fails can resume from the last committed Offset without
+// processing any record twice.
+//
+// To support this, fn is not called when the nth record is scanned, but at
+// the beginning of the following call to Scan, once the caller has finished
+// with the nth record. A final checkpoint is emitted when Scan returns false,
+// if any records were scanned after the previous checkpoint. If the final
+// checkpoint's summary reports that scanning stopped because of an error, the
+// records it covers may be incomplete, and scanning should be resumed from
+// the previous checkpoint.
+//
+// The summary passed to fn is not modified by subsequent calls to Scan, so it
+// can be retained. If fn panics, scanning stops, and the panic is reported by
+// the summary's Err (see CallbackPanicError). A value of n less than 1
+// disables checkpoints.
+func WithCheckpointEvery(n int, fn func(cp Checkpoint, sum *ScanSummary)) Option {
+	return func(o *options) {
+		o.checkpointEvery = n
+		o.checkpointFn = fn
+	}
+}
+
+// checkpoint calls the checkpoint function if n records have been scanned
+// since the last checkpoint, or if final is true and any records have been
+// scanned since the last checkpoint.
+func (s *Scanner) checkpoint(final bool) {
+	if s.opts.checkpointEvery < 1 || s.opts.checkpointFn == nil || s.scanSummary == nil ||
+		s.scanningRaw || s.checkpointedFinal {
+		return
+	}
+	since := s.scanSummary.RecordCount - s.checkpointedRecords
+	if final {
+		s.checkpointedFinal = true
+		if since <= 0 {
+			return
+		}
+	} else if since < s.opts.checkpointEvery {
+		return
+	}
+
+	if s.firstRecord != nil {
+		// the second record has not been read, so it is peeked, as it would
+		// be by RecordIsHeader.
+		s.RecordIsHeader()
+	} else {
+		s.resolveHeader()
+	}
+	var header []string
+	if s.header != nil {
+		header = append([]string{}, s.header...)
+	}
+	cp := Checkpoint{
+		RecordOrdinal: s.scanSummary.RecordCount,
+		Offset:        s.resumeOffset(),
+		Header:        header,
+		Final:         final,
+	}
+	s.checkpointedRecords = s.scanSummary.RecordCount
+	s.callCheckpoint(cp, s.scanSummary.clone())
+}
@@ -0,0 +1,129 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithCheckpointEvery(t *testing.T) {
+	const data = "a,b\n1,2\n3\n\n5,6\r\n7,8"
+	tests := []struct {
+		name           string
+		n              int
+		opts           []permissivecsv.Option
+		expCheckpoints []permissivecsv.Checkpoint
+	}{
+		{
+			name: "every 2 records",
+			n:    2,
+			expCheckpoints: []permissivecsv.Checkpoint{
+				{RecordOrdinal: 2, Offset: 8, Header: []string{"a", "b"}},
+				{RecordOrdinal: 4, Offset: 16, Header: []string{"a", "b"}},
+				{RecordOrdinal: 5, Offset: 19, Header: []string{"a", "b"}, Final: true},
+			},
+		},
+		{
+			name: "record count is a multiple of n",
+			n:    5,
+			expCheckpoints: []permissivecsv.Checkpoint{
+				{RecordOrdinal: 5, Offset: 19, Header: []string{"a", "b"}},
+			},
+		},
+		{
+			name: "every record",
+			n:    1,
+			expCheckpoints: []permissivecsv.Checkpoint{
+				{RecordOrdinal: 1, Offset: 4, Header: []string{"a", "b"}},
+				{RecordOrdinal: 2, Offset: 8, Header: []string{"a", "b"}},
+				{RecordOrdinal: 3, Offset: 10, Header: []string{"a", "b"}},
+				{RecordOrdinal: 4, Offset: 16, Header: []string{"a", "b"}},
+				{RecordOrdinal: 5, Offset: 19, Header: []string{"a", "b"}},
+			},
+		},
+		{
+			name: "limited",
+			n:    2,
+			opts: []permissivecsv.Option{permissivecsv.WithMaxRecords(3)},
+			expCheckpoints: []permissivecsv.Checkpoint{
+				{RecordOrdinal: 2, Offset: 8, Header: []string{"a", "b"}},
+				{RecordOrdinal: 3, Offset: 10, Header: []string{"a", "b"}, Final: true},
+			},
+		},
+		{
+			name:           "disabled",
+			n:              0,
+			expCheckpoints: []permissivecsv.Checkpoint{},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			checkpoints := []permissivecsv.Checkpoint{}
+			processed := 0
+			opts := append([]permissivecsv.Option{
+				permissivecsv.WithCheckpointEvery(test.n, func(cp permissivecsv.Checkpoint, sum *permissivecsv.ScanSummary) {
+					// records are processed before the checkpoint that covers them.
+					assert.Equal(t, processed, cp.RecordOrdinal)
+					assert.Equal(t, cp.RecordOrdinal, sum.RecordCount)
+					checkpoints = append(checkpoints, cp)
+				}),
+			}, test.opts...)
+			s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists, opts...)
+			for s.Scan() {
+				processed++
+			}
+			assert.Equal(t, test.expCheckpoints, checkpoints)
+			assert.False(t, s.Scan())
+			assert.Len(t, checkpoints, len(test.expCheckpoints))
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_CheckpointResume(t *testing.T) {
+	const data = "id,value\n1,a\n2,b\n3,c\n4,d\n5,e\n6,f\n7,g"
+	var last permissivecsv.Checkpoint
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithCheckpointEvery(3, func(cp permissivecsv.Checkpoint, sum *permissivecsv.ScanSummary) {
+			last = cp
+		}))
+	// simulate a failure part way through the fifth record.
+	for i := 0; i < 5 && s.Scan(); i++ {
+	}
+	assert.Equal(t, 3, last.RecordOrdinal)
+	assert.Equal(t, []string{"id", "value"}, last.Header)
+
+	resumed := permissivecsv.NewScanner(strings.NewReader(data[last.Offset:]), permissivecsv.HeaderCheckAssumeNoHeader)
+	records := [][]string{}
+	for resumed.Scan() {
+		records = append(records, resumed.CurrentRecord())
+	}
+	assert.Equal(t, [][]string{
+		{"3", "c"},
+		{"4", "d"},
+		{"5", "e"},
+		{"6", "f"},
+		{"7", "g"},
+	}, records)
+}
+
+func Test_CheckpointSummaryIsSnapshot(t *testing.T) {
+	summaries := []*permissivecsv.ScanSummary{}
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\nc\nd,e\nf\ng,h"), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithCheckpointEvery(2, func(cp permissivecsv.Checkpoint, sum *permissivecsv.ScanSummary) {
+			summaries = append(summaries, sum)
+		}))
+	for s.Scan() {
+	}
+	if assert.Len(t, summaries, 3) {
+		assert.Equal(t, 1, summaries[0].AlterationCount)
+		assert.Len(t, summaries[0].Alterations, 1)
+		assert.False(t, summaries[0].EOF)
+		assert.Equal(t, 2, summaries[1].AlterationCount)
+		assert.True(t, summaries[2].EOF)
+		assert.Equal(t, 5, summaries[2].RecordCount)
+	}
+}
@@ -0,0 +1,60 @@
+package permissivecsv
+
+import "io"
+
+// WithOwnedReader instructs the Scanner to take ownership of its reader, so
+// that Close also closes the reader if it implements io.Closer. This allows a
+// helper that opens a reader (such as a file) to return a single Scanner for
+// the caller to manage. By default, the reader remains the caller's
+// responsibility.
+func WithOwnedReader() Option {
+	return func(o *options) {
+		o.ownedReader = true
+	}
+}
+
+// Close stops scanning, and releases the Scanner's resources. If Scan has not
+// yet returned false, the summary is finalized as though scanning had been
+// stopped by a limit: Err reports ErrClosed, ResumeOffset reports where
+// scanning could be resumed, and State reports ScannerStateLimited. Any
+// checkpoint and span are then completed, as when Scan returns false (see
+// WithCheckpointEvery and WithTracer). If the Scanner owns its reader (see
+// WithOwnedReader), and the reader implements io.Closer, the reader is closed,
+// and its error is returned.
+//
+// Scan returns false once the Scanner is closed. Subsequent calls to Close
+// have no effect, and return nil.
+func (s *Scanner) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.stopForClose()
+	if !s.opts.ownedReader {
+		return nil
+	}
+	closer, ok := s.reader.(io.Closer)
+	if !ok {
+		return nil
+	}
+	return closer.Close()
+}
+
+// stopForClose finalizes the summary, if Scan has not yet returned false.
+func (s *Scanner) stopForClose() {
+	defer s.holdSummary()()
+	if s.done {
+		return
+	}
+	if s.scanSummary == nil {
+		s.scanSummary = s.newScanSummary()
+	}
+	if s.state == ScannerStateScanning {
+		s.scanSummary.addErr(ErrClosed)
+		s.scanSummary.ResumeOffset = s.resumeOffset()
+		s.state = ScannerStateLimited
+	}
+	s.checkpoint(true)
+	s.endSpan()
+	s.finish()
+}
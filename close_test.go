@@ -0,0 +1,139 @@
+package permissivecsv_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+var ErrClose = errors.New("close failed")
+
+type closeSpy struct {
+	*strings.Reader
+	closed int
+	err    error
+}
+
+func (c *closeSpy) Close() error {
+	c.closed++
+	return c.err
+}
+
+func Test_Close(t *testing.T) {
+	tests := []struct {
+		name          string
+		scans         int
+		opts          []permissivecsv.Option
+		closeErr      error
+		expErr        error
+		expClosed     int
+		expState      permissivecsv.ScannerState
+		expSummaryErr error
+		expResume     int64
+		expRecord     []string
+	}{
+		{
+			name:          "part way through",
+			scans:         2,
+			expState:      permissivecsv.ScannerStateLimited,
+			expSummaryErr: permissivecsv.ErrClosed,
+			expResume:     8,
+			expRecord:     []string{"c", "d"},
+		},
+		{
+			name:          "owned reader",
+			scans:         2,
+			opts:          []permissivecsv.Option{permissivecsv.WithOwnedReader()},
+			expClosed:     1,
+			expState:      permissivecsv.ScannerStateLimited,
+			expSummaryErr: permissivecsv.ErrClosed,
+			expResume:     8,
+			expRecord:     []string{"c", "d"},
+		},
+		{
+			name:          "owned reader fails to close",
+			scans:         2,
+			opts:          []permissivecsv.Option{permissivecsv.WithOwnedReader()},
+			closeErr:      ErrClose,
+			expErr:        ErrClose,
+			expClosed:     1,
+			expState:      permissivecsv.ScannerStateLimited,
+			expSummaryErr: permissivecsv.ErrClosed,
+			expResume:     8,
+			expRecord:     []string{"c", "d"},
+		},
+		{
+			name:          "before scanning",
+			opts:          []permissivecsv.Option{permissivecsv.WithOwnedReader()},
+			expClosed:     1,
+			expState:      permissivecsv.ScannerStateLimited,
+			expSummaryErr: permissivecsv.ErrClosed,
+			expRecord:     []string{},
+		},
+		{
+			name:      "after scanning",
+			scans:     4,
+			opts:      []permissivecsv.Option{permissivecsv.WithOwnedReader()},
+			expClosed: 1,
+			expState:  permissivecsv.ScannerStateEOF,
+			expRecord: []string{"e", "f"},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			reader := &closeSpy{Reader: strings.NewReader("a,b\nc,d\ne,f\n"), err: test.closeErr}
+			s := permissivecsv.NewScanner(reader, permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			for i := 0; i < test.scans; i++ {
+				s.Scan()
+			}
+			err := s.Close()
+			assert.Equal(t, test.expErr, err)
+			assert.Nil(t, s.Close(), "subsequent calls have no effect")
+			assert.Equal(t, test.expClosed, reader.closed)
+			assert.False(t, s.Scan())
+			assert.True(t, s.Done())
+			assert.Equal(t, test.expState, s.State())
+			assert.Equal(t, test.expRecord, s.CurrentRecord())
+			summary := s.Summary()
+			assert.Equal(t, test.expSummaryErr, summary.Err)
+			assert.Equal(t, test.expResume, summary.ResumeOffset)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_CloseFinalizesCheckpoint(t *testing.T) {
+	var checkpoints []permissivecsv.Checkpoint
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\nc,d\ne,f\n"), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithCheckpointEvery(2, func(cp permissivecsv.Checkpoint, summary *permissivecsv.ScanSummary) {
+			checkpoints = append(checkpoints, cp)
+		}))
+	s.Scan()
+	assert.NoError(t, s.Close())
+	if assert.Len(t, checkpoints, 1) {
+		assert.True(t, checkpoints[0].Final)
+		assert.Equal(t, 1, checkpoints[0].RecordOrdinal)
+	}
+}
+
+func Test_ScanHTTPClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("a,b\nc,d\n"))
+	}))
+	defer server.Close()
+	s, err := permissivecsv.ScanHTTP(context.Background(), server.URL, permissivecsv.HeaderCheckAssumeNoHeader)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, s.Scan())
+	assert.NoError(t, s.Close())
+	assert.False(t, s.Scan())
+	assert.True(t, errors.Is(s.Summary().Err, permissivecsv.ErrClosed))
+}
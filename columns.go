@@ -0,0 +1,94 @@
+package permissivecsv
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// byteOrderMark is the UTF-8 encoding of the Unicode byte order mark, which
+// some applications write at the start of a file.
+const byteOrderMark = "\ufeff"
+
+// SnakeCase converts name to lower snake_case. Word boundaries are identified
+// by changes in case (including the end of an acronym, as in HTTPServer), and
+// by any characters that are not ASCII letters or digits, which are replaced
+// with underscores. Runs of underscores are collapsed, and leading and
+// trailing underscores are removed.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	pendingUnderscore := false
+	for i, r := range runes {
+		isLetter := r < unicode.MaxASCII && unicode.IsLetter(r)
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !isDigit {
+			pendingUnderscore = true
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				pendingUnderscore = true
+			}
+		}
+		if pendingUnderscore && b.Len() > 0 {
+			b.WriteByte('_')
+		}
+		pendingUnderscore = false
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// NormalizeColumnName converts name into a form that is safe to use as a
+// database column or identifier. Any byte order mark and surrounding
+// whitespace are removed, and the remainder is converted to snake_case (see
+// SnakeCase). If the result begins with a digit, it is prefixed with an
+// underscore. The result may be empty; see NormalizeColumnNames.
+func NormalizeColumnName(name string) string {
+	name = strings.TrimPrefix(name, byteOrderMark)
+	name = SnakeCase(strings.TrimSpace(name))
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// NormalizeColumnNames applies NormalizeColumnName to each of names, and then
+// ensures the results are usable as a set of columns. Names that are empty
+// after normalization are replaced with column_N, where N is the (1 based)
+// position of the column, and duplicate names are made unique by appending _2,
+// _3, and so on. names is not modified.
+func NormalizeColumnNames(names []string) []string {
+	normalized := make([]string, len(names))
+	for i, name := range names {
+		normalized[i] = NormalizeColumnName(name)
+		if normalized[i] == "" {
+			normalized[i] = "column_" + strconv.Itoa(i+1)
+		}
+	}
+
+	used := make(map[string]bool, len(normalized))
+	for _, name := range normalized {
+		used[name] = true
+	}
+	seen := make(map[string]bool, len(normalized))
+	for i, name := range normalized {
+		if !seen[name] {
+			seen[name] = true
+			continue
+		}
+		for n := 2; ; n++ {
+			candidate := name + "_" + strconv.Itoa(n)
+			if !used[candidate] {
+				normalized[i] = candidate
+				used[candidate] = true
+				seen[candidate] = true
+				break
+			}
+		}
+	}
+	return normalized
+}
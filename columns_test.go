@@ -0,0 +1,46 @@
+package permissivecsv_test
+
+import (
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SnakeCase(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expResult string
+	}{
+		{name: "already snake case", input: "first_name", expResult: "first_name"},
+		{name: "camel case", input: "firstName", expResult: "first_name"},
+		{name: "pascal case", input: "FirstName", expResult: "first_name"},
+		{name: "acronym", input: "HTTPServerURL", expResult: "http_server_url"},
+		{name: "digits", input: "address2Line", expResult: "address2_line"},
+		{name: "spaces and punctuation", input: "  Zip Code (5-digit) ", expResult: "zip_code_5_digit"},
+		{name: "non-ascii", input: "Café Name", expResult: "caf_name"},
+		{name: "only punctuation", input: "#!", expResult: ""},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			assert.Equal(t, test.expResult, permissivecsv.SnakeCase(test.input))
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_NormalizeColumnName(t *testing.T) {
+	assert.Equal(t, "email_address", permissivecsv.NormalizeColumnName("\ufeff Email Address\t"))
+	assert.Equal(t, "_2nd_phone", permissivecsv.NormalizeColumnName("2nd Phone"))
+	assert.Equal(t, "", permissivecsv.NormalizeColumnName("  "))
+}
+
+func Test_NormalizeColumnNames(t *testing.T) {
+	names := []string{"\ufeffID", "Name", "name", "", "NAME", "name_2", "#"}
+	result := permissivecsv.NormalizeColumnNames(names)
+	expected := []string{"id", "name", "name_3", "column_4", "name_4", "name_2", "column_7"}
+	assert.Equal(t, expected, result)
+	assert.Equal(t, "Name", names[1], "names must not be modified")
+}
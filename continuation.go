@@ -0,0 +1,53 @@
+package permissivecsv
+
+// WithContinuationRule tolerates exports that wrap long records across more
+// than one line without quoting them. Before each record is processed, rule
+// is called with the fields of the record and the fields of the line that
+// follows it. If rule returns true, the line is folded into the record, as if
+// the terminator between them had been quoted (so the last field of the record
+// and the first field of the line become a single field containing the
+// terminator), and rule is called again with the folded record and the next
+// line. Folding stops at the first empty line, or once rule returns false.
+//
+// A record is parsed with commas before it is passed to rule, and is not
+// padded or truncated. If a record cannot be parsed (such as because of an
+// ambiguous quote), rule is passed nil in its place. Each folded record is
+// reported with an AlterationFoldedContinuation alteration, whose
+// OriginalData holds the folded lines (including the terminators between
+// them). Any further alteration needed to fit the folded record to the
+// expected field count is reported separately.
+//
+// Peek is not aware of folding, so it returns each line as a separate record.
+func WithContinuationRule(rule func(prev, next []string) bool) Option {
+	return func(o *options) {
+		o.continuationRule = rule
+	}
+}
+
+// foldContinuations folds any continuation lines that follow token into it
+// (see WithContinuationRule).
+func (s *Scanner) foldContinuations(token rawToken) rawToken {
+	if s.opts.continuationRule == nil {
+		return token
+	}
+	for len(token.terminator) > 0 && s.state == ScannerStateScanning {
+		if len(s.lookahead) == 0 && !s.readAhead() {
+			break
+		}
+		next := s.lookahead[0]
+		if next.text == "" || s.isEmptyToken(next.text, next.terminator) {
+			break
+		}
+		if !s.callContinuationRule(peekFields(token), peekFields(next)) {
+			break
+		}
+		s.lookahead = s.lookahead[1:]
+		token = rawToken{
+			text:             token.text + next.text,
+			terminator:       next.terminator,
+			quoteForceClosed: token.quoteForceClosed || next.quoteForceClosed,
+			folds:            token.folds + 1,
+		}
+	}
+	return token
+}
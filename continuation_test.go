@@ -0,0 +1,147 @@
+package permissivecsv_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+// nonNumericContinuation folds lines that do not begin with a numeric id.
+func nonNumericContinuation(prev, next []string) bool {
+	if len(next) == 0 {
+		return false
+	}
+	_, err := strconv.Atoi(next[0])
+	return err != nil
+}
+
+func Test_WithContinuationRule(t *testing.T) {
+	tests := []struct {
+		name            string
+		data            string
+		expRecords      [][]string
+		expKinds        []permissivecsv.AlterationKind
+		expOriginalData []string
+	}{
+		{
+			name: "single fold",
+			data: "id,note,n\n1,first\nsecond,10\n2,x,20\n",
+			expRecords: [][]string{
+				{"id", "note", "n"},
+				{"1", "first\nsecond", "10"},
+				{"2", "x", "20"},
+			},
+			expKinds:        []permissivecsv.AlterationKind{permissivecsv.AlterationFoldedContinuation},
+			expOriginalData: []string{"1,first\nsecond,10"},
+		},
+		{
+			name: "several folds",
+			data: "id,note,n\r\n1,a\r\nb\r\nc,9\r\n2,x,8",
+			expRecords: [][]string{
+				{"id", "note", "n"},
+				{"1", "a\r\nb\r\nc", "9"},
+				{"2", "x", "8"},
+			},
+			expKinds:        []permissivecsv.AlterationKind{permissivecsv.AlterationFoldedContinuation},
+			expOriginalData: []string{"1,a\r\nb\r\nc,9"},
+		},
+		{
+			name: "folded record is padded",
+			data: "id,note,n\n1,a\nb\n2,x,8",
+			expRecords: [][]string{
+				{"id", "note", "n"},
+				{"1", "a\nb", ""},
+				{"2", "x", "8"},
+			},
+			expKinds: []permissivecsv.AlterationKind{
+				permissivecsv.AlterationFoldedContinuation,
+				permissivecsv.AlterationPaddedRecord,
+			},
+			expOriginalData: []string{"1,a\nb", "1,a\nb"},
+		},
+		{
+			name: "empty line stops folding",
+			data: "id,note,n\n1,a,2\n\nb,3,4",
+			expRecords: [][]string{
+				{"id", "note", "n"},
+				{"1", "a", "2"},
+				{"b", "3", "4"},
+			},
+			expKinds:        []permissivecsv.AlterationKind{},
+			expOriginalData: []string{},
+		},
+		{
+			name: "no folds",
+			data: "id,note,n\n1,a,2\n3,b,4",
+			expRecords: [][]string{
+				{"id", "note", "n"},
+				{"1", "a", "2"},
+				{"3", "b", "4"},
+			},
+			expKinds:        []permissivecsv.AlterationKind{},
+			expOriginalData: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists,
+				permissivecsv.WithContinuationRule(nonNumericContinuation))
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			summary := s.Summary()
+			kinds := []permissivecsv.AlterationKind{}
+			originalData := []string{}
+			for _, alteration := range summary.Alterations {
+				kinds = append(kinds, alteration.Kind)
+				originalData = append(originalData, alteration.OriginalData)
+			}
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expKinds, kinds)
+			assert.Equal(t, test.expOriginalData, originalData)
+			assert.Equal(t, len(test.expRecords), summary.RecordCount)
+			assert.True(t, summary.EOF)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithContinuationRuleRecordInfo(t *testing.T) {
+	calls := [][2][]string{}
+	rule := func(prev, next []string) bool {
+		calls = append(calls, [2][]string{prev, next})
+		return nonNumericContinuation(prev, next)
+	}
+	s := permissivecsv.NewScanner(strings.NewReader("1,a\nb\nc,9\n2,x,8"), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithContinuationRule(rule))
+	assert.True(t, s.Scan())
+	info := s.CurrentRecordInfo()
+	assert.Equal(t, int64(0), info.ByteOffset)
+	assert.Equal(t, int64(10), info.ByteLength)
+	assert.Equal(t, permissivecsv.AlterationFoldedContinuation, info.AlterationKind)
+	assert.Equal(t, [][2][]string{
+		{{"1", "a"}, {"b"}},
+		{{"1", "a\nb"}, {"c", "9"}},
+		{{"1", "a\nb\nc", "9"}, {"2", "x", "8"}},
+	}, calls)
+
+	assert.True(t, s.Scan())
+	assert.Equal(t, []string{"2", "x", "8"}, s.CurrentRecord())
+	assert.Equal(t, int64(10), s.CurrentRecordInfo().ByteOffset)
+	assert.False(t, s.Scan())
+}
+
+func Test_WithContinuationRulePanic(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("1,a\nb\n"), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithContinuationRule(func(prev, next []string) bool {
+			panic("boom")
+		}))
+	assert.False(t, s.Scan())
+	assert.True(t, s.Summary().CallbackFailed())
+	assert.Equal(t, permissivecsv.ScannerStateErrored, s.State())
+}
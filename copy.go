@@ -0,0 +1,62 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// copyEscaper escapes the characters that have special meaning in the text
+// format of PostgreSQL's COPY command.
+var copyEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"\t", "\\t",
+	"\n", "\\n",
+	"\r", "\\r",
+)
+
+// copyNull is the representation of a null value in COPY's text format.
+const copyNull = "\\N"
+
+// ToPostgresCopy scans the remainder of the input, and writes each (possibly
+// altered) record to w in the text format of PostgreSQL's COPY command, so
+// that repaired data can be streamed directly into COPY ... FROM STDIN. Fields
+// are separated by tabs, and records are terminated by newlines. Backslashes,
+// tabs, newlines, and carriage returns within fields are escaped with
+// backslashes. Fields that were missing from the input (see FieldMissing),
+// such as those added when padding a short record, are written as \N (null),
+// while empty fields are written as empty strings. The header, if any (see
+// RecordIsHeader), is not written, as COPY's text format has no header.
+//
+// If w returns an error, ToPostgresCopy stops scanning and returns that error.
+// Otherwise, ToPostgresCopy returns any error returned by the underlaying
+// reader (which is also available via the summary).
+func (s *Scanner) ToPostgresCopy(w io.Writer) (*ScanSummary, error) {
+	bw := bufio.NewWriter(w)
+	for s.Scan() {
+		if s.recordsScanned == 1 && s.RecordIsHeader() {
+			continue
+		}
+		states := s.CurrentFieldStates()
+		for i, field := range s.currentRecord {
+			if i > 0 {
+				bw.WriteByte('\t')
+			}
+			if states[i] == FieldMissing {
+				bw.WriteString(copyNull)
+				continue
+			}
+			copyEscaper.WriteString(bw, field)
+		}
+		_, err := bw.WriteString("\n")
+		if err != nil {
+			return s.Summary(), err
+		}
+	}
+	err := bw.Flush()
+	if err != nil {
+		return s.Summary(), err
+	}
+	summary := s.Summary()
+	return summary, summary.Err
+}
@@ -0,0 +1,74 @@
+package permissivecsv_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ToPostgresCopy(t *testing.T) {
+	tests := []struct {
+		name        string
+		reader      io.Reader
+		headerCheck permissivecsv.HeaderCheck
+		writer      io.Writer
+		expOutput   string
+		expErr      error
+	}{
+		{
+			name:        "escaping and nulls",
+			reader:      strings.NewReader("id,note,extra\r\n1,\"tab\there\",\"\"\n2,\"line\r\nbreak\",C:\\temp\n3\n\"4\"x,y,z"),
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expOutput: "1\ttab\\there\t\n" +
+				"2\tline\\r\\nbreak\tC:\\\\temp\n" +
+				"3\t\\N\t\\N\n" +
+				"\\N\t\\N\t\\N\n",
+		},
+		{
+			name:        "no header",
+			reader:      strings.NewReader("a,b\n\\.,\\N"),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expOutput:   "a\tb\n\\\\.\t\\\\N\n",
+		},
+		{
+			name:        "reader error",
+			reader:      BadReader(strings.NewReader("a,b")),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expOutput:   "",
+			expErr:      ErrReader,
+		},
+		{
+			name:        "writer error",
+			reader:      strings.NewReader("a,b\nc,d"),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			writer:      &failingWriter{limit: 1},
+			expOutput:   "",
+			expErr:      ErrWriter,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			w := test.writer
+			if w == nil {
+				w = buf
+			}
+			s := permissivecsv.NewScanner(test.reader, test.headerCheck)
+			summary, err := s.ToPostgresCopy(w)
+			assert.True(t, errors.Is(err, test.expErr), "got %v", err)
+			assert.NotNil(t, summary)
+			if fw, ok := w.(*failingWriter); ok {
+				assert.Equal(t, test.expOutput, fw.buf.String())
+			} else {
+				assert.Equal(t, test.expOutput, buf.String())
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
@@ -0,0 +1,218 @@
+// Package corpus generates pathological CSV inputs (such as mixed terminators,
+// quote storms, giant fields, and byte order marks), along with golden results
+// that describe how permissivecsv scans each of them.
+//
+// The corpus is deterministic, so the same inputs are generated on every call.
+// permissivecsv uses the corpus to detect unintended changes in its behavior,
+// and consumers can use it to test their own pipelines against permissivecsv's
+// behavior:
+//
+//	for _, c := range corpus.Generate() {
+//	    golden, err := corpus.Golden(c.Name)
+//	    ...
+//	    records := runPipeline(bytes.NewReader(c.Data))
+//	    // compare records to golden.Records
+//	}
+package corpus
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eltorocorp/permissivecsv"
+)
+
+// Case is a generated input. Name is unique within the corpus, and is a valid
+// file name.
+type Case struct {
+	Name        string
+	Description string
+	Data        []byte
+}
+
+// Result describes how permissivecsv scans an input using a Scanner with the
+// default options and HeaderCheckAssumeNoHeader. So that results can be
+// serialized, any invalid UTF-8 in Records is replaced with the Unicode
+// replacement character (U+FFFD).
+type Result struct {
+	Records         [][]string
+	RecordCount     int
+	AlterationCount int
+	Alterations     []ResultAlteration
+	EOF             bool
+	Err             string
+}
+
+// ResultAlteration describes an alteration by the ordinal of the record that
+// was altered, and the alteration's code (see AlterationKind.Code).
+type ResultAlteration struct {
+	RecordOrdinal int
+	Code          string
+}
+
+// Generate returns the cases of the corpus.
+func Generate() []Case {
+	return []Case{
+		{
+			Name:        "mixed_terminators",
+			Description: "Records terminated by every supported terminator, in varying order.",
+			Data:        mixedTerminators(),
+		},
+		{
+			Name:        "quote_storm",
+			Description: "Records containing bare, extraneous, doubled, and embedded quotes.",
+			Data:        quoteStorm(),
+		},
+		{
+			Name:        "giant_field",
+			Description: "A quoted field nearly as long as the longest supported record, containing delimiters and terminators.",
+			Data:        giantField(),
+		},
+		{
+			Name:        "oversized_record",
+			Description: "A record longer than the longest supported record.",
+			Data:        oversizedRecord(),
+		},
+		{
+			Name:        "byte_order_marks",
+			Description: "A byte order mark at the start of the file, and another within a field.",
+			Data:        []byte("\ufeffid,name\n1,\ufeffa\n2,b\n"),
+		},
+		{
+			Name:        "empty_record_storm",
+			Description: "Leading, interleaved, and trailing runs of empty records.",
+			Data:        emptyRecordStorm(),
+		},
+		{
+			Name:        "ragged_records",
+			Description: "Records whose field counts rise and fall around the expected count.",
+			Data:        raggedRecords(),
+		},
+		{
+			Name:        "unterminated_quote",
+			Description: "A quoted field that is still open at the end of the input.",
+			Data:        []byte("a,b\nc,\"d\ne,f\n"),
+		},
+		{
+			Name:        "control_characters",
+			Description: "Fields containing NUL, tab, form feed, and escape characters.",
+			Data:        []byte("a\x00b,c\td\n\x0c,\x1b[0m\n"),
+		},
+		{
+			Name:        "invalid_utf8",
+			Description: "Fields containing bytes that are not valid UTF-8.",
+			Data:        []byte("name,city\n\xffalice,M\xfcnchen\nbob,\xc3\x28\n"),
+		},
+	}
+}
+
+func mixedTerminators() []byte {
+	terminators := []string{"\n", "\r\n", "\r", "\n\r"}
+	buf := new(bytes.Buffer)
+	buf.WriteString("id,value")
+	for i := 0; i < 16; i++ {
+		buf.WriteString(terminators[(i*3)%len(terminators)])
+		buf.WriteString(strconv.Itoa(i) + ",v" + strconv.Itoa(i))
+	}
+	return buf.Bytes()
+}
+
+func quoteStorm() []byte {
+	fields := []string{
+		`plain`,
+		`"quoted"`,
+		`"with ""doubled"" quotes"`,
+		`bare"quote`,
+		`"extraneous"quote`,
+		`""`,
+		`"comma, inside"`,
+		`"""`,
+		`trailing"`,
+	}
+	buf := new(bytes.Buffer)
+	for i := 0; i < len(fields)*2; i++ {
+		buf.WriteString(fields[i%len(fields)])
+		buf.WriteString(",")
+		buf.WriteString(fields[(i*5+1)%len(fields)])
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+func giantField() []byte {
+	line := strings.Repeat("x", 60) + ", \r\n"
+	field := strings.Repeat(line, 60000/len(line))
+	return []byte("id,text\n1,\"" + field + "\"\n2,short\n")
+}
+
+func oversizedRecord() []byte {
+	return []byte("id,text\n1," + strings.Repeat("y", 70000) + "\n2,short\n")
+}
+
+func emptyRecordStorm() []byte {
+	return []byte("\n\r\n\na,b\n\n\n\rc,d\r\n\r\n\r\ne,f\n\n\n")
+}
+
+func raggedRecords() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString("a,b,c,d\n")
+	for i := 0; i < 12; i++ {
+		n := 1 + (i*7)%7
+		fields := make([]string, n)
+		for j := range fields {
+			fields[j] = strconv.Itoa(i) + "." + strconv.Itoa(j)
+		}
+		buf.WriteString(strings.Join(fields, ",") + "\n")
+	}
+	return buf.Bytes()
+}
+
+// Scan scans data using a Scanner with the default options and
+// HeaderCheckAssumeNoHeader, and returns the result.
+func Scan(data []byte) Result {
+	result := Result{
+		Records:     [][]string{},
+		Alterations: []ResultAlteration{},
+	}
+	s := permissivecsv.NewScanner(bytes.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	for s.Scan() {
+		record := make([]string, len(s.CurrentRecord()))
+		for i, field := range s.CurrentRecord() {
+			record[i] = strings.ToValidUTF8(field, "\ufffd")
+		}
+		result.Records = append(result.Records, record)
+	}
+	summary := s.Summary()
+	result.RecordCount = summary.RecordCount
+	result.AlterationCount = summary.AlterationCount
+	for _, alteration := range summary.Alterations {
+		result.Alterations = append(result.Alterations, ResultAlteration{
+			RecordOrdinal: alteration.RecordOrdinal,
+			Code:          alteration.Kind.Code(),
+		})
+	}
+	result.EOF = summary.EOF
+	if summary.Err != nil {
+		result.Err = summary.Err.Error()
+	}
+	return result
+}
+
+//go:embed golden/*.json
+var golden embed.FS
+
+// Golden returns the golden result for the named case, which is the result
+// of Scan at the time the case was added (or last intentionally changed).
+func Golden(name string) (Result, error) {
+	data, err := golden.ReadFile("golden/" + name + ".json")
+	if err != nil {
+		return Result{}, fmt.Errorf("no golden result for case %q", name)
+	}
+	result := Result{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
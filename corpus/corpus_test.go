@@ -0,0 +1,66 @@
+package corpus_test
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv/corpus"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden results with current results")
+
+// Test_Golden scans every case in the corpus and compares the results to the
+// case's golden result. To accept intentional behavior changes, run:
+//
+//	go test ./corpus -run Test_Golden -update
+func Test_Golden(t *testing.T) {
+	for _, c := range corpus.Generate() {
+		c := c
+		testFn := func(t *testing.T) {
+			actual := corpus.Scan(c.Data)
+			if *updateGolden {
+				data, err := json.MarshalIndent(actual, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = ioutil.WriteFile(filepath.Join("golden", c.Name+".json"), append(data, '\n'), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := corpus.Golden(c.Name)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if diff := deep.Equal(expected, actual); diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(c.Name, testFn)
+	}
+}
+
+func Test_Generate(t *testing.T) {
+	first := corpus.Generate()
+	second := corpus.Generate()
+	assert.Equal(t, first, second, "the corpus must be deterministic")
+
+	names := map[string]bool{}
+	for _, c := range first {
+		assert.False(t, names[c.Name], "duplicate case %s", c.Name)
+		names[c.Name] = true
+		assert.NotEmpty(t, c.Description)
+		assert.NotEmpty(t, c.Data)
+	}
+}
+
+func Test_GoldenUnknownCase(t *testing.T) {
+	_, err := corpus.Golden("unknown")
+	assert.Error(t, err)
+}
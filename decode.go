@@ -0,0 +1,304 @@
+package permissivecsv
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidDecodeTarget is returned by Decode if it is not supplied a
+	// non-nil pointer to a struct.
+	ErrInvalidDecodeTarget = fmt.Errorf("decode target must be a non-nil pointer to a struct")
+
+	// ErrDecodeHeader is returned by Decode if the current record is a header.
+	// The header's column names are retained, and used to decode subsequent
+	// records.
+	ErrDecodeHeader = fmt.Errorf("current record is a header")
+)
+
+// FieldDecoder decodes field into dst, which is a pointer to a struct field.
+// For instance, a FieldDecoder for a field of type Money receives a *Money.
+type FieldDecoder func(field string, dst interface{}) error
+
+// WithFieldDecoder registers a FieldDecoder for the named column. When Decode
+// assigns the column to a struct field, decoder is used instead of the default
+// decoding for the field's type. This allows values such as currency amounts
+// (like "$1,024.00") to be decoded directly from messy fields. column is
+// matched in the same way as struct fields are matched to columns (see
+// Decode).
+func WithFieldDecoder(column string, decoder FieldDecoder) Option {
+	return func(o *options) {
+		if o.fieldDecoders == nil {
+			o.fieldDecoders = make(map[string]FieldDecoder)
+		}
+		o.fieldDecoders[NormalizeColumnName(column)] = decoder
+	}
+}
+
+// DecodeError is returned by Decode if a field cannot be decoded.
+// RecordOrdinal is the ordinal of the record, as reported by RecordInfo and
+// Alteration.
+type DecodeError struct {
+	RecordOrdinal int
+	Column        string
+	Value         string
+	Err           error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("record %d: cannot decode %q into column %s: %v",
+		e.RecordOrdinal, e.Value, e.Column, e.Err)
+}
+
+// Unwrap returns the underlaying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Decode stores the fields of the current record in the struct pointed to by
+// v.
+//
+// Each exported field of the struct is matched to a column by name. The name
+// is the field's name, or the name given by a csv struct tag (as in
+// `csv:"first_name"`). A tag of "-" excludes the field. If a header has been
+// identified (see RecordIsHeader), names are matched to the header's column
+// names after both are normalized by NormalizeColumnName, so a field named
+// FirstName matches a column named "First Name". Otherwise, fields are
+// matched to columns by position, in the order they are declared. Struct
+// fields that do not match a column are left unchanged.
+//
+// The fields of a nested struct are matched to columns whose names are
+// prefixed by the name of the struct field, which suits flattened exports. For
+// instance, an Address field with Street and City fields matches the columns
+// address_street and address_city. The fields of an embedded struct are
+// matched without a prefix, unless the embedded struct has a csv tag. Nested
+// structs must not be pointers, and structs that implement
+// encoding.TextUnmarshaler (such as time.Time) are decoded as a single field.
+//
+// Fields are decoded as follows, in order of precedence:
+//   - Using the FieldDecoder registered for the column (see WithFieldDecoder).
+//   - Using UnmarshalText, if a pointer to the struct field implements
+//     encoding.TextUnmarshaler (as time.Time does).
+//   - For strings, the field is assigned as is.
+//   - For bools, integers, and floats, the field is parsed using the strconv
+//     package, after trimming surrounding whitespace. A blank field leaves the
+//     struct field unchanged.
+//   - For pointers, a value is allocated (unless the field is blank) and
+//     decoded using the rules above.
+//
+// If a field cannot be decoded, Decode returns a *DecodeError, and the
+// remaining fields are not decoded. If the current record is the header,
+// Decode returns ErrDecodeHeader.
+func (s *Scanner) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidDecodeTarget
+	}
+	if s.recordsScanned == 1 && s.firstRecord != nil && s.RecordIsHeader() {
+		return ErrDecodeHeader
+	}
+	s.resolveHeader()
+	rv = rv.Elem()
+
+	record, _ := s.outputRecord(nil)
+	for _, f := range s.decodePlan(rv.Type()) {
+		if f.column >= len(record) {
+			continue
+		}
+		field := record[f.column]
+		err := s.decodeField(field, f, rv.FieldByIndex(f.index))
+		if err != nil {
+			return &DecodeError{
+				RecordOrdinal: s.scanSummary.RecordCount,
+				Column:        f.name,
+				Value:         field,
+				Err:           err,
+			}
+		}
+	}
+	return nil
+}
+
+// resolveHeader identifies the header (if any) using the leading records, in
+// case the first record was not checked while it was current.
+func (s *Scanner) resolveHeader() {
+	if s.headerResolved || len(s.leadingRecords) == 0 {
+		return
+	}
+	var secondRecord []string
+	if len(s.leadingRecords) > 1 {
+		secondRecord = s.leadingRecords[1]
+	}
+	if s.callHeaderCheck(s.leadingRecords[0], secondRecord) {
+		s.header = s.leadingRecords[0]
+		if secondRecord != nil {
+			s.checkHeaderWidth(s.header, s.scanSummary.fieldCountAt(2))
+		}
+	}
+	s.headerResolved = true
+	s.decodePlans = nil
+}
+
+// decodeTarget is a struct field that has been matched to a column.
+type decodeTarget struct {
+	index   []int
+	column  int
+	name    string
+	decoder FieldDecoder
+}
+
+// decodePlan returns the struct fields of t that match a column. Plans are
+// cached for each type, and discarded if the header changes.
+func (s *Scanner) decodePlan(t reflect.Type) []decodeTarget {
+	if plan, ok := s.decodePlans[t]; ok {
+		return plan
+	}
+
+	var columns map[string]int
+	if header := s.outputHeader(); header != nil {
+		columns = make(map[string]int, len(header))
+		for i, name := range header {
+			name = NormalizeColumnName(name)
+			if _, exists := columns[name]; !exists {
+				columns[name] = i
+			}
+		}
+	}
+
+	position := 0
+	plan := s.appendDecodeTargets([]decodeTarget{}, t, nil, "", "", columns, &position)
+	if s.decodePlans == nil {
+		s.decodePlans = make(map[reflect.Type][]decodeTarget)
+	}
+	s.decodePlans[t] = plan
+	return plan
+}
+
+// appendDecodeTargets appends the fields of t that match a column to plan.
+// index is the index sequence of t within the decoded struct, and prefix and
+// keyPrefix are prepended to the names of its fields. position is the next
+// column to assign if there is no header.
+func (s *Scanner) appendDecodeTargets(plan []decodeTarget, t reflect.Type, index []int, prefix, keyPrefix string, columns map[string]int, position *int) []decodeTarget {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		tag, tagged := sf.Tag.Lookup("csv")
+		if tag == "-" {
+			continue
+		}
+		name := sf.Name
+		if tag != "" {
+			name = tag
+		}
+		key := NormalizeColumnName(name)
+		if keyPrefix != "" {
+			key = keyPrefix + "_" + key
+			name = prefix + "_" + name
+		}
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		decoder := s.opts.fieldDecoders[key]
+		if decoder == nil && isNestedStruct(sf.Type) {
+			if sf.Anonymous && !tagged {
+				plan = s.appendDecodeTargets(plan, sf.Type, fieldIndex, prefix, keyPrefix, columns, position)
+			} else {
+				plan = s.appendDecodeTargets(plan, sf.Type, fieldIndex, name, key, columns, position)
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		column := *position
+		*position++
+		if columns != nil {
+			var ok bool
+			column, ok = columns[key]
+			if !ok {
+				continue
+			}
+		}
+		plan = append(plan, decodeTarget{
+			index:   fieldIndex,
+			column:  column,
+			name:    name,
+			decoder: decoder,
+		})
+	}
+	return plan
+}
+
+// isNestedStruct reports whether the fields of a struct field of type t are
+// decoded individually, rather than decoding the field as a whole.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func (s *Scanner) decodeField(field string, target decodeTarget, dst reflect.Value) error {
+	if target.decoder != nil {
+		return s.callFieldDecoder(target.decoder, field, dst.Addr().Interface())
+	}
+	return decodeValue(field, dst)
+}
+
+// decodeValue decodes field into dst, which must be addressable.
+func decodeValue(field string, dst reflect.Value) error {
+	if dst.Addr().Type().Implements(textUnmarshalerType) {
+		return dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(field))
+	}
+	if dst.Kind() == reflect.String {
+		dst.SetString(field)
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(field)
+	if trimmed == "" {
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		value := reflect.New(dst.Type().Elem())
+		err := decodeValue(field, value.Elem())
+		if err != nil {
+			return err
+		}
+		dst.Set(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(trimmed, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(trimmed, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(trimmed, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", dst.Type())
+	}
+	return nil
+}
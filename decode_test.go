@@ -0,0 +1,283 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+type status int
+
+func (st *status) UnmarshalText(text []byte) error {
+	switch strings.ToLower(strings.TrimSpace(string(text))) {
+	case "active":
+		*st = 1
+	case "inactive":
+		*st = 2
+	default:
+		return fmt.Errorf("unknown status %q", text)
+	}
+	return nil
+}
+
+type money int64
+
+func decodeMoney(field string, dst interface{}) error {
+	field = strings.NewReplacer("$", "", ",", "", ".", "").Replace(strings.TrimSpace(field))
+	cents, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		return err
+	}
+	*dst.(*money) = money(cents)
+	return nil
+}
+
+type account struct {
+	Name     string
+	Age      int `csv:"years"`
+	Balance  money
+	Status   status
+	Opened   *time.Time
+	Verified bool
+	Ignored  string `csv:"-"`
+	internal string
+}
+
+func Test_Decode(t *testing.T) {
+	opened := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name        string
+		data        string
+		headerCheck permissivecsv.HeaderCheck
+		expAccounts []account
+	}{
+		{
+			name:        "match by header",
+			data:        "Verified,Status,Balance,Years,name\ntrue,active,\"$1,024.50\",42,alice\n,Inactive,$0.01, ,bob",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expAccounts: []account{
+				{Name: "alice", Age: 42, Balance: 102450, Status: 1, Verified: true},
+				{Name: "bob", Balance: 1, Status: 2},
+			},
+		},
+		{
+			name:        "match by position",
+			data:        "alice,42,$5.00,active,2020-01-02T03:04:05Z,1",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expAccounts: []account{
+				{Name: "alice", Age: 42, Balance: 500, Status: 1, Opened: &opened, Verified: true},
+			},
+		},
+		{
+			name:        "missing columns",
+			data:        "name\nalice",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expAccounts: []account{
+				{Name: "alice"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck,
+				permissivecsv.WithFieldDecoder("balance", decodeMoney))
+			accounts := []account{}
+			for s.Scan() {
+				a := account{Ignored: "unchanged", internal: "unchanged"}
+				err := s.Decode(&a)
+				if err == permissivecsv.ErrDecodeHeader {
+					continue
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, "unchanged", a.Ignored)
+				assert.Equal(t, "unchanged", a.internal)
+				a.Ignored, a.internal = "", ""
+				accounts = append(accounts, a)
+			}
+			if diff := deep.Equal(test.expAccounts, accounts); diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		target    interface{}
+		expErr    error
+		expColumn string
+	}{
+		{
+			name:   "nil target",
+			data:   "a",
+			target: nil,
+			expErr: permissivecsv.ErrInvalidDecodeTarget,
+		},
+		{
+			name:   "non-pointer target",
+			data:   "a",
+			target: account{},
+			expErr: permissivecsv.ErrInvalidDecodeTarget,
+		},
+		{
+			name:   "pointer to non-struct",
+			data:   "a",
+			target: new(string),
+			expErr: permissivecsv.ErrInvalidDecodeTarget,
+		},
+		{
+			name:      "unparsable integer",
+			data:      "alice,forty",
+			target:    &account{},
+			expColumn: "years",
+		},
+		{
+			name:      "text unmarshaler error",
+			data:      "alice,40,,closed",
+			target:    &account{},
+			expColumn: "Status",
+		},
+		{
+			name: "unsupported type",
+			data: "a",
+			target: &struct {
+				Values []string
+			}{},
+			expColumn: "Values",
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader)
+			s.Scan()
+			err := s.Decode(test.target)
+			if test.expErr != nil {
+				assert.Equal(t, test.expErr, err)
+				return
+			}
+			var decodeErr *permissivecsv.DecodeError
+			if assert.True(t, errors.As(err, &decodeErr)) {
+				assert.Equal(t, test.expColumn, decodeErr.Column)
+				assert.Equal(t, 1, decodeErr.RecordOrdinal)
+				assert.NotNil(t, errors.Unwrap(err))
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_DecodeErrorOrdinal(t *testing.T) {
+	data := "name,years\nalice,40\n\nbob,old,extra\n"
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists, permissivecsv.WithKeepEmptyRecords())
+	var decodeErr *permissivecsv.DecodeError
+	for s.Scan() {
+		target := &struct {
+			Name  string
+			Years int
+		}{}
+		err := s.Decode(target)
+		if err != nil && err != permissivecsv.ErrDecodeHeader {
+			assert.True(t, errors.As(err, &decodeErr), "%v", err)
+		}
+	}
+	if assert.NotNil(t, decodeErr) {
+		alterations := s.Summary().Alterations
+		assert.Len(t, alterations, 1)
+		assert.Equal(t, 4, decodeErr.RecordOrdinal)
+		assert.Equal(t, alterations[0].RecordOrdinal, decodeErr.RecordOrdinal)
+	}
+}
+
+type address struct {
+	Street string
+	City   string
+	Zip    *int
+}
+
+type audit struct {
+	Created time.Time
+}
+
+type contact struct {
+	audit
+	Name     string
+	Home     address
+	Work     address `csv:"office"`
+	Internal address `csv:"-"`
+}
+
+func Test_DecodeNested(t *testing.T) {
+	created := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	zip := 27601
+	tests := []struct {
+		name        string
+		data        string
+		headerCheck permissivecsv.HeaderCheck
+		expContact  contact
+	}{
+		{
+			name:        "prefixed header columns",
+			data:        "name,home_street,Home City,home_zip,office_city,created\nalice,1 Main St,Raleigh,27601,Durham,2020-01-02T00:00:00Z",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expContact: contact{
+				audit: audit{Created: created},
+				Name:  "alice",
+				Home:  address{Street: "1 Main St", City: "Raleigh", Zip: &zip},
+				Work:  address{City: "Durham"},
+			},
+		},
+		{
+			name:        "by position",
+			data:        "2020-01-02T00:00:00Z,alice,1 Main St,Raleigh,27601,2 Side St,Durham,",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expContact: contact{
+				audit: audit{Created: created},
+				Name:  "alice",
+				Home:  address{Street: "1 Main St", City: "Raleigh", Zip: &zip},
+				Work:  address{Street: "2 Side St", City: "Durham"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck)
+			c := contact{}
+			for s.Scan() {
+				err := s.Decode(&c)
+				if err == permissivecsv.ErrDecodeHeader {
+					continue
+				}
+				assert.NoError(t, err)
+			}
+			if diff := deep.Equal(test.expContact, c); diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_DecodeNestedError(t *testing.T) {
+	data := "name,home_zip\nalice,unknown"
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists)
+	s.Scan()
+	s.Scan()
+	err := s.Decode(&contact{})
+	var decodeErr *permissivecsv.DecodeError
+	if assert.True(t, errors.As(err, &decodeErr)) {
+		assert.Equal(t, "Home_Zip", decodeErr.Column)
+	}
+}
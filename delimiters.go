@@ -0,0 +1,60 @@
+package permissivecsv
+
+import "github.com/eltorocorp/permissivecsv/internal/util"
+
+// defaultAlternateDelimiters are the delimiters tried by
+// WithAlternateDelimiters if none are supplied.
+var defaultAlternateDelimiters = []rune{';', '\t', '|'}
+
+// WithAlternateDelimiters tolerates files that switch delimiter part way
+// through, such as a comma separated header followed by semicolon separated
+// records (a common result of merging exports from different locales). When a
+// record does not have the expected number of fields, it is parsed again with
+// each of delimiters in turn, and the first delimiter that produces exactly
+// the expected number of fields is used. Such records are reported with an
+// AlterationSwitchedDelimiter alteration, whose AlternateRecord holds the
+// record as it would have been returned had it been parsed with commas.
+// CurrentRecordInfo reports the delimiter used for each record.
+//
+// If no delimiters are supplied, semicolons, tabs, and pipes are tried. Only
+// ASCII delimiters are supported, and commas, quotes, carriage returns, and
+// line feeds are ignored. The first record is always parsed with commas.
+func WithAlternateDelimiters(delimiters ...rune) Option {
+	return func(o *options) {
+		if len(delimiters) == 0 {
+			delimiters = defaultAlternateDelimiters
+		}
+		o.alternateDelimiters = nil
+		for _, delimiter := range delimiters {
+			if validAlternateDelimiter(delimiter) {
+				o.alternateDelimiters = append(o.alternateDelimiters, delimiter)
+			}
+		}
+	}
+}
+
+// validAlternateDelimiter reports whether delimiter can be used by
+// WithAlternateDelimiters.
+func validAlternateDelimiter(delimiter rune) bool {
+	switch delimiter {
+	case ',', util.QuoteChar, '\r', '\n':
+		return false
+	}
+	return delimiter > 0 && delimiter < 0x80
+}
+
+// switchDelimiter parses trimmedRawRecord with each of the alternate
+// delimiters, and returns the first record that has the expected number of
+// fields, along with the delimiter that produced it.
+func (s *Scanner) switchDelimiter(trimmedRawRecord string) ([]string, rune, bool) {
+	if s.expectedFieldCount < 2 {
+		return nil, 0, false
+	}
+	for _, delimiter := range s.opts.alternateDelimiters {
+		record, err := parseFields(trimmedRawRecord, delimiter, false)
+		if err == nil && len(record) == s.expectedFieldCount {
+			return record, delimiter, true
+		}
+	}
+	return nil, 0, false
+}
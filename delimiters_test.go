@@ -0,0 +1,146 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithAlternateDelimiters(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		opts          []permissivecsv.Option
+		expRecords    [][]string
+		expKinds      []permissivecsv.AlterationKind
+		expDelimiters []rune
+	}{
+		{
+			name: "disabled",
+			data: "a,b,c\n1;2;3",
+			expRecords: [][]string{
+				[]string{"a", "b", "c"},
+				[]string{"1;2;3", "", ""},
+			},
+			expKinds: []permissivecsv.AlterationKind{
+				permissivecsv.AlterationPaddedRecord,
+			},
+			expDelimiters: []rune{',', ','},
+		},
+		{
+			name: "default delimiters",
+			data: "a,b,c\n1;\"x;y\";3\n4,5,6\n7\t8\t9\n10|11|12",
+			opts: []permissivecsv.Option{permissivecsv.WithAlternateDelimiters()},
+			expRecords: [][]string{
+				[]string{"a", "b", "c"},
+				[]string{"1", "x;y", "3"},
+				[]string{"4", "5", "6"},
+				[]string{"7", "8", "9"},
+				[]string{"10", "11", "12"},
+			},
+			expKinds: []permissivecsv.AlterationKind{
+				permissivecsv.AlterationSwitchedDelimiter,
+				permissivecsv.AlterationSwitchedDelimiter,
+				permissivecsv.AlterationSwitchedDelimiter,
+			},
+			expDelimiters: []rune{',', ';', ',', '\t', '|'},
+		},
+		{
+			name: "no delimiter produces the expected field count",
+			data: "a,b,c\n1;2\n3;4;5;6",
+			opts: []permissivecsv.Option{permissivecsv.WithAlternateDelimiters()},
+			expRecords: [][]string{
+				[]string{"a", "b", "c"},
+				[]string{"1;2", "", ""},
+				[]string{"3;4;5;6", "", ""},
+			},
+			expKinds: []permissivecsv.AlterationKind{
+				permissivecsv.AlterationPaddedRecord,
+				permissivecsv.AlterationPaddedRecord,
+			},
+			expDelimiters: []rune{',', ',', ','},
+		},
+		{
+			name: "custom delimiters",
+			data: "a,b\n1:2\n3;4",
+			opts: []permissivecsv.Option{permissivecsv.WithAlternateDelimiters(':', ',', '"', 'é')},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"1", "2"},
+				[]string{"3;4", ""},
+			},
+			expKinds: []permissivecsv.AlterationKind{
+				permissivecsv.AlterationSwitchedDelimiter,
+				permissivecsv.AlterationPaddedRecord,
+			},
+			expDelimiters: []rune{',', ':', ','},
+		},
+		{
+			name: "first record is always parsed with commas",
+			data: "a;b;c\n1,2,3",
+			opts: []permissivecsv.Option{permissivecsv.WithAlternateDelimiters()},
+			expRecords: [][]string{
+				[]string{"a;b;c"},
+				[]string{"1"},
+			},
+			expKinds: []permissivecsv.AlterationKind{
+				permissivecsv.AlterationTruncatedRecord,
+			},
+			expDelimiters: []rune{',', ','},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists, test.opts...)
+			records := [][]string{}
+			delimiters := []rune{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+				delimiters = append(delimiters, s.CurrentRecordInfo().Delimiter)
+			}
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expDelimiters, delimiters)
+			kinds := []permissivecsv.AlterationKind{}
+			for _, alteration := range s.Summary().Alterations {
+				kinds = append(kinds, alteration.Kind)
+			}
+			assert.Equal(t, test.expKinds, kinds)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_SwitchedDelimiterAlteration(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b,c\n\"1\";\"x\"\"y\";3"), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithAlternateDelimiters())
+	s.Scan()
+	s.Scan()
+	assert.Equal(t, []string{"1", "x\"y", "3"}, s.CurrentRecord())
+	assert.Equal(t, []permissivecsv.FieldState{
+		permissivecsv.FieldQuoted,
+		permissivecsv.FieldQuoted,
+		permissivecsv.FieldBare,
+	}, s.CurrentFieldStates())
+	unescaped := s.CurrentUnescapedFields()
+	assert.False(t, unescaped.IsSet(0))
+	assert.True(t, unescaped.IsSet(1))
+	assert.False(t, unescaped.IsSet(2))
+
+	summary := s.Summary()
+	assert.Equal(t, 1, summary.AlterationCount)
+	alteration := summary.Alterations[0]
+	assert.Equal(t, permissivecsv.AltSwitchedDelimiter, alteration.AlterationDescription)
+	assert.Equal(t, "\"1\";\"x\"\"y\";3", alteration.OriginalData)
+	assert.Equal(t, []string{"1", "x\"y", "3"}, alteration.ResultingRecord)
+	assert.Equal(t, []string{"", "", ""}, alteration.AlternateRecord, "the record has a bare quote if parsed with commas")
+	assert.Equal(t, 1, summary.AlterationKindCounts[permissivecsv.AlterationSwitchedDelimiter])
+
+	s = permissivecsv.NewScanner(strings.NewReader("a,b,c\n1;2;3"), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithAlternateDelimiters())
+	for s.Scan() {
+	}
+	assert.Equal(t, []string{"1;2;3", "", ""}, s.Summary().Alterations[0].AlternateRecord)
+}
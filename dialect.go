@@ -0,0 +1,160 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidDialect is returned by Rewrite if the supplied Dialect cannot be
+// written unambiguously.
+var ErrInvalidDialect = fmt.Errorf("invalid dialect")
+
+// QuotingStyle determines which fields are quoted when records are written.
+type QuotingStyle int
+
+const (
+	// QuoteMinimal quotes only those fields whose content requires quotes.
+	QuoteMinimal QuotingStyle = iota
+
+	// QuoteAll quotes every field.
+	QuoteAll
+
+	// QuotePreserve quotes fields that were quoted in the input, along with
+	// any fields whose content requires quotes (see CurrentFieldStates).
+	QuotePreserve
+)
+
+// Dialect describes the format in which records are written.
+//
+// Delimiter is the field delimiter, and defaults to a comma. The delimiter
+// must not be a double quote, carriage return, or newline. Terminator is
+// written after each record, and defaults to a unix terminator (\n). Quoting
+// determines which fields are quoted, and defaults to QuoteMinimal. Quotes
+// within quoted fields are always escaped by doubling them.
+type Dialect struct {
+	Delimiter  rune
+	Terminator string
+	Quoting    QuotingStyle
+}
+
+// Rewrite scans the remainder of the input, and writes each (possibly altered)
+// record to w in the supplied dialect. Rewrite operates in a single streaming
+// pass, so its memory use does not depend on the size of the input. This is
+// useful for converting messy input into whatever format a downstream parser
+// requires, such as a tab delimited file with DOS terminators.
+//
+// If dialect is invalid, ErrInvalidDialect is returned before any input is
+// scanned. If w returns an error, Rewrite stops scanning and returns that
+// error. Otherwise, Rewrite returns any error returned by the underlaying
+// reader (which is also available via the summary).
+func (s *Scanner) Rewrite(w io.Writer, dialect Dialect) (*ScanSummary, error) {
+	return s.rewrite(w, normalizeOptions{dialect: dialect})
+}
+
+func (s *Scanner) rewrite(w io.Writer, o normalizeOptions) (*ScanSummary, error) {
+	encoder, err := newRecordEncoder(o.dialect)
+	if err != nil {
+		return s.Summary(), err
+	}
+
+	projection := newColumnProjection(o.columns)
+	bw := bufio.NewWriter(w)
+	for s.Scan() {
+		if o.skipHeader && s.RecordIsHeader() {
+			projection.project(s, nil)
+			continue
+		}
+		var states []FieldState
+		if o.dialect.Quoting == QuotePreserve {
+			states = s.CurrentFieldStates()
+		}
+		record, states := projection.project(s, states)
+		err = encoder.write(bw, record, states)
+		if err != nil {
+			return s.Summary(), err
+		}
+	}
+	err = bw.Flush()
+	if err != nil {
+		return s.Summary(), err
+	}
+	summary := s.Summary()
+	return summary, summary.Err
+}
+
+// recordEncoder writes records in a particular dialect.
+type recordEncoder struct {
+	dialect   Dialect
+	delimiter string
+	special   string
+}
+
+func newRecordEncoder(dialect Dialect) (*recordEncoder, error) {
+	if dialect.Delimiter == 0 {
+		dialect.Delimiter = ','
+	}
+	if dialect.Terminator == "" {
+		dialect.Terminator = "\n"
+	}
+	switch {
+	case !utf8.ValidRune(dialect.Delimiter),
+		dialect.Delimiter == utf8.RuneError,
+		dialect.Delimiter == '"',
+		dialect.Delimiter == '\r',
+		dialect.Delimiter == '\n':
+		return nil, ErrInvalidDialect
+	}
+	switch dialect.Quoting {
+	case QuoteMinimal, QuoteAll, QuotePreserve:
+	default:
+		return nil, ErrInvalidDialect
+	}
+	return &recordEncoder{
+		dialect:   dialect,
+		delimiter: string(dialect.Delimiter),
+		special:   string(dialect.Delimiter) + "\"\r\n",
+	}, nil
+}
+
+// write writes record to w, followed by the dialect's terminator. If states is
+// non-nil, fields that were quoted in the input are always quoted.
+func (e *recordEncoder) write(w *bufio.Writer, record []string, states []FieldState) error {
+	for i, field := range record {
+		if i > 0 {
+			w.WriteString(e.delimiter)
+		}
+		quote := e.dialect.Quoting == QuoteAll || e.needsQuotes(field)
+		if states != nil && i < len(states) && states[i] == FieldQuoted {
+			quote = true
+		}
+		// A record consisting of a single empty field is quoted, as it would
+		// otherwise be written as an empty record.
+		if len(record) == 1 && field == "" {
+			quote = true
+		}
+		if !quote {
+			w.WriteString(field)
+			continue
+		}
+		w.WriteByte('"')
+		w.WriteString(strings.Replace(field, `"`, `""`, -1))
+		w.WriteByte('"')
+	}
+	_, err := w.WriteString(e.dialect.Terminator)
+	return err
+}
+
+// needsQuotes reports whether field must be quoted in order to be read back
+// unchanged.
+func (e *recordEncoder) needsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if strings.ContainsAny(field, e.special) {
+		return true
+	}
+	return field[0] == ' ' || field[0] == '\t'
+}
@@ -0,0 +1,81 @@
+package permissivecsv_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Rewrite(t *testing.T) {
+	data := "a,\"b\tc\"\r\nd\n\"e\"\"f\",g,h\n\" i\",\"\""
+	tests := []struct {
+		name      string
+		dialect   permissivecsv.Dialect
+		expOutput string
+		expErr    error
+	}{
+		{
+			name:      "default dialect",
+			dialect:   permissivecsv.Dialect{},
+			expOutput: "a,b\tc\nd,\n\"e\"\"f\",g\n\" i\",\n",
+		},
+		{
+			name: "tab delimited with dos terminators",
+			dialect: permissivecsv.Dialect{
+				Delimiter:  '\t',
+				Terminator: "\r\n",
+			},
+			expOutput: "a\t\"b\tc\"\r\nd\t\r\n\"e\"\"f\"\tg\r\n\" i\"\t\r\n",
+		},
+		{
+			name: "quote all",
+			dialect: permissivecsv.Dialect{
+				Delimiter: ';',
+				Quoting:   permissivecsv.QuoteAll,
+			},
+			expOutput: "\"a\";\"b\tc\"\n\"d\";\"\"\n\"e\"\"f\";\"g\"\n\" i\";\"\"\n",
+		},
+		{
+			name: "preserve quoting",
+			dialect: permissivecsv.Dialect{
+				Delimiter: '|',
+				Quoting:   permissivecsv.QuotePreserve,
+			},
+			expOutput: "a|\"b\tc\"\nd|\n\"e\"\"f\"|g\n\" i\"|\"\"\n",
+		},
+		{
+			name:    "quote delimiter",
+			dialect: permissivecsv.Dialect{Delimiter: '"'},
+			expErr:  permissivecsv.ErrInvalidDialect,
+		},
+		{
+			name:    "newline delimiter",
+			dialect: permissivecsv.Dialect{Delimiter: '\n'},
+			expErr:  permissivecsv.ErrInvalidDialect,
+		},
+		{
+			name:    "invalid delimiter",
+			dialect: permissivecsv.Dialect{Delimiter: -1},
+			expErr:  permissivecsv.ErrInvalidDialect,
+		},
+		{
+			name:    "invalid quoting",
+			dialect: permissivecsv.Dialect{Quoting: permissivecsv.QuotingStyle(99)},
+			expErr:  permissivecsv.ErrInvalidDialect,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+			buf := new(bytes.Buffer)
+			_, err := s.Rewrite(buf, test.dialect)
+			assert.Equal(t, test.expErr, err)
+			assert.Equal(t, test.expOutput, buf.String())
+		}
+		t.Run(test.name, testFn)
+	}
+}
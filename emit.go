@@ -0,0 +1,234 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Sink publishes messages to a destination such as a message queue or stream.
+// Publish is supplied a batch of messages (see WithEmitBatchSize), and should
+// only return nil once every message in the batch has been accepted by the
+// destination. A batch for which Publish returns an error is retried in its
+// entirety (see WithEmitRetry), so destinations should tolerate duplicates.
+type Sink interface {
+	Publish(ctx context.Context, messages [][]byte) error
+}
+
+// SinkFunc is an adapter that allows an ordinary function (such as one that
+// calls a Kafka producer) to be used as a Sink.
+type SinkFunc func(ctx context.Context, messages [][]byte) error
+
+// Publish calls f(ctx, messages).
+func (f SinkFunc) Publish(ctx context.Context, messages [][]byte) error {
+	return f(ctx, messages)
+}
+
+// MessageFormat determines how Emit encodes each record as a message.
+type MessageFormat int
+
+const (
+	// MessageJSON encodes each record as JSON. If the input has a header (see
+	// RecordIsHeader), each record is encoded as an object keyed by column
+	// name (see CurrentRecordMap), and the header itself is not emitted.
+	// Otherwise, each record is encoded as an array of strings.
+	MessageJSON MessageFormat = iota
+
+	// MessageDelimited encodes each record as a single delimited line, in the
+	// dialect supplied to WithEmitDialect, without a terminator.
+	MessageDelimited
+)
+
+// EmitOption configures the behavior of Emit.
+type EmitOption func(*emitOptions)
+
+type emitOptions struct {
+	format      MessageFormat
+	dialect     Dialect
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+	skipHeader  bool
+}
+
+// WithEmitFormat sets the format of the messages produced by Emit. The default
+// is MessageJSON.
+func WithEmitFormat(format MessageFormat) EmitOption {
+	return func(o *emitOptions) {
+		o.format = format
+	}
+}
+
+// WithEmitDialect sets the dialect of MessageDelimited messages. The dialect's
+// Terminator is ignored. The default is comma delimited with minimal quoting.
+func WithEmitDialect(dialect Dialect) EmitOption {
+	return func(o *emitOptions) {
+		o.dialect = dialect
+	}
+}
+
+// WithEmitBatchSize sets the number of messages that Emit supplies to each
+// call to Publish. The final batch may be smaller. The default is 100. A value
+// of zero or less leaves the default in place.
+func WithEmitBatchSize(n int) EmitOption {
+	return func(o *emitOptions) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithEmitRetry sets how many times Emit attempts to publish each batch before
+// giving up, and how long it waits between attempts. The wait doubles after
+// each failed attempt. The default is 3 attempts with a one second backoff.
+func WithEmitRetry(maxAttempts int, backoff time.Duration) EmitOption {
+	return func(o *emitOptions) {
+		if maxAttempts < 1 {
+			maxAttempts = 1
+		}
+		o.maxAttempts = maxAttempts
+		o.backoff = backoff
+	}
+}
+
+// WithEmitSkipHeader instructs Emit to omit the first record if RecordIsHeader
+// reports that it is a header. The header is always omitted from MessageJSON
+// messages.
+func WithEmitSkipHeader() EmitOption {
+	return func(o *emitOptions) {
+		o.skipHeader = true
+	}
+}
+
+// EmitError is returned by Emit if a batch could not be published. FirstRecord
+// and LastRecord are the ordinals (see RecordInfo) of the first and last
+// records in the batch, and Err is the error returned by the final attempt to
+// publish it. Records before FirstRecord were published successfully.
+type EmitError struct {
+	FirstRecord int
+	LastRecord  int
+	Err         error
+}
+
+func (e *EmitError) Error() string {
+	return fmt.Sprintf("publishing records %d to %d: %v", e.FirstRecord, e.LastRecord, e.Err)
+}
+
+// Unwrap returns e.Err.
+func (e *EmitError) Unwrap() error {
+	return e.Err
+}
+
+// Emit scans the remainder of the input, and publishes each (possibly
+// altered) record to sink as a message, in batches. This is useful for
+// streaming messy CSV files into message queues.
+//
+// If a batch cannot be published, Emit stops scanning and returns an
+// EmitError. If ctx is canceled while waiting to retry a batch, ctx.Err() is
+// returned. Otherwise, Emit returns any error returned by the underlaying
+// reader (which is also available via the summary). In every case, the
+// summary describes the records that were scanned.
+func (s *Scanner) Emit(ctx context.Context, sink Sink, opts ...EmitOption) (*ScanSummary, error) {
+	o := emitOptions{
+		batchSize:   100,
+		maxAttempts: 3,
+		backoff:     time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	dialect := o.dialect
+	dialect.Terminator = "\n"
+	encoder, err := newRecordEncoder(dialect)
+	if err != nil {
+		return s.Summary(), err
+	}
+
+	var (
+		batch       [][]byte
+		firstRecord int
+		lastRecord  int
+		buf         bytes.Buffer
+	)
+	bw := bufio.NewWriter(&buf)
+	publish := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := publishBatch(ctx, sink, batch, o)
+		if err != nil {
+			if err == ctx.Err() {
+				return err
+			}
+			return &EmitError{FirstRecord: firstRecord, LastRecord: lastRecord, Err: err}
+		}
+		batch = nil
+		return nil
+	}
+
+	for s.Scan() {
+		isHeader := s.recordsScanned == 1 && s.RecordIsHeader()
+		if isHeader && (o.skipHeader || o.format == MessageJSON) {
+			continue
+		}
+
+		var message []byte
+		switch o.format {
+		case MessageDelimited:
+			buf.Reset()
+			encoder.write(bw, s.currentRecord, nil)
+			bw.Flush()
+			message = append([]byte{}, buf.Bytes()[:buf.Len()-len(dialect.Terminator)]...)
+		default:
+			var value interface{} = s.currentRecord
+			if m := s.CurrentRecordMap(); m != nil {
+				value = m
+			}
+			message, err = json.Marshal(value)
+			if err != nil {
+				return s.Summary(), err
+			}
+		}
+
+		if len(batch) == 0 {
+			firstRecord = s.scanSummary.RecordCount
+		}
+		lastRecord = s.scanSummary.RecordCount
+		batch = append(batch, message)
+		if len(batch) >= o.batchSize {
+			if err := publish(); err != nil {
+				return s.Summary(), err
+			}
+		}
+	}
+	if err := publish(); err != nil {
+		return s.Summary(), err
+	}
+	summary := s.Summary()
+	return summary, summary.Err
+}
+
+// publishBatch publishes batch to sink, retrying as configured by o.
+func publishBatch(ctx context.Context, sink Sink, batch [][]byte, o emitOptions) error {
+	var err error
+	wait := o.backoff
+	for attempt := 1; attempt <= o.maxAttempts; attempt++ {
+		err = sink.Publish(ctx, batch)
+		if err == nil {
+			return nil
+		}
+		if attempt == o.maxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	return err
+}
@@ -0,0 +1,161 @@
+package permissivecsv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSink records the batches it is asked to publish, and fails the
+// first failures calls.
+type recordingSink struct {
+	batches  [][]string
+	calls    int
+	failures int
+}
+
+func (r *recordingSink) Publish(ctx context.Context, messages [][]byte) error {
+	r.calls++
+	if r.calls <= r.failures {
+		return fmt.Errorf("publish failed")
+	}
+	batch := []string{}
+	for _, message := range messages {
+		batch = append(batch, string(message))
+	}
+	r.batches = append(r.batches, batch)
+	return nil
+}
+
+func Test_Emit(t *testing.T) {
+	data := "id,name\n1,alice\n2\n3,\"carol, jr\""
+	tests := []struct {
+		name        string
+		headerCheck permissivecsv.HeaderCheck
+		opts        []permissivecsv.EmitOption
+		expBatches  [][]string
+	}{
+		{
+			name:        "json with header",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expBatches: [][]string{
+				[]string{
+					`{"id":"1","name":"alice"}`,
+					`{"id":"2","name":""}`,
+					`{"id":"3","name":"carol, jr"}`,
+				},
+			},
+		},
+		{
+			name:        "json without header",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			opts:        []permissivecsv.EmitOption{permissivecsv.WithEmitBatchSize(3)},
+			expBatches: [][]string{
+				[]string{
+					`["id","name"]`,
+					`["1","alice"]`,
+					`["2",""]`,
+				},
+				[]string{
+					`["3","carol, jr"]`,
+				},
+			},
+		},
+		{
+			name:        "delimited",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.EmitOption{
+				permissivecsv.WithEmitFormat(permissivecsv.MessageDelimited),
+				permissivecsv.WithEmitBatchSize(2),
+			},
+			expBatches: [][]string{
+				[]string{"id,name", "1,alice"},
+				[]string{"2,", "3,\"carol, jr\""},
+			},
+		},
+		{
+			name:        "delimited in a dialect without the header",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.EmitOption{
+				permissivecsv.WithEmitFormat(permissivecsv.MessageDelimited),
+				permissivecsv.WithEmitDialect(permissivecsv.Dialect{Delimiter: '|', Terminator: "\r\n", Quoting: permissivecsv.QuoteAll}),
+				permissivecsv.WithEmitSkipHeader(),
+				permissivecsv.WithEmitBatchSize(0),
+			},
+			expBatches: [][]string{
+				[]string{`"1"|"alice"`, `"2"|""`, `"3"|"carol, jr"`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			sink := &recordingSink{}
+			s := permissivecsv.NewScanner(strings.NewReader(data), test.headerCheck)
+			summary, err := s.Emit(context.Background(), sink, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expBatches, sink.batches)
+			assert.True(t, summary.EOF)
+			assert.Equal(t, 1, summary.AlterationCount)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_EmitRetry(t *testing.T) {
+	data := "a\nb\nc"
+	sink := &recordingSink{failures: 2}
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	_, err := s.Emit(context.Background(), sink,
+		permissivecsv.WithEmitBatchSize(2),
+		permissivecsv.WithEmitRetry(3, time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{[]string{`["a"]`, `["b"]`}, []string{`["c"]`}}, sink.batches)
+	assert.Equal(t, 4, sink.calls)
+
+	sink = &recordingSink{failures: 3}
+	s = permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	summary, err := s.Emit(context.Background(), sink,
+		permissivecsv.WithEmitBatchSize(2),
+		permissivecsv.WithEmitRetry(2, time.Millisecond))
+	assert.Equal(t, &permissivecsv.EmitError{
+		FirstRecord: 1,
+		LastRecord:  2,
+		Err:         fmt.Errorf("publish failed"),
+	}, err)
+	assert.Equal(t, "publishing records 1 to 2: publish failed", err.Error())
+	assert.Equal(t, 2, sink.calls)
+	assert.Equal(t, 2, summary.RecordCount, "scanning stops when a batch fails")
+
+	sink = &recordingSink{failures: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s = permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	_, err = s.Emit(ctx, sink, permissivecsv.WithEmitRetry(3, time.Hour))
+	assert.Equal(t, context.Canceled, err)
+}
+
+func Test_EmitErrors(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a"), permissivecsv.HeaderCheckAssumeNoHeader)
+	_, err := s.Emit(context.Background(), &recordingSink{},
+		permissivecsv.WithEmitDialect(permissivecsv.Dialect{Delimiter: '"'}))
+	assert.Equal(t, permissivecsv.ErrInvalidDialect, err)
+
+	sink := &recordingSink{}
+	s = permissivecsv.NewScanner(BadReader(strings.NewReader("a\nb")), permissivecsv.HeaderCheckAssumeNoHeader)
+	_, err = s.Emit(context.Background(), sink)
+	assert.True(t, errors.Is(err, ErrReader), "got %v", err)
+
+	publishErr := fmt.Errorf("unavailable")
+	s = permissivecsv.NewScanner(strings.NewReader("a"), permissivecsv.HeaderCheckAssumeNoHeader)
+	_, err = s.Emit(context.Background(), permissivecsv.SinkFunc(func(ctx context.Context, messages [][]byte) error {
+		return publishErr
+	}), permissivecsv.WithEmitRetry(0, 0))
+	assert.True(t, errors.Is(err, publishErr))
+}
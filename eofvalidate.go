@@ -0,0 +1,63 @@
+package permissivecsv
+
+import "fmt"
+
+// EOFValidationError is reported by ScanSummary.Err if the function supplied
+// to WithOnEOFValidate rejects the input. Err is the error it returned.
+type EOFValidationError struct {
+	Err error
+}
+
+func (e *EOFValidationError) Error() string {
+	return fmt.Sprintf("EOF validation failed: %v", e.Err)
+}
+
+// Unwrap returns the error returned by the validation function.
+func (e *EOFValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrCallbackFailed.
+func (e *EOFValidationError) Is(target error) bool {
+	return target == ErrCallbackFailed
+}
+
+// WithOnEOFValidate instructs the Scanner to call validate once the end of
+// the input is reached, with a copy of the last record returned by Scan (or
+// nil, if no records were returned) and the summary, which reports EOF. This
+// allows invariants that can only be checked once the whole file has been
+// read to be enforced before the scan is considered successful, such as a
+// trailer record holding a checksum or the number of records, or a record
+// count declared in the header.
+//
+// If validate returns an error, the summary's Err is an *EOFValidationError
+// that wraps it, EOF is false, and the Scanner's State is
+// ScannerStateErrored. validate is also called by Validate, with the same
+// last record, so that Validate reports the same summary as Scan. validate is
+// not called if scanning stops for any other reason, nor by BuildIndex or
+// ScanRaw.
+func WithOnEOFValidate(validate func(lastRecord []string, sum *ScanSummary) error) Option {
+	return func(o *options) {
+		o.onEOFValidate = validate
+	}
+}
+
+// validateEOF calls the function supplied to WithOnEOFValidate, if any, and
+// stops scanning with an error if it rejects the input.
+func (s *Scanner) validateEOF() {
+	if s.opts.onEOFValidate == nil || s.scanningRaw {
+		return
+	}
+	lastRecord := s.CopyCurrentRecord()
+	if lastRecord == nil && s.validating && s.currentRawFields != "" {
+		// the last record was not split into fields (see isWellFormed).
+		lastRecord, _ = parseFields(s.currentRawFields, ',', false)
+	}
+	if len(lastRecord) == 0 {
+		lastRecord = nil
+	}
+	err := s.callEOFValidate(lastRecord, s.scanSummary)
+	if err != nil {
+		s.abort(&EOFValidationError{Err: err})
+	}
+}
@@ -0,0 +1,146 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+var errTrailerMismatch = errors.New("trailer mismatch")
+
+// trailerCount expects the last record to be a trailer of the form
+// TRAILER,<number of data records>.
+func trailerCount(lastRecord []string, sum *permissivecsv.ScanSummary) error {
+	if len(lastRecord) < 2 || lastRecord[0] != "TRAILER" {
+		return errTrailerMismatch
+	}
+	n, err := strconv.Atoi(lastRecord[1])
+	if err != nil || n != sum.RecordCount-2 {
+		return errTrailerMismatch
+	}
+	return nil
+}
+
+func Test_WithOnEOFValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		expRecords int
+		expEOF     bool
+		expState   permissivecsv.ScannerState
+		expErr     error
+	}{
+		{
+			name:       "valid trailer",
+			data:       "id,name\n1,a\n2,b\nTRAILER,2\n",
+			expRecords: 4,
+			expEOF:     true,
+			expState:   permissivecsv.ScannerStateEOF,
+			expErr:     nil,
+		},
+		{
+			name:       "wrong count",
+			data:       "id,name\n1,a\nTRAILER,2\n",
+			expRecords: 3,
+			expEOF:     false,
+			expState:   permissivecsv.ScannerStateErrored,
+			expErr:     errTrailerMismatch,
+		},
+		{
+			name:       "missing trailer",
+			data:       "id,name\n1,a\n2,b",
+			expRecords: 3,
+			expEOF:     false,
+			expState:   permissivecsv.ScannerStateErrored,
+			expErr:     errTrailerMismatch,
+		},
+		{
+			name:       "empty input",
+			data:       "",
+			expRecords: 0,
+			expEOF:     false,
+			expState:   permissivecsv.ScannerStateErrored,
+			expErr:     errTrailerMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists,
+				permissivecsv.WithOnEOFValidate(trailerCount))
+			records := 0
+			for s.Scan() {
+				records++
+			}
+			summary := s.Summary()
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expEOF, summary.EOF)
+			assert.Equal(t, test.expState, s.State())
+			if test.expErr == nil {
+				assert.NoError(t, summary.Err)
+				return
+			}
+			var validationErr *permissivecsv.EOFValidationError
+			assert.True(t, errors.As(summary.Err, &validationErr))
+			assert.True(t, errors.Is(summary.Err, test.expErr))
+			assert.Equal(t, "EOF validation failed: trailer mismatch", summary.Err.Error())
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithOnEOFValidateArguments(t *testing.T) {
+	var lastRecords [][]string
+	var eof []bool
+	validate := func(lastRecord []string, sum *permissivecsv.ScanSummary) error {
+		lastRecords = append(lastRecords, lastRecord)
+		eof = append(eof, sum.EOF)
+		return nil
+	}
+
+	for _, data := range []string{"a,b\nc,d\n\n", ""} {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists,
+			permissivecsv.WithOnEOFValidate(validate))
+		for s.Scan() {
+		}
+	}
+	s := permissivecsv.NewScanner(BadReader(strings.NewReader("a,b")), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithOnEOFValidate(validate))
+	for s.Scan() {
+	}
+	s = permissivecsv.NewScanner(strings.NewReader("a\nb\nc"), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithOnEOFValidate(validate), permissivecsv.WithMaxRecords(2))
+	for s.Scan() {
+	}
+
+	assert.Equal(t, [][]string{{"c", "d"}, nil}, lastRecords)
+	assert.Equal(t, []bool{true, true}, eof)
+}
+
+func Test_WithOnEOFValidateValidate(t *testing.T) {
+	for _, data := range []string{"id,name\n1,a\nTRAILER,1\n", "id,name\n1,a\nTRAILER,2\n", "id,name\n"} {
+		var scanned, validated []string
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists,
+			permissivecsv.WithOnEOFValidate(func(lastRecord []string, sum *permissivecsv.ScanSummary) error {
+				scanned = lastRecord
+				return trailerCount(lastRecord, sum)
+			}))
+		for s.Scan() {
+		}
+		expected := s.Summary()
+
+		s = permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists,
+			permissivecsv.WithOnEOFValidate(func(lastRecord []string, sum *permissivecsv.ScanSummary) error {
+				validated = lastRecord
+				return trailerCount(lastRecord, sum)
+			}))
+		summary := s.Validate()
+		assert.Equal(t, scanned, validated)
+		assert.Equal(t, expected.EOF, summary.EOF)
+		assert.Equal(t, expected.Err, summary.Err)
+	}
+}
@@ -0,0 +1,93 @@
+package permissivecsv
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrReadFailed is matched (see errors.Is) by a summary's Err if the
+	// underlaying reader returned an error (see ReadError).
+	ErrReadFailed = fmt.Errorf("read failed")
+
+	// ErrScanLimited is matched by a summary's Err if scanning stopped before
+	// the end of the input because of a limit, a deadline, or Close (see
+	// ErrLimitReached, ErrDeadlineExceeded, and ErrClosed).
+	ErrScanLimited = fmt.Errorf("scan stopped early")
+
+	// ErrLimitReached is reported by a summary's Err if scanning stopped
+	// because a limit set by WithMaxRecords or WithMaxBytes was reached.
+	ErrLimitReached = fmt.Errorf("%w: limit reached", ErrScanLimited)
+
+	// ErrDeadlineExceeded is reported by a summary's Err if scanning stopped
+	// because the deadline set by WithDeadline or WithTimeout passed.
+	ErrDeadlineExceeded = fmt.Errorf("%w: deadline exceeded", ErrScanLimited)
+
+	// ErrClosed is reported by a summary's Err if scanning stopped because
+	// Close was called before Scan returned false.
+	ErrClosed = fmt.Errorf("%w: scanner closed", ErrScanLimited)
+
+	// ErrCallbackFailed is matched by a summary's Err if a function supplied
+	// to the Scanner panicked (see CallbackPanicError), or rejected the input
+	// (see EOFValidationError).
+	ErrCallbackFailed = fmt.Errorf("callback failed")
+)
+
+// ReadError is reported by a summary's Err if the underlaying reader returned
+// an error, or if a record was too long to be read (see RecordTooLongError).
+// Err is the error that stopped the read. ReadError does not alter the
+// message of Err, and both errors.Is(err, ErrReadFailed) and errors.Is(err,
+// Err) report true.
+type ReadError struct {
+	Err error
+}
+
+func (e *ReadError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error that stopped the read.
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrReadFailed.
+func (e *ReadError) Is(target error) bool {
+	return target == ErrReadFailed
+}
+
+// addErr reports err via the summary. Errors are accumulated, so a summary
+// that records more than one error reports them all, joined by errors.Join.
+func (s *ScanSummary) addErr(err error) {
+	s.Err = joinErrs(s.Err, err)
+}
+
+// joinErrs joins a and b, returning whichever is non-nil unaltered if the
+// other is nil.
+func joinErrs(a, b error) error {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return errors.Join(a, b)
+}
+
+// ReadFailed reports whether Err includes an error returned by the
+// underlaying reader (see ErrReadFailed).
+func (s *ScanSummary) ReadFailed() bool {
+	return errors.Is(s.Err, ErrReadFailed)
+}
+
+// Limited reports whether Err includes a limit, deadline, or Close that stopped
+// scanning early (see ErrScanLimited).
+func (s *ScanSummary) Limited() bool {
+	return errors.Is(s.Err, ErrScanLimited)
+}
+
+// CallbackFailed reports whether Err includes the failure of a function
+// supplied to the Scanner (see ErrCallbackFailed).
+func (s *ScanSummary) CallbackFailed() bool {
+	return errors.Is(s.Err, ErrCallbackFailed)
+}
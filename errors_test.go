@@ -0,0 +1,87 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SummaryErrAggregation(t *testing.T) {
+	tests := []struct {
+		name              string
+		opts              []permissivecsv.Option
+		expReadFailed     bool
+		expLimited        bool
+		expCallbackFailed bool
+	}{
+		{
+			name: "no error",
+			opts: []permissivecsv.Option{},
+		},
+		{
+			name:       "limit",
+			opts:       []permissivecsv.Option{permissivecsv.WithMaxRecords(1)},
+			expLimited: true,
+		},
+		{
+			name: "limit and callback",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithMaxRecords(1),
+				permissivecsv.WithCheckpointEvery(5, func(permissivecsv.Checkpoint, *permissivecsv.ScanSummary) {
+					panic("boom")
+				}),
+			},
+			expLimited:        true,
+			expCallbackFailed: true,
+		},
+		{
+			name: "EOF validation",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithOnEOFValidate(func([]string, *permissivecsv.ScanSummary) error {
+					return errors.New("bad trailer")
+				}),
+			},
+			expCallbackFailed: true,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader("a\nb\nc"), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			for s.Scan() {
+			}
+			summary := s.Summary()
+			assert.Equal(t, test.expReadFailed, summary.ReadFailed())
+			assert.Equal(t, test.expLimited, summary.Limited())
+			assert.Equal(t, test.expCallbackFailed, summary.CallbackFailed())
+			assert.Equal(t, test.expLimited, errors.Is(summary.Err, permissivecsv.ErrLimitReached))
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_SummaryErrReadError(t *testing.T) {
+	s := permissivecsv.NewScanner(BadReader(strings.NewReader("a\nb")), permissivecsv.HeaderCheckAssumeNoHeader)
+	for s.Scan() {
+	}
+	summary := s.Summary()
+	assert.True(t, summary.ReadFailed())
+	assert.False(t, summary.Limited())
+	assert.True(t, errors.Is(summary.Err, ErrReader))
+	assert.Equal(t, ErrReader.Error(), summary.Err.Error())
+	var readErr *permissivecsv.ReadError
+	assert.True(t, errors.As(summary.Err, &readErr))
+}
+
+func Test_SummaryErrMerge(t *testing.T) {
+	a := &permissivecsv.ScanSummary{Err: permissivecsv.ErrDeadlineExceeded}
+	b := &permissivecsv.ScanSummary{Err: &permissivecsv.ReadError{Err: ErrReader}}
+	a.Merge(b)
+	assert.True(t, a.Limited())
+	assert.True(t, a.ReadFailed())
+	assert.True(t, errors.Is(a.Err, permissivecsv.ErrDeadlineExceeded))
+	assert.False(t, errors.Is(a.Err, permissivecsv.ErrLimitReached))
+}
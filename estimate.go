@@ -0,0 +1,222 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/eltorocorp/permissivecsv/linesplit"
+)
+
+var (
+	// ErrScanStarted is returned by methods that must be called before the
+	// first call to Scan.
+	ErrScanStarted = fmt.Errorf("scanning has already started")
+
+	// ErrSizeUnknown is returned by EstimateRecordCount if the size of the
+	// input can not be determined, and the sample did not cover the entire
+	// input.
+	ErrSizeUnknown = fmt.Errorf("input size is unknown")
+)
+
+// RecordCountEstimate is an estimate of the number of records in a file.
+//
+// Records is the estimated number of records. Low and High bound the estimate
+// with roughly 95% confidence, based on the variance of the record sizes
+// observed in the sample. If Exact is true, the sample covered the entire file
+// and Records is the actual number of records (as are Low and High).
+//
+// SampledRecords and SampledBytes describe the sample the estimate was based
+// on, and TotalBytes is the size of the input.
+type RecordCountEstimate struct {
+	Records        int64
+	Low            int64
+	High           int64
+	Exact          bool
+	SampledRecords int64
+	SampledBytes   int64
+	TotalBytes     int64
+}
+
+// EstimateRecordCount estimates the number of records in the input without
+// scanning the entire input. Up to sampleBytes bytes are read from the top of
+// the input. If the underlaying reader implements io.Seeker, up to sampleBytes
+// bytes are also read from the bottom of the input, and the reader is
+// returned to its original position afterward. The average size of the
+// records observed in the sample is then used to extrapolate the total number
+// of records.
+//
+// If the reader does not implement io.Seeker, the sampled bytes are retained
+// and replayed by subsequent calls to Scan, so no data is lost. However, the
+// size of such an input can only be determined if the sample covers the entire
+// input; otherwise ErrSizeUnknown is returned.
+//
+// Tail samples begin at an arbitrary byte, so the first (partial) record of the
+// tail sample is discarded. If the tail sample begins inside a quoted field,
+// record boundaries within the tail sample may be misidentified, which will
+// widen the confidence bounds of the estimate.
+//
+// EstimateRecordCount must be called before the first call to Scan. If Scan
+// has already been called, ErrScanStarted is returned.
+func (s *Scanner) EstimateRecordCount(sampleBytes int64) (*RecordCountEstimate, error) {
+	if s.reader == nil {
+		return nil, ErrReaderIsNil
+	}
+	if s.scanSummary != nil {
+		return nil, ErrScanStarted
+	}
+	if sampleBytes <= 0 {
+		sampleBytes = bufio.MaxScanTokenSize
+	}
+
+	seeker, seekable := s.reader.(io.Seeker)
+	if !seekable {
+		head, err := ioutil.ReadAll(io.LimitReader(s.reader, sampleBytes+1))
+		if err != nil {
+			return nil, err
+		}
+		s.reader = io.MultiReader(bytes.NewReader(head), s.reader)
+		s.scanner = s.newInternalScanner(s.reader)
+		if int64(len(head)) <= sampleBytes {
+			return exactEstimate(head), nil
+		}
+		return nil, ErrSizeUnknown
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	total := end - start
+	defer seeker.Seek(start, io.SeekStart)
+
+	_, err = seeker.Seek(start, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	if total <= sampleBytes {
+		data, err := ioutil.ReadAll(s.reader)
+		if err != nil {
+			return nil, err
+		}
+		return exactEstimate(data), nil
+	}
+
+	head, err := ioutil.ReadAll(io.LimitReader(s.reader, sampleBytes))
+	if err != nil {
+		return nil, err
+	}
+	sizes := sampleRecordSizes(head, false, false)
+
+	tailStart := end - sampleBytes
+	if tailStart > start+sampleBytes {
+		_, err = seeker.Seek(tailStart, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+		tail, err := ioutil.ReadAll(s.reader)
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, sampleRecordSizes(tail, true, true)...)
+	}
+
+	return extrapolate(sizes, total), nil
+}
+
+func exactEstimate(data []byte) *RecordCountEstimate {
+	sizes := sampleRecordSizes(data, true, false)
+	n := int64(len(sizes))
+	return &RecordCountEstimate{
+		Records:        n,
+		Low:            n,
+		High:           n,
+		Exact:          true,
+		SampledRecords: n,
+		SampledBytes:   int64(len(data)),
+		TotalBytes:     int64(len(data)),
+	}
+}
+
+// extrapolate estimates the number of records in total bytes, given a sample
+// of record sizes.
+func extrapolate(sizes []int64, total int64) *RecordCountEstimate {
+	estimate := &RecordCountEstimate{
+		SampledRecords: int64(len(sizes)),
+		TotalBytes:     total,
+		High:           total,
+	}
+	for _, size := range sizes {
+		estimate.SampledBytes += size
+	}
+	if len(sizes) == 0 {
+		// Not a single complete record was observed, so the file contains at
+		// most one record per sample length.
+		return estimate
+	}
+
+	n := float64(len(sizes))
+	mean := float64(estimate.SampledBytes) / n
+	variance := 0.0
+	for _, size := range sizes {
+		variance += (float64(size) - mean) * (float64(size) - mean)
+	}
+	if len(sizes) > 1 {
+		variance /= n - 1
+	}
+	margin := 1.96 * math.Sqrt(variance/n)
+
+	estimate.Records = int64(math.Round(float64(total) / mean))
+	estimate.Low = int64(math.Floor(float64(total) / (mean + margin)))
+	if mean-margin >= 1 {
+		estimate.High = int64(math.Ceil(float64(total) / (mean - margin)))
+	}
+	if estimate.Low < estimate.SampledRecords {
+		estimate.Low = estimate.SampledRecords
+	}
+	return estimate
+}
+
+// sampleRecordSizes returns the size in bytes of each non-empty record in data.
+// Bytes that belong to empty records (superfluous terminators) are attributed
+// to the following record. If atEOF is false, a final record that has no
+// terminator is assumed to be incomplete and is ignored. If skipFirst is true,
+// the first record is ignored, as it may be incomplete.
+func sampleRecordSizes(data []byte, atEOF, skipFirst bool) []int64 {
+	splitter := new(linesplit.Splitter)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(data)+1)
+	scanner.Split(splitter.Split)
+
+	sizes := []int64{}
+	unclaimed := int64(0)
+	first := true
+	for scanner.Scan() {
+		token := scanner.Bytes()
+		terminator := splitter.CurrentTerminator()
+		if first && skipFirst {
+			first = false
+			continue
+		}
+		if len(token) == 0 {
+			continue
+		}
+		if bytes.Equal(token, terminator) {
+			unclaimed += int64(len(token))
+			continue
+		}
+		if len(terminator) == 0 && !atEOF {
+			break
+		}
+		sizes = append(sizes, int64(len(token))+unclaimed)
+		unclaimed = 0
+	}
+	return sizes
+}
@@ -0,0 +1,127 @@
+package permissivecsv_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+// nonSeeker hides any Seek method of the underlaying reader.
+type nonSeeker struct {
+	r io.Reader
+}
+
+func (n *nonSeeker) Read(p []byte) (int, error) { return n.r.Read(p) }
+
+func Test_EstimateRecordCount(t *testing.T) {
+	uniform := strings.Repeat("aaaa,bbbb\n", 1000)
+	varied := strings.Repeat("a,b\naaaaaaaaaa,bbbbbbbbbb\n", 500)
+
+	tests := []struct {
+		name        string
+		reader      io.Reader
+		sampleBytes int64
+		expEstimate *permissivecsv.RecordCountEstimate
+		expErr      error
+		expScanned  int
+	}{
+		{
+			name:        "nil reader",
+			reader:      nil,
+			sampleBytes: 100,
+			expErr:      permissivecsv.ErrReaderIsNil,
+		},
+		{
+			name:        "sample covers file",
+			reader:      strings.NewReader("a,b\n\n\nc,d\ne,f"),
+			sampleBytes: 100,
+			expEstimate: &permissivecsv.RecordCountEstimate{
+				Records:        3,
+				Low:            3,
+				High:           3,
+				Exact:          true,
+				SampledRecords: 3,
+				SampledBytes:   13,
+				TotalBytes:     13,
+			},
+			expScanned: 3,
+		},
+		{
+			name:        "uniform records",
+			reader:      strings.NewReader(uniform),
+			sampleBytes: 500,
+			expEstimate: &permissivecsv.RecordCountEstimate{
+				Records:        1000,
+				Low:            1000,
+				High:           1000,
+				SampledRecords: 99,
+				SampledBytes:   990,
+				TotalBytes:     10000,
+			},
+			expScanned: 1000,
+		},
+		{
+			name:        "varied records",
+			reader:      strings.NewReader(varied),
+			sampleBytes: 520,
+			expEstimate: &permissivecsv.RecordCountEstimate{
+				Records:        991,
+				Low:            860,
+				High:           1170,
+				SampledRecords: 79,
+				SampledBytes:   1036,
+				TotalBytes:     13000,
+			},
+			expScanned: 1000,
+		},
+		{
+			name:        "non-seekable sample covers file",
+			reader:      &nonSeeker{strings.NewReader("a,b\nc,d\ne,f")},
+			sampleBytes: 100,
+			expEstimate: &permissivecsv.RecordCountEstimate{
+				Records:        3,
+				Low:            3,
+				High:           3,
+				Exact:          true,
+				SampledRecords: 3,
+				SampledBytes:   11,
+				TotalBytes:     11,
+			},
+			expScanned: 3,
+		},
+		{
+			name:        "non-seekable size unknown",
+			reader:      &nonSeeker{strings.NewReader(uniform)},
+			sampleBytes: 500,
+			expErr:      permissivecsv.ErrSizeUnknown,
+			expScanned:  1000,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(test.reader, permissivecsv.HeaderCheckAssumeNoHeader)
+			estimate, err := s.EstimateRecordCount(test.sampleBytes)
+			assert.Equal(t, test.expErr, err)
+			assert.Equal(t, test.expEstimate, estimate)
+
+			// Estimating must not consume any of the input.
+			scanned := 0
+			for s.Scan() {
+				scanned++
+			}
+			assert.Equal(t, test.expScanned, scanned, "records scanned after estimate")
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_EstimateRecordCountAfterScan(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a\nb"), permissivecsv.HeaderCheckAssumeNoHeader)
+	s.Scan()
+	_, err := s.EstimateRecordCount(100)
+	assert.Equal(t, permissivecsv.ErrScanStarted, err)
+}
@@ -3,6 +3,7 @@ package permissivecsv_test
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/eltorocorp/permissivecsv"
@@ -46,7 +47,10 @@ func ExampleScanner_Partition() {
 	s := permissivecsv.NewScanner(data, permissivecsv.HeaderCheckAssumeHeaderExists)
 	recordsPerPartition := 2
 	excludeHeader := true
-	partitions := s.Partition(recordsPerPartition, excludeHeader)
+	partitions, err := s.Partition(recordsPerPartition, excludeHeader)
+	if err != nil {
+		panic(err)
+	}
 
 	// serializing to JSON just to prettify the output.
 	segmentJSON, _ := json.MarshalIndent(partitions, "", "  ")
@@ -56,12 +60,18 @@ func ExampleScanner_Partition() {
 	//   {
 	//     "Ordinal": 1,
 	//     "LowerOffset": 6,
-	//     "Length": 12
+	//     "Length": 12,
+	//     "Checksum": 535999018,
+	//     "SkippedEmptyRecords": 0,
+	//     "RecordCount": 2
 	//   },
 	//   {
 	//     "Ordinal": 2,
 	//     "LowerOffset": 18,
-	//     "Length": 6
+	//     "Length": 6,
+	//     "Checksum": 3457476196,
+	//     "SkippedEmptyRecords": 0,
+	//     "RecordCount": 1
 	//   }
 	// ]
 }
@@ -90,22 +100,33 @@ func ExampleScanner_RecordIsHeader_assumeNoHeader() {
 
 // This example demonstrates implementing custom header detection logic.
 // The example shows how to properly check for nil conditions, and how the first
-// record of a file can be evaluated when making a determination about if
-// the first record is a header. This is a fairly trivial example of header
-// detection. Review the HeaderCheck docs for a full list of implementation
-// considerations.
+// and second records of a file can be compared when making a determination
+// about if the first record is a header. This is a fairly trivial example of
+// header detection. Review the HeaderCheck docs for a full list of
+// implementation considerations.
 func ExampleScanner_RecordIsHeader_customDetection() {
-	headerCheck := func(firstRecord []string) bool {
+	headerCheck := func(firstRecord, secondRecord []string) bool {
 		// firstRecord will be nil if Scan has not been called, if the file is
 		// empty, or the Scanner has advanced beyond the first record.
 		if firstRecord == nil {
 			return false
 		}
 
-		return firstRecord[0] == "a"
+		// secondRecord will be nil if the file only contains one record.
+		if secondRecord == nil {
+			return firstRecord[0] == "name"
+		}
+
+		// A first record without numbers followed by a record with numbers
+		// is likely a header.
+		_, err := strconv.Atoi(firstRecord[1])
+		firstIsNumeric := err == nil
+		_, err = strconv.Atoi(secondRecord[1])
+		secondIsNumeric := err == nil
+		return !firstIsNumeric && secondIsNumeric
 	}
 
-	data := strings.NewReader("a,b,c\nd,e,f")
+	data := strings.NewReader("name,age\nalice,30")
 	s := permissivecsv.NewScanner(data, headerCheck)
 	for s.Scan() {
 		fmt.Println(s.RecordIsHeader())
@@ -0,0 +1,76 @@
+package permissivecsv
+
+// FieldChange describes a field that differs between a record as it appeared
+// in the input and the record that the Scanner returned (see
+// Alteration.FieldChanges). Index is the index of the field within the
+// resulting record, and OriginalIndex is its index within the original record.
+// Original and Resulting are the field's values. A field that was added (such
+// as by padding) has an OriginalIndex of -1, and a field that was removed (such
+// as by truncation, or by merging it into another field) has an Index of -1.
+type FieldChange struct {
+	Index         int
+	OriginalIndex int
+	Original      string
+	Resulting     string
+}
+
+// diffFields returns the changes that transform original into resulting. The
+// fields that the records have in common are found by a longest common
+// subsequence, so that fields shifted by the removal or addition of a leading
+// field are not reported as changed. Within each run of differing fields,
+// removed and added fields are paired up as changed fields.
+func diffFields(original, resulting []string) []FieldChange {
+	// lengths[i][j] is the length of the longest common subsequence of
+	// original[i:] and resulting[j:].
+	lengths := make([][]int, len(original)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(resulting)+1)
+	}
+	for i := len(original) - 1; i >= 0; i-- {
+		for j := len(resulting) - 1; j >= 0; j-- {
+			switch {
+			case original[i] == resulting[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	changes := []FieldChange{}
+	var removed, added []int
+	flush := func() {
+		for k := 0; k < len(removed) || k < len(added); k++ {
+			change := FieldChange{Index: -1, OriginalIndex: -1}
+			if k < len(removed) {
+				change.OriginalIndex = removed[k]
+				change.Original = original[removed[k]]
+			}
+			if k < len(added) {
+				change.Index = added[k]
+				change.Resulting = resulting[added[k]]
+			}
+			changes = append(changes, change)
+		}
+		removed, added = removed[:0], added[:0]
+	}
+	i, j := 0, 0
+	for i < len(original) || j < len(resulting) {
+		switch {
+		case i < len(original) && j < len(resulting) && original[i] == resulting[j]:
+			flush()
+			i++
+			j++
+		case j == len(resulting) || i < len(original) && lengths[i+1][j] >= lengths[i][j+1]:
+			removed = append(removed, i)
+			i++
+		default:
+			added = append(added, j)
+			j++
+		}
+	}
+	flush()
+	return changes
+}
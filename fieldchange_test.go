@@ -0,0 +1,94 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FieldChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		opts       []permissivecsv.Option
+		expChanges []permissivecsv.FieldChange
+	}{
+		{
+			name: "trailing fields truncated",
+			data: "a,b\n1,2,3,4",
+			expChanges: []permissivecsv.FieldChange{
+				{Index: -1, OriginalIndex: 2, Original: "3"},
+				{Index: -1, OriginalIndex: 3, Original: "4"},
+			},
+		},
+		{
+			name: "leading field truncated",
+			data: "a,b,c\n0,1,2,3",
+			opts: []permissivecsv.Option{permissivecsv.WithTruncation(permissivecsv.RecordEndLeading)},
+			expChanges: []permissivecsv.FieldChange{
+				{Index: -1, OriginalIndex: 0, Original: "0"},
+			},
+		},
+		{
+			name: "leading field padded",
+			data: "a,b,c\n2,3",
+			opts: []permissivecsv.Option{permissivecsv.WithPadding(permissivecsv.RecordEndLeading)},
+			expChanges: []permissivecsv.FieldChange{
+				{Index: 0, OriginalIndex: -1},
+			},
+		},
+		{
+			name: "unparsable record",
+			data: "a,b\n1,x\"y",
+			expChanges: []permissivecsv.FieldChange{
+				{Index: 0, OriginalIndex: 0, Original: "1"},
+				{Index: 1, OriginalIndex: 1, Original: "x\"y"},
+			},
+		},
+		{
+			name:       "best effort record",
+			data:       "a,b\n1,x\"y",
+			opts:       []permissivecsv.Option{permissivecsv.WithLazyQuoteRetry()},
+			expChanges: []permissivecsv.FieldChange{},
+		},
+		{
+			name:       "switched delimiter",
+			data:       "a,b,c\n1;2;3",
+			opts:       []permissivecsv.Option{permissivecsv.WithAlternateDelimiters(';')},
+			expChanges: []permissivecsv.FieldChange{},
+		},
+		{
+			name: "changed and added fields are paired",
+			data: "a,b,c\n1,x y",
+			opts: []permissivecsv.Option{permissivecsv.WithRepairStrategies(
+				func(raw string, parsed []string, expected int) ([]string, permissivecsv.AlterationKind, bool) {
+					return append([]string{parsed[0]}, strings.Fields(parsed[1])...), permissivecsv.AlterationPaddedRecord, true
+				})},
+			expChanges: []permissivecsv.FieldChange{
+				{Index: 1, OriginalIndex: 1, Original: "x y", Resulting: "x"},
+				{Index: 2, OriginalIndex: -1, Resulting: "y"},
+			},
+		},
+		{
+			name:       "redacted record",
+			data:       "a,b\n1",
+			opts:       []permissivecsv.Option{permissivecsv.WithRedaction([]string{"a"}, strings.ToUpper)},
+			expChanges: nil,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists, test.opts...)
+			for s.Scan() {
+			}
+			alterations := s.Summary().Alterations
+			if assert.Len(t, alterations, 1) {
+				assert.Equal(t, test.expChanges, alterations[0].FieldChanges)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
@@ -0,0 +1,106 @@
+package permissivecsv
+
+import (
+	"strings"
+
+	"github.com/eltorocorp/permissivecsv/internal/util"
+)
+
+// FieldState describes how a field of the current record was represented in
+// the input.
+type FieldState int
+
+const (
+	// FieldBare is a field that was present in the input and was not quoted.
+	// An empty bare field (such as the middle field of "a,,b") is FieldBare.
+	FieldBare FieldState = iota
+
+	// FieldQuoted is a field that was present in the input and was enclosed in
+	// double quotes. An explicitly quoted empty field ("") is FieldQuoted.
+	FieldQuoted
+
+	// FieldMissing is a field that was not present in the input. Fields that
+	// were added when padding a short record, fields of an empty record, and
+	// fields whose data was discarded due to an ambiguous quote are all
+	// FieldMissing.
+	FieldMissing
+)
+
+func (f FieldState) String() string {
+	switch f {
+	case FieldBare:
+		return "bare"
+	case FieldQuoted:
+		return "quoted"
+	case FieldMissing:
+		return "missing"
+	default:
+		return "unknown"
+	}
+}
+
+// CurrentFieldStates returns the state of each field of the most recent record
+// generated by a call to Scan. The returned slice is parallel to
+// CurrentRecord, which allows consumers to distinguish between a field that
+// was explicitly empty ("") and one that was absent and padded, and map these
+// to an empty string and NULL respectively.
+//
+// If the current record was repaired by a RepairStrategy, field states are
+// reported by position within the input, except that merged fields (see
+// RepairMergeSplitFields) are accounted for. Likewise, fields removed or added
+// at the beginning of the record (see WithTruncation and WithPadding) are
+// accounted for.
+//
+// CurrentFieldStates returns nil if Scan has not been called, or if there is
+// no current record.
+func (s *Scanner) CurrentFieldStates() []FieldState {
+	if s.currentRecord == nil {
+		return nil
+	}
+	parsed := make([]FieldState, s.currentParsedFieldCount)
+	markQuotedFields(s.currentRawFields, s.currentDelimiter, parsed)
+	if s.currentMergedField >= 0 {
+		parsed = append(parsed[:s.currentMergedField+1], parsed[s.currentMergedField+2:]...)
+	}
+	states := make([]FieldState, len(s.currentRecord))
+	for i := range states {
+		states[i] = FieldMissing
+		if j := i + s.currentFieldShift; j >= 0 && j < len(parsed) {
+			states[i] = parsed[j]
+		}
+	}
+	return states
+}
+
+// markQuotedFields sets states[i] to FieldQuoted for each field of raw
+// (separated by delimiter) that begins with a double quote. The delimiter may
+// be a multi-byte rune, whose UTF-8 encoding never contains a quote byte.
+func markQuotedFields(raw string, delimiter rune, states []FieldState) {
+	if len(states) == 0 {
+		return
+	}
+	delim := string(delimiter)
+	field := 0
+	atFieldStart := true
+	inQuotes := false
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if atFieldStart {
+			if c == util.QuoteChar {
+				states[field] = FieldQuoted
+			}
+			atFieldStart = false
+		}
+		switch {
+		case c == util.QuoteChar:
+			inQuotes = !inQuotes
+		case !inQuotes && c == delim[0] && strings.HasPrefix(raw[i:], delim):
+			i += len(delim) - 1
+			field++
+			if field >= len(states) {
+				return
+			}
+			atFieldStart = true
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CurrentFieldStates(t *testing.T) {
+	const (
+		bare    = permissivecsv.FieldBare
+		quoted  = permissivecsv.FieldQuoted
+		missing = permissivecsv.FieldMissing
+	)
+	tests := []struct {
+		name      string
+		data      string
+		opts      []permissivecsv.Option
+		expStates [][]permissivecsv.FieldState
+	}{
+		{
+			name: "quoted empty vs padded",
+			data: "a,\"\",c\n\"\",d",
+			expStates: [][]permissivecsv.FieldState{
+				[]permissivecsv.FieldState{bare, quoted, bare},
+				[]permissivecsv.FieldState{quoted, bare, missing},
+			},
+		},
+		{
+			name: "bare empty fields",
+			data: "a,,\n,b,",
+			expStates: [][]permissivecsv.FieldState{
+				[]permissivecsv.FieldState{bare, bare, bare},
+				[]permissivecsv.FieldState{bare, bare, bare},
+			},
+		},
+		{
+			name: "quoted field containing delimiter and terminator",
+			data: "\"a,\nb\",c\nd,\"e\"\"f\"",
+			expStates: [][]permissivecsv.FieldState{
+				[]permissivecsv.FieldState{quoted, bare},
+				[]permissivecsv.FieldState{bare, quoted},
+			},
+		},
+		{
+			name: "truncated record",
+			data: "a,b\n\"c\",d,\"e\"",
+			expStates: [][]permissivecsv.FieldState{
+				[]permissivecsv.FieldState{bare, bare},
+				[]permissivecsv.FieldState{quoted, bare},
+			},
+		},
+		{
+			name: "ambiguous quote",
+			data: "a,b\nc,d\"d",
+			expStates: [][]permissivecsv.FieldState{
+				[]permissivecsv.FieldState{bare, bare},
+				[]permissivecsv.FieldState{missing, missing},
+			},
+		},
+		{
+			name: "alternate delimiter",
+			data: "a,b,c\n\"d,e\";\"f\";g",
+			opts: []permissivecsv.Option{permissivecsv.WithAlternateDelimiters(';')},
+			expStates: [][]permissivecsv.FieldState{
+				[]permissivecsv.FieldState{bare, bare, bare},
+				[]permissivecsv.FieldState{quoted, quoted, bare},
+			},
+		},
+		{
+			name: "kept empty record",
+			data: "a,b\n\nc,d",
+			opts: []permissivecsv.Option{permissivecsv.WithKeepEmptyRecords()},
+			expStates: [][]permissivecsv.FieldState{
+				[]permissivecsv.FieldState{bare, bare},
+				[]permissivecsv.FieldState{missing, missing},
+				[]permissivecsv.FieldState{bare, bare},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			assert.Nil(t, s.CurrentFieldStates())
+			result := [][]permissivecsv.FieldState{}
+			for s.Scan() {
+				states := s.CurrentFieldStates()
+				assert.Len(t, states, len(s.CurrentRecord()))
+				result = append(result, states)
+			}
+			assert.Equal(t, test.expStates, result)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_FieldStateString(t *testing.T) {
+	assert.Equal(t, "bare", permissivecsv.FieldBare.String())
+	assert.Equal(t, "quoted", permissivecsv.FieldQuoted.String())
+	assert.Equal(t, "missing", permissivecsv.FieldMissing.String())
+	assert.Equal(t, "unknown", permissivecsv.FieldState(99).String())
+}
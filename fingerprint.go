@@ -0,0 +1,32 @@
+package permissivecsv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// HeaderFingerprint returns a stable hash of the column names of header, as a
+// hex encoded SHA-256 digest. The names are normalized with
+// NormalizeColumnNames first, so cosmetic differences (such as case,
+// punctuation, surrounding whitespace, or a byte order mark) do not change the
+// fingerprint, while added, removed, renamed, or reordered columns do.
+// Comparing the fingerprints of successive files from the same provider is a
+// cheap way to detect that their schema has changed.
+func HeaderFingerprint(header []string) string {
+	// Normalized names contain only letters, digits, and underscores, so
+	// joining them with commas is unambiguous.
+	sum := sha256.Sum256([]byte(strings.Join(NormalizeColumnNames(header), ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// HeaderFingerprint returns the HeaderFingerprint of the header (see
+// RecordIsHeader). It returns an empty string if Scan has not been called, or
+// if no header was identified.
+func (s *Scanner) HeaderFingerprint() string {
+	s.resolveHeader()
+	if s.header == nil {
+		return ""
+	}
+	return HeaderFingerprint(s.header)
+}
@@ -0,0 +1,86 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HeaderFingerprint(t *testing.T) {
+	base := permissivecsv.HeaderFingerprint([]string{"id", "first_name", "email"})
+	assert.Len(t, base, 64)
+
+	tests := []struct {
+		name     string
+		header   []string
+		expEqual bool
+	}{
+		{
+			name:     "identical",
+			header:   []string{"id", "first_name", "email"},
+			expEqual: true,
+		},
+		{
+			name:     "cosmetic differences",
+			header:   []string{"\ufeffID", " First Name ", "EMAIL"},
+			expEqual: true,
+		},
+		{
+			name:     "reordered",
+			header:   []string{"first_name", "id", "email"},
+			expEqual: false,
+		},
+		{
+			name:     "renamed",
+			header:   []string{"id", "given_name", "email"},
+			expEqual: false,
+		},
+		{
+			name:     "added",
+			header:   []string{"id", "first_name", "email", "phone"},
+			expEqual: false,
+		},
+		{
+			name:     "removed",
+			header:   []string{"id", "first_name"},
+			expEqual: false,
+		},
+		{
+			name:     "fields merged",
+			header:   []string{"id", "first_name,email"},
+			expEqual: false,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			result := permissivecsv.HeaderFingerprint(test.header)
+			assert.Equal(t, test.expEqual, result == base)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_ScannerHeaderFingerprint(t *testing.T) {
+	data := "Id,First Name\n1,alice\n"
+
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists)
+	assert.Equal(t, "", s.HeaderFingerprint(), "no fingerprint before Scan")
+	assert.True(t, s.Scan())
+	exp := permissivecsv.HeaderFingerprint([]string{"id", "first_name"})
+	assert.Equal(t, exp, s.HeaderFingerprint())
+	for s.Scan() {
+	}
+	assert.Equal(t, exp, s.HeaderFingerprint())
+
+	s = permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithNormalizedHeader())
+	s.Scan()
+	assert.Equal(t, exp, s.HeaderFingerprint())
+
+	s = permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	s.Scan()
+	assert.Equal(t, "", s.HeaderFingerprint())
+}
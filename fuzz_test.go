@@ -0,0 +1,83 @@
+//go:build go1.18
+// +build go1.18
+
+package permissivecsv_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+)
+
+// FuzzScan asserts invariants that must hold for any input:
+//   - Scan and Partition never panic.
+//   - Every record has the same number of fields as the first record.
+//   - Alterations are reported in increasing byte offset order.
+//   - Segments are contiguous, ordered, and never extend past the input.
+//
+// The fuzzer is seeded with the integration test corpus.
+//
+//	go test -run=^$ -fuzz=FuzzScan
+func FuzzScan(f *testing.F) {
+	seeds, err := filepath.Glob(filepath.Join(testFileLocation, "*.csv"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, seed := range seeds {
+		data, err := ioutil.ReadFile(seed)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(data))
+	}
+
+	f.Fuzz(func(t *testing.T, data string) {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+		width := -1
+		for s.Scan() {
+			record := s.CurrentRecord()
+			if width == -1 {
+				width = len(record)
+			}
+			if len(record) != width {
+				t.Fatalf("record %d has %d fields, expected %d", s.Summary().RecordCount, len(record), width)
+			}
+		}
+
+		previousOffset := int64(-1)
+		for _, alteration := range s.Summary().Alterations {
+			if alteration.ByteOffset <= previousOffset {
+				t.Fatalf("alteration offset %d does not follow %d", alteration.ByteOffset, previousOffset)
+			}
+			previousOffset = alteration.ByteOffset
+		}
+
+		s = permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+		segments, _ := s.Partition(2, false)
+		nextOffset := int64(0)
+		for i, segment := range segments {
+			if segment.Ordinal != int64(i+1) {
+				t.Fatalf("segment %d has ordinal %d", i+1, segment.Ordinal)
+			}
+			if segment.LowerOffset != nextOffset {
+				t.Fatalf("segment %d starts at %d, expected %d", segment.Ordinal, segment.LowerOffset, nextOffset)
+			}
+			if segment.Length <= 0 {
+				t.Fatalf("segment %d has length %d", segment.Ordinal, segment.Length)
+			}
+			if segment.LowerOffset+segment.Length <= int64(len(data)) {
+				err := segment.Verify(strings.NewReader(data[segment.LowerOffset:]))
+				if err != nil {
+					t.Fatalf("segment %d: %v", segment.Ordinal, err)
+				}
+			}
+			nextOffset += segment.Length
+		}
+		if nextOffset > int64(len(data)) {
+			t.Fatalf("segments cover %d bytes, but input is %d bytes", nextOffset, len(data))
+		}
+	})
+}
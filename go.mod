@@ -1,5 +1,7 @@
 module github.com/eltorocorp/permissivecsv
 
+go 1.20
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/go-test/deep v1.0.1
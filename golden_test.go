@@ -0,0 +1,93 @@
+package permissivecsv_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files with current results")
+
+const goldenFileLocation = "integrationtestdata/golden"
+
+// goldenResult is the serialized form of everything the Scanner reports about
+// a file. Any change to the Scanner's behavior for a corpus file shows up as a
+// diff against the file's golden result.
+type goldenResult struct {
+	Records         [][]string
+	RecordCount     int
+	AlterationCount int
+	Alterations     []*permissivecsv.Alteration
+	EOF             bool
+	Err             string
+	Partitions      []*permissivecsv.Segment
+}
+
+// Test_Golden scans every file in the integration corpus and compares the
+// results to the corresponding golden file. To accept intentional behavior
+// changes, run:
+//
+//	go test -run Test_Golden -update
+func Test_Golden(t *testing.T) {
+	corpus, err := filepath.Glob(filepath.Join(testFileLocation, "*.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range corpus {
+		name := filepath.Base(path)
+		testFn := func(t *testing.T) {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := goldenResult{Records: [][]string{}}
+			s := permissivecsv.NewScanner(bytes.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+			for s.Scan() {
+				result.Records = append(result.Records, s.CurrentRecord())
+			}
+			summary := s.Summary()
+			result.RecordCount = summary.RecordCount
+			result.AlterationCount = summary.AlterationCount
+			result.Alterations = summary.Alterations
+			result.EOF = summary.EOF
+			if summary.Err != nil {
+				result.Err = summary.Err.Error()
+			}
+			s = permissivecsv.NewScanner(bytes.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+			// any error stopping the scan is already reported by result.Err.
+			result.Partitions, _ = s.Partition(2, false)
+
+			actual, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual = append(actual, '\n')
+
+			goldenPath := filepath.Join(goldenFileLocation, strings.TrimSuffix(name, ".csv")+".json")
+			if *updateGolden {
+				err = ioutil.WriteFile(goldenPath, actual, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			expected, err := ioutil.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("unable to read golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(expected, actual) {
+				t.Errorf("result does not match %s\nexpected:\n%s\nactual:\n%s", goldenPath, expected, actual)
+			}
+		}
+		t.Run(name, testFn)
+	}
+}
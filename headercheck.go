@@ -0,0 +1,110 @@
+package permissivecsv
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HeaderCheckNoNumericFields reports that the first record is a header if none
+// of its fields are numeric, and at least one of its fields is not blank. This
+// suits files that have at least one numeric column, since column names are
+// rarely numbers.
+var HeaderCheckNoNumericFields HeaderCheck = func(firstRecord, secondRecord []string) bool {
+	if firstRecord == nil {
+		return false
+	}
+	hasText := false
+	for _, field := range firstRecord {
+		switch classifyField(field) {
+		case fieldClassNumeric:
+			return false
+		case fieldClassText:
+			hasText = true
+		}
+	}
+	return hasText
+}
+
+// HeaderCheckDistinctFromSecondRecord reports that the first record is a header
+// if it looks like a set of column names, and looks different from the record
+// that follows it. Specifically, every field of the first record must be
+// non-blank, non-numeric, and unique, and at least one column must be numeric
+// or blank in the second record. If there is no second record,
+// HeaderCheckDistinctFromSecondRecord returns false.
+var HeaderCheckDistinctFromSecondRecord HeaderCheck = func(firstRecord, secondRecord []string) bool {
+	if firstRecord == nil || secondRecord == nil {
+		return false
+	}
+	seen := make(map[string]bool, len(firstRecord))
+	for _, field := range firstRecord {
+		if classifyField(field) != fieldClassText {
+			return false
+		}
+		name := strings.TrimSpace(field)
+		if seen[name] {
+			return false
+		}
+		seen[name] = true
+	}
+	for i, field := range secondRecord {
+		if i >= len(firstRecord) {
+			break
+		}
+		if classifyField(field) != fieldClassText {
+			return true
+		}
+	}
+	return false
+}
+
+// HeaderCheckMatches returns a HeaderCheck that reports that the first record
+// is a header if each of the supplied patterns matches at least one field of
+// the first record. For instance,
+// HeaderCheckMatches(regexp.MustCompile(`(?i)^email$`)) identifies a header in
+// any file that has a column named "email" (in any case). If no patterns are
+// supplied, the HeaderCheck always returns false.
+func HeaderCheckMatches(patterns ...*regexp.Regexp) HeaderCheck {
+	return func(firstRecord, secondRecord []string) bool {
+		if firstRecord == nil || len(patterns) == 0 {
+			return false
+		}
+		for _, pattern := range patterns {
+			matched := false
+			for _, field := range firstRecord {
+				if pattern.MatchString(field) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+type fieldClass int
+
+const (
+	fieldClassBlank fieldClass = iota
+	fieldClassNumeric
+	fieldClassText
+)
+
+// classifyField reports whether field is blank, numeric, or text. Leading and
+// trailing whitespace is ignored.
+func classifyField(field string) fieldClass {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return fieldClassBlank
+	}
+	// ParseFloat also accepts values such as "Inf" and "NaN", which are
+	// more likely to be text in the context of a CSV.
+	_, err := strconv.ParseFloat(field, 64)
+	if err == nil && strings.ContainsAny(field, "0123456789") {
+		return fieldClassNumeric
+	}
+	return fieldClassText
+}
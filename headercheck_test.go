@@ -0,0 +1,126 @@
+package permissivecsv_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuiltInHeaderChecks(t *testing.T) {
+	tests := []struct {
+		name         string
+		headerCheck  permissivecsv.HeaderCheck
+		firstRecord  []string
+		secondRecord []string
+		expResult    bool
+	}{
+		{
+			name:         "no numeric fields: header",
+			headerCheck:  permissivecsv.HeaderCheckNoNumericFields,
+			firstRecord:  []string{"name", "age", ""},
+			secondRecord: []string{"alice", "30", ""},
+			expResult:    true,
+		},
+		{
+			name:        "no numeric fields: numeric field",
+			headerCheck: permissivecsv.HeaderCheckNoNumericFields,
+			firstRecord: []string{"alice", " 3.5e2 "},
+			expResult:   false,
+		},
+		{
+			name:        "no numeric fields: special float values are text",
+			headerCheck: permissivecsv.HeaderCheckNoNumericFields,
+			firstRecord: []string{"inf", "NaN"},
+			expResult:   true,
+		},
+		{
+			name:        "no numeric fields: blank record",
+			headerCheck: permissivecsv.HeaderCheckNoNumericFields,
+			firstRecord: []string{"", " "},
+			expResult:   false,
+		},
+		{
+			name:        "no numeric fields: nil",
+			headerCheck: permissivecsv.HeaderCheckNoNumericFields,
+			firstRecord: nil,
+			expResult:   false,
+		},
+		{
+			name:         "distinct: header",
+			headerCheck:  permissivecsv.HeaderCheckDistinctFromSecondRecord,
+			firstRecord:  []string{"name", "age"},
+			secondRecord: []string{"alice", "30"},
+			expResult:    true,
+		},
+		{
+			name:         "distinct: blank column in second record",
+			headerCheck:  permissivecsv.HeaderCheckDistinctFromSecondRecord,
+			firstRecord:  []string{"name", "nickname"},
+			secondRecord: []string{"alice", ""},
+			expResult:    true,
+		},
+		{
+			name:         "distinct: both records are text",
+			headerCheck:  permissivecsv.HeaderCheckDistinctFromSecondRecord,
+			firstRecord:  []string{"alice", "smith"},
+			secondRecord: []string{"bob", "jones"},
+			expResult:    false,
+		},
+		{
+			name:         "distinct: duplicate names",
+			headerCheck:  permissivecsv.HeaderCheckDistinctFromSecondRecord,
+			firstRecord:  []string{"x", " x"},
+			secondRecord: []string{"1", "2"},
+			expResult:    false,
+		},
+		{
+			name:         "distinct: blank name",
+			headerCheck:  permissivecsv.HeaderCheckDistinctFromSecondRecord,
+			firstRecord:  []string{"name", ""},
+			secondRecord: []string{"alice", "30"},
+			expResult:    false,
+		},
+		{
+			name:         "distinct: no second record",
+			headerCheck:  permissivecsv.HeaderCheckDistinctFromSecondRecord,
+			firstRecord:  []string{"name", "age"},
+			secondRecord: nil,
+			expResult:    false,
+		},
+		{
+			name:         "matches: all patterns match",
+			headerCheck:  permissivecsv.HeaderCheckMatches(regexp.MustCompile(`(?i)^email$`), regexp.MustCompile(`^zip`)),
+			firstRecord:  []string{"zipcode", "EMAIL"},
+			secondRecord: []string{"27601", "a@b.c"},
+			expResult:    true,
+		},
+		{
+			name:        "matches: one pattern does not match",
+			headerCheck: permissivecsv.HeaderCheckMatches(regexp.MustCompile(`(?i)^email$`), regexp.MustCompile(`^zip`)),
+			firstRecord: []string{"postal", "email"},
+			expResult:   false,
+		},
+		{
+			name:        "matches: no patterns",
+			headerCheck: permissivecsv.HeaderCheckMatches(),
+			firstRecord: []string{"email"},
+			expResult:   false,
+		},
+		{
+			name:        "matches: nil",
+			headerCheck: permissivecsv.HeaderCheckMatches(regexp.MustCompile(`.*`)),
+			firstRecord: nil,
+			expResult:   false,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			result := test.headerCheck(test.firstRecord, test.secondRecord)
+			assert.Equal(t, test.expResult, result)
+		}
+		t.Run(test.name, testFn)
+	}
+}
@@ -0,0 +1,44 @@
+package permissivecsv
+
+// WithSkipLoneHeader instructs Scan not to return the first record if it is
+// identified as a header (see RecordIsHeader), and no records follow it. Thus,
+// an input that contains only a header returns zero records, as if it were
+// empty, and ScanSummary.HeaderOnly reports that the header was found. By
+// default, the header is returned like any other first record, and callers
+// that treat every record other than the header as data must check
+// RecordIsHeader to avoid treating a lone header as data.
+//
+// Like a dropped record (see WithFieldCountTolerance), the skipped header is
+// included in RecordCount. If Partition excludes the header, the header
+// (along with any terminators that follow it) is still available via
+// HeaderSegment.
+func WithSkipLoneHeader() Option {
+	return func(o *options) {
+		o.skipLoneHeader = true
+	}
+}
+
+// skipLoneHeader skips the current record if it is a header that no records
+// follow (see WithSkipLoneHeader), and reports whether it did so. The
+// record's bytes are left unclaimed, so that Partition includes them in the
+// header's segment.
+func (s *Scanner) skipLoneHeader(rawRecord string) bool {
+	if !s.opts.skipLoneHeader || s.recordsScanned != 1 || !s.RecordIsHeader() || s.hasMoreRecords() {
+		return false
+	}
+	s.unclaimBytes([]byte(rawRecord))
+	s.currentDropped = true
+	return true
+}
+
+// checkHeaderOnly records in the summary whether the input consisted of
+// nothing but a header (see ScanSummary.HeaderOnly), once the end of the input
+// has been reached. The header is identified using the HeaderCheck if it was
+// not identified while scanning.
+func (s *Scanner) checkHeaderOnly() {
+	if s.recordsScanned != 1 || len(s.leadingRecords) != 1 {
+		return
+	}
+	s.resolveHeader()
+	s.scanSummary.HeaderOnly = s.header != nil
+}
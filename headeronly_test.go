@@ -0,0 +1,119 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HeaderOnly(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		headerCheck   permissivecsv.HeaderCheck
+		opts          []permissivecsv.Option
+		expRecords    [][]string
+		expHeaderOnly bool
+		expSegments   int
+		expHeaderLen  int64
+	}{
+		{
+			name:          "header only",
+			data:          "id,name\n\n",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			expRecords:    [][]string{{"id", "name"}},
+			expHeaderOnly: true,
+			expHeaderLen:  8,
+		},
+		{
+			name:          "header only skipped",
+			data:          "id,name\n\n",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:          []permissivecsv.Option{permissivecsv.WithSkipLoneHeader()},
+			expRecords:    [][]string{},
+			expHeaderOnly: true,
+			expHeaderLen:  9,
+		},
+		{
+			name:          "header and data",
+			data:          "id,name\n1,a\n",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:          []permissivecsv.Option{permissivecsv.WithSkipLoneHeader()},
+			expRecords:    [][]string{{"id", "name"}, {"1", "a"}},
+			expHeaderOnly: false,
+			expSegments:   1,
+			expHeaderLen:  8,
+		},
+		{
+			name:          "lone record that is not a header",
+			data:          "1,a\n",
+			headerCheck:   permissivecsv.HeaderCheckAssumeNoHeader,
+			opts:          []permissivecsv.Option{permissivecsv.WithSkipLoneHeader()},
+			expRecords:    [][]string{{"1", "a"}},
+			expHeaderOnly: false,
+			expSegments:   1,
+		},
+		{
+			name:          "empty input",
+			data:          "",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:          []permissivecsv.Option{permissivecsv.WithSkipLoneHeader()},
+			expRecords:    [][]string{},
+			expHeaderOnly: false,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck, test.opts...)
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expHeaderOnly, s.Summary().HeaderOnly)
+
+			s = permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck, test.opts...)
+			segments, err := s.Partition(1, true)
+			assert.NoError(t, err)
+			assert.Len(t, segments, test.expSegments)
+			if test.expHeaderLen == 0 {
+				assert.Nil(t, s.HeaderSegment())
+			} else if assert.NotNil(t, s.HeaderSegment()) {
+				assert.Equal(t, test.expHeaderLen, s.HeaderSegment().Length)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_HeaderOnlyUnchecked(t *testing.T) {
+	// the header is identified at the end of the input, even if
+	// RecordIsHeader was never called.
+	checked := 0
+	headerCheck := func(firstRecord, secondRecord []string) bool {
+		checked++
+		return true
+	}
+	s := permissivecsv.NewScanner(strings.NewReader("id,name"), headerCheck)
+	for s.Scan() {
+	}
+	assert.True(t, s.Summary().HeaderOnly)
+	assert.Equal(t, 1, checked)
+}
+
+func Test_HeaderOnlyMerge(t *testing.T) {
+	headerOnly := &permissivecsv.ScanSummary{RecordCount: 1, HeaderOnly: true}
+	empty := &permissivecsv.ScanSummary{}
+	data := &permissivecsv.ScanSummary{RecordCount: 2}
+
+	merged := &permissivecsv.ScanSummary{}
+	merged.Merge(headerOnly)
+	merged.Merge(empty)
+	assert.True(t, merged.HeaderOnly)
+
+	merged.Merge(data)
+	assert.False(t, merged.HeaderOnly)
+}
@@ -0,0 +1,452 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrNoTable is reported via Summary().Err by a Scanner returned from
+// ScanHTMLTable if the document does not contain a table.
+var ErrNoTable = fmt.Errorf("no table found")
+
+// maxColspan limits the number of fields a single table cell can span.
+const maxColspan = 1000
+
+// ScanHTMLTable returns a Scanner that reads the rows of the first table in
+// the HTML document read from r, one record per row. This allows the "export"
+// pages that some vendors provide in place of CSV files to be scanned,
+// repaired, and summarized like any other input.
+//
+// The table is converted to CSV as it is scanned, and the rest of the document
+// is ignored. Each th or td element is a field. The text of a cell is decoded
+// and its whitespace is collapsed as a browser would display it, with br
+// elements becoming newlines; markup within the cell is ignored. A cell that
+// spans several columns (colspan) is followed by empty fields for the columns
+// it covers, while rowspan is ignored, so rows affected by it are padded or
+// truncated like any other record of the wrong length. Nested tables are
+// treated as text of the cell that contains them.
+//
+// The document does not need to be well formed; missing end tags are implied
+// as they would be by a browser. If the document contains no table, the
+// Scanner returns no records, and Summary().Err is ErrNoTable.
+func ScanHTMLTable(r io.Reader, headerCheck HeaderCheck, opts ...Option) *Scanner {
+	if r == nil {
+		return NewScanner(nil, headerCheck, opts...)
+	}
+	rows := &htmlTableRows{tokenizer: newHTMLTokenizer(r)}
+	return NewScanner(newRowReader(rows), headerCheck, opts...)
+}
+
+// htmlTableRows is a rowSource that extracts the rows of the first table in an
+// HTML document.
+type htmlTableRows struct {
+	tokenizer *htmlTokenizer
+
+	// depth is the nesting depth of tables, which is zero until the first
+	// table is found.
+	depth int
+	found bool
+	done  bool
+
+	record []string
+	inRow  bool
+	cell   *htmlCell
+}
+
+// htmlCell accumulates the text of a table cell, one line per br element.
+type htmlCell struct {
+	lines   []strings.Builder
+	colspan int
+}
+
+func (c *htmlCell) String() string {
+	lines := make([]string, len(c.lines))
+	for i := range c.lines {
+		lines[i] = strings.Join(strings.Fields(c.lines[i].String()), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *htmlTableRows) next() ([]string, error) {
+	for !r.done {
+		token, err := r.tokenizer.next()
+		if err == io.EOF {
+			r.done = true
+			if !r.found {
+				return nil, ErrNoTable
+			}
+			if record, ok := r.endRow(); ok {
+				return record, nil
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if r.depth == 0 {
+			if token.kind == htmlStartTag && token.name == "table" {
+				r.found = true
+				r.depth = 1
+			}
+			continue
+		}
+		if r.depth > 1 || token.kind == htmlText {
+			r.nested(token)
+			continue
+		}
+
+		switch {
+		case token.kind == htmlStartTag && token.name == "tr":
+			record, ok := r.endRow()
+			r.inRow = true
+			if ok {
+				return record, nil
+			}
+		case token.kind == htmlStartTag && (token.name == "td" || token.name == "th"):
+			r.endCell()
+			r.inRow = true
+			r.cell = &htmlCell{
+				lines:   make([]strings.Builder, 1),
+				colspan: token.colspan(),
+			}
+		case token.kind == htmlEndTag && (token.name == "td" || token.name == "th"):
+			r.endCell()
+		case token.kind == htmlEndTag && token.name == "tr":
+			if record, ok := r.endRow(); ok {
+				return record, nil
+			}
+		case token.kind == htmlEndTag && token.name == "table":
+			r.done = true
+			if record, ok := r.endRow(); ok {
+				return record, nil
+			}
+		case token.kind == htmlStartTag && (token.name == "thead" || token.name == "tbody" || token.name == "tfoot"),
+			token.kind == htmlEndTag && (token.name == "thead" || token.name == "tbody" || token.name == "tfoot"):
+			if record, ok := r.endRow(); ok {
+				return record, nil
+			}
+		default:
+			r.nested(token)
+		}
+	}
+	return nil, io.EOF
+}
+
+// nested handles text, and tags other than the structure of the outermost
+// table (including nested tables), which only contribute to the text of the
+// current cell.
+func (r *htmlTableRows) nested(token htmlToken) {
+	switch {
+	case token.kind == htmlStartTag && token.name == "table":
+		r.depth++
+	case token.kind == htmlEndTag && token.name == "table":
+		r.depth--
+	}
+	if r.cell == nil {
+		return
+	}
+	switch {
+	case token.kind == htmlText:
+		r.cell.lines[len(r.cell.lines)-1].WriteString(token.text)
+	case token.name == "br" && token.kind != htmlEndTag:
+		r.cell.lines = append(r.cell.lines, strings.Builder{})
+	case token.kind == htmlStartTag && htmlBlockTags[token.name],
+		token.kind == htmlEndTag && htmlBlockTags[token.name]:
+		// Block elements separate the text on either side of them.
+		r.cell.lines[len(r.cell.lines)-1].WriteByte(' ')
+	}
+}
+
+// htmlBlockTags are elements whose boundaries separate words.
+var htmlBlockTags = map[string]bool{
+	"div": true, "p": true, "li": true, "ul": true, "ol": true,
+	"table": true, "tr": true, "td": true, "th": true,
+}
+
+// endCell adds the current cell, if any, to the current record.
+func (r *htmlTableRows) endCell() {
+	if r.cell == nil {
+		return
+	}
+	r.record = append(r.record, r.cell.String())
+	for i := 1; i < r.cell.colspan; i++ {
+		r.record = append(r.record, "")
+	}
+	r.cell = nil
+}
+
+// endRow ends the current row, if any, and returns its record.
+func (r *htmlTableRows) endRow() ([]string, bool) {
+	r.endCell()
+	if !r.inRow {
+		return nil, false
+	}
+	record := r.record
+	if record == nil {
+		record = []string{}
+	}
+	r.record, r.inRow = nil, false
+	return record, true
+}
+
+type htmlTokenKind int
+
+const (
+	htmlText htmlTokenKind = iota
+	htmlStartTag
+	htmlEndTag
+)
+
+// htmlToken is a run of (unescaped) text, or a start or end tag. Tag names are
+// lower case.
+type htmlToken struct {
+	kind  htmlTokenKind
+	name  string
+	attrs map[string]string
+	text  string
+}
+
+// colspan returns the number of columns spanned by a cell.
+func (t htmlToken) colspan() int {
+	n, err := strconv.Atoi(strings.TrimSpace(t.attrs["colspan"]))
+	if err != nil || n < 1 {
+		return 1
+	}
+	if n > maxColspan {
+		return maxColspan
+	}
+	return n
+}
+
+// htmlTokenizer splits an HTML document into tokens. It is deliberately
+// lenient: anything that can't be parsed as markup is treated as text.
+// Comments, doctypes, and processing instructions are skipped, as is the
+// content of script and style elements.
+type htmlTokenizer struct {
+	r *bufio.Reader
+}
+
+func newHTMLTokenizer(r io.Reader) *htmlTokenizer {
+	return &htmlTokenizer{r: bufio.NewReader(r)}
+}
+
+func (t *htmlTokenizer) next() (htmlToken, error) {
+	for {
+		b, err := t.r.ReadByte()
+		if err != nil {
+			return htmlToken{}, err
+		}
+		if b != '<' {
+			t.r.UnreadByte()
+			text, err := t.text()
+			return htmlToken{kind: htmlText, text: text}, err
+		}
+		c, err := t.r.ReadByte()
+		if err == io.EOF {
+			return htmlToken{kind: htmlText, text: "<"}, nil
+		}
+		if err != nil {
+			return htmlToken{}, err
+		}
+		switch {
+		case c == '!' || c == '?':
+			if err := t.skipMarkupDeclaration(); err != nil {
+				return htmlToken{}, err
+			}
+		case c == '/':
+			token, err := t.tag(htmlEndTag)
+			if token.name != "" || err != nil {
+				return token, err
+			}
+		case isASCIILetter(c):
+			t.r.UnreadByte()
+			token, err := t.tag(htmlStartTag)
+			if err != nil {
+				return token, err
+			}
+			if token.name == "script" || token.name == "style" {
+				if err := t.skipRawText(token.name); err != nil {
+					return htmlToken{}, err
+				}
+				continue
+			}
+			return token, nil
+		default:
+			t.r.UnreadByte()
+			return htmlToken{kind: htmlText, text: "<"}, nil
+		}
+	}
+}
+
+// text reads text up to the next tag, and unescapes it.
+func (t *htmlTokenizer) text() (string, error) {
+	raw, err := t.r.ReadString('<')
+	if err == nil {
+		t.r.UnreadByte()
+		raw = raw[:len(raw)-1]
+	} else if err == io.EOF && raw != "" {
+		err = nil
+	}
+	return html.UnescapeString(raw), err
+}
+
+// tag reads the name and attributes of a tag, through the closing >.
+func (t *htmlTokenizer) tag(kind htmlTokenKind) (htmlToken, error) {
+	token := htmlToken{kind: kind, attrs: map[string]string{}}
+	var name bytes.Buffer
+	for {
+		b, err := t.r.ReadByte()
+		if err != nil {
+			return htmlToken{}, err
+		}
+		if b == '>' {
+			token.name = strings.ToLower(name.String())
+			return token, nil
+		}
+		if isHTMLSpace(b) || b == '/' {
+			break
+		}
+		name.WriteByte(b)
+	}
+	token.name = strings.ToLower(name.String())
+	return token, t.attributes(token.attrs)
+}
+
+// attributes reads the attributes of a tag into attrs, through the closing >.
+func (t *htmlTokenizer) attributes(attrs map[string]string) error {
+	for {
+		b, err := t.skipSpace()
+		if err != nil {
+			return err
+		}
+		if b == '>' {
+			return nil
+		}
+		if b == '/' {
+			continue
+		}
+		var key bytes.Buffer
+		key.WriteByte(b)
+		for {
+			b, err = t.r.ReadByte()
+			if err != nil {
+				return err
+			}
+			if isHTMLSpace(b) || b == '=' || b == '>' || b == '/' {
+				break
+			}
+			key.WriteByte(b)
+		}
+		if isHTMLSpace(b) {
+			b, err = t.skipSpace()
+			if err != nil {
+				return err
+			}
+		}
+		name := strings.ToLower(key.String())
+		if b != '=' {
+			attrs[name] = ""
+			t.r.UnreadByte()
+			continue
+		}
+		value, err := t.attributeValue()
+		if err != nil {
+			return err
+		}
+		attrs[name] = html.UnescapeString(value)
+	}
+}
+
+// attributeValue reads a quoted or unquoted attribute value.
+func (t *htmlTokenizer) attributeValue() (string, error) {
+	b, err := t.skipSpace()
+	if err != nil {
+		return "", err
+	}
+	if b == '"' || b == '\'' {
+		value, err := t.r.ReadString(b)
+		if err != nil {
+			return "", err
+		}
+		return value[:len(value)-1], nil
+	}
+	var value bytes.Buffer
+	for !isHTMLSpace(b) && b != '>' {
+		value.WriteByte(b)
+		b, err = t.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+	}
+	t.r.UnreadByte()
+	return value.String(), nil
+}
+
+// skipSpace skips whitespace, and returns the following byte.
+func (t *htmlTokenizer) skipSpace() (byte, error) {
+	for {
+		b, err := t.r.ReadByte()
+		if err != nil || !isHTMLSpace(b) {
+			return b, err
+		}
+	}
+}
+
+// skipMarkupDeclaration skips a comment, doctype, or processing instruction,
+// the opening <! or <? of which has already been read.
+func (t *htmlTokenizer) skipMarkupDeclaration() error {
+	prefix, _ := t.r.Peek(2)
+	if string(prefix) != "--" {
+		_, err := t.r.ReadString('>')
+		return err
+	}
+	t.r.Discard(2)
+	return t.skipUntil("-->")
+}
+
+// skipRawText skips the content of a script or style element, through its end
+// tag.
+func (t *htmlTokenizer) skipRawText(name string) error {
+	for {
+		if err := t.skipUntil("</"); err != nil {
+			return err
+		}
+		tag, _ := t.r.Peek(len(name))
+		if strings.EqualFold(string(tag), name) {
+			_, err := t.r.ReadString('>')
+			return err
+		}
+	}
+}
+
+// skipUntil skips input through the next occurrence of s.
+func (t *htmlTokenizer) skipUntil(s string) error {
+	window := make([]byte, 0, len(s))
+	for {
+		b, err := t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if len(window) == len(s) {
+			window = append(window[:0], window[1:]...)
+		}
+		window = append(window, b)
+		if string(window) == s {
+			return nil
+		}
+	}
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isHTMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
@@ -0,0 +1,102 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScanHTMLTable(t *testing.T) {
+	tests := []struct {
+		name       string
+		reader     io.Reader
+		expRecords [][]string
+		expAltered int
+		expErr     error
+	}{
+		{
+			name: "well formed",
+			reader: strings.NewReader(`<!DOCTYPE html>
+<html><head><title>Export</title><style>td > b { color: red }</style></head>
+<body><p>Exported <b>today</b></p>
+<table class="export">
+  <thead><tr><th>ID</th><th>Name</th><th>Note</th></tr></thead>
+  <tbody>
+    <tr><td>1</td><td>Smith &amp; Sons</td><td>line one<br/>line   two</td></tr>
+    <tr><td>2</td><td><a href="/c?a=1&b=2">Carol,  Jr</a></td><td>&quot;quoted&quot;&nbsp;</td></tr>
+  </tbody>
+</table>
+<table><tr><td>second table</td></tr></table>
+</body></html>`),
+			expRecords: [][]string{
+				[]string{"ID", "Name", "Note"},
+				[]string{"1", "Smith & Sons", "line one\nline two"},
+				[]string{"2", "Carol, Jr", `"quoted"`},
+			},
+		},
+		{
+			name: "implied end tags and spans",
+			reader: strings.NewReader(`<TABLE BORDER=1>
+<TR><TH>a<TH>b<TH>c
+<TR><TD COLSPAN=2>wide<TD>x
+<TR><TD>1 < 2<TD>short
+<TR><TD>p<TD>q<TD>r<TD>extra
+</TABLE>`),
+			expRecords: [][]string{
+				[]string{"a", "b", "c"},
+				[]string{"wide", "", "x"},
+				[]string{"1 < 2", "short", ""},
+				[]string{"p", "q", "r"},
+			},
+			expAltered: 2,
+		},
+		{
+			name: "nested table, comments and scripts",
+			reader: strings.NewReader(`<table><!-- <tr><td>no</td></tr> --->
+<tr><td>outer<table><tr><td>inner</td><td>cell</td></tr></table></td><td>b</td></tr>
+<tr><td><script>if (a < b) { document.write("<td>") }</script>c</td><td>d</td></tr>
+</table>`),
+			expRecords: [][]string{
+				[]string{"outer inner cell", "b"},
+				[]string{"c", "d"},
+			},
+		},
+		{
+			name:       "unterminated document",
+			reader:     strings.NewReader(`<table><tr><td>a</td><td>b</td></tr><tr><td>c`),
+			expRecords: [][]string{[]string{"a", "b"}, []string{"c", ""}},
+			expAltered: 1,
+		},
+		{
+			name:       "no table",
+			reader:     strings.NewReader(`<html><body><p>Nothing to see</p></body></html>`),
+			expRecords: [][]string{},
+			expErr:     permissivecsv.ErrNoTable,
+		},
+		{
+			name:       "reader error",
+			reader:     BadReader(strings.NewReader(`<table><tr><td>a</td></tr>`)),
+			expRecords: [][]string{},
+			expErr:     ErrReader,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.ScanHTMLTable(test.reader, permissivecsv.HeaderCheckAssumeHeaderExists)
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			summary := s.Summary()
+			assert.True(t, errors.Is(summary.Err, test.expErr), "got %v", summary.Err)
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expAltered, summary.AlterationCount)
+		}
+		t.Run(test.name, testFn)
+	}
+}
@@ -0,0 +1,295 @@
+package permissivecsv
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRemoteChanged is returned in the Summary if ScanHTTP needs to resume a
+// download, but the remote file has changed since scanning began.
+var ErrRemoteChanged = fmt.Errorf("remote file changed while scanning")
+
+// HTTPError is returned if a server responds with an unexpected status code.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("GET %s: unexpected status %d %s",
+		e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// defaultHTTPClient has timeouts for each phase of establishing a connection,
+// but no overall timeout, since the time required to stream a file is
+// proportional to its size.
+var defaultHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+	},
+}
+
+// ScanHTTP returns a Scanner that streams the CSV located at url.
+//
+// The initial request is made before ScanHTTP returns, so that problems such as
+// unreachable hosts or missing files are reported immediately via the returned
+// error. Gzip content-encoding is handled transparently.
+//
+// If the connection fails or stalls part way through the file, the download is
+// resumed from where it left off using an HTTP Range request. If the server
+// supplied an ETag or Last-Modified header, the resumed request is conditioned
+// on the file being unchanged; if the file has changed, scanning stops and
+// ErrRemoteChanged is reported via Summary().Err. Since a range cannot be
+// requested within a content-encoded (such as gzip) response, such a download
+// is instead resumed by requesting the file in full with the same encoding,
+// and skipping the data that was already delivered. Errors that persist after
+// the configured number of attempts are also reported via Summary().Err.
+//
+// Canceling ctx (or closing the Scanner, see Close) abandons the download. The
+// response body is closed automatically once the end of the file is reached,
+// an error occurs, or ctx is canceled.
+//
+// See WithHTTPClient, WithHTTPRetry, and WithHTTPReadTimeout for the options
+// that control the behavior of the download. Any other Options are applied to
+// the Scanner.
+func ScanHTTP(ctx context.Context, url string, headerCheck HeaderCheck, opts ...Option) (*Scanner, error) {
+	o := newOptions(opts)
+	client := o.httpClient
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	r := &httpReader{
+		ctx:         ctx,
+		client:      client,
+		url:         url,
+		maxAttempts: o.httpMaxAttempts,
+		backoff:     o.httpRetryBackoff,
+		readTimeout: o.httpReadTimeout,
+	}
+	err := r.reconnect()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(append([]Option{}, opts...), WithOwnedReader())
+	return NewScanner(r, headerCheck, opts...), nil
+}
+
+// httpReader is an io.Reader that resumes an HTTP download if the connection
+// fails.
+type httpReader struct {
+	ctx         context.Context
+	client      *http.Client
+	url         string
+	maxAttempts int
+	backoff     time.Duration
+	readTimeout time.Duration
+
+	body   io.ReadCloser
+	cancel context.CancelFunc
+	timer  *time.Timer
+
+	// offset is the number of (decoded) bytes delivered so far.
+	offset int64
+
+	// validator is the ETag or Last-Modified value of the original response,
+	// used to ensure a resumed download refers to the same file.
+	validator string
+
+	// encoded is true if the original response was content-encoded (such as
+	// with gzip), in which case offset does not correspond to a byte range of
+	// the response, and the file is resumed by requesting it in full.
+	encoded bool
+
+	// failures counts consecutive failed reads that delivered no data.
+	failures int
+	done     bool
+}
+
+func (r *httpReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		err := r.reconnect()
+		if err != nil {
+			r.done = true
+			return 0, err
+		}
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if n > 0 {
+		r.failures = 0
+		if r.timer != nil {
+			r.timer.Reset(r.readTimeout)
+		}
+	}
+	if err == nil {
+		return n, nil
+	}
+
+	r.close()
+	if err == io.EOF {
+		r.done = true
+		return n, io.EOF
+	}
+	if r.ctx.Err() != nil {
+		r.done = true
+		return n, r.ctx.Err()
+	}
+
+	// The connection failed part way through the file. The next call to Read
+	// will resume from the current offset.
+	r.failures++
+	if r.failures >= r.maxAttempts {
+		r.done = true
+		return n, err
+	}
+	return n, nil
+}
+
+// Close abandons the download.
+func (r *httpReader) Close() error {
+	r.close()
+	r.done = true
+	return nil
+}
+
+func (r *httpReader) close() {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	if r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
+
+// responseValidator returns the ETag or Last-Modified value of resp.
+func responseValidator(resp *http.Response) string {
+	validator := resp.Header.Get("ETag")
+	if validator == "" {
+		validator = resp.Header.Get("Last-Modified")
+	}
+	return validator
+}
+
+// reconnect makes up to maxAttempts requests for the remainder of the file.
+func (r *httpReader) reconnect() error {
+	var err error
+	wait := r.backoff
+	for attempt := 1; attempt <= r.maxAttempts; attempt++ {
+		err = r.connect()
+		if err == nil || err == ErrRemoteChanged {
+			return err
+		}
+		if _, ok := err.(*HTTPError); ok {
+			return err
+		}
+		if attempt == r.maxAttempts {
+			break
+		}
+		select {
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	return err
+}
+
+// connect requests the file starting at the current offset.
+func (r *httpReader) connect() error {
+	ctx, cancel := context.WithCancel(r.ctx)
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		cancel()
+		return err
+	}
+	req = req.WithContext(ctx)
+	ranged := r.offset > 0 && !r.encoded
+	if ranged {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-")
+		req.Header.Set("Accept-Encoding", "identity")
+		if r.validator != "" {
+			req.Header.Set("If-Range", r.validator)
+		}
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	var body io.ReadCloser = resp.Body
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if resp.Header.Get("Content-Encoding") == "gzip" && !resp.Uncompressed {
+			gz, err := gzip.NewReader(resp.Body)
+			if err != nil {
+				resp.Body.Close()
+				cancel()
+				return err
+			}
+			body = &readCloser{gz, resp.Body}
+		}
+		if r.offset > 0 {
+			if r.validator != "" && (ranged || responseValidator(resp) != r.validator) {
+				// The server honored If-Range by sending the full (changed)
+				// file rather than the requested range, or the file that was
+				// requested in full has a different validator.
+				body.Close()
+				cancel()
+				return ErrRemoteChanged
+			}
+			_, err = io.CopyN(ioutil.Discard, body, r.offset)
+			if err != nil {
+				body.Close()
+				cancel()
+				return err
+			}
+		} else {
+			r.validator = responseValidator(resp)
+			r.encoded = resp.Uncompressed || resp.Header.Get("Content-Encoding") != ""
+		}
+	case resp.StatusCode == http.StatusPartialContent && ranged:
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && ranged:
+		// The connection failed after the final byte was delivered.
+		body = ioutil.NopCloser(strings.NewReader(""))
+		resp.Body.Close()
+	default:
+		resp.Body.Close()
+		cancel()
+		return &HTTPError{URL: r.url, StatusCode: resp.StatusCode}
+	}
+
+	r.body = body
+	r.cancel = cancel
+	if r.readTimeout > 0 {
+		r.timer = time.AfterFunc(r.readTimeout, cancel)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package permissivecsv_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScanHTTP(t *testing.T) {
+	const data = "a,b\nc,d\ne,f\ng,h"
+	expRecords := [][]string{
+		[]string{"a", "b"},
+		[]string{"c", "d"},
+		[]string{"e", "f"},
+		[]string{"g", "h"},
+	}
+
+	tests := []struct {
+		name       string
+		handler    func(requests int) http.HandlerFunc
+		expErr     bool
+		expRecords [][]string
+		expScanErr error
+	}{
+		{
+			name: "plain",
+			handler: func(requests int) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte(data))
+				}
+			},
+			expRecords: expRecords,
+		},
+		{
+			name: "gzip encoded",
+			handler: func(requests int) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Encoding", "gzip")
+					gz := gzip.NewWriter(w)
+					gz.Write([]byte(data))
+					gz.Close()
+				}
+			},
+			expRecords: expRecords,
+		},
+		{
+			name: "not found",
+			handler: func(requests int) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					http.NotFound(w, r)
+				}
+			},
+			expErr: true,
+		},
+		{
+			name: "resumes after dropped connection",
+			handler: func(requests int) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("ETag", `"v1"`)
+					if requests == 1 {
+						w.Header().Set("Content-Length", "15")
+						w.Write([]byte(data[:6]))
+						w.(http.Flusher).Flush()
+						conn, _, _ := w.(http.Hijacker).Hijack()
+						conn.Close()
+						return
+					}
+					http.ServeContent(w, r, "data.csv", time.Time{}, strings.NewReader(data))
+				}
+			},
+			expRecords: expRecords,
+		},
+		{
+			name: "resumes gzip encoded download",
+			handler: func(requests int) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") || r.Header.Get("Range") != "" {
+						// an identity-encoded representation has its own ETag.
+						w.Header().Set("ETag", `"v1"`)
+						http.ServeContent(w, r, "data.csv", time.Time{}, strings.NewReader(data))
+						return
+					}
+					w.Header().Set("ETag", `"v1-gzip"`)
+					w.Header().Set("Content-Encoding", "gzip")
+					gz := gzip.NewWriter(w)
+					if requests == 1 {
+						gz.Write([]byte(data[:6]))
+						gz.Flush()
+						w.(http.Flusher).Flush()
+						conn, _, _ := w.(http.Hijacker).Hijack()
+						conn.Close()
+						return
+					}
+					gz.Write([]byte(data))
+					gz.Close()
+				}
+			},
+			expRecords: expRecords,
+		},
+		{
+			name: "gzip encoded remote changed while resuming",
+			handler: func(requests int) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("ETag", `"v`+strconv.Itoa(requests)+`"`)
+					w.Header().Set("Content-Encoding", "gzip")
+					gz := gzip.NewWriter(w)
+					if requests == 1 {
+						gz.Write([]byte(data[:4]))
+						gz.Flush()
+						w.(http.Flusher).Flush()
+						conn, _, _ := w.(http.Hijacker).Hijack()
+						conn.Close()
+						return
+					}
+					gz.Write([]byte(data))
+					gz.Close()
+				}
+			},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+			},
+			expScanErr: permissivecsv.ErrRemoteChanged,
+		},
+		{
+			name: "remote changed while resuming",
+			handler: func(requests int) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					if requests == 1 {
+						w.Header().Set("ETag", `"v1"`)
+						w.Header().Set("Content-Length", "15")
+						w.Write([]byte(data[:4]))
+						w.(http.Flusher).Flush()
+						conn, _, _ := w.(http.Hijacker).Hijack()
+						conn.Close()
+						return
+					}
+					w.Header().Set("ETag", `"v2"`)
+					http.ServeContent(w, r, "data.csv", time.Time{}, strings.NewReader(data))
+				}
+			},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+			},
+			expScanErr: permissivecsv.ErrRemoteChanged,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				test.handler(requests)(w, r)
+			}))
+			defer server.Close()
+
+			s, err := permissivecsv.ScanHTTP(context.Background(), server.URL,
+				permissivecsv.HeaderCheckAssumeNoHeader,
+				permissivecsv.WithHTTPRetry(3, time.Millisecond))
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			actual := [][]string{}
+			for s.Scan() {
+				actual = append(actual, s.CurrentRecord())
+			}
+			assert.Equal(t, test.expRecords, actual)
+			assert.True(t, errors.Is(s.Summary().Err, test.expScanErr), "got %v", s.Summary().Err)
+		}
+		t.Run(test.name, testFn)
+	}
+}
@@ -0,0 +1,75 @@
+package permissivecsv
+
+import "fmt"
+
+// ErrInvalidIndexInterval is returned by BuildIndex if the supplied interval is
+// less than 1.
+var ErrInvalidIndexInterval = fmt.Errorf("index interval must be at least 1")
+
+// RecordIndex records the byte offset of every Interval'th record in a file,
+// which allows ranges of records to be read from a large static file without
+// rescanning it.
+//
+// Offsets[i] is the byte offset at which record i*Interval+1 begins (record
+// ordinals are 1 based, as in Alteration.RecordOrdinal). RecordCount is the
+// number of records in the file, and Size is the number of bytes that were
+// scanned while building the index.
+type RecordIndex struct {
+	Interval    int
+	Offsets     []int64
+	RecordCount int
+	Size        int64
+}
+
+// BuildIndex scans the input and returns a RecordIndex containing the byte
+// offset of every interval'th record. Records are identified in the same way
+// as Scan, but are not split into fields, so building an index is relatively
+// cheap. Smaller intervals allow records to be located more precisely, at the
+// cost of a larger index.
+//
+// BuildIndex must be called before the first call to Scan, otherwise
+// ErrScanStarted is returned. If the underlaying reader returns an error,
+// BuildIndex returns the index of the records that were scanned, along with
+// the error.
+func (s *Scanner) BuildIndex(interval int) (*RecordIndex, error) {
+	if interval < 1 {
+		return nil, ErrInvalidIndexInterval
+	}
+	if s.scanSummary != nil {
+		return nil, ErrScanStarted
+	}
+
+	s.scanningRaw = true
+	defer func() {
+		s.scanningRaw = false
+	}()
+	index := &RecordIndex{
+		Interval: interval,
+		Offsets:  []int64{},
+	}
+	for s.Scan() {
+		if (s.scanSummary.RecordCount-1)%interval == 0 {
+			index.Offsets = append(index.Offsets, s.recordOffset)
+		}
+	}
+	if s.scanSummary.RecordCount > 0 {
+		index.RecordCount = s.scanSummary.RecordCount
+	}
+	index.Size = s.bytesConsumed
+	return index, s.scanSummary.Err
+}
+
+// Locate returns the byte offset of the closest indexed record at or before
+// the record with the supplied (1 based) ordinal, along with the number of
+// records that must be skipped from that offset to reach the record. If the
+// index does not contain the record, Locate returns false.
+func (x *RecordIndex) Locate(ordinal int) (offset int64, skip int, ok bool) {
+	if ordinal < 1 || ordinal > x.RecordCount || x.Interval < 1 {
+		return 0, 0, false
+	}
+	i := (ordinal - 1) / x.Interval
+	if i >= len(x.Offsets) {
+		return 0, 0, false
+	}
+	return x.Offsets[i], (ordinal - 1) % x.Interval, true
+}
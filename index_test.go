@@ -0,0 +1,125 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		reader   io.Reader
+		interval int
+		expIndex *permissivecsv.RecordIndex
+		expErr   error
+	}{
+		{
+			name:     "every record",
+			reader:   strings.NewReader("a\r\n\nbb\n\"c\nc\"\n"),
+			interval: 1,
+			expIndex: &permissivecsv.RecordIndex{
+				Interval:    1,
+				Offsets:     []int64{0, 4, 7},
+				RecordCount: 3,
+				Size:        13,
+			},
+		},
+		{
+			name:     "every other record",
+			reader:   strings.NewReader("a\nb\nc\nd\ne"),
+			interval: 2,
+			expIndex: &permissivecsv.RecordIndex{
+				Interval:    2,
+				Offsets:     []int64{0, 4, 8},
+				RecordCount: 5,
+				Size:        9,
+			},
+		},
+		{
+			name:     "empty input",
+			reader:   strings.NewReader(""),
+			interval: 10,
+			expIndex: &permissivecsv.RecordIndex{
+				Interval: 10,
+				Offsets:  []int64{},
+			},
+		},
+		{
+			name:     "reader error",
+			reader:   BadReader(strings.NewReader("a")),
+			interval: 1,
+			expIndex: &permissivecsv.RecordIndex{
+				Interval: 1,
+				Offsets:  []int64{},
+			},
+			expErr: ErrReader,
+		},
+		{
+			name:     "invalid interval",
+			reader:   strings.NewReader("a"),
+			interval: 0,
+			expIndex: nil,
+			expErr:   permissivecsv.ErrInvalidIndexInterval,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(test.reader, permissivecsv.HeaderCheckAssumeNoHeader)
+			index, err := s.BuildIndex(test.interval)
+			assert.True(t, errors.Is(err, test.expErr), "got %v", err)
+			if diff := deep.Equal(test.expIndex, index); diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_BuildIndexScanStarted(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a\nb"), permissivecsv.HeaderCheckAssumeNoHeader)
+	s.Scan()
+	_, err := s.BuildIndex(1)
+	assert.Equal(t, permissivecsv.ErrScanStarted, err)
+}
+
+func Test_RecordIndexLocate(t *testing.T) {
+	data := "a\nb\nc\nd\ne"
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	index, err := s.BuildIndex(2)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		ordinal   int
+		expOffset int64
+		expSkip   int
+		expOK     bool
+	}{
+		{ordinal: 0, expOK: false},
+		{ordinal: 1, expOffset: 0, expSkip: 0, expOK: true},
+		{ordinal: 2, expOffset: 0, expSkip: 1, expOK: true},
+		{ordinal: 4, expOffset: 4, expSkip: 1, expOK: true},
+		{ordinal: 5, expOffset: 8, expSkip: 0, expOK: true},
+		{ordinal: 6, expOK: false},
+	}
+	for _, test := range tests {
+		offset, skip, ok := index.Locate(test.ordinal)
+		assert.Equal(t, test.expOffset, offset, "ordinal %d", test.ordinal)
+		assert.Equal(t, test.expSkip, skip, "ordinal %d", test.ordinal)
+		assert.Equal(t, test.expOK, ok, "ordinal %d", test.ordinal)
+	}
+
+	// a record located via the index can be read by scanning from its offset.
+	offset, skip, _ := index.Locate(4)
+	s = permissivecsv.NewScanner(strings.NewReader(data[offset:]), permissivecsv.HeaderCheckAssumeNoHeader)
+	for i := 0; i <= skip; i++ {
+		s.Scan()
+	}
+	assert.Equal(t, []string{"d"}, s.CurrentRecord())
+}
@@ -5,28 +5,62 @@ import (
 	"strings"
 )
 
-const quoteChar = 34
+// QuoteChar is the double quote character used to quote CSV fields.
+const QuoteChar = 34
 
-// IndexNonQuoted returns the index of the first non-quoted occurrence of
-// substr in s.
+// IndexNonQuoted returns the byte index of the first non-quoted occurrence of
+// substr in s, or -1 if there is no such occurrence.
+//
+// A quoted section begins and ends with a double quote. Within a quoted
+// section, a doubled quote ("") is an escaped quote, and does not end the
+// section. If a quoted section is never closed, the remainder of s is
+// considered to be quoted. Since UTF-8 never uses the quote byte within a
+// multi-byte rune, s is examined byte by byte, and the result is consistent
+// with the byte offsets used by strings.Index.
 func IndexNonQuoted(s, substr string) int {
 	// important performance path: only do an in depth check if s contains
 	// quote characters, otherwise, just return the first occurence of substr.
-	if !bytes.ContainsRune([]byte(s), quoteChar) {
+	if strings.IndexByte(s, QuoteChar) == -1 {
 		return strings.Index(s, substr)
 	}
 
-	quoteCount := 0
-	for i, c := range s {
-		if i+len(substr) > len(s) {
-			break
+	inQuotes := false
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i] == QuoteChar {
+			if inQuotes && i+1 < len(s) && s[i+1] == QuoteChar {
+				// an escaped quote; skip the second quote of the pair.
+				i++
+				continue
+			}
+			inQuotes = !inQuotes
+			continue
 		}
-
-		if c == quoteChar {
-			quoteCount++
+		if !inQuotes && strings.HasPrefix(s[i:], substr) {
+			return i
 		}
+	}
 
-		if quoteCount%2 == 0 && s[i:i+len(substr)] == substr {
+	return -1
+}
+
+// IndexNonQuotedBytes is like IndexNonQuoted, but operates on byte slices, so
+// that callers holding a buffer do not need to convert it to a string.
+func IndexNonQuotedBytes(s, substr []byte) int {
+	if bytes.IndexByte(s, QuoteChar) == -1 {
+		return bytes.Index(s, substr)
+	}
+
+	inQuotes := false
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i] == QuoteChar {
+			if inQuotes && i+1 < len(s) && s[i+1] == QuoteChar {
+				i++
+				continue
+			}
+			inQuotes = !inQuotes
+			continue
+		}
+		if !inQuotes && bytes.HasPrefix(s[i:], substr) {
 			return i
 		}
 	}
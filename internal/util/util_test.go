@@ -90,12 +90,50 @@ func Test_IndexNonQuoted(t *testing.T) {
 			substr:        "\n",
 			expectedIndex: -1,
 		},
+		{
+			name:          "escaped quote does not end quoted section",
+			s:             "\"a\"\",b\",c",
+			substr:        ",",
+			expectedIndex: 7,
+		},
+		{
+			name:          "escaped quote is not matched as unquoted",
+			s:             "\"a\"\"b\",c",
+			substr:        "\"b",
+			expectedIndex: -1,
+		},
+		{
+			name:          "empty quoted field",
+			s:             "\"\",\"\"\n",
+			substr:        "\n",
+			expectedIndex: 5,
+		},
+		{
+			name:          "multi-byte runes",
+			s:             "\"héllo,wörld\",ünï,x",
+			substr:        ",",
+			expectedIndex: 15,
+		},
+		{
+			name:          "multi-byte runes without quotes",
+			s:             "ü,x",
+			substr:        ",",
+			expectedIndex: 2,
+		},
+		{
+			name:          "substr longer than remaining input",
+			s:             "\"a\"bc",
+			substr:        "bcd",
+			expectedIndex: -1,
+		},
 	}
 
 	for _, test := range tests {
 		testFn := func(t *testing.T) {
 			i := util.IndexNonQuoted(test.s, test.substr)
 			assert.Equal(t, test.expectedIndex, i)
+			i = util.IndexNonQuotedBytes([]byte(test.s), []byte(test.substr))
+			assert.Equal(t, test.expectedIndex, i, "bytes")
 		}
 		t.Run(test.name, testFn)
 	}
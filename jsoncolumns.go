@@ -0,0 +1,212 @@
+package permissivecsv
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// WithJSONColumn instructs the Scanner to flatten keys of the JSON objects
+// embedded in the named column into additional columns, for feeds that carry
+// a JSON blob alongside conventional fields. Each key is a path of object keys
+// separated by dots, such as "user.id". The additional columns follow the
+// columns of the input, in the order in which they were supplied, and are
+// named after the column and key, normalized by NormalizeColumnName (so the
+// key "user.id" of the column "Payload" is named payload_user_id).
+//
+// The additional columns are written by Normalize, Rewrite, Pipe, and
+// Repaired (including to the header), and can be decoded by Decode (a nested
+// struct field named Payload, with a field named UserID, matches the column
+// payload_user_id). CurrentRecord is not affected. String values are written
+// as is, other scalars as their JSON text, and objects and arrays as compact
+// JSON. A key that is absent, or a field that does not contain a JSON object,
+// yields a blank field (which has the state FieldMissing, as does a JSON null).
+//
+// Columns are matched to the header's column names after both are normalized
+// by NormalizeColumnName, so columns are only flattened if the first record is
+// a header (see RecordIsHeader). WithJSONColumn can be supplied more than once
+// to flatten several columns.
+func WithJSONColumn(column string, keys ...string) Option {
+	return func(o *options) {
+		o.jsonColumns = append(o.jsonColumns, jsonColumn{
+			column: column,
+			keys:   append([]string{}, keys...),
+		})
+	}
+}
+
+// jsonColumn is a column supplied to WithJSONColumn, and the keys to be
+// flattened.
+type jsonColumn struct {
+	column string
+	keys   []string
+}
+
+// jsonExpansion is a jsonColumn that has been matched to the index of a
+// column.
+type jsonExpansion struct {
+	index int
+	paths [][]string
+}
+
+// CurrentJSONFields returns the indexes of the fields of the current record
+// that contain a JSON object or array (ignoring surrounding whitespace). This
+// can be used to detect columns that hold embedded JSON, which can then be
+// flattened with WithJSONColumn. CurrentJSONFields returns nil if no fields
+// contain JSON.
+func (s *Scanner) CurrentJSONFields() []int {
+	var indexes []int
+	for i, field := range s.currentRecord {
+		trimmed := strings.TrimSpace(field)
+		if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+			continue
+		}
+		if json.Valid([]byte(trimmed)) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// resolveJSONColumns matches the columns supplied to WithJSONColumn to the
+// header's columns, and determines the names of the additional columns.
+func (s *Scanner) resolveJSONColumns() {
+	if s.jsonColumnsResolved {
+		return
+	}
+	if s.firstRecord != nil {
+		s.RecordIsHeader()
+	} else {
+		s.resolveHeader()
+	}
+	if !s.headerResolved {
+		return
+	}
+	s.jsonColumnsResolved = true
+	if s.header == nil {
+		return
+	}
+
+	columns := make(map[string]int, len(s.header))
+	for i, name := range s.header {
+		name = NormalizeColumnName(name)
+		if _, exists := columns[name]; !exists {
+			columns[name] = i
+		}
+	}
+	for _, c := range s.opts.jsonColumns {
+		index, ok := columns[NormalizeColumnName(c.column)]
+		if !ok {
+			continue
+		}
+		expansion := jsonExpansion{index: index}
+		for _, key := range c.keys {
+			expansion.paths = append(expansion.paths, strings.Split(key, "."))
+			s.jsonColumnNames = append(s.jsonColumnNames, NormalizeColumnName(c.column+"_"+key))
+		}
+		s.jsonExpansions = append(s.jsonExpansions, expansion)
+	}
+}
+
+// outputRecord returns the current record, followed by the columns flattened
+// from JSON (see WithJSONColumn), along with their field states if states is
+// non-nil. If the current record is the header, the names of the flattened
+// columns are appended instead.
+func (s *Scanner) outputRecord(states []FieldState) ([]string, []FieldState) {
+	record := s.currentRecord
+	if len(s.opts.jsonColumns) == 0 {
+		return record, states
+	}
+	s.resolveJSONColumns()
+	if len(s.jsonColumnNames) == 0 {
+		return record, states
+	}
+
+	output := make([]string, len(record), len(record)+len(s.jsonColumnNames))
+	copy(output, record)
+	if states != nil {
+		states = append(make([]FieldState, 0, len(output)+len(s.jsonColumnNames)), states...)
+	}
+	if s.recordsScanned == 1 && s.firstRecord != nil {
+		output = append(output, s.jsonColumnNames...)
+		for range s.jsonColumnNames {
+			if states != nil {
+				states = append(states, FieldBare)
+			}
+		}
+		return output, states
+	}
+
+	for _, expansion := range s.jsonExpansions {
+		var object interface{}
+		if expansion.index < len(record) {
+			object = decodeJSONField(record[expansion.index])
+		}
+		for _, path := range expansion.paths {
+			value, ok := lookupJSON(object, path)
+			output = append(output, value)
+			if states != nil {
+				if ok {
+					states = append(states, FieldBare)
+				} else {
+					states = append(states, FieldMissing)
+				}
+			}
+		}
+	}
+	return output, states
+}
+
+// outputHeader returns the header (if any), followed by the names of the
+// columns flattened from JSON.
+func (s *Scanner) outputHeader() []string {
+	if s.header == nil || len(s.opts.jsonColumns) == 0 {
+		return s.header
+	}
+	s.resolveJSONColumns()
+	return append(append([]string{}, s.header...), s.jsonColumnNames...)
+}
+
+// decodeJSONField returns the JSON object in field, or nil if field does not
+// contain an object. Numbers are decoded as json.Number, so that they are
+// reproduced exactly.
+func decodeJSONField(field string) interface{} {
+	decoder := json.NewDecoder(strings.NewReader(field))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil
+	}
+	return object
+}
+
+// lookupJSON returns the value at path within v, formatted as a field, and
+// reports whether the value is present (and not null).
+func lookupJSON(v interface{}, path []string) (string, bool) {
+	for _, key := range path {
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		v, ok = object[key]
+		if !ok {
+			return "", false
+		}
+	}
+	switch value := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	case json.Number:
+		return value.String(), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+}
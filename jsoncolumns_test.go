@@ -0,0 +1,152 @@
+package permissivecsv_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+const jsonColumnData = `id,Payload,note
+1,"{""user"":{""id"":7,""name"":""Ann""},""tags"":[""a"",""b""],""ok"":true}",first
+2,"{""user"":{""id"":8},""ok"":null}",second
+3,not json,third
+4`
+
+func Test_WithJSONColumn(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		headerCheck permissivecsv.HeaderCheck
+		opts        []permissivecsv.Option
+		normalize   []permissivecsv.NormalizeOption
+		expOutput   string
+	}{
+		{
+			name:        "flattened keys",
+			data:        jsonColumnData,
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.Option{
+				permissivecsv.WithJSONColumn("payload", "user.id", "user.name", "tags", "ok"),
+			},
+			expOutput: "id,Payload,note,payload_user_id,payload_user_name,payload_tags,payload_ok\n" +
+				`1,"{""user"":{""id"":7,""name"":""Ann""},""tags"":[""a"",""b""],""ok"":true}",first,7,Ann,"[""a"",""b""]",true` + "\n" +
+				`2,"{""user"":{""id"":8},""ok"":null}",second,8,,,` + "\n" +
+				"3,not json,third,,,,\n" +
+				"4,,,,,,\n",
+		},
+		{
+			name:        "projected columns and skipped header",
+			data:        jsonColumnData,
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.Option{
+				permissivecsv.WithJSONColumn("payload", "user.id"),
+			},
+			normalize: []permissivecsv.NormalizeOption{
+				permissivecsv.WithNormalizeColumns("id", "payload_user_id"),
+				permissivecsv.WithNormalizeSkipHeader(),
+			},
+			expOutput: "1,7\n2,8\n3,\n4,\n",
+		},
+		{
+			name:        "unmatched column",
+			data:        "a,b\n1,2",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.Option{
+				permissivecsv.WithJSONColumn("payload", "id"),
+			},
+			expOutput: "a,b\n1,2\n",
+		},
+		{
+			name:        "no header",
+			data:        "1,\"{\"\"id\"\":1}\"",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			opts: []permissivecsv.Option{
+				permissivecsv.WithJSONColumn("column_2", "id"),
+			},
+			expOutput: "1,\"{\"\"id\"\":1}\"\n",
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck, test.opts...)
+			buf := new(bytes.Buffer)
+			_, err := s.Normalize(buf, test.normalize...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expOutput, buf.String())
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithJSONColumnPreserveQuoting(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader(jsonColumnData), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithJSONColumn("payload", "user.name"))
+	buf := new(bytes.Buffer)
+	_, err := s.Normalize(buf, permissivecsv.WithNormalizePreserveQuoting(), permissivecsv.WithNormalizeColumns("id", "payload_user_name"))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,payload_user_name\n1,Ann\n2,\n3,\n4,\n", buf.String())
+}
+
+func Test_WithJSONColumnPipeAndRepaired(t *testing.T) {
+	opts := []permissivecsv.Option{permissivecsv.WithJSONColumn("payload", "user.id")}
+	expOutput := "id,payload_user_id\n1,7\n2,8\n3,\n4,\n"
+
+	s := permissivecsv.NewScanner(strings.NewReader(jsonColumnData), permissivecsv.HeaderCheckAssumeHeaderExists, opts...)
+	buf := new(bytes.Buffer)
+	_, err := s.Pipe(csv.NewWriter(buf), permissivecsv.WithPipeColumns("id", "payload_user_id"))
+	assert.NoError(t, err)
+	assert.Equal(t, expOutput, buf.String())
+
+	output, err := ioutil.ReadAll(permissivecsv.Repaired(strings.NewReader("id,payload\n1,\"{\"\"user\"\":{\"\"id\"\":7}}\""), opts...))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,payload,payload_user_id\n1,\"{\"\"user\"\":{\"\"id\"\":7}}\",7\n", string(output))
+}
+
+func Test_WithJSONColumnDecode(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	type payload struct {
+		User user
+		OK   *bool
+	}
+	type row struct {
+		ID      int
+		Payload payload
+		Note    string
+	}
+
+	s := permissivecsv.NewScanner(strings.NewReader(jsonColumnData), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithJSONColumn("payload", "user.id", "user.name", "ok"))
+	rows := []row{}
+	for s.Scan() {
+		var r row
+		err := s.Decode(&r)
+		if err == permissivecsv.ErrDecodeHeader {
+			continue
+		}
+		assert.NoError(t, err)
+		rows = append(rows, r)
+	}
+	ok := true
+	assert.Equal(t, []row{
+		row{ID: 1, Payload: payload{User: user{ID: 7, Name: "Ann"}, OK: &ok}, Note: "first"},
+		row{ID: 2, Payload: payload{User: user{ID: 8}}, Note: "second"},
+		row{ID: 3, Note: "third"},
+		row{ID: 4},
+	}, rows)
+}
+
+func Test_CurrentJSONFields(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader(`a," [1,2] ","{""x"":1}",{broken,[],"""3"""`), permissivecsv.HeaderCheckAssumeNoHeader)
+	assert.Nil(t, s.CurrentJSONFields())
+	s.Scan()
+	assert.Equal(t, []int{1, 2, 4}, s.CurrentJSONFields())
+}
@@ -0,0 +1,33 @@
+package permissivecsv
+
+// WithSkipEmptyFirstRecords instructs the Scanner to skip any records that
+// consist of a single empty field (such as "") and precede the first record
+// that contains data. By default, such a record is the first record, so it
+// sets the expected field count to one, and every subsequent record is
+// truncated to its first field. Skipped records are reported via
+// ScanSummary.Warnings (see WarningEmptyFirstRecordSkipped), and, like a
+// dropped record (see WithFieldCountTolerance), are included in RecordCount,
+// so that record ordinals continue to reflect the input. The expected field
+// count, and the record supplied to the HeaderCheck, are instead taken from
+// the first record that is not skipped.
+func WithSkipEmptyFirstRecords() Option {
+	return func(o *options) {
+		o.skipEmptyFirstRecords = true
+	}
+}
+
+// skipEmptyFirstRecord skips the current record if it is an empty record
+// that precedes the first record (see WithSkipEmptyFirstRecords), and reports
+// whether it did so. record is the result of parsing the record's fields. The
+// record's bytes are left unclaimed, so that Partition includes them in the
+// enclosing segment.
+func (s *Scanner) skipEmptyFirstRecord(rawRecord string, record []string) bool {
+	if !s.opts.skipEmptyFirstRecords || s.recordsScanned > 0 || len(record) != 1 || record[0] != "" {
+		return false
+	}
+	s.warn(WarningEmptyFirstRecordSkipped, s.scanSummary.RecordCount,
+		"record contains a single empty field, so it was not used to set the expected field count")
+	s.unclaimBytes([]byte(rawRecord))
+	s.currentDropped = true
+	return true
+}
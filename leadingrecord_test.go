@@ -0,0 +1,106 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithSkipEmptyFirstRecords(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		opts        []permissivecsv.Option
+		expRecords  [][]string
+		expOrdinals []int
+		expWarnings []*permissivecsv.Warning
+	}{
+		{
+			name:        "disabled",
+			data:        "\"\"\na,a,a\nb,b,b\n",
+			expRecords:  [][]string{{""}, {"a"}, {"b"}},
+			expOrdinals: []int{1, 2, 3},
+		},
+		{
+			name:        "empty first record",
+			data:        "\"\"\na,a,a\nb,b,b\n",
+			opts:        []permissivecsv.Option{permissivecsv.WithSkipEmptyFirstRecords()},
+			expRecords:  [][]string{{"a", "a", "a"}, {"b", "b", "b"}},
+			expOrdinals: []int{2, 3},
+			expWarnings: []*permissivecsv.Warning{
+				{
+					Kind:          permissivecsv.WarningEmptyFirstRecordSkipped,
+					RecordOrdinal: 1,
+					Detail:        "record contains a single empty field, so it was not used to set the expected field count",
+				},
+			},
+		},
+		{
+			name:        "several empty first records",
+			data:        "\"\"\r\n\"\"\r\na,a\r\n\"\"\r\n",
+			opts:        []permissivecsv.Option{permissivecsv.WithSkipEmptyFirstRecords()},
+			expRecords:  [][]string{{"a", "a"}, {"", ""}},
+			expOrdinals: []int{3, 4},
+			expWarnings: []*permissivecsv.Warning{
+				{
+					Kind:          permissivecsv.WarningEmptyFirstRecordSkipped,
+					RecordOrdinal: 1,
+					Detail:        "record contains a single empty field, so it was not used to set the expected field count",
+				},
+				{
+					Kind:          permissivecsv.WarningEmptyFirstRecordSkipped,
+					RecordOrdinal: 2,
+					Detail:        "record contains a single empty field, so it was not used to set the expected field count",
+				},
+			},
+		},
+		{
+			name:        "first record contains data",
+			data:        "a\n\"\"\nb,b\n",
+			opts:        []permissivecsv.Option{permissivecsv.WithSkipEmptyFirstRecords()},
+			expRecords:  [][]string{{"a"}, {""}, {"b"}},
+			expOrdinals: []int{1, 2, 3},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			records := [][]string{}
+			ordinals := []int{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+				ordinals = append(ordinals, s.CurrentRecordInfo().Ordinal)
+			}
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expOrdinals, ordinals)
+			assert.Equal(t, test.expWarnings, s.Summary().Warnings)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithSkipEmptyFirstRecordsHeader(t *testing.T) {
+	var checked []string
+	headerCheck := func(firstRecord, secondRecord []string) bool {
+		checked = firstRecord
+		return true
+	}
+	data := "\"\"\nid,name\n1,a\n"
+	s := permissivecsv.NewScanner(strings.NewReader(data), headerCheck, permissivecsv.WithSkipEmptyFirstRecords())
+	assert.True(t, s.Scan())
+	assert.True(t, s.RecordIsHeader())
+	assert.Equal(t, []string{"id", "name"}, checked)
+
+	r := strings.NewReader(data)
+	s = permissivecsv.NewScanner(r, headerCheck, permissivecsv.WithSkipEmptyFirstRecords())
+	segments, err := s.Partition(1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), s.HeaderSegment().Length, "the skipped record precedes the header")
+	if assert.Len(t, segments, 1) {
+		assert.Equal(t, int64(11), segments[0].LowerOffset)
+		assert.Equal(t, int64(4), segments[0].Length)
+	}
+}
@@ -0,0 +1,332 @@
+// Package linesplit provides the permissive record boundary detection used by
+// permissivecsv. A Splitter's Split method is a bufio.SplitFunc that splits
+// CSV input into raw records, each including its terminator, while ignoring
+// any terminators that fall within double quotes. Terminators can be any (or a
+// mix) of DOS (\r\n), inverted DOS (\n\r), unix (\n), or carriage return
+// (\r) sequences.
+//
+// linesplit is intended for use by consumers that need to locate records
+// without parsing them, such as indexers or alternative scanners. The zero
+// value of Splitter is ready to use:
+//
+//	splitter := new(linesplit.Splitter)
+//	scanner := bufio.NewScanner(r)
+//	scanner.Split(splitter.Split)
+//	for scanner.Scan() {
+//	    record, terminator := scanner.Bytes(), splitter.CurrentTerminator()
+//	    ...
+//	}
+//
+// A Splitter maintains state between calls to Split, so each Splitter must
+// only be used with a single bufio.Scanner.
+package linesplit
+
+import (
+	"bufio"
+
+	"github.com/eltorocorp/permissivecsv/internal/util"
+)
+
+// Terminators recognized by the Splitter.
+const (
+	DOS            = "\r\n"
+	InvertedDOS    = "\n\r"
+	Unix           = "\n"
+	CarriageReturn = "\r"
+)
+
+// DefaultPriority is the order in which terminators are preferred when more
+// than one possible terminator is present in the search space.
+var DefaultPriority = []string{DOS, InvertedDOS, Unix, CarriageReturn}
+
+// Splitter provides a lineSplit function that will split records on
+// unix, DOS, inverted DOS (/n/r) or bare carriage return (/r) terminators.
+// Splitter emits certain information about the status of the splitter,
+// such as the most recently read record, terminator, terminator length, etc...
+//
+// When Split requests a larger search space, the Splitter remembers how far it
+// has already searched (along with the quote state at that point), so that the
+// next call to Split only needs to examine the newly read bytes. This keeps the
+// cost of scanning large quoted fields that span many buffer expansions linear
+// in the size of the field.
+type Splitter struct {
+	// DisableInvertedDOS prevents the Splitter from recognizing inverted DOS
+	// (\n\r) terminators. When set, a newline followed by a carriage return
+	// is treated as a unix terminator, and the carriage return is considered
+	// part of the following record (where it usually begins a DOS terminator).
+	DisableInvertedDOS bool
+
+	// Priority is the order in which terminators are preferred when more than
+	// one possible terminator is present in the search space, highest priority
+	// first. Any of the recognized terminators that are not listed follow the
+	// listed terminators, in their default order. If CarriageReturn is given
+	// priority over Unix, the search space ends at the first carriage return,
+	// rather than the first newline. Priority must not be changed after Split
+	// has been called.
+	Priority []string
+
+	// MaxQuotedBytes limits the length of a quoted section. If a quote has
+	// been open for more than MaxQuotedBytes bytes, and a newline has been
+	// found within it, the quote is presumed to be missing its closing quote,
+	// and the quoted section is closed at the first such newline (or, if
+	// CarriageReturn has priority over Unix, at the first carriage return).
+	// This prevents a single missing quote from merging the remainder of the
+	// input into one record. A value of zero (the default) disables the limit.
+	MaxQuotedBytes int
+
+	currentTerminator []byte
+	unterminatedQuote bool
+	quoteForceClosed  bool
+	priority          []string
+	stopAtCR          bool
+
+	// The following fields carry the search state from a call to Split that
+	// requested a larger search space to the next call to Split.
+	// searched is the number of bytes of data that have been examined, inQuotes
+	// is the quote state at that point, and newlineIndex and
+	// carriageReturnIndex are the indexes of the first non-quoted newline and
+	// carriage return found so far (or -1).
+	searched            int
+	inQuotes            bool
+	newlineIndex        int
+	carriageReturnIndex int
+
+	// quoteStart is the index of the quote that opened the current quoted
+	// section, and quoteEnd is the index of the quote that most recently
+	// closed one (so that escaped quotes do not begin a new section).
+	// quotedNewlineIndex and quotedCarriageReturnIndex are the indexes of the
+	// first newline and carriage return within the current quoted section (or
+	// -1), and forced is true if a quoted section was closed because it
+	// exceeded MaxQuotedBytes.
+	quoteStart                int
+	quoteEnd                  int
+	quotedNewlineIndex        int
+	quotedCarriageReturnIndex int
+	forced                    bool
+}
+
+// CurrentTerminator returns the terminator that was most recently identified
+// by the splitter. This value will be nil if no data was returned in the
+// most recent Split. This value will be an empty slice if data was returned,
+// but contained no terminator. Otherwise, if a terminator was identified within
+// the slice, that terminator is returned.
+func (l *Splitter) CurrentTerminator() []byte {
+	return l.currentTerminator
+}
+
+// UnterminatedQuote reports whether the most recently returned token ended
+// within a quoted section. Since terminators within quotes are ignored, this
+// is only possible for the final token of the input, and indicates that the
+// input contains an unclosed (or extraneous) quote, which causes all of the
+// input following the quote to be returned as a single token.
+func (l *Splitter) UnterminatedQuote() bool {
+	return l.unterminatedQuote
+}
+
+// QuoteForceClosed reports whether the most recently returned token ends
+// within a quoted section that was closed because it exceeded MaxQuotedBytes.
+// Such a token contains an unclosed quote.
+func (l *Splitter) QuoteForceClosed() bool {
+	return l.quoteForceClosed
+}
+
+// Split performs the line splitting operations. Split is a bufio.SplitFunc.
+func (l *Splitter) Split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if l.priority == nil {
+		l.initPriority()
+	}
+	l.currentTerminator = nil
+	l.unterminatedQuote = false
+	l.quoteForceClosed = false
+	newlineIndex, carriageReturnIndex := l.search(data)
+
+	nearestTerminator := -1
+	terminatorLength := 0
+
+	// Of the terminators present in the search space, the one with the
+	// highest priority is selected.
+	for _, terminator := range l.priority {
+		index := -1
+		switch terminator {
+		case DOS:
+			// A carriage return immediately followed by a newline. If the
+			// search stopped at the carriage return, the newline has not yet
+			// been searched for.
+			if carriageReturnIndex != -1 &&
+				(newlineIndex == carriageReturnIndex+1 ||
+					newlineIndex == -1 &&
+						carriageReturnIndex+1 < len(data) &&
+						data[carriageReturnIndex+1] == Unix[0]) {
+				index = carriageReturnIndex
+			}
+		case InvertedDOS:
+			// A newline immediately followed by a carriage return, so long as
+			// no carriage return precedes the newline.
+			if !l.DisableInvertedDOS &&
+				newlineIndex != -1 &&
+				carriageReturnIndex == -1 &&
+				newlineIndex+1 < len(data) &&
+				data[newlineIndex+1] == CarriageReturn[0] {
+				index = newlineIndex
+			}
+		case Unix:
+			index = newlineIndex
+		case CarriageReturn:
+			index = carriageReturnIndex
+		}
+		if index != -1 {
+			l.currentTerminator = []byte(terminator)
+			nearestTerminator = index
+			terminatorLength = len(terminator)
+			break
+		}
+	}
+
+	if nearestTerminator != -1 {
+		if nearestTerminator == len(data)-terminatorLength && !atEOF {
+			// The nearest terminator is at the end of the current search
+			// space. We need to expand the search space to ensure we are
+			// observing the full terminator sequence.
+			l.currentTerminator = nil
+			return
+		}
+		advance = nearestTerminator + terminatorLength
+		token = data[:advance]
+		l.quoteForceClosed = l.forced
+		l.reset()
+		return
+	}
+
+	if !atEOF {
+		// requesting a larger search space
+		return
+	}
+
+	token = data
+	err = bufio.ErrFinalToken
+	if data != nil {
+		l.currentTerminator = []byte{}
+	}
+	l.unterminatedQuote = l.inQuotes
+	l.reset()
+	return
+}
+
+// initPriority determines the effective terminator priority from Priority.
+func (l *Splitter) initPriority() {
+	l.priority = []string{}
+	listed := map[string]bool{}
+	for _, terminator := range append(append([]string{}, l.Priority...), DefaultPriority...) {
+		if listed[terminator] {
+			continue
+		}
+		for _, recognized := range DefaultPriority {
+			if terminator == recognized {
+				l.priority = append(l.priority, terminator)
+				listed[terminator] = true
+			}
+		}
+	}
+	for _, terminator := range l.priority {
+		if terminator == Unix {
+			break
+		}
+		if terminator == CarriageReturn {
+			l.stopAtCR = true
+		}
+	}
+}
+
+// IndexNonQuoted returns the byte index of the first occurrence of substr in s
+// that does not fall within double quotes, or -1 if there is no such
+// occurrence. Quotes are interpreted in the same way as they are when
+// splitting records: a doubled quote within a quoted section is an escaped
+// quote, and an unclosed quote causes the remainder of s to be considered
+// quoted.
+func IndexNonQuoted(s, substr string) int {
+	return util.IndexNonQuoted(s, substr)
+}
+
+// IndexNonQuotedBytes is like IndexNonQuoted, but operates on byte slices,
+// which avoids converting large buffers to strings.
+func IndexNonQuotedBytes(s, substr []byte) int {
+	return util.IndexNonQuotedBytes(s, substr)
+}
+
+// search examines data for the first non-quoted newline and carriage return,
+// resuming from wherever the previous search left off. search stops as soon as
+// a newline is found, as no terminator can begin after the first newline. If
+// carriage returns have priority over newlines, search also stops as soon as a
+// carriage return is found.
+func (l *Splitter) search(data []byte) (newlineIndex, carriageReturnIndex int) {
+	if l.searched == 0 || l.searched > len(data) {
+		l.reset()
+	}
+
+	i := l.searched
+	for ; i < len(data) && l.newlineIndex == -1; i++ {
+		if l.stopAtCR && l.carriageReturnIndex != -1 {
+			break
+		}
+		switch data[i] {
+		case util.QuoteChar:
+			if l.inQuotes {
+				l.quoteEnd = i
+			} else if l.quoteEnd != i-1 {
+				// not an escaped quote, so a new quoted section begins.
+				l.quoteStart = i
+				l.quotedNewlineIndex = -1
+				l.quotedCarriageReturnIndex = -1
+			}
+			l.inQuotes = !l.inQuotes
+		case '\n':
+			if !l.inQuotes {
+				l.newlineIndex = i
+			} else if l.quotedNewlineIndex == -1 {
+				l.quotedNewlineIndex = i
+			}
+		case '\r':
+			if !l.inQuotes && l.carriageReturnIndex == -1 {
+				l.carriageReturnIndex = i
+			} else if l.inQuotes && l.quotedCarriageReturnIndex == -1 {
+				l.quotedCarriageReturnIndex = i
+			}
+		}
+		if l.inQuotes && l.MaxQuotedBytes > 0 && i-l.quoteStart >= l.MaxQuotedBytes {
+			l.forceClose()
+		}
+	}
+	l.searched = i
+	return l.newlineIndex, l.carriageReturnIndex
+}
+
+// forceClose closes the current quoted section at the first terminator within
+// it, if there is one.
+func (l *Splitter) forceClose() {
+	newlineIndex, carriageReturnIndex := l.quotedNewlineIndex, l.quotedCarriageReturnIndex
+	if newlineIndex == -1 && !(l.stopAtCR && carriageReturnIndex != -1) {
+		return
+	}
+	if newlineIndex != -1 {
+		l.newlineIndex = newlineIndex
+	}
+	if l.carriageReturnIndex == -1 && carriageReturnIndex != -1 &&
+		(newlineIndex == -1 || carriageReturnIndex < newlineIndex) {
+		l.carriageReturnIndex = carriageReturnIndex
+	}
+	l.inQuotes = false
+	l.forced = true
+}
+
+// reset clears any search state carried over from a previous call to Split.
+func (l *Splitter) reset() {
+	l.searched = 0
+	l.inQuotes = false
+	l.newlineIndex = -1
+	l.carriageReturnIndex = -1
+	l.quoteStart = 0
+	l.quoteEnd = -2
+	l.quotedNewlineIndex = -1
+	l.quotedCarriageReturnIndex = -1
+	l.forced = false
+}
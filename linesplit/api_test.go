@@ -0,0 +1,635 @@
+package linesplit_test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv/linesplit"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Split(t *testing.T) {
+	tests := []struct {
+		name                 string
+		data                 []byte
+		atEOF                bool
+		expAdvance           int
+		expToken             []byte
+		expErr               error
+		expCurrentTerminator []byte
+	}{
+		{
+			name:                 "no data",
+			data:                 nil,
+			atEOF:                true,
+			expAdvance:           0,
+			expToken:             nil,
+			expErr:               bufio.ErrFinalToken,
+			expCurrentTerminator: nil,
+		},
+		{
+			name:                 "empty data at EOF",
+			data:                 []byte{},
+			atEOF:                true,
+			expAdvance:           0,
+			expToken:             []byte{},
+			expErr:               bufio.ErrFinalToken,
+			expCurrentTerminator: []byte{},
+		},
+		{
+			// In the initial read, Split should return 0, nil, nil, requesting
+			// that the search space be increased.
+			name:                 "no terminator and not EOF",
+			data:                 []byte("a,b,c"),
+			atEOF:                false,
+			expAdvance:           0,
+			expToken:             nil,
+			expErr:               nil,
+			expCurrentTerminator: nil,
+		},
+		{
+			name:                 "no terminator, at EOF",
+			data:                 []byte("a,b,c"),
+			atEOF:                true,
+			expAdvance:           0,
+			expToken:             []byte("a,b,c"),
+			expErr:               bufio.ErrFinalToken,
+			expCurrentTerminator: []byte{},
+		},
+		// The trailing terminator should be included with the record it
+		// terminates.
+		{
+			name:                 "unix",
+			data:                 []byte("a,b,c\nd,e,f"),
+			atEOF:                false,
+			expAdvance:           6,
+			expToken:             []byte("a,b,c\n"),
+			expErr:               nil,
+			expCurrentTerminator: []byte{10},
+		},
+		{
+			name:                 "dos",
+			data:                 []byte("a,b,c\r\nd,e,f"),
+			atEOF:                false,
+			expAdvance:           7,
+			expToken:             []byte("a,b,c\r\n"),
+			expErr:               nil,
+			expCurrentTerminator: []byte{13, 10},
+		},
+		{
+			name:                 "carriage return",
+			data:                 []byte("a,b,c\rd,e,f"),
+			atEOF:                false,
+			expAdvance:           6,
+			expToken:             []byte("a,b,c\r"),
+			expErr:               nil,
+			expCurrentTerminator: []byte{13},
+		},
+		{
+			name:                 "inverted dos",
+			data:                 []byte("a,b,c\n\rd,e,f"),
+			atEOF:                false,
+			expAdvance:           7,
+			expToken:             []byte("a,b,c\n\r"),
+			expErr:               nil,
+			expCurrentTerminator: []byte{10, 13},
+		},
+		// If the current search space ends in a newline or carriage return,
+		// and no other non-quoted terminators are present at an earlier index,
+		// the search space should be increased to ensure that the correct
+		// terminator is chosen.
+		{
+			name:                 "partial dos terminator closing search space",
+			data:                 []byte("a,b,c\r"),
+			atEOF:                false,
+			expAdvance:           0,
+			expToken:             nil,
+			expErr:               nil,
+			expCurrentTerminator: nil,
+		},
+		{
+			name:                 "partial invdos terminator closing search space",
+			data:                 []byte("a,b,c\n"),
+			atEOF:                false,
+			expAdvance:           0,
+			expToken:             nil,
+			expErr:               nil,
+			expCurrentTerminator: nil,
+		},
+		// Since bare carriage returns are quite rare to be used as terminators,
+		// we only want to select a carriage return as the terminator if no
+		// other more likely terminator exists within the current search space.
+		{
+			name:                 "prefer newline over carriage return",
+			data:                 []byte("a,b\rc,d\ne,f,g,h"),
+			atEOF:                false,
+			expAdvance:           8,
+			expToken:             []byte("a,b\rc,d\n"),
+			expErr:               nil,
+			expCurrentTerminator: []byte{10},
+		},
+		// A terminator at the end of the search space (but not EOF) should
+		// always trigger a search space extension.
+		// Note that these tests use \r\n as the test case to avoid
+		// collision with the partial terminator search space extension
+		// requirement.
+		{
+			name:                 "terminator at end of search space",
+			data:                 []byte("a,b,c\r\n"),
+			atEOF:                false,
+			expAdvance:           0,
+			expToken:             nil,
+			expErr:               nil,
+			expCurrentTerminator: nil,
+		},
+		{
+			name:                 "terminator at end of file",
+			data:                 []byte("a,b,c\r\n"),
+			atEOF:                true,
+			expAdvance:           7,
+			expToken:             []byte("a,b,c\r\n"),
+			expErr:               nil,
+			expCurrentTerminator: []byte{13, 10},
+		},
+		// If there are an even number of extraneous quotes before any terminator
+		// they will be identified as such, and the terminator will be found.
+		{
+			name:                 "extraneous quotes (even)",
+			data:                 []byte("b\"\"b,b,b\nc,c,c"),
+			atEOF:                true,
+			expAdvance:           9,
+			expToken:             []byte("b\"\"b,b,b\n"),
+			expErr:               nil,
+			expCurrentTerminator: []byte{10},
+		},
+		// If there are an odd number of extraneous quotes before any terminator,
+		// and we are at the end of the file, linesplit can't trust any
+		// terminator it finds after the last quote, as it doesn't know if it
+		// is "quoted" or not. Instead, the remaineder of the text is returned
+		// in full.
+		{
+			name:                 "extraneous quotes (odd at EOF)",
+			data:                 []byte("b\"\"\"b,b,b\nc,c,c"),
+			atEOF:                true,
+			expAdvance:           0,
+			expToken:             []byte("b\"\"\"b,b,b\nc,c,c"),
+			expErr:               bufio.ErrFinalToken,
+			expCurrentTerminator: []byte{},
+		},
+		// If there are an odd number of extraneous quotes before any terminator
+		// and we are not at the end of the file, linesplit will request to have
+		// the search space increased, in an effort to idenfity a missing quote.
+		{
+			name:                 "extraneous quotes (odd not EOF)",
+			data:                 []byte("b\"\"\"b,b,b\nc,c,c"),
+			atEOF:                false,
+			expAdvance:           0,
+			expToken:             nil,
+			expErr:               nil,
+			expCurrentTerminator: nil,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			splitter := new(linesplit.Splitter)
+			actAdvance, actToken, actErr := splitter.Split(test.data, test.atEOF)
+			actCurrentTerminator := splitter.CurrentTerminator()
+			assert.Equal(t, test.expAdvance, actAdvance, "advance")
+			assert.Equal(t, test.expToken, actToken, "token")
+			assert.Equal(t, test.expErr, actErr, "err")
+			if test.expCurrentTerminator == nil {
+				assert.Nil(t, splitter.CurrentTerminator(), "terminator")
+			} else if assert.NotNil(t, splitter.CurrentTerminator(), "terminator") {
+				assert.Equal(t, test.expCurrentTerminator, actCurrentTerminator, "terminator")
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+// Test_SplitExpandingSearchSpace simulates bufio.Scanner repeatedly expanding
+// the search space, and verifies that the quote state carried between calls
+// produces the same result as a single search of the full space.
+func Test_SplitExpandingSearchSpace(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []byte
+		chunkSize  int
+		expTokens  [][]byte
+		expTermins [][]byte
+	}{
+		{
+			name:      "quoted terminators span expansions",
+			data:      []byte("\"a\nb\nc\nd\ne\",f\r\ng,h"),
+			chunkSize: 2,
+			expTokens: [][]byte{
+				[]byte("\"a\nb\nc\nd\ne\",f\r\n"),
+				[]byte("g,h"),
+			},
+			expTermins: [][]byte{
+				[]byte("\r\n"),
+				[]byte{},
+			},
+		},
+		{
+			name:      "carriage return selected when no newline in search space",
+			data:      []byte("a,b\rc,d\ne,f"),
+			chunkSize: 3,
+			expTokens: [][]byte{
+				[]byte("a,b\r"),
+				[]byte("c,d\n"),
+				[]byte("e,f"),
+			},
+			expTermins: [][]byte{
+				[]byte("\r"),
+				[]byte("\n"),
+				[]byte{},
+			},
+		},
+		{
+			name:      "inverted dos split across expansions",
+			data:      []byte("a,b\n\rc,d"),
+			chunkSize: 4,
+			expTokens: [][]byte{
+				[]byte("a,b\n\r"),
+				[]byte("c,d"),
+			},
+			expTermins: [][]byte{
+				[]byte("\n\r"),
+				[]byte{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			splitter := new(linesplit.Splitter)
+			actTokens := [][]byte{}
+			actTermins := [][]byte{}
+			start, end := 0, 0
+			for start < len(test.data) {
+				end += test.chunkSize
+				atEOF := false
+				if end >= len(test.data) {
+					end = len(test.data)
+					atEOF = true
+				}
+				advance, token, err := splitter.Split(test.data[start:end], atEOF)
+				if token == nil {
+					continue
+				}
+				actTokens = append(actTokens, token)
+				actTermins = append(actTermins, splitter.CurrentTerminator())
+				if err == bufio.ErrFinalToken {
+					break
+				}
+				start += advance
+				end = start
+			}
+			assert.Equal(t, test.expTokens, actTokens, "tokens")
+			assert.Equal(t, test.expTermins, actTermins, "terminators")
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+// Test_SplitResumesSearch verifies that when the search space is expanded,
+// Split only examines the newly read bytes. bufio.Scanner never changes the
+// bytes it has already supplied, so the test supplies a different prefix on
+// the second call to detect whether it is searched again.
+func Test_SplitResumesSearch(t *testing.T) {
+	splitter := new(linesplit.Splitter)
+	advance, token, err := splitter.Split([]byte("abcd"), false)
+	assert.Equal(t, 0, advance)
+	assert.Nil(t, token)
+	assert.NoError(t, err)
+
+	advance, token, err = splitter.Split([]byte("\n\n\n\nef\ngh"), false)
+	assert.Equal(t, 7, advance)
+	assert.Equal(t, []byte("\n\n\n\nef\n"), token)
+	assert.NoError(t, err)
+
+	// A smaller search space indicates a new record, so the search restarts.
+	advance, _, _ = splitter.Split([]byte("abcd"), false)
+	assert.Equal(t, 0, advance)
+	advance, _, _ = splitter.Split([]byte("a\n"), true)
+	assert.Equal(t, 2, advance)
+}
+
+func Test_SplitDisableInvertedDOS(t *testing.T) {
+	data := "a,b\n\r\nc,d\r\n"
+	tests := []struct {
+		name               string
+		disableInvertedDOS bool
+		expTokens          []string
+	}{
+		{
+			name:               "inverted dos enabled",
+			disableInvertedDOS: false,
+			expTokens:          []string{"a,b\n\r", "\n", "c,d\r\n", ""},
+		},
+		{
+			name:               "inverted dos disabled",
+			disableInvertedDOS: true,
+			expTokens:          []string{"a,b\n", "\r\n", "c,d\r\n", ""},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			splitter := &linesplit.Splitter{DisableInvertedDOS: test.disableInvertedDOS}
+			scanner := bufio.NewScanner(strings.NewReader(data))
+			scanner.Split(splitter.Split)
+			actTokens := []string{}
+			for scanner.Scan() {
+				actTokens = append(actTokens, scanner.Text())
+			}
+			assert.Equal(t, test.expTokens, actTokens)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_SplitPriority(t *testing.T) {
+	data := "a\rb\nc\r\nd"
+	tests := []struct {
+		name      string
+		priority  []string
+		chunkSize int
+		expTokens []string
+	}{
+		{
+			name:      "default priority",
+			priority:  nil,
+			expTokens: []string{"a\rb\n", "c\r\n", "d"},
+		},
+		{
+			name:      "carriage return first",
+			priority:  []string{linesplit.CarriageReturn},
+			expTokens: []string{"a\r", "b\n", "c\r", "\n", "d"},
+		},
+		{
+			name:      "dos then carriage return",
+			priority:  []string{linesplit.DOS, linesplit.CarriageReturn},
+			expTokens: []string{"a\r", "b\n", "c\r\n", "d"},
+		},
+		{
+			name:      "dos then carriage return with small buffer",
+			priority:  []string{linesplit.DOS, linesplit.CarriageReturn},
+			chunkSize: 2,
+			expTokens: []string{"a\r", "b\n", "c\r\n", "d"},
+		},
+		{
+			name:      "unrecognized terminators are ignored",
+			priority:  []string{"x", linesplit.Unix, linesplit.Unix},
+			expTokens: []string{"a\rb\n", "c\r\n", "d"},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			splitter := &linesplit.Splitter{Priority: test.priority}
+			actTokens := []string{}
+			start, end := 0, 0
+			chunkSize := test.chunkSize
+			if chunkSize == 0 {
+				chunkSize = len(data)
+			}
+			for start < len(data) {
+				end += chunkSize
+				atEOF := false
+				if end >= len(data) {
+					end = len(data)
+					atEOF = true
+				}
+				advance, token, err := splitter.Split([]byte(data[start:end]), atEOF)
+				if token == nil {
+					continue
+				}
+				actTokens = append(actTokens, string(token))
+				if err == bufio.ErrFinalToken {
+					break
+				}
+				start += advance
+				end = start
+			}
+			assert.Equal(t, test.expTokens, actTokens)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_UnterminatedQuote(t *testing.T) {
+	tests := []struct {
+		name                 string
+		data                 string
+		expUnterminatedQuote []bool
+	}{
+		{
+			name:                 "balanced quotes",
+			data:                 "\"a\",b\nc,d",
+			expUnterminatedQuote: []bool{false, false},
+		},
+		{
+			name:                 "unclosed quote",
+			data:                 "a,b\nc,\"d\ne,f",
+			expUnterminatedQuote: []bool{false, true},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			splitter := new(linesplit.Splitter)
+			scanner := bufio.NewScanner(strings.NewReader(test.data))
+			scanner.Split(splitter.Split)
+			actUnterminatedQuote := []bool{}
+			for scanner.Scan() {
+				actUnterminatedQuote = append(actUnterminatedQuote, splitter.UnterminatedQuote())
+			}
+			assert.Equal(t, test.expUnterminatedQuote, actUnterminatedQuote)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_MaxQuotedBytes(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		max            int
+		priority       []string
+		chunkSize      int
+		expTokens      []string
+		expForceClosed []bool
+	}{
+		{
+			name:           "no limit",
+			data:           "a,\"b\nc,d\ne,f",
+			max:            0,
+			expTokens:      []string{"a,\"b\nc,d\ne,f"},
+			expForceClosed: []bool{false},
+		},
+		{
+			name:           "unclosed quote within limit",
+			data:           "a,\"b\nc,d\ne,f",
+			max:            100,
+			expTokens:      []string{"a,\"b\nc,d\ne,f"},
+			expForceClosed: []bool{false},
+		},
+		{
+			name:           "unclosed quote exceeds limit",
+			data:           "a,\"b\nc,d\ne,f",
+			max:            4,
+			expTokens:      []string{"a,\"b\n", "c,d\n", "e,f"},
+			expForceClosed: []bool{true, false, false},
+		},
+		{
+			name:           "DOS terminator within quote",
+			data:           "a,\"b\r\nc,d\r\ne,f",
+			max:            4,
+			expTokens:      []string{"a,\"b\r\n", "c,d\r\n", "e,f"},
+			expForceClosed: []bool{true, false, false},
+		},
+		{
+			name:           "closed quote within limit",
+			data:           "a,\"b\nc\"\nd,e",
+			max:            4,
+			expTokens:      []string{"a,\"b\nc\"\n", "d,e"},
+			expForceClosed: []bool{false, false},
+		},
+		{
+			name:           "escaped quotes do not restart the limit",
+			data:           "\"a\"\"\nb\"\"c,d\ne,f",
+			max:            6,
+			expTokens:      []string{"\"a\"\"\n", "b\"\"c,d\n", "e,f"},
+			expForceClosed: []bool{true, false, false},
+		},
+		{
+			name:           "carriage return priority",
+			data:           "a,\"b\rc,d\re,f",
+			max:            4,
+			priority:       []string{linesplit.CarriageReturn},
+			expTokens:      []string{"a,\"b\r", "c,d\r", "e,f"},
+			expForceClosed: []bool{true, false, false},
+		},
+		{
+			name:           "small reads",
+			data:           "a,\"b\nc,d\ne,f",
+			max:            4,
+			chunkSize:      1,
+			expTokens:      []string{"a,\"b\n", "c,d\n", "e,f"},
+			expForceClosed: []bool{true, false, false},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			splitter := &linesplit.Splitter{
+				MaxQuotedBytes: test.max,
+				Priority:       test.priority,
+			}
+			scanner := bufio.NewScanner(strings.NewReader(test.data))
+			if test.chunkSize > 0 {
+				scanner.Buffer(make([]byte, test.chunkSize), bufio.MaxScanTokenSize)
+			}
+			scanner.Split(splitter.Split)
+			actTokens := []string{}
+			actForceClosed := []bool{}
+			for scanner.Scan() {
+				actTokens = append(actTokens, scanner.Text())
+				actForceClosed = append(actForceClosed, splitter.QuoteForceClosed())
+			}
+			assert.Equal(t, test.expTokens, actTokens)
+			assert.Equal(t, test.expForceClosed, actForceClosed)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+// Test_IndexNonQuotedMatchesSplit verifies that IndexNonQuoted locates the
+// same unix terminator that Split selects.
+func Test_IndexNonQuotedMatchesSplit(t *testing.T) {
+	inputs := []string{
+		"a,b\nc,d",
+		"\"a\nb\",c\nd",
+		"\"a\"\"\nb\",c\nd",
+		"\"\",\"\"\n\"\"",
+		"\"ü\nü\",ü\nü",
+		"a,\"b\nc",
+	}
+	for _, input := range inputs {
+		splitter := new(linesplit.Splitter)
+		advance, token, _ := splitter.Split([]byte(input), true)
+		expected := -1
+		if string(splitter.CurrentTerminator()) == linesplit.Unix {
+			expected = advance - 1
+		}
+		assert.Equal(t, expected, linesplit.IndexNonQuoted(input, "\n"), "input %q, token %q", input, token)
+		assert.Equal(t, expected, linesplit.IndexNonQuotedBytes([]byte(input), []byte("\n")), "input %q, token %q", input, token)
+	}
+}
+
+// longRecords returns n records, each containing a quoted field of size bytes
+// with embedded newlines, as produced by exports of free-text columns.
+func longRecords(n, size int) string {
+	field := strings.Repeat(strings.Repeat("x", 99)+"\n", size/100)
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteString("1,\"")
+		b.WriteString(field)
+		b.WriteString("\",z\r\n")
+	}
+	return b.String()
+}
+
+func Benchmark_SplitLongRecords(b *testing.B) {
+	data := longRecords(20, 60000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		splitter := new(linesplit.Splitter)
+		scanner := bufio.NewScanner(strings.NewReader(data))
+		scanner.Split(splitter.Split)
+		for scanner.Scan() {
+		}
+	}
+}
+
+// Benchmark_SplitRecordNearBufferCap splits records just under
+// bufio.MaxScanTokenSize, which require the search space to be expanded
+// several times. Because each expansion only searches the new bytes, the cost
+// is linear in the length of the record.
+func Benchmark_SplitRecordNearBufferCap(b *testing.B) {
+	record := strings.Repeat("x", bufio.MaxScanTokenSize-2) + "\n"
+	data := strings.Repeat(record, 10)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		splitter := new(linesplit.Splitter)
+		scanner := bufio.NewScanner(strings.NewReader(data))
+		scanner.Split(splitter.Split)
+		for scanner.Scan() {
+		}
+	}
+}
+
+func Benchmark_IndexNonQuoted(b *testing.B) {
+	data := []byte(longRecords(1, 60000))
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		linesplit.IndexNonQuoted(string(data), linesplit.DOS)
+	}
+}
+
+func Benchmark_IndexNonQuotedBytes(b *testing.B) {
+	data := []byte(longRecords(1, 60000))
+	terminator := []byte(linesplit.DOS)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		linesplit.IndexNonQuotedBytes(data, terminator)
+	}
+}
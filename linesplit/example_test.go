@@ -0,0 +1,27 @@
+package linesplit_test
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/eltorocorp/permissivecsv/linesplit"
+)
+
+// This example demonstrates using a Splitter to locate the records of a file,
+// and the terminator of each record, without parsing the records.
+func ExampleSplitter() {
+	data := "a,b\r\n\"c\nd\",e\nf,g"
+	splitter := new(linesplit.Splitter)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Split(splitter.Split)
+	offset := 0
+	for scanner.Scan() {
+		fmt.Printf("%d %q %q\n", offset, scanner.Text(), splitter.CurrentTerminator())
+		offset += len(scanner.Bytes())
+	}
+	// Output:
+	// 0 "a,b\r\n" "\r\n"
+	// 5 "\"c\nd\",e\n" "\n"
+	// 13 "f,g" ""
+}
@@ -0,0 +1,54 @@
+//go:build go1.18
+// +build go1.18
+
+package linesplit_test
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv/linesplit"
+)
+
+// FuzzSplit asserts that the splitter never panics, never drops or duplicates
+// bytes (the tokens always reassemble into the original input), and that the
+// reported terminator is always a suffix of the token.
+//
+//	go test -run=^$ -fuzz=FuzzSplit
+func FuzzSplit(f *testing.F) {
+	seeds, err := filepath.Glob(filepath.Join("..", "..", "integrationtestdata", "*.csv"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, seed := range seeds {
+		data, err := ioutil.ReadFile(seed)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		splitter := new(linesplit.Splitter)
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 16), bufio.MaxScanTokenSize)
+		scanner.Split(splitter.Split)
+		reassembled := []byte{}
+		for scanner.Scan() {
+			token := scanner.Bytes()
+			if !bytes.HasSuffix(token, splitter.CurrentTerminator()) {
+				t.Fatalf("token %q does not end with terminator %q", token, splitter.CurrentTerminator())
+			}
+			reassembled = append(reassembled, token...)
+		}
+		if scanner.Err() != nil {
+			t.Fatal(scanner.Err())
+		}
+		if !bytes.Equal(data, reassembled) {
+			t.Fatalf("tokens reassemble to %q, expected %q", reassembled, data)
+		}
+	})
+}
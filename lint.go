@@ -0,0 +1,115 @@
+package permissivecsv
+
+import (
+	"fmt"
+	"io"
+)
+
+// LintRule documents a rule checked by Lint. Code is the rule's stable
+// identifier (such as PC001), Kind is the kind of Deviation that the rule
+// reports, and Title and Description explain the rule.
+type LintRule struct {
+	Code        string
+	Kind        DeviationKind
+	Title       string
+	Description string
+}
+
+// LintRules lists the rules checked by Lint, ordered by Code. Codes are never
+// reused, so they can be safely referenced by CI configuration and
+// documentation.
+var LintRules = []LintRule{
+	{
+		Code:        "PC001",
+		Kind:        DeviationMixedTerminators,
+		Title:       "mixed terminators",
+		Description: "A record is terminated differently from the first terminated record of the file. RFC 4180 requires every record to be terminated by CRLF.",
+	},
+	{
+		Code:        "PC002",
+		Kind:        DeviationRaggedRecord,
+		Title:       "ragged row",
+		Description: "A record has a different number of fields from the first record of the file. RFC 4180 requires every record to have the same number of fields.",
+	},
+	{
+		Code:        "PC003",
+		Kind:        DeviationBareCarriageReturn,
+		Title:       "bare carriage return",
+		Description: "A record is terminated by a carriage return that is not followed by a newline. Such files are often misread as a single record.",
+	},
+	{
+		Code:        "PC004",
+		Kind:        DeviationBareQuote,
+		Title:       "bare quote",
+		Description: "A quote appears in an unquoted field, or data appears outside of a quoted field. RFC 4180 requires fields containing quotes to be quoted, and their quotes to be doubled.",
+	},
+	{
+		Code:        "PC005",
+		Kind:        DeviationExtraneousQuote,
+		Title:       "extraneous quote",
+		Description: "A quoted field is not closed, or contains a quote that is not doubled, so it is impossible to tell where the field was meant to end.",
+	},
+	{
+		Code:        "PC006",
+		Kind:        DeviationEmptyRecord,
+		Title:       "empty line",
+		Description: "The file contains an empty line. RFC 4180 does not permit empty records, and most readers skip them.",
+	},
+}
+
+// Code returns the code of the LintRule that reports deviations of kind k
+// (such as PC001), or an empty string if there is no such rule.
+func (k DeviationKind) Code() string {
+	return lintRule(k).Code
+}
+
+// lintRule returns the LintRule that reports deviations of kind k, or a rule
+// with an empty Code and the title k.String() if there is no such rule.
+func lintRule(k DeviationKind) LintRule {
+	for _, rule := range LintRules {
+		if rule.Kind == k {
+			return rule
+		}
+	}
+	return LintRule{Kind: k, Title: k.String()}
+}
+
+// Finding is a violation of a LintRule reported by Lint. Code identifies the
+// rule, and Title is its title. RecordOrdinal and ByteOffset locate the record
+// that violates the rule (see Deviation), and Detail describes the violation.
+type Finding struct {
+	Code          string
+	Title         string
+	RecordOrdinal int
+	ByteOffset    int64
+	Detail        string
+}
+
+func (f *Finding) String() string {
+	return fmt.Sprintf("offset %d (record %d): %s %s: %s", f.ByteOffset, f.RecordOrdinal, f.Code, f.Title, f.Detail)
+}
+
+// Lint scans r with WithStrictRFC4180 (along with any supplied opts), and
+// returns a Finding for each deviation from RFC 4180, in the order in which
+// they were encountered. The result is empty if r complies with every rule in
+// LintRules. The error is the summary's Err (see ScanSummary), in which case
+// the findings cover only the part of r that was scanned.
+func Lint(r io.Reader, opts ...Option) ([]*Finding, error) {
+	opts = append(append([]Option{}, opts...), WithStrictRFC4180())
+	s := NewScanner(r, HeaderCheckAssumeNoHeader, opts...)
+	for s.Scan() {
+	}
+	summary := s.Summary()
+	findings := make([]*Finding, 0, len(summary.Deviations))
+	for _, deviation := range summary.Deviations {
+		rule := lintRule(deviation.Kind)
+		findings = append(findings, &Finding{
+			Code:          rule.Code,
+			Title:         rule.Title,
+			RecordOrdinal: deviation.RecordOrdinal,
+			ByteOffset:    deviation.ByteOffset,
+			Detail:        deviation.Detail,
+		})
+	}
+	return findings, summary.Err
+}
@@ -0,0 +1,52 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Lint(t *testing.T) {
+	findings, err := permissivecsv.Lint(strings.NewReader("a,b\r\nc\n\nd,\"e\r\n"))
+	assert.NoError(t, err)
+	codes := []string{}
+	for _, finding := range findings {
+		codes = append(codes, finding.Code)
+	}
+	assert.Equal(t, []string{"PC001", "PC002", "PC006", "PC001", "PC005"}, codes)
+	assert.Equal(t, "offset 5 (record 2): PC001 mixed terminators: terminator \"\\n\" differs from first terminator \"\\r\\n\"", findings[0].String())
+
+	findings, err = permissivecsv.Lint(strings.NewReader("a,b\r\nc,d\r\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+
+	findings, err = permissivecsv.Lint(strings.NewReader("a\rb"), permissivecsv.WithMaxRecords(1))
+	assert.Equal(t, permissivecsv.ErrLimitReached, err)
+	assert.Equal(t, "PC003", findings[0].Code)
+
+	findings, err = permissivecsv.Lint(BadReader(strings.NewReader("a")))
+	assert.Equal(t, ErrReader.Error(), err.Error())
+	assert.Empty(t, findings)
+}
+
+func Test_LintRules(t *testing.T) {
+	codes := map[string]bool{}
+	kinds := map[permissivecsv.DeviationKind]bool{}
+	for i, rule := range permissivecsv.LintRules {
+		assert.False(t, codes[rule.Code], "duplicate code %s", rule.Code)
+		assert.False(t, kinds[rule.Kind], "duplicate kind %v", rule.Kind)
+		codes[rule.Code] = true
+		kinds[rule.Kind] = true
+		assert.Equal(t, rule.Code, rule.Kind.Code())
+		assert.NotEmpty(t, rule.Title)
+		assert.NotEmpty(t, rule.Description)
+		if i > 0 {
+			assert.True(t, permissivecsv.LintRules[i-1].Code < rule.Code)
+		}
+	}
+	assert.Equal(t, "PC001", permissivecsv.DeviationMixedTerminators.Code())
+	assert.Equal(t, "PC002", permissivecsv.DeviationRaggedRecord.Code())
+	assert.Equal(t, "", permissivecsv.DeviationKind(0).Code())
+}
@@ -0,0 +1,131 @@
+package permissivecsv
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ErrUnsupportedVersion is returned by UnmarshalSegments and UnmarshalIndex if
+// the data was produced by an incompatible version of the format.
+var ErrUnsupportedVersion = fmt.Errorf("unsupported serialization format version")
+
+// serializationVersion is the version of the format produced by
+// MarshalSegments and MarshalIndex. It must be incremented whenever the format
+// changes in a way that older versions of this package can not read.
+const serializationVersion = 1
+
+type segmentsEnvelope struct {
+	Version  int           `json:"version"`
+	Segments []segmentJSON `json:"segments"`
+}
+
+type segmentJSON struct {
+	Ordinal             int64  `json:"ordinal"`
+	LowerOffset         int64  `json:"lower_offset"`
+	Length              int64  `json:"length"`
+	Checksum            uint32 `json:"checksum"`
+	SkippedEmptyRecords int64  `json:"skipped_empty_records"`
+	RecordCount         int64  `json:"record_count"`
+}
+
+type indexEnvelope struct {
+	Version     int     `json:"version"`
+	Interval    int     `json:"interval"`
+	Offsets     []int64 `json:"offsets"`
+	RecordCount int     `json:"record_count"`
+	Size        int64   `json:"size"`
+}
+
+// MarshalSegments encodes segments (as returned by Partition) in a versioned
+// JSON format, so that a partitioning pass on one machine can be distributed
+// to workers on other machines. The result can be decoded by
+// UnmarshalSegments.
+func MarshalSegments(segments []*Segment) ([]byte, error) {
+	envelope := segmentsEnvelope{
+		Version:  serializationVersion,
+		Segments: make([]segmentJSON, len(segments)),
+	}
+	for i, segment := range segments {
+		envelope.Segments[i] = segmentJSON{
+			Ordinal:             segment.Ordinal,
+			LowerOffset:         segment.LowerOffset,
+			Length:              segment.Length,
+			Checksum:            segment.Checksum,
+			SkippedEmptyRecords: segment.SkippedEmptyRecords,
+			RecordCount:         segment.RecordCount,
+		}
+	}
+	return json.Marshal(envelope)
+}
+
+// UnmarshalSegments decodes segments that were encoded by MarshalSegments. If
+// data was produced by an incompatible version of the format,
+// ErrUnsupportedVersion is returned.
+func UnmarshalSegments(data []byte) ([]*Segment, error) {
+	envelope := segmentsEnvelope{}
+	err := json.Unmarshal(data, &envelope)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Version != serializationVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	segments := make([]*Segment, len(envelope.Segments))
+	for i, segment := range envelope.Segments {
+		segments[i] = &Segment{
+			Ordinal:     segment.Ordinal,
+			LowerOffset: segment.LowerOffset,
+			Length:      segment.Length,
+			Checksum:    segment.Checksum,
+
+			SkippedEmptyRecords: segment.SkippedEmptyRecords,
+			RecordCount:         segment.RecordCount,
+		}
+	}
+	return segments, nil
+}
+
+// MarshalIndex encodes index (as returned by BuildIndex) in a versioned JSON
+// format, so that it can be persisted, or distributed to other machines. The
+// result can be decoded by UnmarshalIndex.
+func MarshalIndex(index *RecordIndex) ([]byte, error) {
+	offsets := index.Offsets
+	if offsets == nil {
+		offsets = []int64{}
+	}
+	return json.Marshal(indexEnvelope{
+		Version:     serializationVersion,
+		Interval:    index.Interval,
+		Offsets:     offsets,
+		RecordCount: index.RecordCount,
+		Size:        index.Size,
+	})
+}
+
+// UnmarshalIndex decodes an index that was encoded by MarshalIndex. If data
+// was produced by an incompatible version of the format, ErrUnsupportedVersion
+// is returned. If the index's interval is less than 1,
+// ErrInvalidIndexInterval is returned.
+func UnmarshalIndex(data []byte) (*RecordIndex, error) {
+	envelope := indexEnvelope{}
+	err := json.Unmarshal(data, &envelope)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Version != serializationVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	if envelope.Interval < 1 {
+		return nil, ErrInvalidIndexInterval
+	}
+	offsets := envelope.Offsets
+	if offsets == nil {
+		offsets = []int64{}
+	}
+	return &RecordIndex{
+		Interval:    envelope.Interval,
+		Offsets:     offsets,
+		RecordCount: envelope.RecordCount,
+		Size:        envelope.Size,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MarshalSegmentsRoundTrip(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("h\na\nb\nc"), permissivecsv.HeaderCheckAssumeHeaderExists)
+	segments, err := s.Partition(2, true)
+	assert.NoError(t, err)
+
+	data, err := permissivecsv.MarshalSegments(segments)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"version":1,"segments":[{"ordinal":1,"lower_offset":2,"length":4,"checksum":1386543883,"skipped_empty_records":0,"record_count":2},{"ordinal":2,"lower_offset":6,"length":1,"checksum":552285127,"skipped_empty_records":0,"record_count":1}]}`, string(data))
+
+	result, err := permissivecsv.UnmarshalSegments(data)
+	assert.NoError(t, err)
+	if diff := deep.Equal(segments, result); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func Test_MarshalIndexRoundTrip(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a\nb\nc"), permissivecsv.HeaderCheckAssumeNoHeader)
+	index, err := s.BuildIndex(2)
+	assert.NoError(t, err)
+
+	data, err := permissivecsv.MarshalIndex(index)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"version":1,"interval":2,"offsets":[0,4],"record_count":3,"size":5}`, string(data))
+
+	result, err := permissivecsv.UnmarshalIndex(data)
+	assert.NoError(t, err)
+	if diff := deep.Equal(index, result); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func Test_UnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		fn     func([]byte) error
+		expErr error
+	}{
+		{
+			name:   "segments: unsupported version",
+			data:   `{"version":99,"segments":[]}`,
+			fn:     unmarshalSegments,
+			expErr: permissivecsv.ErrUnsupportedVersion,
+		},
+		{
+			name:   "segments: missing version",
+			data:   `{"segments":[]}`,
+			fn:     unmarshalSegments,
+			expErr: permissivecsv.ErrUnsupportedVersion,
+		},
+		{
+			name:   "index: unsupported version",
+			data:   `{"version":2,"interval":1}`,
+			fn:     unmarshalIndex,
+			expErr: permissivecsv.ErrUnsupportedVersion,
+		},
+		{
+			name:   "index: invalid interval",
+			data:   `{"version":1,"interval":0}`,
+			fn:     unmarshalIndex,
+			expErr: permissivecsv.ErrInvalidIndexInterval,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			assert.Equal(t, test.expErr, test.fn([]byte(test.data)))
+		}
+		t.Run(test.name, testFn)
+	}
+
+	_, err := permissivecsv.UnmarshalSegments([]byte("not json"))
+	assert.Error(t, err)
+	_, err = permissivecsv.UnmarshalIndex([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func unmarshalSegments(data []byte) error {
+	_, err := permissivecsv.UnmarshalSegments(data)
+	return err
+}
+
+func unmarshalIndex(data []byte) error {
+	_, err := permissivecsv.UnmarshalIndex(data)
+	return err
+}
@@ -0,0 +1,82 @@
+package permissivecsv
+
+import "bufio"
+
+// alterationOverhead is the approximate number of bytes used by an Alteration
+// in addition to its strings, and fieldOverhead is the approximate number of
+// bytes used by each field of a record in addition to its contents.
+const (
+	alterationOverhead = 160
+	fieldOverhead      = 16
+)
+
+// WithMemoryBudget bounds the two largest consumers of the Scanner's memory,
+// the read buffer and the summary's Alterations, to approximately n bytes, so
+// that pathological inputs (such as a file with no terminators, or with an
+// alteration on every record) cannot cause them to grow without limit. Half of
+// the budget is available to the buffer used to read records, which limits
+// the length of the longest record (including any records read ahead to
+// identify the header); a longer record stops the scan with a
+// RecordTooLongError (see ScanSummary.Err). The other half is available to the
+// Alterations of the summary. Once it is exhausted, further alterations are
+// still counted by AlterationCount, but are not retained, and are instead
+// counted by DroppedAlterationCount.
+//
+// Nothing else is counted against the budget. In particular, records read
+// ahead by Peek, the keys tracked by WithUniqueColumns (unless
+// WithUniqueColumnsBloomFilter is supplied), and the summary's Deviations,
+// DuplicateKeys, Warnings, and AlterationGroups are not bounded by it.
+//
+// The default is no budget, in which case the length of records is limited to
+// bufio.MaxScanTokenSize (or the limit set by WithAutoGrowBuffer) and every
+// alteration is retained. A value of zero or less leaves the default in place.
+func WithMemoryBudget(n int64) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.memoryBudget = n
+		}
+	}
+}
+
+// maxRecordSize returns the length of the longest record (including its
+// terminator) that the Scanner can read.
+func (o options) maxRecordSize() int {
+	size := bufio.MaxScanTokenSize
+	if o.bufferLimit > size {
+		size = o.bufferLimit
+	}
+	if o.memoryBudget <= 0 || o.memoryBudget/2 >= int64(size) {
+		return size
+	}
+	if o.memoryBudget < 2 {
+		return 1
+	}
+	return int(o.memoryBudget / 2)
+}
+
+// reserveAlterationMemory reports whether alteration can be retained within
+// the memory budget, and if so, accounts for it.
+func (s *Scanner) reserveAlterationMemory(alteration *Alteration) bool {
+	if s.opts.memoryBudget <= 0 {
+		return true
+	}
+	size := int64(alterationOverhead + len(alteration.OriginalData))
+	for _, record := range [][]string{alteration.ResultingRecord, alteration.AlternateRecord} {
+		for _, field := range record {
+			size += int64(fieldOverhead + len(field))
+		}
+	}
+	for _, change := range alteration.FieldChanges {
+		size += int64(2*fieldOverhead + len(change.Original) + len(change.Resulting))
+	}
+	limit := s.opts.memoryBudget - s.opts.memoryBudget/2
+	if s.alterationMemory+size > limit {
+		// Once the budget is exhausted, no further alterations are retained,
+		// even if they would fit, so that the retained alterations are always
+		// the earliest ones.
+		s.alterationMemory = limit
+		return false
+	}
+	s.alterationMemory += size
+	return true
+}
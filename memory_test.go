@@ -0,0 +1,72 @@
+package permissivecsv_test
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithMemoryBudget(t *testing.T) {
+	tests := []struct {
+		name                 string
+		data                 string
+		budget               int64
+		expRecordCount       int
+		expAlterationCount   int
+		expRetained          int
+		expDroppedAlteration int
+		expErr               error
+	}{
+		{
+			name:               "no budget",
+			data:               "a,b\n" + strings.Repeat("1\n", 100),
+			budget:             0,
+			expRecordCount:     101,
+			expAlterationCount: 100,
+			expRetained:        100,
+		},
+		{
+			name:                 "alterations exceed budget",
+			data:                 "a,b\n" + strings.Repeat("1\n", 100),
+			budget:               2000,
+			expRecordCount:       101,
+			expAlterationCount:   100,
+			expRetained:          4,
+			expDroppedAlteration: 96,
+		},
+		{
+			name:           "record exceeds budget",
+			data:           "a,b\n" + strings.Repeat("x", 1000) + "\nc,d",
+			budget:         1000,
+			expRecordCount: 1,
+			expErr:         bufio.ErrTooLong,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists,
+				permissivecsv.WithMemoryBudget(test.budget))
+			for s.Scan() {
+			}
+			summary := s.Summary()
+			if test.expErr == nil {
+				assert.NoError(t, summary.Err)
+			} else {
+				assert.True(t, errors.Is(summary.Err, test.expErr), "got %v", summary.Err)
+			}
+			assert.Equal(t, test.expRecordCount, summary.RecordCount)
+			assert.Equal(t, test.expAlterationCount, summary.AlterationCount)
+			assert.Len(t, summary.Alterations, test.expRetained)
+			assert.Equal(t, test.expDroppedAlteration, summary.DroppedAlterationCount)
+			for i, alteration := range summary.Alterations {
+				assert.Equal(t, i+2, alteration.RecordOrdinal, "the earliest alterations are retained")
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
@@ -0,0 +1,85 @@
+package permissivecsv
+
+// MessageCatalog maps alteration kinds to human-readable messages, such as
+// the Title of a Problem. Catalogs allow data-quality reports to be displayed
+// in languages other than English (or with wording suited to a particular
+// audience), without affecting the stable descriptions returned by
+// AlterationKind.String and stored in Alteration.AlterationDescription.
+//
+// A catalog need not contain every kind. Messages for missing kinds are taken
+// from DefaultMessageCatalog.
+type MessageCatalog map[AlterationKind]string
+
+// DefaultMessageCatalog returns the default (English) catalog. The returned
+// catalog is a copy, which can be modified to override individual messages.
+func DefaultMessageCatalog() MessageCatalog {
+	return MessageCatalog{
+		AlterationBareQuote:       "Record contains a bare quote",
+		AlterationExtraneousQuote: "Record contains an extraneous quote",
+		AlterationTruncatedRecord: "Record has too many fields",
+		AlterationPaddedRecord:    "Record has too few fields",
+		AlterationMergedFields:    "Record has a value that was split by an unquoted comma",
+
+		AlterationJoinedTrailingFields: "Record has extra fields that were joined into the last field",
+		AlterationSwitchedDelimiter:    "Record uses a different delimiter than the rest of the file",
+		AlterationDroppedRecord:        "Record has far too many or too few fields, and was dropped",
+		AlterationFoldedContinuation:   "Record was wrapped across several lines, which were joined",
+	}
+}
+
+var defaultMessageCatalog = DefaultMessageCatalog()
+
+// Message returns the message for kind. If the catalog does not contain kind,
+// the message from DefaultMessageCatalog is returned, and failing that, the
+// kind's description (kind.String()).
+func (c MessageCatalog) Message(kind AlterationKind) string {
+	if message, ok := c[kind]; ok {
+		return message
+	}
+	if message, ok := defaultMessageCatalog[kind]; ok {
+		return message
+	}
+	return kind.String()
+}
+
+// Code returns a stable, machine-readable identifier for the kind, such as
+// ProblemBareQuote. Unlike messages, codes are never localized. The code of a
+// kind allocated by RegisterAlterationKind is its description.
+func (k AlterationKind) Code() string {
+	switch k {
+	case AlterationNone:
+		return "none"
+	case AlterationBareQuote:
+		return ProblemBareQuote
+	case AlterationExtraneousQuote:
+		return ProblemExtraneousQuote
+	case AlterationTruncatedRecord:
+		return ProblemTruncatedRecord
+	case AlterationPaddedRecord:
+		return ProblemPaddedRecord
+	case AlterationMergedFields:
+		return ProblemMergedFields
+	case AlterationJoinedTrailingFields:
+		return ProblemJoinedTrailingFields
+	case AlterationSwitchedDelimiter:
+		return ProblemSwitchedDelimiter
+	case AlterationDroppedRecord:
+		return ProblemDroppedRecord
+	case AlterationFoldedContinuation:
+		return ProblemFoldedContinuation
+	default:
+		return registeredAlterationKind(k)
+	}
+}
+
+// kindFromDescription returns the built-in kind with the supplied description,
+// for alterations whose Kind was not set. For other descriptions, it returns
+// AlterationNone.
+func kindFromDescription(description string) AlterationKind {
+	for k := AlterationBareQuote; k < firstCustomAlterationKind; k++ {
+		if k.String() == description {
+			return k
+		}
+	}
+	return AlterationNone
+}
@@ -0,0 +1,96 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+var customMessageKind = permissivecsv.RegisterAlterationKind("custom message kind")
+
+func Test_MessageCatalog(t *testing.T) {
+	catalog := permissivecsv.MessageCatalog{
+		permissivecsv.AlterationPaddedRecord: "El registro tiene muy pocos campos",
+	}
+	tests := []struct {
+		name       string
+		catalog    permissivecsv.MessageCatalog
+		kind       permissivecsv.AlterationKind
+		expMessage string
+	}{
+		{
+			name:       "overridden message",
+			catalog:    catalog,
+			kind:       permissivecsv.AlterationPaddedRecord,
+			expMessage: "El registro tiene muy pocos campos",
+		},
+		{
+			name:       "default message",
+			catalog:    catalog,
+			kind:       permissivecsv.AlterationBareQuote,
+			expMessage: "Record contains a bare quote",
+		},
+		{
+			name:       "nil catalog",
+			catalog:    nil,
+			kind:       permissivecsv.AlterationTruncatedRecord,
+			expMessage: "Record has too many fields",
+		},
+		{
+			name:       "custom kind",
+			catalog:    catalog,
+			kind:       customMessageKind,
+			expMessage: "custom message kind",
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			assert.Equal(t, test.expMessage, test.catalog.Message(test.kind))
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_DefaultMessageCatalogIsACopy(t *testing.T) {
+	catalog := permissivecsv.DefaultMessageCatalog()
+	catalog[permissivecsv.AlterationBareQuote] = "changed"
+	assert.Equal(t, "Record contains a bare quote", permissivecsv.DefaultMessageCatalog().Message(permissivecsv.AlterationBareQuote))
+}
+
+func Test_AlterationKindCode(t *testing.T) {
+	tests := []struct {
+		kind    permissivecsv.AlterationKind
+		expCode string
+	}{
+		{permissivecsv.AlterationNone, "none"},
+		{permissivecsv.AlterationBareQuote, permissivecsv.ProblemBareQuote},
+		{permissivecsv.AlterationExtraneousQuote, permissivecsv.ProblemExtraneousQuote},
+		{permissivecsv.AlterationTruncatedRecord, permissivecsv.ProblemTruncatedRecord},
+		{permissivecsv.AlterationPaddedRecord, permissivecsv.ProblemPaddedRecord},
+		{permissivecsv.AlterationMergedFields, permissivecsv.ProblemMergedFields},
+		{permissivecsv.AlterationJoinedTrailingFields, permissivecsv.ProblemJoinedTrailingFields},
+		{permissivecsv.AlterationSwitchedDelimiter, permissivecsv.ProblemSwitchedDelimiter},
+		{customMessageKind, "custom message kind"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expCode, test.kind.Code())
+	}
+}
+
+func Test_ToProblemsWithCatalog(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\nc"), permissivecsv.HeaderCheckAssumeNoHeader)
+	for s.Scan() {
+	}
+	catalog := permissivecsv.MessageCatalog{
+		permissivecsv.AlterationPaddedRecord: "El registro tiene muy pocos campos",
+	}
+	problems := s.Summary().ToProblemsWithCatalog(catalog)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "El registro tiene muy pocos campos", problems[0].Title)
+		assert.Equal(t, permissivecsv.ProblemPaddedRecord, problems[0].Code)
+		assert.Equal(t, permissivecsv.AltPaddedRecord, s.Summary().Alterations[0].AlterationDescription)
+	}
+}
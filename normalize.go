@@ -0,0 +1,68 @@
+package permissivecsv
+
+import "io"
+
+// NormalizeOption configures the behavior of Normalize.
+type NormalizeOption func(*normalizeOptions)
+
+type normalizeOptions struct {
+	dialect    Dialect
+	skipHeader bool
+	columns    []string
+}
+
+// WithNormalizeTerminator sets the terminator that Normalize writes after each
+// record. The default is a unix terminator (\n).
+func WithNormalizeTerminator(terminator string) NormalizeOption {
+	return func(o *normalizeOptions) {
+		o.dialect.Terminator = terminator
+	}
+}
+
+// WithNormalizePreserveQuoting instructs Normalize to preserve the original
+// quoting of each field, rather than only quoting fields that require it.
+// Fields that were quoted in the input remain quoted, and fields that were bare
+// remain bare (unless their content requires quotes). This minimizes the
+// differences between the input and output.
+func WithNormalizePreserveQuoting() NormalizeOption {
+	return func(o *normalizeOptions) {
+		o.dialect.Quoting = QuotePreserve
+	}
+}
+
+// WithNormalizeSkipHeader instructs Normalize to omit the first record from its
+// output if RecordIsHeader reports that it is a header.
+func WithNormalizeSkipHeader() NormalizeOption {
+	return func(o *normalizeOptions) {
+		o.skipHeader = true
+	}
+}
+
+// WithNormalizeColumns instructs Normalize to write the supplied columns, in
+// the supplied order, so that a feed can be repaired and reshaped to a target
+// schema in a single pass. Columns are matched to the header's column names
+// after both are normalized by NormalizeColumnName. Columns of the input that
+// are not named are dropped, and named columns that the input lacks are
+// written as blank fields. The header is written with the supplied names
+// (unless WithNormalizeSkipHeader is also supplied). If the input does not have
+// a header, columns are matched by position instead.
+func WithNormalizeColumns(names ...string) NormalizeOption {
+	return func(o *normalizeOptions) {
+		o.columns = append([]string{}, names...)
+	}
+}
+
+// Normalize scans the remainder of the input, and writes each (possibly
+// altered) record to w as standards-compliant CSV, using consistent
+// terminators. By default, a field is quoted only if its content requires it.
+//
+// If w returns an error, Normalize stops scanning and returns that error.
+// Otherwise, Normalize returns any error returned by the underlaying reader
+// (which is also available via the summary).
+func (s *Scanner) Normalize(w io.Writer, opts ...NormalizeOption) (*ScanSummary, error) {
+	o := normalizeOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return s.rewrite(w, o)
+}
@@ -0,0 +1,118 @@
+package permissivecsv_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Normalize(t *testing.T) {
+	tests := []struct {
+		name        string
+		reader      io.Reader
+		headerCheck permissivecsv.HeaderCheck
+		opts        []permissivecsv.NormalizeOption
+		expOutput   string
+		expErr      error
+	}{
+		{
+			name:        "minimal quoting",
+			reader:      strings.NewReader("\"a\",b\r\nc\n\"d,e\",\"\"\"f\"\"\",g\r\" h\",i"),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expOutput:   "a,b\nc,\n\"d,e\",\"\"\"f\"\"\"\n\" h\",i\n",
+		},
+		{
+			name:        "preserve quoting",
+			reader:      strings.NewReader("\"a\",b\r\nc\n\"d,e\",\"\"\"f\"\"\",g\r\"\",i"),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			opts:        []permissivecsv.NormalizeOption{permissivecsv.WithNormalizePreserveQuoting()},
+			expOutput:   "\"a\",b\nc,\n\"d,e\",\"\"\"f\"\"\"\n\"\",i\n",
+		},
+		{
+			name:        "preserve quoting still quotes bare fields that require it",
+			reader:      strings.NewReader("a\rb,c\n"),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			opts:        []permissivecsv.NormalizeOption{permissivecsv.WithNormalizePreserveQuoting()},
+			expOutput:   "\"a\rb\",c\n",
+		},
+		{
+			name:        "terminator and header",
+			reader:      strings.NewReader("h1\na\n\"\""),
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.NormalizeOption{
+				permissivecsv.WithNormalizeTerminator("\r\n"),
+				permissivecsv.WithNormalizeSkipHeader(),
+			},
+			expOutput: "a\r\n\"\"\r\n",
+		},
+		{
+			name:        "column order",
+			reader:      strings.NewReader("ID,First Name,Extra\n1,\"a\",x\n2"),
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.NormalizeOption{
+				permissivecsv.WithNormalizeColumns("first_name", "city", "id"),
+				permissivecsv.WithNormalizePreserveQuoting(),
+			},
+			expOutput: "first_name,city,id\n\"a\",,1\n,,2\n",
+		},
+		{
+			name:        "column order without header",
+			reader:      strings.NewReader("1,a,x\n2,b,y"),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			opts: []permissivecsv.NormalizeOption{
+				permissivecsv.WithNormalizeColumns("id", "name"),
+			},
+			expOutput: "1,a\n2,b\n",
+		},
+		{
+			name:        "column order skipping header",
+			reader:      strings.NewReader("id,name\n1,a"),
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.NormalizeOption{
+				permissivecsv.WithNormalizeColumns("name", "id"),
+				permissivecsv.WithNormalizeSkipHeader(),
+			},
+			expOutput: "a,1\n",
+		},
+		{
+			name:        "reader error",
+			reader:      BadReader(strings.NewReader("a,b")),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expOutput:   "",
+			expErr:      ErrReader,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(test.reader, test.headerCheck)
+			buf := new(bytes.Buffer)
+			_, err := s.Normalize(buf, test.opts...)
+			assert.True(t, errors.Is(err, test.expErr), "got %v", err)
+			assert.Equal(t, test.expOutput, buf.String())
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_NormalizeWriterError(t *testing.T) {
+	data := strings.Repeat("a,b\n", 2000)
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	summary, err := s.Normalize(&failingWriter{limit: 10})
+	assert.Equal(t, ErrWriter, err)
+	assert.True(t, summary.RecordCount < 2000, "scanning should stop once the writer fails")
+}
+
+func Test_NormalizeRoundTrip(t *testing.T) {
+	data := "a,\"b\nc\",\"\"\n\"d\"\"\",,\" e\"\n"
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	buf := new(bytes.Buffer)
+	_, err := s.Normalize(buf, permissivecsv.WithNormalizePreserveQuoting())
+	assert.NoError(t, err)
+	assert.Equal(t, data, buf.String())
+}
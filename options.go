@@ -0,0 +1,352 @@
+package permissivecsv
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/eltorocorp/permissivecsv/linesplit"
+)
+
+// Option configures optional behavior of a Scanner. Options are supplied to
+// NewScanner (or to helpers that construct a Scanner, such as ScanHTTP).
+type Option func(*options)
+
+// options holds the values set by any Options that were supplied when the
+// Scanner was constructed. The zero value represents the default behavior.
+type options struct {
+	httpClient       *http.Client
+	httpMaxAttempts  int
+	httpRetryBackoff time.Duration
+	httpReadTimeout  time.Duration
+	stopAtBlankRun   int
+	keepEmptyRecords bool
+	repairStrategies RepairChain
+	normalizeHeader  bool
+	maxRecords       int
+	maxBytes         int64
+	deadline         time.Time
+	timeout          time.Duration
+
+	disableInvertedDOS  bool
+	terminatorPriority  []string
+	maxQuotedFieldBytes int
+
+	fieldDecoders   map[string]FieldDecoder
+	collisionPolicy CollisionPolicy
+
+	truncation RecordEnd
+	padding    RecordEnd
+
+	lazyQuoteRetry bool
+	expectedHeader []string
+	memoryBudget   int64
+	redactions     []redaction
+	jsonColumns    []jsonColumn
+	safeRecords    bool
+
+	fieldCountTolerance int
+	tolerancePolicy     TolerancePolicy
+
+	checkpointEvery int
+	checkpointFn    func(Checkpoint, *ScanSummary)
+	onEOFValidate   func([]string, *ScanSummary) error
+	trailerPattern  *regexp.Regexp
+	strictRFC4180   bool
+
+	skipRepeatedHeaders   bool
+	skipEmptyFirstRecords bool
+
+	uniqueColumns   []string
+	uniqueBloomKeys int
+	uniqueBloomRate float64
+
+	alterationSampling  map[AlterationKind]int
+	alternateDelimiters []rune
+	bufferLimit         int
+
+	continuationRule func(prev, next []string) bool
+	rowStart         func(line []byte) bool
+
+	tracer Tracer
+
+	clearRecordOnDone bool
+	emptySegments     bool
+	skipLoneHeader    bool
+
+	whitespaceRecordsAsEmpty bool
+	preserveQuotes           bool
+	alterationGrouping       bool
+	ownedReader              bool
+
+	sampling   bool
+	sampleRate float64
+	sampleSeed int64
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		httpMaxAttempts:  3,
+		httpRetryBackoff: time.Second,
+		httpReadTimeout:  time.Minute,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithHTTPClient instructs ScanHTTP to use client rather than its default
+// client. The default client has connection, TLS handshake, and response
+// header timeouts, but no overall timeout, so arbitrarily large files can be
+// streamed.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithHTTPRetry sets how many times ScanHTTP will attempt to (re)connect
+// before giving up, and how long it waits between attempts. The wait doubles
+// after each failed attempt. The default is 3 attempts with a one second
+// backoff.
+func WithHTTPRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		if maxAttempts < 1 {
+			maxAttempts = 1
+		}
+		o.httpMaxAttempts = maxAttempts
+		o.httpRetryBackoff = backoff
+	}
+}
+
+// WithHTTPReadTimeout sets how long ScanHTTP will wait for data from the
+// server before abandoning the connection and attempting to resume. The
+// default is one minute. A value of zero disables the timeout.
+func WithHTTPReadTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.httpReadTimeout = d
+	}
+}
+
+// WithStopAtBlankRun instructs the Scanner to treat n consecutive empty records
+// as the logical end of the file. This accommodates exports that follow their
+// data with a number of blank lines and then some sort of footer. Once a blank
+// run is encountered, the remainder of the input is read and discarded, and
+// the amount of content that was ignored is reported via the Summary. Empty
+// records that precede the first non-empty record are not counted, since
+// there is no data for them to follow. A value of zero (the default) disables
+// this behavior.
+func WithStopAtBlankRun(n int) Option {
+	return func(o *options) {
+		o.stopAtBlankRun = n
+	}
+}
+
+// WithKeepEmptyRecords instructs the Scanner to emit empty records rather than
+// skipping them. Each empty record is emitted as a record of empty fields whose
+// width matches the expected field count, and is not considered an
+// alteration. This is useful for consumers that treat blank lines as
+// meaningful, such as group separators.
+//
+// Leading terminators (those that precede the first non-empty record) and
+// dangling terminators (those that follow the last non-empty record) are still
+// ignored, since there is no data for them to separate.
+func WithKeepEmptyRecords() Option {
+	return func(o *options) {
+		o.keepEmptyRecords = true
+	}
+}
+
+// WithRepairStrategies appends strategies to the chain of RepairStrategies
+// that the Scanner consults before padding or truncating a record. See
+// RepairStrategy for details.
+func WithRepairStrategies(strategies ...RepairStrategy) Option {
+	return func(o *options) {
+		o.repairStrategies = append(o.repairStrategies, strategies...)
+	}
+}
+
+// WithMergeSplitFields instructs the Scanner to attempt to repair records that
+// have exactly one more field than expected because a value containing an
+// unquoted comma (such as Smith, Jr) was split in two. It is equivalent to
+// WithRepairStrategies(RepairMergeSplitFields).
+func WithMergeSplitFields() Option {
+	return WithRepairStrategies(RepairMergeSplitFields)
+}
+
+// WithRaggedRight instructs the Scanner to join any fields beyond the expected
+// field count into the last field, rather than truncating the record, which
+// suits files whose last column contains free text. It is equivalent to
+// WithRepairStrategies(RepairJoinTrailingFields).
+func WithRaggedRight() Option {
+	return WithRepairStrategies(RepairJoinTrailingFields)
+}
+
+// WithLazyQuoteRetry instructs the Scanner to retry parsing records that
+// contain bare or extraneous quotes with relaxed quoting rules (like those of
+// csv.Reader's LazyQuotes), rather than returning a record of blank fields. A
+// quote that appears in an unquoted field is kept as part of the field, and a
+// quote in a quoted field that is not followed by a delimiter is treated as a
+// literal quote. The result is a best effort, so the alteration is still
+// reported, with BestEffort set, and with both candidate records available for
+// auditing.
+func WithLazyQuoteRetry() Option {
+	return func(o *options) {
+		o.lazyQuoteRetry = true
+	}
+}
+
+// RecordEnd identifies the end of a record from which fields are removed when
+// it is truncated, or to which fields are added when it is padded.
+type RecordEnd int
+
+const (
+	// RecordEndTrailing is the end of the record (the right-hand side). This
+	// is the default for both truncation and padding.
+	RecordEndTrailing RecordEnd = iota
+
+	// RecordEndLeading is the beginning of the record (the left-hand side).
+	RecordEndLeading
+)
+
+// WithTruncation sets the end of the record from which surplus fields are
+// removed when a record has more fields than expected. By default, trailing
+// fields are removed. Some producers misalign records by prepending fields
+// (such as a row number), in which case RecordEndLeading keeps the remaining
+// fields under the correct columns.
+func WithTruncation(end RecordEnd) Option {
+	return func(o *options) {
+		o.truncation = end
+	}
+}
+
+// WithPadding sets the end of the record to which blank fields are added when
+// a record has fewer fields than expected. By default, blank fields are
+// appended. RecordEndLeading instead prepends them, which suits producers that
+// omit leading fields rather than trailing ones.
+func WithPadding(end RecordEnd) Option {
+	return func(o *options) {
+		o.padding = end
+	}
+}
+
+// WithNormalizedHeader instructs the Scanner to normalize the column names of
+// the header (see NormalizeColumnNames) before returning it from
+// CurrentRecord. The first record is only normalized if RecordIsHeader reports
+// that it is a header, and the HeaderCheck is always supplied the original
+// first record.
+func WithNormalizedHeader() Option {
+	return func(o *options) {
+		o.normalizeHeader = true
+	}
+}
+
+// WithMaxRecords limits the number of records that Scan will return to n.
+// Once n records have been returned, Scan returns false, and, if the input
+// contains further records, the summary reports that the limit was reached
+// (see ScanSummary.LimitReached). Records that are not returned by Scan (such
+// as those dropped by WithFieldCountTolerance) do not count toward the limit.
+// A value of zero (the default) disables the limit.
+func WithMaxRecords(n int) Option {
+	return func(o *options) {
+		o.maxRecords = n
+	}
+}
+
+// WithMaxBytes limits the amount of input that Scan will process to n bytes.
+// Scan returns false rather than returning a record that would extend beyond
+// the first n bytes of the input, and the summary reports that the limit was
+// reached (see ScanSummary.LimitReached). Records are never split, so the
+// amount of input processed may be less than n. A value of zero (the default)
+// disables the limit.
+func WithMaxBytes(n int64) Option {
+	return func(o *options) {
+		o.maxBytes = n
+	}
+}
+
+// WithDeadline instructs Scan to return false once t has passed, and to report
+// in the summary that the deadline was exceeded (see
+// ScanSummary.DeadlineExceeded). The deadline is checked before each record is
+// read, so Scan cannot interrupt a read that is blocked on the underlaying
+// reader. A zero value (the default) disables the deadline.
+func WithDeadline(t time.Time) Option {
+	return func(o *options) {
+		o.deadline = t
+	}
+}
+
+// WithTimeout is equivalent to WithDeadline, with a deadline of d after Scan
+// is first called. If both WithTimeout and WithDeadline are supplied, the
+// earlier of the two deadlines applies. A value of zero (the default) disables
+// the timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithDisableInvertedDOS prevents the Scanner from recognizing inverted DOS
+// (\n\r) terminators.
+//
+// Inverted DOS terminators are ambiguous. A file that mixes unix and DOS
+// terminators can contain a unix-terminated line followed by a blank
+// DOS-terminated line (\n\r\n). By default, the Scanner reads the first two
+// bytes of such a sequence as an inverted DOS terminator, and the trailing
+// newline as a separate unix terminator, so the terminators (and the byte
+// ranges of any records and empty records that are reported) do not reflect
+// the author's intent. If the input is not expected to contain inverted DOS
+// terminators, this option avoids that misinterpretation.
+func WithDisableInvertedDOS() Option {
+	return func(o *options) {
+		o.disableInvertedDOS = true
+	}
+}
+
+// WithMaxQuotedFieldBytes limits the number of bytes that a quoted field can
+// span before the Scanner presumes that its closing quote is missing. Since
+// terminators within quotes are ignored, a single missing quote would
+// otherwise merge the remainder of the input (or everything up to the next
+// stray quote) into one record. Once a quoted field exceeds n bytes, the
+// record is instead ended at the first terminator within the field, and
+// scanning continues with the following line. The record contains an unclosed
+// quote, so it is reported as a quote alteration (typically
+// AlterationExtraneousQuote), and is counted by
+// ScanSummary.ForceClosedQuoteCount.
+//
+// n should comfortably exceed the longest legitimate quoted field, since a
+// longer field (such as a multi-line comment) is split. A value of zero (the
+// default) disables the limit.
+func WithMaxQuotedFieldBytes(n int) Option {
+	return func(o *options) {
+		o.maxQuotedFieldBytes = n
+	}
+}
+
+// Terminators that can be supplied to WithTerminatorPriority.
+const (
+	TerminatorDOS            = linesplit.DOS
+	TerminatorInvertedDOS    = linesplit.InvertedDOS
+	TerminatorUnix           = linesplit.Unix
+	TerminatorCarriageReturn = linesplit.CarriageReturn
+)
+
+// WithTerminatorPriority overrides the order in which the Scanner prefers
+// terminators when more than one possible terminator is present in the
+// current search space. The default order is TerminatorDOS,
+// TerminatorInvertedDOS, TerminatorUnix, TerminatorCarriageReturn (see
+// Scanner). Any terminators that are not supplied follow the supplied
+// terminators in their default order.
+//
+// The right order depends on the origin of the file. For instance, files
+// exported by older Mac applications use bare carriage returns as terminators.
+// By default, a bare carriage return is only selected if no newline follows it
+// in the search space, so a stray newline causes several such records to be
+// read as one. WithTerminatorPriority(TerminatorCarriageReturn) instead ends
+// each record at the first carriage return, unless a newline precedes it.
+func WithTerminatorPriority(terminators ...string) Option {
+	return func(o *options) {
+		o.terminatorPriority = terminators
+	}
+}
@@ -0,0 +1,651 @@
+package permissivecsv_test
+
+import (
+	"hash/crc32"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithStopAtBlankRun(t *testing.T) {
+	tests := []struct {
+		name              string
+		data              string
+		n                 int
+		expRecords        [][]string
+		expStopped        bool
+		expIgnoredBytes   int64
+		expIgnoredRecords int
+	}{
+		{
+			name: "disabled",
+			data: "a,b\nc,d\n\n\n\nFOOTER",
+			n:    0,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+				[]string{"FOOTER", ""},
+			},
+		},
+		{
+			name: "run shorter than n",
+			data: "a,b\nc,d\n\n\ne,f",
+			n:    3,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+				[]string{"e", "f"},
+			},
+		},
+		{
+			name: "run reaches n",
+			data: "a,b\nc,d\n\n\n\nFOOTER\r\nTOTAL,2",
+			n:    3,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+			expStopped:        true,
+			expIgnoredBytes:   18,
+			expIgnoredRecords: 2,
+		},
+		{
+			name: "leading blank lines are not a run",
+			data: "\n\n\na,b\nc,d\n\n\n\nFOOTER",
+			n:    3,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+			expStopped:        true,
+			expIgnoredBytes:   9,
+			expIgnoredRecords: 1,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data),
+				permissivecsv.HeaderCheckAssumeNoHeader,
+				permissivecsv.WithStopAtBlankRun(test.n))
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.False(t, s.Scan(), "scan after logical EOF")
+			summary := s.Summary()
+			assert.Equal(t, test.expRecords, records)
+			assert.True(t, summary.EOF, "EOF")
+			assert.Equal(t, test.expStopped, summary.StoppedAtBlankRun, "stopped")
+			assert.Equal(t, test.expIgnoredBytes, summary.IgnoredBytes, "ignored bytes")
+			assert.Equal(t, test.expIgnoredRecords, summary.IgnoredRecords, "ignored records")
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithKeepEmptyRecords(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		expRecords    [][]string
+		expEmptyCount int
+		expPartitions []*permissivecsv.Segment
+	}{
+		{
+			name: "interior empty records are emitted",
+			data: "a,b\n\n\nc,d\r\n\r\ne,f",
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"", ""},
+				[]string{"", ""},
+				[]string{"c", "d"},
+				[]string{"", ""},
+				[]string{"e", "f"},
+			},
+			expEmptyCount: 3,
+			expPartitions: []*permissivecsv.Segment{
+				&permissivecsv.Segment{Ordinal: 1, LowerOffset: 0, Length: 5, RecordCount: 2},
+				&permissivecsv.Segment{Ordinal: 2, LowerOffset: 5, Length: 6, RecordCount: 2},
+				&permissivecsv.Segment{Ordinal: 3, LowerOffset: 11, Length: 5, RecordCount: 2},
+			},
+		},
+		{
+			name: "leading and dangling terminators are ignored",
+			data: "\n\na,b\nc,d\n\n\n",
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+			expEmptyCount: 4,
+			expPartitions: []*permissivecsv.Segment{
+				&permissivecsv.Segment{Ordinal: 1, LowerOffset: 0, Length: 12, SkippedEmptyRecords: 4, RecordCount: 2},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data),
+				permissivecsv.HeaderCheckAssumeNoHeader,
+				permissivecsv.WithKeepEmptyRecords())
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, len(test.expRecords), s.Summary().RecordCount, "record count")
+			assert.Equal(t, test.expEmptyCount, s.Summary().EmptyRecordCount, "empty record count")
+			assert.Empty(t, s.Summary().Alterations, "alterations")
+
+			s = permissivecsv.NewScanner(strings.NewReader(test.data),
+				permissivecsv.HeaderCheckAssumeNoHeader,
+				permissivecsv.WithKeepEmptyRecords())
+			for _, segment := range test.expPartitions {
+				b := test.data[segment.LowerOffset : segment.LowerOffset+segment.Length]
+				segment.Checksum = crc32.Checksum([]byte(b), crc32.MakeTable(crc32.Castagnoli))
+			}
+			partitions, err := s.Partition(2, false)
+			assert.NoError(t, err)
+			diff := deep.Equal(test.expPartitions, partitions)
+			if diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithMergeSplitFields(t *testing.T) {
+	data := "last,first,city\n" +
+		"Smith, Jr,\"John\",Austin\n" +
+		"Doe,Jane,Reno,extra\n" +
+		"\"Lee\", Jr,Ann,Waco\n" +
+		"A, B, C,D"
+
+	s := permissivecsv.NewScanner(strings.NewReader(data),
+		permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithMergeSplitFields())
+	records := [][]string{}
+	states := [][]permissivecsv.FieldState{}
+	for s.Scan() {
+		records = append(records, s.CurrentRecord())
+		states = append(states, s.CurrentFieldStates())
+	}
+	expRecords := [][]string{
+		[]string{"last", "first", "city"},
+		[]string{"Smith, Jr", "John", "Austin"},
+		[]string{"Doe", "Jane", "Reno"},
+		[]string{"Lee", " Jr", "Ann"},
+		[]string{"A", " B", " C"},
+	}
+	assert.Equal(t, expRecords, records)
+	assert.Equal(t, []permissivecsv.FieldState{
+		permissivecsv.FieldBare,
+		permissivecsv.FieldQuoted,
+		permissivecsv.FieldBare,
+	}, states[1])
+
+	expKinds := []permissivecsv.AlterationKind{
+		permissivecsv.AlterationMergedFields,
+		permissivecsv.AlterationTruncatedRecord,
+		permissivecsv.AlterationTruncatedRecord,
+		permissivecsv.AlterationTruncatedRecord,
+	}
+	kinds := []permissivecsv.AlterationKind{}
+	for _, alteration := range s.Summary().Alterations {
+		kinds = append(kinds, alteration.Kind)
+	}
+	assert.Equal(t, expKinds, kinds)
+
+	merge := s.Summary().Alterations[0]
+	assert.Equal(t, permissivecsv.AltMergedFields, merge.AlterationDescription)
+	assert.Equal(t, []string{"Smith, Jr", "John", "Austin"}, merge.ResultingRecord)
+	assert.Equal(t, []string{"Smith", " Jr", "John"}, merge.AlternateRecord)
+	assert.Nil(t, s.Summary().Alterations[1].AlternateRecord)
+
+	s = permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists)
+	s.Scan()
+	s.Scan()
+	assert.Equal(t, []string{"Smith", " Jr", "John"}, s.CurrentRecord(), "merging is disabled by default")
+}
+
+func Test_WithRaggedRight(t *testing.T) {
+	data := "id,level,message\n" +
+		"1,info,started\n" +
+		"2,warn,disk low, 10% free, retrying\n" +
+		"3,error\n" +
+		"4,info,\"quoted, once\",again"
+
+	s := permissivecsv.NewScanner(strings.NewReader(data),
+		permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithRaggedRight())
+	records := [][]string{}
+	for s.Scan() {
+		records = append(records, s.CurrentRecord())
+	}
+	expRecords := [][]string{
+		[]string{"id", "level", "message"},
+		[]string{"1", "info", "started"},
+		[]string{"2", "warn", "disk low, 10% free, retrying"},
+		[]string{"3", "error", ""},
+		[]string{"4", "info", "quoted, once,again"},
+	}
+	assert.Equal(t, expRecords, records)
+
+	expKinds := []permissivecsv.AlterationKind{
+		permissivecsv.AlterationJoinedTrailingFields,
+		permissivecsv.AlterationPaddedRecord,
+		permissivecsv.AlterationJoinedTrailingFields,
+	}
+	kinds := []permissivecsv.AlterationKind{}
+	for _, alteration := range s.Summary().Alterations {
+		kinds = append(kinds, alteration.Kind)
+	}
+	assert.Equal(t, expKinds, kinds)
+	assert.Equal(t, permissivecsv.AltJoinedTrailingFields, s.Summary().Alterations[0].AlterationDescription)
+}
+
+func Test_WithTruncationAndPadding(t *testing.T) {
+	data := "a,b,c\n1,2,3,4\n\"x\",y"
+	tests := []struct {
+		name       string
+		opts       []permissivecsv.Option
+		expRecords [][]string
+		expStates  [][]permissivecsv.FieldState
+	}{
+		{
+			name: "default",
+			opts: []permissivecsv.Option{},
+			expRecords: [][]string{
+				[]string{"a", "b", "c"},
+				[]string{"1", "2", "3"},
+				[]string{"x", "y", ""},
+			},
+			expStates: [][]permissivecsv.FieldState{
+				[]permissivecsv.FieldState{permissivecsv.FieldBare, permissivecsv.FieldBare, permissivecsv.FieldBare},
+				[]permissivecsv.FieldState{permissivecsv.FieldBare, permissivecsv.FieldBare, permissivecsv.FieldBare},
+				[]permissivecsv.FieldState{permissivecsv.FieldQuoted, permissivecsv.FieldBare, permissivecsv.FieldMissing},
+			},
+		},
+		{
+			name: "leading",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithTruncation(permissivecsv.RecordEndLeading),
+				permissivecsv.WithPadding(permissivecsv.RecordEndLeading),
+			},
+			expRecords: [][]string{
+				[]string{"a", "b", "c"},
+				[]string{"2", "3", "4"},
+				[]string{"", "x", "y"},
+			},
+			expStates: [][]permissivecsv.FieldState{
+				[]permissivecsv.FieldState{permissivecsv.FieldBare, permissivecsv.FieldBare, permissivecsv.FieldBare},
+				[]permissivecsv.FieldState{permissivecsv.FieldBare, permissivecsv.FieldBare, permissivecsv.FieldBare},
+				[]permissivecsv.FieldState{permissivecsv.FieldMissing, permissivecsv.FieldQuoted, permissivecsv.FieldBare},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists, test.opts...)
+			records := [][]string{}
+			states := [][]permissivecsv.FieldState{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+				states = append(states, s.CurrentFieldStates())
+			}
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expStates, states)
+			alterations := s.Summary().Alterations
+			if assert.Len(t, alterations, 2) {
+				assert.Equal(t, permissivecsv.AlterationTruncatedRecord, alterations[0].Kind)
+				assert.Equal(t, test.expRecords[1], alterations[0].ResultingRecord)
+				assert.Equal(t, permissivecsv.AlterationPaddedRecord, alterations[1].Kind)
+				assert.Equal(t, test.expRecords[2], alterations[1].ResultingRecord)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithLazyQuoteRetry(t *testing.T) {
+	data := "a,b,c\n1,2 \"inch\",3\n\"x\"y,z\n4,5,6"
+
+	s := permissivecsv.NewScanner(strings.NewReader(data),
+		permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithLazyQuoteRetry())
+	records := [][]string{}
+	for s.Scan() {
+		records = append(records, s.CurrentRecord())
+	}
+	expRecords := [][]string{
+		[]string{"a", "b", "c"},
+		[]string{"1", "2 \"inch\"", "3"},
+		[]string{"x\"y,z", "", ""},
+		[]string{"4", "5", "6"},
+	}
+	assert.Equal(t, expRecords, records)
+
+	alterations := s.Summary().Alterations
+	if assert.Len(t, alterations, 2) {
+		assert.Equal(t, permissivecsv.AlterationBareQuote, alterations[0].Kind)
+		assert.Equal(t, permissivecsv.AlterationExtraneousQuote, alterations[1].Kind)
+		for i, alteration := range alterations {
+			assert.True(t, alteration.BestEffort)
+			assert.Equal(t, expRecords[i+1], alteration.ResultingRecord)
+			assert.Equal(t, []string{"", "", ""}, alteration.AlternateRecord)
+		}
+	}
+
+	s = permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists)
+	s.Scan()
+	s.Scan()
+	assert.Equal(t, []string{"", "", ""}, s.CurrentRecord(), "retrying is disabled by default")
+	assert.False(t, s.Summary().Alterations[0].BestEffort)
+	assert.Nil(t, s.Summary().Alterations[0].AlternateRecord)
+}
+
+func Test_WithNormalizedHeader(t *testing.T) {
+	data := "\ufeffUser ID,First Name,First Name\n1,a,b"
+	var headerCheckInput []string
+	headerCheck := func(firstRecord, secondRecord []string) bool {
+		if firstRecord != nil {
+			headerCheckInput = firstRecord
+		}
+		return firstRecord != nil
+	}
+
+	s := permissivecsv.NewScanner(strings.NewReader(data), headerCheck, permissivecsv.WithNormalizedHeader())
+	records := [][]string{}
+	for s.Scan() {
+		records = append(records, s.CurrentRecord())
+	}
+	expRecords := [][]string{
+		[]string{"user_id", "first_name", "first_name_2"},
+		[]string{"1", "a", "b"},
+	}
+	assert.Equal(t, expRecords, records)
+	assert.Equal(t, []string{"\ufeffUser ID", "First Name", "First Name"}, headerCheckInput)
+
+	s = permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader, permissivecsv.WithNormalizedHeader())
+	s.Scan()
+	assert.Equal(t, []string{"\ufeffUser ID", "First Name", "First Name"}, s.CurrentRecord(), "records that are not headers are not normalized")
+}
+
+func Test_WithMaxRecordsAndBytes(t *testing.T) {
+	tests := []struct {
+		name            string
+		data            string
+		opts            []permissivecsv.Option
+		expRecords      [][]string
+		expLimitReached bool
+		expEOF          bool
+		expResumeOffset int64
+	}{
+		{
+			name: "record limit reached",
+			data: "a,b\nc,d\ne,f",
+			opts: []permissivecsv.Option{permissivecsv.WithMaxRecords(2)},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+			expLimitReached: true,
+			expEOF:          false,
+			expResumeOffset: 8,
+		},
+		{
+			name: "record limit not reached",
+			data: "a,b\nc,d\n\n",
+			opts: []permissivecsv.Option{permissivecsv.WithMaxRecords(2)},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+			expLimitReached: false,
+			expEOF:          true,
+			expResumeOffset: 0,
+		},
+		{
+			name: "record limit with kept empty records",
+			data: "a,b\n\nc,d",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithMaxRecords(1),
+				permissivecsv.WithKeepEmptyRecords(),
+			},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+			},
+			expLimitReached: true,
+			expEOF:          false,
+			expResumeOffset: 4,
+		},
+		{
+			name: "byte limit reached",
+			data: "a,b\nc,d\ne,f",
+			opts: []permissivecsv.Option{permissivecsv.WithMaxBytes(9)},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+			expLimitReached: true,
+			expEOF:          false,
+			expResumeOffset: 8,
+		},
+		{
+			name: "byte limit not reached",
+			data: "a,b\nc,d\ne,f",
+			opts: []permissivecsv.Option{permissivecsv.WithMaxBytes(11)},
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+				[]string{"e", "f"},
+			},
+			expLimitReached: false,
+			expEOF:          true,
+			expResumeOffset: 0,
+		},
+		{
+			name:            "byte limit smaller than first record",
+			data:            "a,b\nc,d",
+			opts:            []permissivecsv.Option{permissivecsv.WithMaxBytes(2)},
+			expRecords:      [][]string{},
+			expLimitReached: true,
+			expEOF:          false,
+			expResumeOffset: 0,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.False(t, s.Scan(), "Scan must continue to return false")
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, len(test.expRecords), s.Summary().RecordCount)
+			assert.Equal(t, test.expLimitReached, s.Summary().LimitReached)
+			assert.Equal(t, test.expEOF, s.Summary().EOF)
+			assert.Equal(t, test.expResumeOffset, s.Summary().ResumeOffset)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithMaxRecordsExcludesDroppedRecords(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\nc\nd,e\nf,g"),
+		permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithMaxRecords(2),
+		permissivecsv.WithFieldCountTolerance(0, permissivecsv.ToleranceDrop))
+	records := [][]string{}
+	for s.Scan() {
+		records = append(records, s.CurrentRecord())
+	}
+	summary := s.Summary()
+	assert.Equal(t, [][]string{{"a", "b"}, {"d", "e"}}, records)
+	assert.Equal(t, 1, summary.DroppedRecordCount)
+	assert.Equal(t, 3, summary.RecordCount)
+	assert.True(t, summary.LimitReached)
+	assert.Equal(t, int64(10), summary.ResumeOffset)
+}
+
+// slowReader returns one line of data per call to Read, after a delay.
+type slowReader struct {
+	lines []string
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.lines) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	n := copy(p, r.lines[0])
+	r.lines[0] = r.lines[0][n:]
+	if r.lines[0] == "" {
+		r.lines = r.lines[1:]
+	}
+	return n, nil
+}
+
+func Test_WithDeadline(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\nc,d"),
+		permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithDeadline(time.Now().Add(-time.Second)))
+	assert.False(t, s.Scan())
+	assert.True(t, s.Summary().DeadlineExceeded)
+	assert.False(t, s.Summary().EOF)
+	assert.Equal(t, 0, s.Summary().RecordCount)
+
+	s = permissivecsv.NewScanner(strings.NewReader("a,b\nc,d"),
+		permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithDeadline(time.Now().Add(time.Hour)))
+	for s.Scan() {
+	}
+	assert.False(t, s.Summary().DeadlineExceeded)
+	assert.True(t, s.Summary().EOF)
+	assert.Equal(t, 2, s.Summary().RecordCount)
+}
+
+func Test_WithTimeout(t *testing.T) {
+	lines := []string{}
+	for i := 0; i < 100; i++ {
+		lines = append(lines, "a,b\n")
+	}
+	s := permissivecsv.NewScanner(&slowReader{lines: lines, delay: 5 * time.Millisecond},
+		permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithTimeout(50*time.Millisecond))
+	var last *permissivecsv.RecordInfo
+	for s.Scan() {
+		last = s.CurrentRecordInfo()
+	}
+	summary := s.Summary()
+	assert.True(t, summary.DeadlineExceeded)
+	assert.False(t, summary.EOF)
+	assert.True(t, summary.RecordCount > 0 && summary.RecordCount < 100, "expected a partial scan")
+	assert.Equal(t, last.ByteOffset+last.ByteLength, summary.ResumeOffset)
+	assert.False(t, s.Scan(), "Scan must continue to return false")
+}
+
+func Test_WithDisableInvertedDOS(t *testing.T) {
+	data := "a,b\n\r\nc,d\r\n"
+	terminators := func(opts ...permissivecsv.Option) []string {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader, opts...)
+		result := []string{}
+		for s.Scan() {
+			result = append(result, s.CurrentRecordInfo().Terminator)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"\n\r", "\r\n"}, terminators())
+	assert.Equal(t, []string{"\n", "\r\n"}, terminators(permissivecsv.WithDisableInvertedDOS()))
+	assert.Equal(t, []string{"\n", "\r\n", "\r\n"}, terminators(permissivecsv.WithDisableInvertedDOS(), permissivecsv.WithKeepEmptyRecords()))
+}
+
+func Test_WithTerminatorPriority(t *testing.T) {
+	data := "a,b\rc,d\re,f\ng,h\r"
+	scan := func(opts ...permissivecsv.Option) [][]string {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader, opts...)
+		records := [][]string{}
+		for s.Scan() {
+			records = append(records, s.CurrentRecord())
+		}
+		return records
+	}
+	assert.Equal(t, [][]string{
+		[]string{"a", "b\rc", "d\re", "f"},
+		[]string{"g", "h", "", ""},
+	}, scan())
+	assert.Equal(t, [][]string{
+		[]string{"a", "b"},
+		[]string{"c", "d"},
+		[]string{"e", "f"},
+		[]string{"g", "h"},
+	}, scan(permissivecsv.WithTerminatorPriority(permissivecsv.TerminatorCarriageReturn)))
+}
+
+func Test_WithMaxQuotedFieldBytes(t *testing.T) {
+	data := "id,note\n0,\"multi\nline\"\n1,\"missing quote\n2,two\n3,three\n"
+	tests := []struct {
+		name           string
+		opts           []permissivecsv.Option
+		expRecords     [][]string
+		expForceClosed []bool
+		expCount       int
+	}{
+		{
+			name: "no limit",
+			opts: nil,
+			expRecords: [][]string{
+				{"id", "note"},
+				{"0", "multi\nline"},
+				{"", ""},
+			},
+			expForceClosed: []bool{false, false, false},
+			expCount:       0,
+		},
+		{
+			name: "limit exceeded",
+			opts: []permissivecsv.Option{permissivecsv.WithMaxQuotedFieldBytes(20)},
+			expRecords: [][]string{
+				{"id", "note"},
+				{"0", "multi\nline"},
+				{"", ""},
+				{"2", "two"},
+				{"3", "three"},
+			},
+			expForceClosed: []bool{false, false, true, false, false},
+			expCount:       1,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists, test.opts...)
+			records := [][]string{}
+			forceClosed := []bool{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+				forceClosed = append(forceClosed, s.CurrentRecordInfo().QuoteForceClosed)
+			}
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expForceClosed, forceClosed)
+			summary := s.Summary()
+			assert.Equal(t, test.expCount, summary.ForceClosedQuoteCount)
+			if assert.Len(t, summary.Alterations, 1) {
+				assert.Equal(t, permissivecsv.AlterationExtraneousQuote, summary.Alterations[0].Kind)
+				assert.Equal(t, 3, summary.Alterations[0].RecordOrdinal)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
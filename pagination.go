@@ -0,0 +1,35 @@
+package permissivecsv
+
+// AlterationsPage returns at most limit of the retained alterations, starting
+// with the alteration at index offset of Alterations, which allows a large
+// number of alterations to be presented (or returned by an API) a page at a
+// time. The returned slice is a copy, so it can be modified without affecting
+// the summary. It is empty if offset is beyond the last alteration, or if
+// limit is less than 1.
+func (s *ScanSummary) AlterationsPage(offset, limit int) []*Alteration {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 1 || offset >= len(s.Alterations) {
+		return []*Alteration{}
+	}
+	end := len(s.Alterations)
+	if limit < end-offset {
+		end = offset + limit
+	}
+	return append([]*Alteration{}, s.Alterations[offset:end]...)
+}
+
+// AlterationsByKind returns the retained alterations of the supplied kind, in
+// the order in which they were made. Alterations that were not retained (see
+// DroppedAlterationCount) are not included, but are counted by
+// AlterationKindCounts.
+func (s *ScanSummary) AlterationsByKind(kind AlterationKind) []*Alteration {
+	alterations := []*Alteration{}
+	for _, alteration := range s.Alterations {
+		if alteration.Kind == kind {
+			alterations = append(alterations, alteration)
+		}
+	}
+	return alterations
+}
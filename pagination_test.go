@@ -0,0 +1,62 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AlterationsPage(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\n1\n2,3,4\n5\n6,7,8\n9"), permissivecsv.HeaderCheckAssumeHeaderExists)
+	for s.Scan() {
+	}
+	summary := s.Summary()
+
+	tests := []struct {
+		name        string
+		offset      int
+		limit       int
+		expOrdinals []int
+	}{
+		{name: "first page", offset: 0, limit: 2, expOrdinals: []int{2, 3}},
+		{name: "middle page", offset: 2, limit: 2, expOrdinals: []int{4, 5}},
+		{name: "partial last page", offset: 4, limit: 2, expOrdinals: []int{6}},
+		{name: "offset beyond end", offset: 5, limit: 2, expOrdinals: []int{}},
+		{name: "negative offset", offset: -1, limit: 1, expOrdinals: []int{2}},
+		{name: "zero limit", offset: 0, limit: 0, expOrdinals: []int{}},
+		{name: "limit beyond end", offset: 3, limit: 100, expOrdinals: []int{5, 6}},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			ordinals := []int{}
+			for _, alteration := range summary.AlterationsPage(test.offset, test.limit) {
+				ordinals = append(ordinals, alteration.RecordOrdinal)
+			}
+			assert.Equal(t, test.expOrdinals, ordinals)
+		}
+		t.Run(test.name, testFn)
+	}
+
+	page := summary.AlterationsPage(0, 2)
+	page[0] = nil
+	assert.NotNil(t, summary.Alterations[0])
+}
+
+func Test_AlterationsByKind(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\n1\n2,3,4\n5\n6,7,8\n9"), permissivecsv.HeaderCheckAssumeHeaderExists)
+	for s.Scan() {
+	}
+	ordinals := func(kind permissivecsv.AlterationKind) []int {
+		result := []int{}
+		for _, alteration := range s.Summary().AlterationsByKind(kind) {
+			result = append(result, alteration.RecordOrdinal)
+		}
+		return result
+	}
+	assert.Equal(t, []int{2, 4, 6}, ordinals(permissivecsv.AlterationPaddedRecord))
+	assert.Equal(t, []int{3, 5}, ordinals(permissivecsv.AlterationTruncatedRecord))
+	assert.Equal(t, []int{}, ordinals(permissivecsv.AlterationBareQuote))
+}
@@ -0,0 +1,39 @@
+package permissivecsv
+
+// Peek returns up to n raw records that follow the current position, without
+// advancing the Scanner, so that consumers can make decisions that depend on
+// the records that follow (such as combining continuation rows) while still
+// streaming the input. Any input that must be read to locate the records is
+// retained for subsequent calls to Scan, so at most n records are buffered.
+//
+// The records are only parsed from the input with commas. None of the
+// processing Scan applies to a record is applied, so peeked records are not
+// padded or truncated to the expected field count, continuation rows are not
+// folded (see WithContinuationRule), and records that Scan would drop or skip
+// (see WithFieldCountTolerance and WithSampleRate) are still included. A
+// record that cannot be parsed (such as one with an unclosed quote) is nil.
+// Empty records are not included, even if they will be returned by Scan (see
+// WithKeepEmptyRecords). Limits set by WithMaxRecords and WithMaxBytes are
+// ignored.
+//
+// Fewer than n records are returned if the input ends (or a blank run is
+// encountered, see WithStopAtBlankRun) before n records are found. Peek
+// returns an empty slice once scanning has stopped, or if the reader is nil.
+func (s *Scanner) Peek(n int) [][]string {
+	records := [][]string{}
+	if s.reader == nil || s.state != ScannerStateScanning {
+		return records
+	}
+	if s.pendingRawRecord != nil && len(records) < n {
+		records = append(records, peekFields(*s.pendingRawRecord))
+	}
+	for i := 0; len(records) < n; i++ {
+		token, j, ok := s.peekTokenFrom(i)
+		if !ok {
+			break
+		}
+		records = append(records, peekFields(token))
+		i = j
+	}
+	return records
+}
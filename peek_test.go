@@ -0,0 +1,124 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Peek(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		opts    []permissivecsv.Option
+		scans   int
+		n       int
+		expPeek [][]string
+	}{
+		{
+			name:  "before first scan",
+			data:  "a,b\nc,d\ne,f",
+			scans: 0,
+			n:     2,
+			expPeek: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+		},
+		{
+			name:  "beyond end of input",
+			data:  "a,b\nc,d\n\n\ne,f\n",
+			scans: 1,
+			n:     5,
+			expPeek: [][]string{
+				[]string{"c", "d"},
+				[]string{"e", "f"},
+			},
+		},
+		{
+			name:  "records are not altered",
+			data:  "a,b\nc\nd,e,f",
+			scans: 1,
+			n:     2,
+			expPeek: [][]string{
+				[]string{"c"},
+				[]string{"d", "e", "f"},
+			},
+		},
+		{
+			name:  "unparsable record",
+			data:  "a,b\nc\"d\"e\nf,g",
+			scans: 1,
+			n:     2,
+			expPeek: [][]string{
+				nil,
+				[]string{"f", "g"},
+			},
+		},
+		{
+			name:  "kept empty records are skipped",
+			data:  "a,b\n\nc,d",
+			opts:  []permissivecsv.Option{permissivecsv.WithKeepEmptyRecords()},
+			scans: 1,
+			n:     1,
+			expPeek: [][]string{
+				[]string{"c", "d"},
+			},
+		},
+		{
+			name:    "blank run",
+			data:    "a,b\n\n\nc,d",
+			opts:    []permissivecsv.Option{permissivecsv.WithStopAtBlankRun(2)},
+			scans:   1,
+			n:       1,
+			expPeek: [][]string{},
+		},
+		{
+			name:    "zero records",
+			data:    "a,b\nc,d",
+			scans:   0,
+			n:       0,
+			expPeek: [][]string{},
+		},
+		{
+			name:    "after end of input",
+			data:    "a,b",
+			scans:   2,
+			n:       1,
+			expPeek: [][]string{},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			expected := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			expRecords := [][]string{}
+			for expected.Scan() {
+				expRecords = append(expRecords, expected.CurrentRecord())
+			}
+
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			records := [][]string{}
+			for i := 0; i < test.scans; i++ {
+				if s.Scan() {
+					records = append(records, s.CurrentRecord())
+				}
+			}
+			assert.Equal(t, test.expPeek, s.Peek(test.n))
+			assert.Equal(t, test.expPeek, s.Peek(test.n), "peeking again returns the same records")
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, expRecords, records, "peeking does not alter the records returned by Scan")
+			assert.Equal(t, expected.Summary(), s.Summary())
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_PeekNilReader(t *testing.T) {
+	s := permissivecsv.NewScanner(nil, permissivecsv.HeaderCheckAssumeNoHeader)
+	assert.Equal(t, [][]string{}, s.Peek(1))
+}
@@ -0,0 +1,231 @@
+package permissivecsvtest
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/eltorocorp/permissivecsv"
+)
+
+// ExpectedAlteration is an alteration that a Scanner is expected to make to
+// the record with the given ordinal.
+type ExpectedAlteration struct {
+	RecordOrdinal int
+	Kind          permissivecsv.AlterationKind
+}
+
+// Builder builds CSV data that contains known defects. Each method appends a
+// record (or an empty record) to the data, and notes the alteration, if any,
+// that a Scanner using the default options is expected to make to it. The
+// first record determines the expected field count; use NewBuilder to begin
+// with a header.
+//
+// The zero value is an empty Builder that terminates records with a unix
+// terminator.
+type Builder struct {
+	buf        bytes.Buffer
+	terminator string
+	fieldCount int
+	records    int
+	expected   []ExpectedAlteration
+
+	// pending is the terminator of the last record, which is written when
+	// another record follows it.
+	pending string
+}
+
+// NewBuilder returns a Builder whose first record is a header containing
+// columns.
+func NewBuilder(columns ...string) *Builder {
+	b := &Builder{}
+	if len(columns) > 0 {
+		b.Record(columns...)
+	}
+	return b
+}
+
+// Terminator sets the terminator written after subsequent records, such as
+// "\r\n". The last record of the data is not terminated.
+func (b *Builder) Terminator(terminator string) *Builder {
+	b.terminator = terminator
+	return b
+}
+
+// Record appends a record containing fields, which are quoted if necessary.
+func (b *Builder) Record(fields ...string) *Builder {
+	return b.raw(join(fields), len(fields), permissivecsv.AlterationNone)
+}
+
+// Short appends a record containing fields, where fields is expected to be
+// shorter than the first record, so that it is padded
+// (AlterationPaddedRecord). fields must not be empty, as a record with no
+// fields is an empty record (see Empty).
+func (b *Builder) Short(fields ...string) *Builder {
+	return b.fitted(fields)
+}
+
+// Long appends a record containing fields, where fields is expected to be
+// longer than the first record, so that it is truncated
+// (AlterationTruncatedRecord).
+func (b *Builder) Long(fields ...string) *Builder {
+	return b.fitted(fields)
+}
+
+func (b *Builder) fitted(fields []string) *Builder {
+	kind := permissivecsv.AlterationNone
+	if b.records > 0 && len(fields) < b.fieldCount {
+		kind = permissivecsv.AlterationPaddedRecord
+	} else if b.records > 0 && len(fields) > b.fieldCount {
+		kind = permissivecsv.AlterationTruncatedRecord
+	}
+	return b.raw(join(fields), len(fields), kind)
+}
+
+// BareQuote appends a record containing quotes within an unquoted field,
+// which the Scanner replaces with blank fields (AlterationBareQuote). The
+// quotes are balanced, so that the record's terminator is not quoted.
+func (b *Builder) BareQuote() *Builder {
+	return b.raw(b.filler(`bare"quote"`), b.fieldCount, permissivecsv.AlterationBareQuote)
+}
+
+// ExtraneousQuote appends a record containing a quoted field that is followed
+// by more data, which the Scanner replaces with blank fields
+// (AlterationExtraneousQuote).
+func (b *Builder) ExtraneousQuote() *Builder {
+	return b.raw(b.filler(`"extraneous"quote`), b.fieldCount, permissivecsv.AlterationExtraneousQuote)
+}
+
+// Empty appends n empty records (terminators with no data), which the Scanner
+// skips by default.
+func (b *Builder) Empty(n int) *Builder {
+	for i := 0; i < n; i++ {
+		b.terminate()
+		b.pending = b.currentTerminator()
+	}
+	return b
+}
+
+// filler returns a record whose first field is field, padded with further
+// fields to the expected field count.
+func (b *Builder) filler(field string) string {
+	fields := []string{field}
+	for i := 1; i < b.fieldCount; i++ {
+		fields = append(fields, "x")
+	}
+	return strings.Join(fields, ",")
+}
+
+// raw appends text as a record of fieldCount fields.
+func (b *Builder) raw(text string, fieldCount int, kind permissivecsv.AlterationKind) *Builder {
+	b.terminate()
+	b.buf.WriteString(text)
+	b.pending = b.currentTerminator()
+	b.records++
+	if b.records == 1 {
+		b.fieldCount = fieldCount
+	}
+	if kind != permissivecsv.AlterationNone {
+		b.expected = append(b.expected, ExpectedAlteration{
+			RecordOrdinal: b.records,
+			Kind:          kind,
+		})
+	}
+	return b
+}
+
+// terminate writes the terminator that ends the previous record, if any.
+func (b *Builder) terminate() {
+	b.buf.WriteString(b.pending)
+	b.pending = ""
+}
+
+func (b *Builder) currentTerminator() string {
+	if b.terminator == "" {
+		return "\n"
+	}
+	return b.terminator
+}
+
+// Bytes returns the data that has been built.
+func (b *Builder) Bytes() []byte {
+	return append([]byte{}, b.buf.Bytes()...)
+}
+
+// RecordCount returns the number of (non-empty) records that have been
+// appended, which is the RecordCount a Scanner is expected to report.
+func (b *Builder) RecordCount() int {
+	return b.records
+}
+
+// ExpectedAlterations returns the alterations that a Scanner using the
+// default options is expected to report, in order.
+func (b *Builder) ExpectedAlterations() []ExpectedAlteration {
+	return append([]ExpectedAlteration{}, b.expected...)
+}
+
+// Generate returns a Builder containing a header of columns fields, followed
+// by records records, a deterministic selection of which (based on seed) are
+// short, long, contain quote defects, or are preceded by empty records.
+// Terminators are also varied. The same seed always produces the same data.
+func Generate(seed int64, columns, records int) *Builder {
+	if columns < 1 {
+		columns = 1
+	}
+	rng := rand.New(rand.NewSource(seed))
+	header := make([]string, columns)
+	for i := range header {
+		header[i] = "column_" + strconv.Itoa(i+1)
+	}
+	b := NewBuilder(header...)
+	terminators := []string{"\n", "\r\n"}
+	for i := 0; i < records; i++ {
+		b.Terminator(terminators[rng.Intn(len(terminators))])
+		fields := make([]string, columns)
+		for j := range fields {
+			fields[j] = "r" + strconv.Itoa(i) + "c" + strconv.Itoa(j)
+		}
+		switch rng.Intn(10) {
+		case 0:
+			b.Short(fields[:1+rng.Intn(columns)]...)
+		case 1:
+			b.Long(append(fields, "extra")...)
+		case 2:
+			b.BareQuote()
+		case 3:
+			b.ExtraneousQuote()
+		case 4:
+			b.Empty(1 + rng.Intn(2))
+			b.Record(fields...)
+		case 5:
+			fields[rng.Intn(columns)] = "quoted, \"field\"\nwith a newline"
+			b.Record(fields...)
+		default:
+			b.Record(fields...)
+		}
+	}
+	return b
+}
+
+// join joins fields into a record, quoting them if necessary. A record
+// consisting of a single empty field is quoted, as it would otherwise be an
+// empty record.
+func join(fields []string) string {
+	if len(fields) == 1 && fields[0] == "" {
+		return `""`
+	}
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = quote(field)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// quote quotes field if it contains characters that require quotes.
+func quote(field string) string {
+	if !strings.ContainsAny(field, ",\"\r\n") {
+		return field
+	}
+	return `"` + strings.Replace(field, `"`, `""`, -1) + `"`
+}
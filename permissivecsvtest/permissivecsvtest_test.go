@@ -0,0 +1,144 @@
+package permissivecsvtest_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/eltorocorp/permissivecsv/permissivecsvtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// scan scans data with the default options, and returns the records and
+// summary.
+func scan(r io.Reader) ([][]string, *permissivecsv.ScanSummary) {
+	s := permissivecsv.NewScanner(r, permissivecsv.HeaderCheckAssumeHeaderExists)
+	records := [][]string{}
+	for s.Scan() {
+		records = append(records, s.CurrentRecord())
+	}
+	return records, s.Summary()
+}
+
+func alterations(summary *permissivecsv.ScanSummary) []permissivecsvtest.ExpectedAlteration {
+	actual := []permissivecsvtest.ExpectedAlteration{}
+	for _, alteration := range summary.Alterations {
+		actual = append(actual, permissivecsvtest.ExpectedAlteration{
+			RecordOrdinal: alteration.RecordOrdinal,
+			Kind:          alteration.Kind,
+		})
+	}
+	return actual
+}
+
+func Test_Builder(t *testing.T) {
+	b := permissivecsvtest.NewBuilder("id", "name", "note").
+		Record("1", "alice", "comma, quote\" and\nnewline").
+		Short("2").
+		Empty(2).
+		Long("3", "bob", "x", "extra").
+		Terminator("\r\n").
+		BareQuote().
+		ExtraneousQuote().
+		Record("4", "", "")
+
+	assert.Equal(t, "id,name,note\n"+
+		"1,alice,\"comma, quote\"\" and\nnewline\"\n"+
+		"2\n\n\n"+
+		"3,bob,x,extra\n"+
+		"bare\"quote\",x,x\r\n"+
+		"\"extraneous\"quote,x,x\r\n"+
+		"4,,", string(b.Bytes()))
+
+	records, summary := scan(bytes.NewReader(b.Bytes()))
+	assert.Equal(t, b.RecordCount(), summary.RecordCount)
+	assert.Equal(t, []string{"1", "alice", "comma, quote\" and\nnewline"}, records[1])
+	assert.Equal(t, []permissivecsvtest.ExpectedAlteration{
+		{RecordOrdinal: 3, Kind: permissivecsv.AlterationPaddedRecord},
+		{RecordOrdinal: 4, Kind: permissivecsv.AlterationTruncatedRecord},
+		{RecordOrdinal: 5, Kind: permissivecsv.AlterationBareQuote},
+		{RecordOrdinal: 6, Kind: permissivecsv.AlterationExtraneousQuote},
+	}, b.ExpectedAlterations())
+	assert.Equal(t, b.ExpectedAlterations(), alterations(summary))
+}
+
+func Test_BuilderSingleEmptyField(t *testing.T) {
+	b := permissivecsvtest.NewBuilder("a").Record("").Short("")
+	assert.Equal(t, "a\n\"\"\n\"\"", string(b.Bytes()))
+	_, summary := scan(bytes.NewReader(b.Bytes()))
+	assert.Equal(t, 3, summary.RecordCount)
+	assert.Empty(t, b.ExpectedAlterations())
+}
+
+func Test_Generate(t *testing.T) {
+	assert.Equal(t, permissivecsvtest.Generate(1, 4, 100).Bytes(), permissivecsvtest.Generate(1, 4, 100).Bytes())
+	assert.NotEqual(t, permissivecsvtest.Generate(1, 4, 100).Bytes(), permissivecsvtest.Generate(2, 4, 100).Bytes())
+
+	for seed := int64(0); seed < 50; seed++ {
+		for _, columns := range []int{0, 1, 3} {
+			b := permissivecsvtest.Generate(seed, columns, 60)
+			_, summary := scan(bytes.NewReader(b.Bytes()))
+			assert.Equal(t, b.RecordCount(), summary.RecordCount, "seed %d", seed)
+			assert.Equal(t, b.ExpectedAlterations(), alterations(summary), "seed %d", seed)
+		}
+	}
+	assert.NotEmpty(t, permissivecsvtest.Generate(1, 4, 100).ExpectedAlterations())
+}
+
+func Test_FailAfter(t *testing.T) {
+	r := permissivecsvtest.FailAfter(strings.NewReader("a,b\nc,d\ne,f"), 6, nil)
+	data, err := ioutil.ReadAll(r)
+	assert.Equal(t, "a,b\nc,", string(data))
+	assert.Equal(t, permissivecsvtest.ErrInjected, err)
+
+	custom := errors.New("custom")
+	records, summary := scan(permissivecsvtest.FailAfter(strings.NewReader("a,b\nc,d\ne,f"), 0, custom))
+	assert.Empty(t, records)
+	assert.True(t, errors.Is(summary.Err, custom), "got %v", summary.Err)
+}
+
+func Test_Flaky(t *testing.T) {
+	r := permissivecsvtest.Flaky(strings.NewReader("abcdef"), 2, nil)
+	p := make([]byte, 2)
+	results := []string{}
+	for i := 0; i < 7; i++ {
+		n, err := r.Read(p)
+		if err != nil {
+			results = append(results, err.Error())
+			continue
+		}
+		results = append(results, string(p[:n]))
+	}
+	injected := permissivecsvtest.ErrInjected.Error()
+	assert.Equal(t, []string{"ab", injected, "cd", injected, "ef", injected, "EOF"}, results)
+
+	data, err := ioutil.ReadAll(permissivecsvtest.Flaky(strings.NewReader("abc"), 0, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", string(data))
+}
+
+func Test_SlowAndChunk(t *testing.T) {
+	b := permissivecsvtest.Generate(7, 3, 20)
+	expected, _ := scan(bytes.NewReader(b.Bytes()))
+
+	for _, size := range []int{0, 1, 2, 5} {
+		records, summary := scan(permissivecsvtest.Chunk(bytes.NewReader(b.Bytes()), size))
+		assert.NoError(t, summary.Err)
+		assert.Equal(t, expected, records)
+	}
+
+	start := time.Now()
+	s := permissivecsv.NewScanner(
+		permissivecsvtest.Slow(permissivecsvtest.Chunk(bytes.NewReader(b.Bytes()), 64), 10*time.Millisecond),
+		permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithTimeout(25*time.Millisecond))
+	for s.Scan() {
+	}
+	assert.True(t, s.Summary().DeadlineExceeded)
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+}
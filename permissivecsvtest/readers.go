@@ -0,0 +1,111 @@
+// Package permissivecsvtest provides test doubles for code that consumes
+// permissivecsv: readers that fail, stall, or deliver data in awkward pieces
+// in a deterministic way, and a Builder that produces CSV containing known
+// defects, along with the alterations that a Scanner is expected to report.
+//
+// It is intended for downstream projects that need to test their handling of
+// errors and alterations without reimplementing such fixtures.
+package permissivecsvtest
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrInjected is the error returned by the readers in this package when no
+// other error is supplied.
+var ErrInjected = errors.New("permissivecsvtest: injected error")
+
+// FailAfter returns a reader that reads the first n bytes of r, and then
+// returns err (or ErrInjected, if err is nil) from every subsequent call to
+// Read. If n is zero, the first call to Read fails.
+func FailAfter(r io.Reader, n int64, err error) io.Reader {
+	if err == nil {
+		err = ErrInjected
+	}
+	return &failAfterReader{r: r, remaining: n, err: err}
+}
+
+type failAfterReader struct {
+	r         io.Reader
+	remaining int64
+	err       error
+}
+
+func (f *failAfterReader) Read(p []byte) (int, error) {
+	if f.remaining <= 0 {
+		return 0, f.err
+	}
+	if int64(len(p)) > f.remaining {
+		p = p[:f.remaining]
+	}
+	n, err := f.r.Read(p)
+	f.remaining -= int64(n)
+	return n, err
+}
+
+// Flaky returns a reader that fails every nth call to Read with err (or
+// ErrInjected, if err is nil), without consuming any data, and otherwise reads
+// from r. This simulates a transient fault (such as a dropped connection) that
+// succeeds if retried. If n is less than 1, Read never fails.
+func Flaky(r io.Reader, n int, err error) io.Reader {
+	if err == nil {
+		err = ErrInjected
+	}
+	return &flakyReader{r: r, n: n, err: err}
+}
+
+type flakyReader struct {
+	r     io.Reader
+	n     int
+	calls int
+	err   error
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	f.calls++
+	if f.n > 0 && f.calls%f.n == 0 {
+		return 0, f.err
+	}
+	return f.r.Read(p)
+}
+
+// Slow returns a reader that sleeps for delay before each call to Read of r.
+// Combined with Chunk, this simulates a slow network connection, which is
+// useful for testing timeouts and deadlines.
+func Slow(r io.Reader, delay time.Duration) io.Reader {
+	return &slowReader{r: r, delay: delay}
+}
+
+type slowReader struct {
+	r     io.Reader
+	delay time.Duration
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(s.delay)
+	return s.r.Read(p)
+}
+
+// Chunk returns a reader that reads at most size bytes from r in each call to
+// Read, so that records and terminators are split across reads. If size is
+// less than 1, one byte is read at a time.
+func Chunk(r io.Reader, size int) io.Reader {
+	if size < 1 {
+		size = 1
+	}
+	return &chunkReader{r: r, size: size}
+}
+
+type chunkReader struct {
+	r    io.Reader
+	size int
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if len(p) > c.size {
+		p = p[:c.size]
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,84 @@
+package permissivecsv
+
+import "encoding/csv"
+
+// PipeOption configures the behavior of Pipe.
+type PipeOption func(*pipeOptions)
+
+type pipeOptions struct {
+	flushEvery int
+	skipHeader bool
+	columns    []string
+}
+
+// WithPipeFlushEvery instructs Pipe to flush the csv.Writer after every n
+// records. The default is 1000. A value of zero or less causes Pipe to flush
+// only once all records have been written.
+func WithPipeFlushEvery(n int) PipeOption {
+	return func(o *pipeOptions) {
+		o.flushEvery = n
+	}
+}
+
+// WithPipeSkipHeader instructs Pipe to omit the first record from its output
+// if RecordIsHeader reports that it is a header.
+func WithPipeSkipHeader() PipeOption {
+	return func(o *pipeOptions) {
+		o.skipHeader = true
+	}
+}
+
+// WithPipeColumns instructs Pipe to write the supplied columns, in the
+// supplied order. Columns are matched as by WithNormalizeColumns.
+func WithPipeColumns(names ...string) PipeOption {
+	return func(o *pipeOptions) {
+		o.columns = append([]string{}, names...)
+	}
+}
+
+// Pipe scans the remainder of the input, and writes each (possibly altered)
+// record to w. The writer is flushed periodically (see WithPipeFlushEvery), so
+// that the output keeps pace with the input, and so that errors from the
+// destination are detected promptly. Pipe always flushes w before returning.
+//
+// If w reports an error, Pipe stops scanning and returns that error. Otherwise,
+// Pipe returns any error returned by the underlaying reader (which is also
+// available via the summary). In either case, the summary describes the
+// records that were scanned.
+func (s *Scanner) Pipe(w *csv.Writer, opts ...PipeOption) (*ScanSummary, error) {
+	o := pipeOptions{
+		flushEvery: 1000,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	projection := newColumnProjection(o.columns)
+	written := 0
+	for s.Scan() {
+		if o.skipHeader && s.RecordIsHeader() {
+			projection.project(s, nil)
+			continue
+		}
+		record, _ := projection.project(s, nil)
+		err := w.Write(record)
+		if err != nil {
+			return s.Summary(), err
+		}
+		written++
+		if o.flushEvery > 0 && written%o.flushEvery == 0 {
+			w.Flush()
+			err = w.Error()
+			if err != nil {
+				return s.Summary(), err
+			}
+		}
+	}
+	w.Flush()
+	err := w.Error()
+	if err != nil {
+		return s.Summary(), err
+	}
+	summary := s.Summary()
+	return summary, summary.Err
+}
@@ -0,0 +1,107 @@
+package permissivecsv_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+// ErrWriter is the error returned by a failingWriter.
+var ErrWriter = fmt.Errorf("writer error")
+
+// failingWriter accepts limit bytes, and returns ErrWriter thereafter.
+type failingWriter struct {
+	limit int
+	n     int
+	buf   bytes.Buffer
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.n+len(p) > f.limit {
+		return 0, ErrWriter
+	}
+	f.n += len(p)
+	return f.buf.Write(p)
+}
+
+func Test_Pipe(t *testing.T) {
+	tests := []struct {
+		name        string
+		reader      io.Reader
+		headerCheck permissivecsv.HeaderCheck
+		opts        []permissivecsv.PipeOption
+		writer      io.Writer
+		expOutput   string
+		expErr      error
+		expRecords  int
+	}{
+		{
+			name:        "repairs records",
+			reader:      strings.NewReader("a,b\r\nc\nd,e,f\n\"g,h\",i"),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			writer:      new(bytes.Buffer),
+			expOutput:   "a,b\nc,\nd,e\n\"g,h\",i\n",
+			expRecords:  4,
+		},
+		{
+			name:        "skip header",
+			reader:      strings.NewReader("h1,h2\na,b"),
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:        []permissivecsv.PipeOption{permissivecsv.WithPipeSkipHeader()},
+			writer:      new(bytes.Buffer),
+			expOutput:   "a,b\n",
+			expRecords:  2,
+		},
+		{
+			name:        "column order",
+			reader:      strings.NewReader("h1,h2,h3\na,b,c"),
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:        []permissivecsv.PipeOption{permissivecsv.WithPipeColumns("h3", "h0", "h1")},
+			writer:      new(bytes.Buffer),
+			expOutput:   "h3,h0,h1\nc,,a\n",
+			expRecords:  2,
+		},
+		{
+			name:        "reader error",
+			reader:      BadReader(strings.NewReader("a,b")),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			writer:      new(bytes.Buffer),
+			expOutput:   "",
+			expErr:      ErrReader,
+			expRecords:  0,
+		},
+		{
+			name:        "writer error stops scanning",
+			reader:      strings.NewReader("a,b\nc,d\ne,f\ng,h"),
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			opts:        []permissivecsv.PipeOption{permissivecsv.WithPipeFlushEvery(1)},
+			writer:      &failingWriter{limit: 8},
+			expOutput:   "a,b\nc,d\n",
+			expErr:      ErrWriter,
+			expRecords:  3,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(test.reader, test.headerCheck)
+			summary, err := s.Pipe(csv.NewWriter(test.writer), test.opts...)
+			assert.True(t, errors.Is(err, test.expErr), "got %v", err)
+			assert.Equal(t, test.expRecords, summary.RecordCount)
+			switch w := test.writer.(type) {
+			case *bytes.Buffer:
+				assert.Equal(t, test.expOutput, w.String())
+			case *failingWriter:
+				assert.Equal(t, test.expOutput, w.buf.String())
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
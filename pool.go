@@ -0,0 +1,99 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+// pooledBufferSize is the size of the read buffer allocated for each pooled
+// Scanner. It is the longest record that a Scanner can read by default (see
+// bufio.MaxScanTokenSize), so the buffer never needs to grow.
+const pooledBufferSize = bufio.MaxScanTokenSize
+
+// ScannerPool reuses the internal buffers of Scanners across inputs, which
+// reduces allocation churn in services that scan many small files (such as
+// validating uploads). All Scanners obtained from a pool share the
+// HeaderCheck and Options supplied to NewScannerPool. A ScannerPool is safe
+// for concurrent use by multiple goroutines, though each Scanner it returns
+// is not.
+type ScannerPool struct {
+	headerCheck HeaderCheck
+	opts        options
+	pool        sync.Pool
+}
+
+// scannerBuffers holds the allocations that a pooled Scanner retains between
+// uses. Only the read buffer is retained, since the summary (and the slices
+// and maps it holds) belong to the caller, and may outlive the Scanner.
+type scannerBuffers struct {
+	readBuffer []byte
+}
+
+// NewScannerPool returns a ScannerPool whose Scanners use headerCheck and
+// opts, as if supplied to NewScanner.
+func NewScannerPool(headerCheck HeaderCheck, opts ...Option) *ScannerPool {
+	return &ScannerPool{
+		headerCheck: headerCheck,
+		opts:        newOptions(opts),
+	}
+}
+
+// Get returns a Scanner that reads from r, reusing the buffers of a Scanner
+// that was returned to the pool by Put if one is available.
+func (p *ScannerPool) Get(r io.Reader) *Scanner {
+	buffers, ok := p.pool.Get().(*scannerBuffers)
+	if !ok {
+		buffers = &scannerBuffers{
+			readBuffer: make([]byte, pooledBufferSize),
+		}
+	}
+	s := newScanner(r, p.headerCheck, p.opts)
+	s.useReadBuffer(buffers.readBuffer)
+	s.recycledBuffers = buffers
+	return s
+}
+
+// Put returns s to the pool so that its read buffer can be reused by Get. s
+// must not be used after calling Put, though any Summary, Alterations, or
+// records obtained from it remain valid. Scanners that were not obtained from
+// p are ignored.
+func (p *ScannerPool) Put(s *Scanner) {
+	if s == nil || s.readBuffer == nil {
+		return
+	}
+	buffers := s.recycledBuffers
+	if buffers == nil {
+		buffers = &scannerBuffers{}
+	}
+	buffers.readBuffer = s.readBuffer
+	*s = Scanner{}
+	p.pool.Put(buffers)
+}
+
+// useReadBuffer instructs the Scanner to read into buf, rather than allocating
+// its own buffer. buf is not used if it is larger than the longest record
+// permitted by the memory budget (see WithMemoryBudget).
+func (s *Scanner) useReadBuffer(buf []byte) {
+	if buf == nil || cap(buf) > s.opts.maxRecordSize() {
+		return
+	}
+	s.readBuffer = buf
+	s.scanner.Buffer(buf, s.opts.maxRecordSize())
+}
+
+// newScanSummary returns an empty ScanSummary.
+func (s *Scanner) newScanSummary() *ScanSummary {
+	summary := &ScanSummary{
+		Alterations:          []*Alteration{},
+		FieldCountRuns:       []*FieldCountRun{},
+		TerminatorCounts:     map[Terminator]int{},
+		AlterationKindCounts: map[AlterationKind]int{},
+		mu:                   s.summaryLock(),
+	}
+	if s.opts.strictRFC4180 {
+		summary.Deviations = []*Deviation{}
+	}
+	s.retainedAlterations = map[AlterationKind]int{}
+	return summary
+}
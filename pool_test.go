@@ -0,0 +1,119 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScannerPool(t *testing.T) {
+	pool := permissivecsv.NewScannerPool(permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithKeepEmptyRecords())
+
+	inputs := []struct {
+		data           string
+		expRecords     [][]string
+		expAlterations int
+	}{
+		{
+			data: "a,b\n1\n\n2,3,4",
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"1", ""},
+				[]string{"", ""},
+				[]string{"2", "3"},
+			},
+			expAlterations: 2,
+		},
+		{
+			data: "x\r\ny",
+			expRecords: [][]string{
+				[]string{"x"},
+				[]string{"y"},
+			},
+			expAlterations: 0,
+		},
+		{
+			data: strings.Repeat("z", 50000) + "\nw",
+			expRecords: [][]string{
+				[]string{strings.Repeat("z", 50000)},
+				[]string{"w"},
+			},
+			expAlterations: 0,
+		},
+	}
+
+	for round := 0; round < 2; round++ {
+		for _, input := range inputs {
+			s := pool.Get(strings.NewReader(input.data))
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, input.expRecords, records)
+			summary := s.Summary()
+			assert.True(t, summary.EOF)
+			assert.Equal(t, input.expAlterations, summary.AlterationCount)
+			assert.Len(t, summary.Alterations, input.expAlterations)
+			assert.Equal(t, len(input.expRecords), summary.RecordCount)
+			terminated := 0
+			for _, count := range summary.TerminatorCounts {
+				terminated += count
+			}
+			assert.Equal(t, len(input.expRecords), terminated)
+			pool.Put(s)
+		}
+	}
+
+	// A Scanner that was never scanned can be returned, as can one that was
+	// not obtained from the pool.
+	pool.Put(pool.Get(strings.NewReader("a")))
+	pool.Put(permissivecsv.NewScanner(strings.NewReader("a"), permissivecsv.HeaderCheckAssumeNoHeader))
+	pool.Put(nil)
+	s := pool.Get(strings.NewReader("a"))
+	assert.True(t, s.Scan())
+	assert.Equal(t, []string{"a"}, s.CurrentRecord())
+}
+
+func Test_ScannerPoolRetainedSummary(t *testing.T) {
+	pool := permissivecsv.NewScannerPool(permissivecsv.HeaderCheckAssumeNoHeader)
+	s := pool.Get(strings.NewReader("a,b\nc\n"))
+	for s.Scan() {
+	}
+	summary := s.Summary()
+	pool.Put(s)
+
+	s = pool.Get(strings.NewReader("x\r\ny,z\r\n"))
+	for s.Scan() {
+	}
+	pool.Put(s)
+
+	assert.Equal(t, 2, summary.RecordCount)
+	assert.Len(t, summary.Alterations, 1)
+	assert.Equal(t, "c", summary.Alterations[0].OriginalData)
+	assert.Equal(t, map[permissivecsv.Terminator]int{permissivecsv.Terminator("\n"): 2}, summary.TerminatorCounts)
+}
+
+func Benchmark_ScannerPool(b *testing.B) {
+	data := "id,name,amount\n1,alice,10\n2,bob\n3,carol,30,extra\n"
+	pool := permissivecsv.NewScannerPool(permissivecsv.HeaderCheckAssumeHeaderExists)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := pool.Get(strings.NewReader(data))
+		for s.Scan() {
+		}
+		pool.Put(s)
+	}
+}
+
+func Benchmark_NewScannerPerInput(b *testing.B) {
+	data := "id,name,amount\n1,alice,10\n2,bob\n3,carol,30,extra\n"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists)
+		for s.Scan() {
+		}
+	}
+}
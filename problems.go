@@ -0,0 +1,147 @@
+package permissivecsv
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// problemTypePrefix is prepended to a Problem's Code to form its Type URI.
+const problemTypePrefix = "urn:permissivecsv:problem:"
+
+// maxSnippetLength caps the amount of original data included in a Problem.
+const maxSnippetLength = 128
+
+// Problem codes identify the kind of issue that a Problem describes.
+const (
+	ProblemBareQuote       = "bare-quote"
+	ProblemExtraneousQuote = "extraneous-quote"
+	ProblemTruncatedRecord = "truncated-record"
+	ProblemPaddedRecord    = "padded-record"
+	ProblemMergedFields    = "merged-fields"
+	ProblemReaderError     = "reader-error"
+	ProblemCallbackError   = "callback-error"
+
+	ProblemJoinedTrailingFields = "joined-trailing-fields"
+	ProblemSwitchedDelimiter    = "switched-delimiter"
+	ProblemDroppedRecord        = "dropped-record"
+	ProblemFoldedContinuation   = "folded-continuation"
+)
+
+// Problem is a structured description of an issue encountered while scanning,
+// modeled after RFC 7807 problem details. Problems are intended to be
+// serialized (typically as JSON) and returned to the clients of services that
+// accept CSV uploads.
+//
+// Type is a URI that identifies the kind of problem, and Code is the short
+// form of the same identifier. Title is a short, human-readable summary of the
+// problem type, and Detail is specific to this occurrence of the problem.
+//
+// RecordOrdinal and ByteOffset locate the record that the problem pertains to.
+// Snippet contains the original data of the record, truncated if the record is
+// large. Problems that do not pertain to a specific record (such as reader
+// errors) have a RecordOrdinal of 0.
+type Problem struct {
+	Type          string `json:"type"`
+	Code          string `json:"code"`
+	Title         string `json:"title"`
+	Detail        string `json:"detail"`
+	RecordOrdinal int    `json:"recordOrdinal,omitempty"`
+	ByteOffset    int64  `json:"byteOffset,omitempty"`
+	Snippet       string `json:"snippet,omitempty"`
+}
+
+// ToProblems converts the alterations and error held by the summary into a
+// slice of Problems, in the order in which they were encountered. A failed
+// callback (see ErrCallbackFailed) is reported as ProblemCallbackError, rather
+// than ProblemReaderError, and limits (see ErrScanLimited) are not reported.
+// ToProblems returns an empty slice if there were no problems. Titles are
+// taken from DefaultMessageCatalog.
+func (s *ScanSummary) ToProblems() []*Problem {
+	return s.ToProblemsWithCatalog(nil)
+}
+
+// ToProblemsWithCatalog is like ToProblems, but takes the Title of each
+// Problem from catalog (see MessageCatalog), which allows problems to be
+// presented in other languages. Codes and Types are not affected by the
+// catalog.
+func (s *ScanSummary) ToProblemsWithCatalog(catalog MessageCatalog) []*Problem {
+	problems := []*Problem{}
+	for _, alteration := range s.Alterations {
+		code, title := problemCodeAndTitle(alteration, catalog)
+		problems = append(problems, &Problem{
+			Type:  problemTypePrefix + code,
+			Code:  code,
+			Title: title,
+			Detail: fmt.Sprintf("record %d was altered (%s); resulting record has %d fields",
+				alteration.RecordOrdinal,
+				alteration.AlterationDescription,
+				len(alteration.ResultingRecord)),
+			RecordOrdinal: alteration.RecordOrdinal,
+			ByteOffset:    alteration.ByteOffset,
+			Snippet:       snippet(alteration.OriginalData),
+		})
+	}
+	for _, err := range splitErrs(s.Err) {
+		switch {
+		case errors.Is(err, ErrScanLimited):
+			// A limit is not a problem with the file.
+		case errors.Is(err, ErrCallbackFailed):
+			problems = append(problems, &Problem{
+				Type:   problemTypePrefix + ProblemCallbackError,
+				Code:   ProblemCallbackError,
+				Title:  "The file could not be processed",
+				Detail: err.Error(),
+			})
+		default:
+			problems = append(problems, &Problem{
+				Type:   problemTypePrefix + ProblemReaderError,
+				Code:   ProblemReaderError,
+				Title:  "The file could not be read",
+				Detail: err.Error(),
+			})
+		}
+	}
+	return problems
+}
+
+func problemCodeAndTitle(alteration *Alteration, catalog MessageCatalog) (string, string) {
+	kind := alteration.Kind
+	if kind == AlterationNone {
+		kind = kindFromDescription(alteration.AlterationDescription)
+	}
+	if kind == AlterationNone {
+		return alteration.AlterationDescription, alteration.AlterationDescription
+	}
+	return kind.Code(), catalog.Message(kind)
+}
+
+// snippet returns s, truncated to at most maxSnippetLength bytes. s is only
+// cut at the start of a rune, so that a valid UTF-8 string remains valid.
+func snippet(s string) string {
+	if len(s) <= maxSnippetLength {
+		return s
+	}
+	cut := maxSnippetLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
+
+// splitErrs returns the errors joined in err (see ScanSummary.Err), or nil if
+// err is nil.
+func splitErrs(err error) []error {
+	if err == nil {
+		return nil
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+	errs := []error{}
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, splitErrs(e)...)
+	}
+	return errs
+}
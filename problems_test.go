@@ -0,0 +1,119 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ToProblems(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expProblems []*permissivecsv.Problem
+	}{
+		{
+			name:        "no problems",
+			data:        "a,b\nc,d",
+			expProblems: []*permissivecsv.Problem{},
+		},
+		{
+			name: "alterations",
+			data: "a,b\nc\nf,g,h\nd\"d,e",
+			expProblems: []*permissivecsv.Problem{
+				&permissivecsv.Problem{
+					Type:          "urn:permissivecsv:problem:padded-record",
+					Code:          permissivecsv.ProblemPaddedRecord,
+					Title:         "Record has too few fields",
+					Detail:        "record 2 was altered (padded record); resulting record has 2 fields",
+					RecordOrdinal: 2,
+					ByteOffset:    4,
+					Snippet:       "c",
+				},
+				&permissivecsv.Problem{
+					Type:          "urn:permissivecsv:problem:truncated-record",
+					Code:          permissivecsv.ProblemTruncatedRecord,
+					Title:         "Record has too many fields",
+					Detail:        "record 3 was altered (truncated record); resulting record has 2 fields",
+					RecordOrdinal: 3,
+					ByteOffset:    6,
+					Snippet:       "f,g,h",
+				},
+				&permissivecsv.Problem{
+					Type:          "urn:permissivecsv:problem:bare-quote",
+					Code:          permissivecsv.ProblemBareQuote,
+					Title:         "Record contains a bare quote",
+					Detail:        "record 4 was altered (bare quote); resulting record has 2 fields",
+					RecordOrdinal: 4,
+					ByteOffset:    12,
+					Snippet:       "d\"d,e",
+				},
+			},
+		},
+		{
+			name: "reader error",
+			data: "",
+			expProblems: []*permissivecsv.Problem{
+				&permissivecsv.Problem{
+					Type:   "urn:permissivecsv:problem:reader-error",
+					Code:   permissivecsv.ProblemReaderError,
+					Title:  "The file could not be read",
+					Detail: ErrReader.Error(),
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			r := strings.NewReader(test.data)
+			var s *permissivecsv.Scanner
+			if test.data == "" {
+				s = permissivecsv.NewScanner(BadReader(r), permissivecsv.HeaderCheckAssumeNoHeader)
+			} else {
+				s = permissivecsv.NewScanner(r, permissivecsv.HeaderCheckAssumeNoHeader)
+			}
+			for s.Scan() {
+			}
+			diff := deep.Equal(test.expProblems, s.Summary().ToProblems())
+			if diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_ToProblemsErrors(t *testing.T) {
+	headerCheck := func(firstRecord, secondRecord []string) bool {
+		panic("boom")
+	}
+	s := permissivecsv.NewScanner(strings.NewReader("a\nb"), headerCheck, permissivecsv.WithNormalizedHeader())
+	for s.Scan() {
+	}
+	problems := s.Summary().ToProblems()
+	assert.Len(t, problems, 1)
+	assert.Equal(t, permissivecsv.ProblemCallbackError, problems[0].Code)
+	assert.Equal(t, "urn:permissivecsv:problem:callback-error", problems[0].Type)
+	assert.Equal(t, "HeaderCheck panicked: boom", problems[0].Detail)
+
+	s = permissivecsv.NewScanner(strings.NewReader("a\nb"), permissivecsv.HeaderCheckAssumeNoHeader, permissivecsv.WithMaxRecords(1))
+	for s.Scan() {
+	}
+	assert.Empty(t, s.Summary().ToProblems(), "limits are not problems")
+}
+
+func Test_ToProblemsSnippet(t *testing.T) {
+	data := "a,b\n" + strings.Repeat("x", 127) + "é,\"y\"z\n"
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	for s.Scan() {
+	}
+	problems := s.Summary().ToProblems()
+	assert.Len(t, problems, 1)
+	assert.True(t, utf8.ValidString(problems[0].Snippet))
+	assert.Equal(t, strings.Repeat("x", 127)+"...", problems[0].Snippet)
+}
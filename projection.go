@@ -0,0 +1,92 @@
+package permissivecsv
+
+// columnProjection arranges the fields of records into an output column order
+// (see WithNormalizeColumns and WithPipeColumns).
+type columnProjection struct {
+	names []string
+
+	// indexes holds, for each output column, the index of the input field
+	// that it is taken from, or -1 if the input has no such column.
+	indexes []int
+
+	// resolved is true once the input's columns have been matched to names.
+	resolved bool
+}
+
+func newColumnProjection(names []string) *columnProjection {
+	if names == nil {
+		return nil
+	}
+	return &columnProjection{
+		names: append([]string{}, names...),
+	}
+}
+
+// resolve matches the output columns to the input's columns, using the header
+// if the current record is the first record and is a header, or otherwise by
+// position. resolve reports whether the current record is a header.
+func (p *columnProjection) resolve(s *Scanner, record []string) bool {
+	if p.resolved {
+		return false
+	}
+	p.resolved = true
+	p.indexes = make([]int, len(p.names))
+	isHeader := s.firstRecord != nil && s.RecordIsHeader()
+	if !isHeader {
+		for i := range p.indexes {
+			p.indexes[i] = i
+		}
+		return false
+	}
+
+	columns := make(map[string]int, len(record))
+	for i, name := range record {
+		name = NormalizeColumnName(name)
+		if _, exists := columns[name]; !exists {
+			columns[name] = i
+		}
+	}
+	for i, name := range p.names {
+		index, ok := columns[NormalizeColumnName(name)]
+		if !ok {
+			index = -1
+		}
+		p.indexes[i] = index
+	}
+	return true
+}
+
+// project returns the current record of s (including any columns flattened
+// from JSON) arranged in the output column order, along with its field states
+// if states is non-nil. If the current record is the header, the output column
+// names are returned instead.
+func (p *columnProjection) project(s *Scanner, states []FieldState) ([]string, []FieldState) {
+	record, states := s.outputRecord(states)
+	if p == nil {
+		return record, states
+	}
+	if p.resolve(s, record) {
+		names := append([]string{}, p.names...)
+		if states != nil {
+			states = make([]FieldState, len(names))
+		}
+		return names, states
+	}
+
+	projected := make([]string, len(p.indexes))
+	var projectedStates []FieldState
+	if states != nil {
+		projectedStates = make([]FieldState, len(p.indexes))
+	}
+	for i, index := range p.indexes {
+		if index >= 0 && index < len(record) {
+			projected[i] = record[index]
+			if states != nil && index < len(states) {
+				projectedStates[i] = states[index]
+			}
+		} else if states != nil {
+			projectedStates[i] = FieldMissing
+		}
+	}
+	return projected, projectedStates
+}
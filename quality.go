@@ -0,0 +1,78 @@
+package permissivecsv
+
+// Weights of the components of a QualityScore.
+const (
+	qualityAlterationWeight = 0.5
+	qualityFieldCountWeight = 0.3
+	qualityEncodingWeight   = 0.2
+)
+
+// QualityScore is a measure of the quality of the records covered by a
+// ScanSummary. Score ranges from 0 (worst) to 100 (best), which allows a
+// pipeline to gate on a single threshold. The components from which the score
+// is computed are also exposed, each as a rate between 0 and 1:
+//
+// AlterationRate is the fraction of records that were altered
+// (AlterationCount / RecordCount).
+//
+// FieldCountDeviation is the fraction of records whose field count differed
+// from the most common field count (see FieldCountRuns).
+//
+// EncodingIssueRate is the fraction of records that contained invalid UTF-8
+// (InvalidUTF8Count / RecordCount).
+//
+// Score is computed as:
+//
+//	100 * (1 - (0.5*AlterationRate + 0.3*FieldCountDeviation + 0.2*EncodingIssueRate))
+type QualityScore struct {
+	Score               float64
+	AlterationRate      float64
+	FieldCountDeviation float64
+	EncodingIssueRate   float64
+}
+
+// QualityScore computes a QualityScore from the summary. The score only
+// reflects the records covered by the summary, so the summary of a sample of
+// a large file (such as one produced by a Scanner using WithMaxRecords) yields
+// a sample-based score. A summary with no records has a Score of 100.
+func (s *ScanSummary) QualityScore() *QualityScore {
+	q := &QualityScore{Score: 100}
+	if s.RecordCount <= 0 {
+		return q
+	}
+	records := float64(s.RecordCount)
+	q.AlterationRate = clampRate(float64(s.AlterationCount) / records)
+	q.EncodingIssueRate = clampRate(float64(s.InvalidUTF8Count) / records)
+
+	observed := 0
+	countsByFieldCount := map[int]int{}
+	for _, run := range s.FieldCountRuns {
+		n := run.LastRecordOrdinal - run.FirstRecordOrdinal + 1
+		countsByFieldCount[run.FieldCount] += n
+		observed += n
+	}
+	if observed > 0 {
+		dominant := 0
+		for _, n := range countsByFieldCount {
+			if n > dominant {
+				dominant = n
+			}
+		}
+		q.FieldCountDeviation = clampRate(float64(observed-dominant) / float64(observed))
+	}
+
+	q.Score = 100 * (1 - (qualityAlterationWeight*q.AlterationRate +
+		qualityFieldCountWeight*q.FieldCountDeviation +
+		qualityEncodingWeight*q.EncodingIssueRate))
+	return q
+}
+
+func clampRate(rate float64) float64 {
+	if rate < 0 {
+		return 0
+	}
+	if rate > 1 {
+		return 1
+	}
+	return rate
+}
@@ -0,0 +1,68 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+)
+
+func Test_QualityScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		opts     []permissivecsv.Option
+		expScore *permissivecsv.QualityScore
+	}{
+		{
+			name:     "clean file",
+			data:     "a,b\nc,d\ne,f\ng,h",
+			expScore: &permissivecsv.QualityScore{Score: 100},
+		},
+		{
+			name:     "empty file",
+			data:     "",
+			expScore: &permissivecsv.QualityScore{Score: 100},
+		},
+		{
+			name: "altered records",
+			data: "a,b\nc\ne,f\ng,h",
+			expScore: &permissivecsv.QualityScore{
+				Score:               80,
+				AlterationRate:      0.25,
+				FieldCountDeviation: 0.25,
+			},
+		},
+		{
+			name: "invalid encoding",
+			data: "a,b\nc,d\n\xff,f\ng,h",
+			expScore: &permissivecsv.QualityScore{
+				Score:             95,
+				EncodingIssueRate: 0.25,
+			},
+		},
+		{
+			name: "sample",
+			data: "a,b\nc\ne,f\ng,h,i\nj,k",
+			opts: []permissivecsv.Option{permissivecsv.WithMaxRecords(2)},
+			expScore: &permissivecsv.QualityScore{
+				Score:               60,
+				AlterationRate:      0.5,
+				FieldCountDeviation: 0.5,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			for s.Scan() {
+			}
+			if diff := deep.Equal(test.expScore, s.Summary().QualityScore()); diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
@@ -0,0 +1,51 @@
+package permissivecsv
+
+import (
+	"strings"
+
+	"github.com/eltorocorp/permissivecsv/internal/util"
+)
+
+// WithPreserveQuotes instructs the Scanner to return each field exactly as it
+// appeared in the input, rather than as parsed by encoding/csv. Quoted fields
+// keep their surrounding quotes, and doubled quotes within them are not
+// unescaped, so "a ""b""" is returned as "a ""b""" rather than a "b". This
+// suits consumers that unescape fields themselves, and audits that require
+// fields to be byte-faithful to the input.
+//
+// Fields are only preserved if the record could be parsed. A record that
+// contains a bare or extraneous quote is handled as usual (see
+// AlterationBareQuote and AlterationExtraneousQuote). Since the values
+// supplied to a HeaderCheck, RepairStrategy, FieldDecoder, and similar
+// functions are taken from the current record, they also include any quotes.
+func WithPreserveQuotes() Option {
+	return func(o *options) {
+		o.preserveQuotes = true
+	}
+}
+
+// preserveQuotes returns the fields of raw (separated by delimiter) as they
+// appeared in raw, if raw contains as many fields as record (the result of
+// parsing raw). Otherwise, record is returned.
+func preserveQuotes(raw string, delimiter rune, record []string) []string {
+	delim := string(delimiter)
+	fields := make([]string, 0, len(record))
+	start := 0
+	inQuotes := false
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == util.QuoteChar:
+			inQuotes = !inQuotes
+		case !inQuotes && c == delim[0] && strings.HasPrefix(raw[i:], delim):
+			fields = append(fields, raw[start:i])
+			i += len(delim) - 1
+			start = i + 1
+		}
+	}
+	fields = append(fields, raw[start:])
+	if len(fields) != len(record) {
+		return record
+	}
+	return fields
+}
@@ -0,0 +1,80 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithPreserveQuotes(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		opts       []permissivecsv.Option
+		expRecords [][]string
+	}{
+		{
+			name:       "disabled",
+			data:       "a,\"b\"\n\"c \"\"d\"\"\",\"\"\n",
+			expRecords: [][]string{{"a", "b"}, {"c \"d\"", ""}},
+		},
+		{
+			name:       "quoted fields",
+			data:       "a,\"b\"\n\"c \"\"d\"\"\",\"\"\n",
+			opts:       []permissivecsv.Option{permissivecsv.WithPreserveQuotes()},
+			expRecords: [][]string{{"a", "\"b\""}, {"\"c \"\"d\"\"\"", "\"\""}},
+		},
+		{
+			name:       "quoted delimiter and terminator",
+			data:       "\"a,b\",\"c\r\nd\"\r\ne,f\r\n",
+			opts:       []permissivecsv.Option{permissivecsv.WithPreserveQuotes()},
+			expRecords: [][]string{{"\"a,b\"", "\"c\r\nd\""}, {"e", "f"}},
+		},
+		{
+			name:       "padded record",
+			data:       "a,b,c\n\"d\"\n",
+			opts:       []permissivecsv.Option{permissivecsv.WithPreserveQuotes()},
+			expRecords: [][]string{{"a", "b", "c"}, {"\"d\"", "", ""}},
+		},
+		{
+			name: "switched delimiter",
+			data: "a,b\n\"c;d\";e\n",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithPreserveQuotes(),
+				permissivecsv.WithAlternateDelimiters(';'),
+			},
+			expRecords: [][]string{{"a", "b"}, {"\"c;d\"", "e"}},
+		},
+		{
+			name:       "bare quote",
+			data:       "a,b\nc\"d,e\n",
+			opts:       []permissivecsv.Option{permissivecsv.WithPreserveQuotes()},
+			expRecords: [][]string{{"a", "b"}, {"", ""}},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, test.expRecords, records)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithPreserveQuotesFieldStates(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("\"a\",b,\"\"\n"), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithPreserveQuotes())
+	assert.True(t, s.Scan())
+	assert.Equal(t, []permissivecsv.FieldState{
+		permissivecsv.FieldQuoted,
+		permissivecsv.FieldBare,
+		permissivecsv.FieldQuoted,
+	}, s.CurrentFieldStates())
+}
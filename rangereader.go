@@ -0,0 +1,261 @@
+package permissivecsv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RangeReader fetches a contiguous range of bytes from some underlaying
+// object. RangeReader is the bridge between the Segments produced by Partition
+// and remote storage that supports ranged reads, such as HTTP servers that
+// honor the Range header, or object stores like S3 (GetObject with a Range).
+//
+// ReadRange must return a ReadCloser that yields at most length bytes,
+// starting at offset. The caller is responsible for closing the returned
+// ReadCloser.
+type RangeReader interface {
+	ReadRange(ctx context.Context, offset, length int64) (io.ReadCloser, error)
+}
+
+// RangeReaderFunc is an adapter that allows an ordinary function to be used as
+// a RangeReader. This is the simplest way to plug an object store client into
+// permissivecsv. For example, an S3 client can be adapted by issuing a
+// GetObject request with its Range set to RangeHeader(offset, length), and
+// returning the response body.
+type RangeReaderFunc func(ctx context.Context, offset, length int64) (io.ReadCloser, error)
+
+// ReadRange calls f(ctx, offset, length).
+func (f RangeReaderFunc) ReadRange(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	return f(ctx, offset, length)
+}
+
+// ReaderAtRangeReader returns a RangeReader that reads ranges directly from ra,
+// such as an *os.File. This is useful when segments are processed on the same
+// machine that partitioned the file.
+func ReaderAtRangeReader(ra io.ReaderAt) RangeReader {
+	return RangeReaderFunc(func(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+		return ioutil.NopCloser(io.NewSectionReader(ra, offset, length)), nil
+	})
+}
+
+// RangeHeader returns the value of an HTTP Range header (RFC 7233) that
+// requests length bytes starting at offset. Most object stores accept the same
+// format for ranged reads.
+func RangeHeader(offset, length int64) string {
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+}
+
+// HTTPRangeReader is a RangeReader that fetches byte ranges from a URL using
+// HTTP Range requests.
+//
+// If the server ignores the Range header and responds with the full object
+// (200 OK), HTTPRangeReader discards the bytes that precede the requested
+// offset and limits the body to the requested length, so the caller always
+// receives the requested range.
+type HTTPRangeReader struct {
+	// Client is the client used to make requests. If Client is nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+
+	// URL is the location of the object being read.
+	URL string
+
+	// Header contains any additional headers to send with each request, such
+	// as authorization headers.
+	Header http.Header
+}
+
+// ReadRange requests length bytes starting at offset from r.URL.
+func (r *HTTPRangeReader) ReadRange(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	if length <= 0 {
+		return ioutil.NopCloser(strings.NewReader("")), nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, r.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for key, values := range r.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Range", RangeHeader(offset, length))
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return &readCloser{io.LimitReader(resp.Body, length), resp.Body}, nil
+	case http.StatusOK:
+		_, err = io.CopyN(ioutil.Discard, resp.Body, offset)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		return &readCloser{io.LimitReader(resp.Body, length), resp.Body}, nil
+	default:
+		resp.Body.Close()
+		return nil, &RangeError{
+			Offset:     offset,
+			Length:     length,
+			StatusCode: resp.StatusCode,
+		}
+	}
+}
+
+// RangeError is returned by HTTPRangeReader when the server responds to a range
+// request with an unexpected status code.
+type RangeError struct {
+	Offset     int64
+	Length     int64
+	StatusCode int
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("range request for %d bytes at offset %d failed with status %d %s",
+		e.Length, e.Offset, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// RetryRangeReader returns a RangeReader that retries failed reads from rr.
+//
+// A read is retried if either the request for the range fails, or if the
+// returned body fails part way through. In the latter case, only the bytes
+// that have not yet been delivered to the caller are requested, so the caller
+// observes a single uninterrupted stream. Up to maxAttempts requests are made
+// for each failure, waiting backoff between attempts (the wait doubles after
+// each failed attempt). Retries stop early if ctx is canceled.
+func RetryRangeReader(rr RangeReader, maxAttempts int, backoff time.Duration) RangeReader {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &retryRangeReader{
+		rr:          rr,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+}
+
+type retryRangeReader struct {
+	rr          RangeReader
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func (r *retryRangeReader) ReadRange(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	body, err := r.open(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return &retryReadCloser{
+		ctx:       ctx,
+		rr:        r,
+		body:      body,
+		offset:    offset,
+		remaining: length,
+	}, nil
+}
+
+func (r *retryRangeReader) open(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	var (
+		body io.ReadCloser
+		err  error
+	)
+	wait := r.backoff
+	for attempt := 1; attempt <= r.maxAttempts; attempt++ {
+		body, err = r.rr.ReadRange(ctx, offset, length)
+		if err == nil {
+			return body, nil
+		}
+		if attempt == r.maxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	return nil, err
+}
+
+// retryReadCloser tracks how much of a range has been delivered so that a
+// failed body can be replaced with a request for the remainder of the range.
+type retryReadCloser struct {
+	ctx       context.Context
+	rr        *retryRangeReader
+	body      io.ReadCloser
+	offset    int64
+	remaining int64
+
+	// failures counts the consecutive body failures that occurred without any
+	// bytes being delivered in between.
+	failures int
+}
+
+func (r *retryReadCloser) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	r.remaining -= int64(n)
+	if r.remaining <= 0 {
+		return n, io.EOF
+	}
+	if err == nil {
+		return n, nil
+	}
+
+	// The body ended (or failed) before the full range was delivered.
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if n > 0 {
+		r.failures = 0
+	}
+	r.failures++
+	if r.failures >= r.rr.maxAttempts {
+		return n, err
+	}
+	r.body.Close()
+	body, openErr := r.rr.open(r.ctx, r.offset, r.remaining)
+	if openErr != nil {
+		r.body = ioutil.NopCloser(strings.NewReader(""))
+		r.failures = r.rr.maxAttempts
+		return n, openErr
+	}
+	r.body = body
+	return n, nil
+}
+
+func (r *retryReadCloser) Close() error {
+	return r.body.Close()
+}
+
+// Open fetches the bytes described by the segment from rr.
+func (s *Segment) Open(ctx context.Context, rr RangeReader) (io.ReadCloser, error) {
+	return rr.ReadRange(ctx, s.LowerOffset, s.Length)
+}
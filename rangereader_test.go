@@ -0,0 +1,187 @@
+package permissivecsv_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HTTPRangeReader(t *testing.T) {
+	const data = "a,b\nc,d\ne,f\ng,h"
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		offset  int64
+		length  int64
+		expData string
+		expErr  bool
+	}{
+		{
+			name: "server honors range",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "data.csv", time.Time{}, strings.NewReader(data))
+			},
+			offset:  4,
+			length:  8,
+			expData: "c,d\ne,f\n",
+		},
+		{
+			name: "server ignores range",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(data))
+			},
+			offset:  4,
+			length:  8,
+			expData: "c,d\ne,f\n",
+		},
+		{
+			name: "zero length",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				t.Error("no request should be made for an empty range")
+			},
+			offset:  4,
+			length:  0,
+			expData: "",
+		},
+		{
+			name: "unexpected status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			offset: 4,
+			length: 8,
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			server := httptest.NewServer(test.handler)
+			defer server.Close()
+			rr := &permissivecsv.HTTPRangeReader{URL: server.URL}
+			body, err := rr.ReadRange(context.Background(), test.offset, test.length)
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			defer body.Close()
+			actual, err := ioutil.ReadAll(body)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expData, string(actual))
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+// failingReader returns the first n bytes of its data, then fails.
+type failingReader struct {
+	r io.Reader
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func Test_RetryRangeReader(t *testing.T) {
+	const data = "a,b\nc,d\ne,f\ng,h"
+	tests := []struct {
+		name        string
+		openErrors  int
+		bodyCutoff  int64
+		maxAttempts int
+		expData     string
+		expErr      bool
+	}{
+		{
+			name:        "no failures",
+			maxAttempts: 3,
+			expData:     data,
+		},
+		{
+			name:        "open fails then succeeds",
+			openErrors:  2,
+			maxAttempts: 3,
+			expData:     data,
+		},
+		{
+			name:        "open fails too many times",
+			openErrors:  3,
+			maxAttempts: 3,
+			expErr:      true,
+		},
+		{
+			name:        "body fails part way",
+			bodyCutoff:  5,
+			maxAttempts: 3,
+			expData:     data,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			opens := 0
+			source := permissivecsv.RangeReaderFunc(func(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+				opens++
+				if opens <= test.openErrors {
+					return nil, errors.New("service unavailable")
+				}
+				r := io.NewSectionReader(strings.NewReader(data), offset, length)
+				if test.bodyCutoff > 0 && opens == 1 {
+					return ioutil.NopCloser(&failingReader{io.LimitReader(r, test.bodyCutoff)}), nil
+				}
+				return ioutil.NopCloser(r), nil
+			})
+			rr := permissivecsv.RetryRangeReader(source, test.maxAttempts, time.Millisecond)
+			body, err := rr.ReadRange(context.Background(), 0, int64(len(data)))
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			defer body.Close()
+			actual, err := ioutil.ReadAll(body)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expData, string(actual))
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_SegmentOpen(t *testing.T) {
+	data := strings.NewReader("a,b\nc,d\ne,f\ng,h")
+	s := permissivecsv.NewScanner(data, permissivecsv.HeaderCheckAssumeNoHeader)
+	segments, err := s.Partition(2, false)
+	assert.NoError(t, err)
+	rr := permissivecsv.ReaderAtRangeReader(data)
+	actual := [][]string{}
+	for _, segment := range segments {
+		body, err := segment.Open(context.Background(), rr)
+		assert.NoError(t, err)
+		segmentScanner := permissivecsv.NewScanner(body, permissivecsv.HeaderCheckAssumeNoHeader)
+		for segmentScanner.Scan() {
+			actual = append(actual, segmentScanner.CurrentRecord())
+		}
+		body.Close()
+	}
+	expected := [][]string{
+		[]string{"a", "b"},
+		[]string{"c", "d"},
+		[]string{"e", "f"},
+		[]string{"g", "h"},
+	}
+	assert.Equal(t, expected, actual)
+}
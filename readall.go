@@ -0,0 +1,50 @@
+package permissivecsv
+
+import "fmt"
+
+var (
+	// ErrRecordLimitExceeded is returned by ReadAll if the input contains
+	// more records than the supplied limit.
+	ErrRecordLimitExceeded = fmt.Errorf("record limit exceeded")
+
+	// ErrByteLimitExceeded is returned by ReadAll if the input is larger than
+	// the supplied limit.
+	ErrByteLimitExceeded = fmt.Errorf("byte limit exceeded")
+)
+
+// ReadAll scans the remainder of the input, and returns all of the records
+// along with the resulting summary. ReadAll is intended for small files, and
+// requires explicit limits to guard against unexpectedly large inputs.
+//
+// If more than maxRecords records are encountered, or more than maxBytes bytes
+// of input are consumed, ReadAll stops and returns ErrRecordLimitExceeded or
+// ErrByteLimitExceeded respectively, along with the records that were read
+// before the limit was exceeded. A limit of zero or less disables that limit.
+//
+// If the underlaying reader returns an error, that error is returned (and is
+// also available via the summary).
+func (s *Scanner) ReadAll(maxRecords int, maxBytes int64) ([][]string, *ScanSummary, error) {
+	records := [][]string{}
+	for maxRecords <= 0 || len(records) < maxRecords {
+		if !s.Scan() {
+			summary := s.Summary()
+			return records, summary, summary.Err
+		}
+		if maxBytes > 0 && s.bytesConsumed > maxBytes {
+			return records, s.Summary(), ErrByteLimitExceeded
+		}
+		records = append(records, s.CurrentRecord())
+	}
+	// Peek, rather than Scan, for the record beyond the limit, so that it is
+	// not counted in the summary.
+	if len(s.Peek(1)) > 0 {
+		return records, s.Summary(), ErrRecordLimitExceeded
+	}
+	// Peek does not report empty records, so one kept by WithKeepEmptyRecords
+	// may still follow.
+	if s.Scan() {
+		return records, s.Summary(), ErrRecordLimitExceeded
+	}
+	summary := s.Summary()
+	return records, summary, summary.Err
+}
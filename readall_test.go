@@ -0,0 +1,88 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReadAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		reader     io.Reader
+		maxRecords int
+		maxBytes   int64
+		expRecords [][]string
+		expCount   int
+		expErr     error
+	}{
+		{
+			name:       "within limits",
+			reader:     strings.NewReader("a,b\nc,d\ne,f"),
+			maxRecords: 3,
+			maxBytes:   11,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+				[]string{"e", "f"},
+			},
+			expCount: 3,
+		},
+		{
+			name:       "no limits",
+			reader:     strings.NewReader("a,b\nc,d"),
+			maxRecords: 0,
+			maxBytes:   0,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+			expCount: 2,
+		},
+		{
+			name:       "record limit exceeded",
+			reader:     strings.NewReader("a,b\nc,d\ne,f"),
+			maxRecords: 2,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+				[]string{"c", "d"},
+			},
+			expCount: 2,
+			expErr:   permissivecsv.ErrRecordLimitExceeded,
+		},
+		{
+			name:     "byte limit exceeded",
+			reader:   strings.NewReader("a,b\nc,d\ne,f"),
+			maxBytes: 6,
+			expRecords: [][]string{
+				[]string{"a", "b"},
+			},
+			expCount: 2,
+			expErr:   permissivecsv.ErrByteLimitExceeded,
+		},
+		{
+			name:       "reader error",
+			reader:     BadReader(strings.NewReader("a,b")),
+			maxRecords: 10,
+			expRecords: [][]string{},
+			expErr:     ErrReader,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(test.reader, permissivecsv.HeaderCheckAssumeNoHeader)
+			records, summary, err := s.ReadAll(test.maxRecords, test.maxBytes)
+			assert.Equal(t, test.expRecords, records)
+			assert.True(t, errors.Is(err, test.expErr), "got %v", err)
+			if assert.NotNil(t, summary) {
+				assert.Equal(t, test.expCount, summary.RecordCount)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
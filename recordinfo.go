@@ -0,0 +1,46 @@
+package permissivecsv
+
+// RecordInfo describes the provenance of a record.
+//
+// Ordinal is the record's position within the scan (starting at 1), and
+// ByteOffset and ByteLength locate the record (including its terminator)
+// within the input. Terminator is the terminator that ended the record, and is
+// empty for the final record of a file that has no trailing terminator.
+// Altered is true if the Scanner altered the record, in which case
+// AlterationKind identifies the alteration that was made. Delimiter is the
+// delimiter with which the record's fields were parsed, which is a comma
+// unless the record was parsed with an alternate delimiter (see
+// WithAlternateDelimiters). QuoteForceClosed is true if the record ends within
+// a quoted field that was closed because it exceeded the limit set by
+// WithMaxQuotedFieldBytes.
+type RecordInfo struct {
+	Ordinal        int
+	ByteOffset     int64
+	ByteLength     int64
+	Terminator     string
+	Altered        bool
+	AlterationKind AlterationKind
+	Delimiter      rune
+
+	QuoteForceClosed bool
+}
+
+// CurrentRecordInfo returns the provenance of the most recent record generated
+// by a call to Scan. CurrentRecordInfo returns nil if Scan has not been called,
+// or if there is no current record.
+func (s *Scanner) CurrentRecordInfo() *RecordInfo {
+	if s.currentRecord == nil || s.scanSummary == nil {
+		return nil
+	}
+	return &RecordInfo{
+		Ordinal:        s.scanSummary.RecordCount,
+		ByteOffset:     s.recordOffset,
+		ByteLength:     s.rawRecordLength,
+		Terminator:     string(s.currentTerminator),
+		Altered:        s.currentAlteration != AlterationNone,
+		AlterationKind: s.currentAlteration,
+		Delimiter:      s.currentDelimiter,
+
+		QuoteForceClosed: s.currentQuoteForceClosed,
+	}
+}
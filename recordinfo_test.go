@@ -0,0 +1,132 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CurrentRecordInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		opts    []permissivecsv.Option
+		expInfo []*permissivecsv.RecordInfo
+	}{
+		{
+			name: "mixed terminators and alterations",
+			data: "a,b\r\nc\nd,e,f",
+			expInfo: []*permissivecsv.RecordInfo{
+				&permissivecsv.RecordInfo{
+					Ordinal:        1,
+					ByteOffset:     0,
+					ByteLength:     5,
+					Terminator:     "\r\n",
+					Altered:        false,
+					AlterationKind: permissivecsv.AlterationNone,
+					Delimiter:      ',',
+				},
+				&permissivecsv.RecordInfo{
+					Ordinal:        2,
+					ByteOffset:     5,
+					ByteLength:     2,
+					Terminator:     "\n",
+					Altered:        true,
+					AlterationKind: permissivecsv.AlterationPaddedRecord,
+					Delimiter:      ',',
+				},
+				&permissivecsv.RecordInfo{
+					Ordinal:        3,
+					ByteOffset:     7,
+					ByteLength:     5,
+					Terminator:     "",
+					Altered:        true,
+					AlterationKind: permissivecsv.AlterationTruncatedRecord,
+					Delimiter:      ',',
+				},
+			},
+		},
+		{
+			name: "skipped empty records",
+			data: "\na,b\n\nc\"c\"c,d\n",
+			expInfo: []*permissivecsv.RecordInfo{
+				&permissivecsv.RecordInfo{
+					Ordinal:        1,
+					ByteOffset:     1,
+					ByteLength:     4,
+					Terminator:     "\n",
+					AlterationKind: permissivecsv.AlterationNone,
+					Delimiter:      ',',
+				},
+				&permissivecsv.RecordInfo{
+					Ordinal:        2,
+					ByteOffset:     6,
+					ByteLength:     8,
+					Terminator:     "\n",
+					Altered:        true,
+					AlterationKind: permissivecsv.AlterationBareQuote,
+					Delimiter:      ',',
+				},
+			},
+		},
+		{
+			name: "kept empty record",
+			data: "a,b\r\rc,d",
+			opts: []permissivecsv.Option{permissivecsv.WithKeepEmptyRecords()},
+			expInfo: []*permissivecsv.RecordInfo{
+				&permissivecsv.RecordInfo{
+					Ordinal:        1,
+					ByteOffset:     0,
+					ByteLength:     4,
+					Terminator:     "\r",
+					AlterationKind: permissivecsv.AlterationNone,
+					Delimiter:      ',',
+				},
+				&permissivecsv.RecordInfo{
+					Ordinal:        2,
+					ByteOffset:     4,
+					ByteLength:     1,
+					Terminator:     "\r",
+					AlterationKind: permissivecsv.AlterationNone,
+					Delimiter:      ',',
+				},
+				&permissivecsv.RecordInfo{
+					Ordinal:        3,
+					ByteOffset:     5,
+					ByteLength:     3,
+					Terminator:     "",
+					AlterationKind: permissivecsv.AlterationNone,
+					Delimiter:      ',',
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			assert.Nil(t, s.CurrentRecordInfo())
+			result := []*permissivecsv.RecordInfo{}
+			for s.Scan() {
+				result = append(result, s.CurrentRecordInfo())
+			}
+			diff := deep.Equal(test.expInfo, result)
+			if diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_AlterationKindString(t *testing.T) {
+	assert.Equal(t, "none", permissivecsv.AlterationNone.String())
+	assert.Equal(t, permissivecsv.AltBareQuote, permissivecsv.AlterationBareQuote.String())
+	assert.Equal(t, permissivecsv.AltExtraneousQuote, permissivecsv.AlterationExtraneousQuote.String())
+	assert.Equal(t, permissivecsv.AltTruncatedRecord, permissivecsv.AlterationTruncatedRecord.String())
+	assert.Equal(t, permissivecsv.AltPaddedRecord, permissivecsv.AlterationPaddedRecord.String())
+	assert.Equal(t, "unknown", permissivecsv.AlterationKind(99).String())
+}
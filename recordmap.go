@@ -0,0 +1,116 @@
+package permissivecsv
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CollisionPolicy determines how CurrentRecordMap handles header columns that
+// share a name.
+type CollisionPolicy int
+
+const (
+	// CollisionKeepFirst maps a duplicated name to the first column with that
+	// name. This is the default.
+	CollisionKeepFirst CollisionPolicy = iota
+
+	// CollisionKeepLast maps a duplicated name to the last column with that
+	// name.
+	CollisionKeepLast
+
+	// CollisionRename keeps every column, by appending _2, _3, and so on to
+	// the second and subsequent occurrences of a name.
+	CollisionRename
+)
+
+// WithCollisionPolicy sets the policy CurrentRecordMap uses when the header
+// contains duplicate column names. The default is CollisionKeepFirst.
+func WithCollisionPolicy(policy CollisionPolicy) Option {
+	return func(o *options) {
+		o.collisionPolicy = policy
+	}
+}
+
+// CurrentRecordMap returns the current record as a map from column names to
+// fields. Column names are taken from the header (see RecordIsHeader), with
+// any byte order mark removed. Duplicate column names are handled according
+// to the policy set by WithCollisionPolicy.
+//
+// CurrentRecordMap returns nil if no header has been identified, or if the
+// current record is the header. If the current record has fewer fields than
+// the header, the missing columns are omitted from the map.
+func (s *Scanner) CurrentRecordMap() map[string]string {
+	if s.recordsScanned == 1 && s.firstRecord != nil && s.RecordIsHeader() {
+		return nil
+	}
+	s.resolveHeader()
+	if s.header == nil {
+		return nil
+	}
+	if s.recordMapKeys == nil {
+		s.recordMapKeys = recordMapKeys(s.header, s.opts.collisionPolicy)
+	}
+
+	m := make(map[string]string, len(s.header))
+	for i, key := range s.recordMapKeys {
+		if i < len(s.currentRecord) {
+			m[key] = s.currentRecord[i]
+		}
+	}
+	return m
+}
+
+// recordMapKeys returns the map key for each column index of header. Columns
+// that are excluded by the collision policy are omitted.
+func recordMapKeys(header []string, policy CollisionPolicy) map[int]string {
+	keys := make([]string, len(header))
+	for i, name := range header {
+		keys[i] = name
+	}
+	if len(keys) > 0 {
+		keys[0] = strings.TrimPrefix(keys[0], byteOrderMark)
+	}
+
+	result := make(map[int]string, len(keys))
+	switch policy {
+	case CollisionKeepLast:
+		seen := make(map[string]bool, len(keys))
+		for i := len(keys) - 1; i >= 0; i-- {
+			if !seen[keys[i]] {
+				seen[keys[i]] = true
+				result[i] = keys[i]
+			}
+		}
+	case CollisionRename:
+		used := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			used[key] = true
+		}
+		seen := make(map[string]bool, len(keys))
+		for i, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				result[i] = key
+				continue
+			}
+			for n := 2; ; n++ {
+				candidate := key + "_" + strconv.Itoa(n)
+				if !used[candidate] {
+					result[i] = candidate
+					used[candidate] = true
+					seen[candidate] = true
+					break
+				}
+			}
+		}
+	default:
+		seen := make(map[string]bool, len(keys))
+		for i, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				result[i] = key
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CurrentRecordMap(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		headerCheck permissivecsv.HeaderCheck
+		opts        []permissivecsv.Option
+		expMaps     []map[string]string
+	}{
+		{
+			name:        "header",
+			data:        "\ufeffname,age\nalice,30\nbob",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expMaps: []map[string]string{
+				nil,
+				{"name": "alice", "age": "30"},
+				{"name": "bob", "age": ""},
+			},
+		},
+		{
+			name:        "no header",
+			data:        "alice,30",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expMaps: []map[string]string{
+				nil,
+			},
+		},
+		{
+			name:        "keep first",
+			data:        "a,b,a,\nx,y,z,w",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expMaps: []map[string]string{
+				nil,
+				{"a": "x", "b": "y", "": "w"},
+			},
+		},
+		{
+			name:        "keep last",
+			data:        "a,b,a\nx,y,z",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:        []permissivecsv.Option{permissivecsv.WithCollisionPolicy(permissivecsv.CollisionKeepLast)},
+			expMaps: []map[string]string{
+				nil,
+				{"a": "z", "b": "y"},
+			},
+		},
+		{
+			name:        "rename",
+			data:        "a,a_2,a,a\nw,x,y,z",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:        []permissivecsv.Option{permissivecsv.WithCollisionPolicy(permissivecsv.CollisionRename)},
+			expMaps: []map[string]string{
+				nil,
+				{"a": "w", "a_2": "x", "a_3": "y", "a_4": "z"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck, test.opts...)
+			maps := []map[string]string{}
+			for s.Scan() {
+				maps = append(maps, s.CurrentRecordMap())
+			}
+			assert.Equal(t, test.expMaps, maps)
+		}
+		t.Run(test.name, testFn)
+	}
+}
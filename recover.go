@@ -0,0 +1,118 @@
+package permissivecsv
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// CallbackPanicError is reported by ScanSummary.Err if a function supplied to
+// the Scanner (such as a HeaderCheck, RepairStrategy, FieldDecoder, or
+// redaction function) panics. The panic is recovered, and scanning stops, so
+// that a faulty callback cannot crash a long-running process. Callback
+// identifies the kind of function that panicked, Value is the value passed to
+// panic, and Stack is the stack trace of the goroutine at the time of the
+// panic.
+type CallbackPanicError struct {
+	Callback string
+	Value    interface{}
+	Stack    []byte
+}
+
+func (e *CallbackPanicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.Callback, e.Value)
+}
+
+// Is reports whether target is ErrCallbackFailed.
+func (e *CallbackPanicError) Is(target error) bool {
+	return target == ErrCallbackFailed
+}
+
+// recoverCallback recovers from a panic in the named callback, and stops
+// scanning. It must be deferred.
+func (s *Scanner) recoverCallback(callback string) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	s.abort(newCallbackPanicError(callback, value))
+}
+
+func newCallbackPanicError(callback string, value interface{}) *CallbackPanicError {
+	return &CallbackPanicError{
+		Callback: callback,
+		Value:    value,
+		Stack:    debug.Stack(),
+	}
+}
+
+// abort stops scanning, and reports err via the summary, along with any error
+// that was already reported.
+func (s *Scanner) abort(err error) {
+	defer s.holdSummary()()
+	if s.scanSummary == nil {
+		s.scanSummary = s.newScanSummary()
+	}
+	s.scanSummary.addErr(err)
+	s.scanSummary.EOF = false
+	s.state = ScannerStateErrored
+}
+
+// callHeaderCheck calls the HeaderCheck. If it panics before the header has
+// been resolved, a warning is also recorded.
+func (s *Scanner) callHeaderCheck(firstRecord, secondRecord []string) bool {
+	defer func() {
+		if value := recover(); value != nil {
+			panicErr := newCallbackPanicError("HeaderCheck", value)
+			s.abort(panicErr)
+			if !s.headerResolved {
+				s.warn(WarningHeaderCheckPanicked, 1, panicErr.Error())
+			}
+		}
+	}()
+	defer s.releaseSummary()()
+	return s.headerCheck(firstRecord, secondRecord)
+}
+
+func (s *Scanner) callRepairStrategies(raw string, parsed []string, expected int) ([]string, AlterationKind, bool) {
+	defer s.recoverCallback("RepairStrategy")
+	defer s.releaseSummary()()
+	return s.opts.repairStrategies.Repair(raw, parsed, expected)
+}
+
+func (s *Scanner) callRedaction(redact func(string) string, field string) string {
+	defer s.recoverCallback("redaction")
+	defer s.releaseSummary()()
+	return redact(field)
+}
+
+// callFieldDecoder calls decoder, and additionally returns a
+// *CallbackPanicError if it panics.
+func (s *Scanner) callFieldDecoder(decoder FieldDecoder, field string, dst interface{}) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			panicErr := newCallbackPanicError("FieldDecoder", value)
+			s.abort(panicErr)
+			err = panicErr
+		}
+	}()
+	defer s.releaseSummary()()
+	return decoder(field, dst)
+}
+
+func (s *Scanner) callContinuationRule(prev, next []string) bool {
+	defer s.recoverCallback("continuation rule")
+	defer s.releaseSummary()()
+	return s.opts.continuationRule(prev, next)
+}
+
+func (s *Scanner) callCheckpoint(cp Checkpoint, summary *ScanSummary) {
+	defer s.recoverCallback("checkpoint")
+	defer s.releaseSummary()()
+	s.opts.checkpointFn(cp, summary)
+}
+
+func (s *Scanner) callEOFValidate(lastRecord []string, summary *ScanSummary) error {
+	defer s.recoverCallback("EOF validation")
+	defer s.releaseSummary()()
+	return s.opts.onEOFValidate(lastRecord, summary)
+}
@@ -0,0 +1,105 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CallbackPanics(t *testing.T) {
+	panicky := func(string) string { panic("boom") }
+	tests := []struct {
+		name        string
+		headerCheck permissivecsv.HeaderCheck
+		opts        []permissivecsv.Option
+		expRecords  int
+		expCallback string
+	}{
+		{
+			name: "HeaderCheck",
+			headerCheck: func(firstRecord, secondRecord []string) bool {
+				panic("boom")
+			},
+			opts:        []permissivecsv.Option{permissivecsv.WithNormalizedHeader()},
+			expRecords:  0,
+			expCallback: "HeaderCheck",
+		},
+		{
+			name:        "RepairStrategy",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.Option{
+				permissivecsv.WithRepairStrategies(func(raw string, parsed []string, expected int) ([]string, permissivecsv.AlterationKind, bool) {
+					panic("boom")
+				}),
+			},
+			expRecords:  2,
+			expCallback: "RepairStrategy",
+		},
+		{
+			name:        "redaction",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:        []permissivecsv.Option{permissivecsv.WithRedaction([]string{"b"}, panicky)},
+			expRecords:  1,
+			expCallback: "redaction",
+		},
+		{
+			name:        "checkpoint",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.Option{
+				permissivecsv.WithCheckpointEvery(2, func(permissivecsv.Checkpoint, *permissivecsv.ScanSummary) { panic("boom") }),
+			},
+			expRecords:  2,
+			expCallback: "checkpoint",
+		},
+		{
+			name:        "EOF validation",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.Option{
+				permissivecsv.WithOnEOFValidate(func([]string, *permissivecsv.ScanSummary) error { panic("boom") }),
+			},
+			expRecords:  4,
+			expCallback: "EOF validation",
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader("a,b\n1,2\n3\n4,5"), test.headerCheck, test.opts...)
+			records := 0
+			assert.NotPanics(t, func() {
+				for s.Scan() {
+					records++
+				}
+			})
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, permissivecsv.ScannerStateErrored, s.State())
+			assert.False(t, s.Summary().EOF)
+			var panicErr *permissivecsv.CallbackPanicError
+			if assert.True(t, errors.As(s.Summary().Err, &panicErr)) {
+				assert.Equal(t, test.expCallback, panicErr.Callback)
+				assert.Equal(t, "boom", panicErr.Value)
+				assert.NotEmpty(t, panicErr.Stack)
+				assert.Equal(t, test.expCallback+" panicked: boom", panicErr.Error())
+			}
+			assert.False(t, s.Scan())
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_FieldDecoderPanic(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("name\nalice\nbob"), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithFieldDecoder("name", func(string, interface{}) error { panic("boom") }))
+	s.Scan()
+	s.Scan()
+	var v struct{ Name string }
+	err := s.Decode(&v)
+	var panicErr *permissivecsv.CallbackPanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "FieldDecoder", panicErr.Callback)
+	assert.False(t, s.Scan())
+	assert.Equal(t, panicErr, s.Summary().Err)
+}
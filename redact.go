@@ -0,0 +1,82 @@
+package permissivecsv
+
+// WithRedaction instructs the Scanner to replace each field of the named
+// columns with the result of calling redact on it, so that sensitive values
+// (such as personally identifiable information) can be hashed or masked during
+// ingestion. Fields are redacted before records are made available by
+// CurrentRecord, or written by methods such as Normalize and Pipe, and
+// before they are retained in the summary's Alterations. The OriginalData of
+// an alteration to a record that contains redacted fields is omitted (left
+// blank), since it contains the original values. ScanRaw, which does not parse
+// records, is not affected.
+//
+// Columns are matched to the header's column names after both are normalized
+// by NormalizeColumnName, so redaction only occurs if the first record is a
+// header (see RecordIsHeader). The number of fields redacted in each column is
+// reported by the RedactionCounts field of the ScanSummary, which includes
+// every named column, so that columns that were never matched are evident.
+// WithRedaction can be supplied more than once to redact different columns in
+// different ways.
+func WithRedaction(columns []string, redact func(string) string) Option {
+	return func(o *options) {
+		for _, column := range columns {
+			o.redactions = append(o.redactions, redaction{
+				column: column,
+				redact: redact,
+			})
+		}
+	}
+}
+
+// redaction is a column to be redacted, and the function used to redact it.
+type redaction struct {
+	column string
+	redact func(string) string
+}
+
+// redactedColumn is a redaction that has been matched to the index of a
+// column.
+type redactedColumn struct {
+	redaction
+	index int
+}
+
+// resolveRedactions matches the columns supplied to WithRedaction to the
+// header's columns.
+func (s *Scanner) resolveRedactions(header []string) {
+	s.scanSummary.RedactionCounts = make(map[string]int, len(s.opts.redactions))
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = NormalizeColumnName(name)
+		if _, exists := columns[name]; !exists {
+			columns[name] = i
+		}
+	}
+	for _, r := range s.opts.redactions {
+		s.scanSummary.RedactionCounts[r.column] = 0
+		index, ok := columns[NormalizeColumnName(r.column)]
+		if ok {
+			s.redactedColumns = append(s.redactedColumns, redactedColumn{
+				redaction: r,
+				index:     index,
+			})
+		}
+	}
+}
+
+// redact redacts the fields of record (and of alternateRecord, if it is not
+// nil), and reports whether any fields were redacted.
+func (s *Scanner) redact(record, alternateRecord []string) bool {
+	redacted := false
+	for _, column := range s.redactedColumns {
+		if column.index < len(record) {
+			record[column.index] = s.callRedaction(column.redact, record[column.index])
+			s.scanSummary.RedactionCounts[column.column]++
+			redacted = true
+		}
+		if column.index < len(alternateRecord) {
+			alternateRecord[column.index] = s.callRedaction(column.redact, alternateRecord[column.index])
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,81 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func mask(field string) string {
+	return strings.Repeat("*", len(field))
+}
+
+func Test_WithRedaction(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		headerCheck    permissivecsv.HeaderCheck
+		expRecords     [][]string
+		expCounts      map[string]int
+		expAlterations []*permissivecsv.Alteration
+	}{
+		{
+			name:        "header",
+			data:        "id,Email,SSN\n1,a@b.c,123\n2,d@e.f\n3,g@h.i,456,x",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expRecords: [][]string{
+				[]string{"id", "Email", "SSN"},
+				[]string{"1", "*****", "redacted"},
+				[]string{"2", "*****", "redacted"},
+				[]string{"3", "*****", "redacted"},
+			},
+			expCounts: map[string]int{"email": 3, "ssn": 3, "phone": 0},
+			expAlterations: []*permissivecsv.Alteration{
+				&permissivecsv.Alteration{
+					RecordOrdinal:         3,
+					ByteOffset:            25,
+					OriginalData:          "",
+					ResultingRecord:       []string{"2", "*****", "redacted"},
+					Kind:                  permissivecsv.AlterationPaddedRecord,
+					AlterationDescription: permissivecsv.AltPaddedRecord,
+				},
+				&permissivecsv.Alteration{
+					RecordOrdinal:         4,
+					ByteOffset:            33,
+					OriginalData:          "",
+					ResultingRecord:       []string{"3", "*****", "redacted"},
+					Kind:                  permissivecsv.AlterationTruncatedRecord,
+					AlterationDescription: permissivecsv.AltTruncatedRecord,
+				},
+			},
+		},
+		{
+			name:        "no header",
+			data:        "1,a@b.c,123",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expRecords: [][]string{
+				[]string{"1", "a@b.c", "123"},
+			},
+			expCounts:      map[string]int{"email": 0, "ssn": 0, "phone": 0},
+			expAlterations: []*permissivecsv.Alteration{},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck,
+				permissivecsv.WithRedaction([]string{"email", "phone"}, mask),
+				permissivecsv.WithRedaction([]string{"ssn"}, func(string) string { return "redacted" }))
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expCounts, s.Summary().RedactionCounts)
+			assert.Equal(t, test.expAlterations, s.Summary().Alterations)
+		}
+		t.Run(test.name, testFn)
+	}
+}
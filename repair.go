@@ -0,0 +1,159 @@
+package permissivecsv
+
+import (
+	"strings"
+	"sync"
+)
+
+// RepairStrategy attempts to repair a record whose field count does not match
+// the expected field count. raw is the record as it appeared in the input
+// (without its terminator), parsed is the record's fields, and expected is the
+// expected field count.
+//
+// If the strategy is able to repair the record, it returns the repaired
+// record, the kind of alteration that was made, and true. The repaired record
+// is still padded or truncated if its length does not match expected. If the
+// strategy does not apply to the record, it returns false, and the next
+// strategy in the chain is consulted. parsed must not be modified.
+//
+// Strategies are not consulted for the first record (which defines the
+// expected field count), or for records with ambiguous quotes.
+type RepairStrategy func(raw string, parsed []string, expected int) ([]string, AlterationKind, bool)
+
+// RepairChain is a chain of RepairStrategies that are consulted in order.
+type RepairChain []RepairStrategy
+
+// Repair returns the result of the first strategy in the chain that is able to
+// repair the record. If no strategy applies, Repair returns false.
+func (c RepairChain) Repair(raw string, parsed []string, expected int) ([]string, AlterationKind, bool) {
+	for _, strategy := range c {
+		repaired, kind, ok := strategy(raw, parsed, expected)
+		if ok {
+			return repaired, kind, true
+		}
+	}
+	return nil, AlterationNone, false
+}
+
+// RepairMergeSplitFields is a RepairStrategy for records that have exactly one
+// more field than expected because a value containing an unquoted comma (such
+// as Smith, Jr) was split in two. If exactly one pair of adjacent unquoted
+// fields looks like such a split value (the second field begins with a space,
+// and neither field is blank), the pair is merged, and the repair is reported
+// as AlterationMergedFields.
+var RepairMergeSplitFields RepairStrategy = func(raw string, parsed []string, expected int) ([]string, AlterationKind, bool) {
+	if len(parsed) != expected+1 {
+		return nil, AlterationNone, false
+	}
+	states := make([]FieldState, len(parsed))
+	markQuotedFields(raw, ',', states)
+
+	candidate := -1
+	for i := 0; i < len(parsed)-1; i++ {
+		if states[i] != FieldBare || states[i+1] != FieldBare {
+			continue
+		}
+		left, right := parsed[i], parsed[i+1]
+		if strings.TrimSpace(left) == "" || strings.TrimSpace(right) == "" {
+			continue
+		}
+		if !strings.HasPrefix(right, " ") {
+			continue
+		}
+		if candidate >= 0 {
+			// more than one candidate; the split is ambiguous.
+			return nil, AlterationNone, false
+		}
+		candidate = i
+	}
+	if candidate < 0 {
+		return nil, AlterationNone, false
+	}
+
+	merged := make([]string, 0, len(parsed)-1)
+	merged = append(merged, parsed[:candidate]...)
+	merged = append(merged, parsed[candidate]+","+parsed[candidate+1])
+	merged = append(merged, parsed[candidate+2:]...)
+	return merged, AlterationMergedFields, true
+}
+
+// RepairJoinTrailingFields is a RepairStrategy for records that have more
+// fields than expected because the last column contains free text (such as a
+// comment or log message) with unquoted commas. The extra trailing fields are
+// joined back into the last field (separated by commas), rather than being
+// truncated, and the repair is reported as AlterationJoinedTrailingFields.
+var RepairJoinTrailingFields RepairStrategy = func(raw string, parsed []string, expected int) ([]string, AlterationKind, bool) {
+	if expected < 1 || len(parsed) <= expected {
+		return nil, AlterationNone, false
+	}
+	joined := make([]string, expected)
+	copy(joined, parsed[:expected-1])
+	joined[expected-1] = strings.Join(parsed[expected-1:], ",")
+	return joined, AlterationJoinedTrailingFields, true
+}
+
+var (
+	customAlterationKindsMu sync.RWMutex
+	customAlterationKinds   []string
+)
+
+// RegisterAlterationKind allocates a new AlterationKind for use by a custom
+// RepairStrategy. The kind's String method returns description.
+// RegisterAlterationKind is typically called when initializing a package
+// level variable.
+func RegisterAlterationKind(description string) AlterationKind {
+	customAlterationKindsMu.Lock()
+	defer customAlterationKindsMu.Unlock()
+	customAlterationKinds = append(customAlterationKinds, description)
+	return firstCustomAlterationKind + AlterationKind(len(customAlterationKinds)-1)
+}
+
+func registeredAlterationKind(k AlterationKind) string {
+	customAlterationKindsMu.RLock()
+	defer customAlterationKindsMu.RUnlock()
+	i := int(k - firstCustomAlterationKind)
+	if i < 0 || i >= len(customAlterationKinds) {
+		return "unknown"
+	}
+	return customAlterationKinds[i]
+}
+
+// fitRecord returns a copy of record padded or truncated to n fields, at the
+// ends indicated by truncation and padding. shift is the number of fields that
+// were removed from (if positive) or added to (if negative) the beginning of
+// the record.
+func fitRecord(record []string, n int, truncation, padding RecordEnd) (fitted []string, shift int) {
+	if len(record) > n && truncation == RecordEndLeading {
+		shift = len(record) - n
+	} else if len(record) < n && padding == RecordEndLeading {
+		shift = len(record) - n
+	}
+	fitted = make([]string, n)
+	for i := range fitted {
+		if j := i + shift; j >= 0 && j < len(record) {
+			fitted[i] = record[j]
+		}
+	}
+	return fitted, shift
+}
+
+// mergedFieldIndex returns the index i if repaired is parsed with fields i and
+// i+1 joined by a comma. Otherwise, mergedFieldIndex returns -1.
+func mergedFieldIndex(parsed, repaired []string) int {
+	if len(repaired) != len(parsed)-1 {
+		return -1
+	}
+	i := 0
+	for i < len(repaired) && repaired[i] == parsed[i] {
+		i++
+	}
+	if i == len(repaired) || repaired[i] != parsed[i]+","+parsed[i+1] {
+		return -1
+	}
+	for j := i + 1; j < len(repaired); j++ {
+		if repaired[j] != parsed[j+1] {
+			return -1
+		}
+	}
+	return i
+}
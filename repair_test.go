@@ -0,0 +1,123 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+var altSplitCityState = permissivecsv.RegisterAlterationKind("split city and state")
+
+// splitCityState repairs records whose final field contains both a city and a
+// two letter state code separated by a space.
+func splitCityState(raw string, parsed []string, expected int) ([]string, permissivecsv.AlterationKind, bool) {
+	if len(parsed) != expected-1 {
+		return nil, permissivecsv.AlterationNone, false
+	}
+	last := parsed[len(parsed)-1]
+	i := strings.LastIndex(last, " ")
+	if i < 0 || len(last)-i != 3 {
+		return nil, permissivecsv.AlterationNone, false
+	}
+	repaired := append([]string{}, parsed[:len(parsed)-1]...)
+	return append(repaired, last[:i], last[i+1:]), altSplitCityState, true
+}
+
+func Test_RepairStrategies(t *testing.T) {
+	data := "name,city,state\n" +
+		"Ann,Austin TX\n" +
+		"Bob,Reno\n" +
+		"Smith, Jr,Waco,TX\n"
+
+	s := permissivecsv.NewScanner(strings.NewReader(data),
+		permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithRepairStrategies(splitCityState),
+		permissivecsv.WithMergeSplitFields())
+	records := [][]string{}
+	for s.Scan() {
+		records = append(records, s.CurrentRecord())
+	}
+
+	expRecords := [][]string{
+		[]string{"name", "city", "state"},
+		[]string{"Ann", "Austin", "TX"},
+		[]string{"Bob", "Reno", ""},
+		[]string{"Smith, Jr", "Waco", "TX"},
+	}
+	assert.Equal(t, expRecords, records)
+
+	expAlterations := []*permissivecsv.Alteration{
+		&permissivecsv.Alteration{
+			RecordOrdinal:         2,
+			ByteOffset:            16,
+			OriginalData:          "Ann,Austin TX",
+			ResultingRecord:       []string{"Ann", "Austin", "TX"},
+			AlternateRecord:       []string{"Ann", "Austin TX", ""},
+			Kind:                  altSplitCityState,
+			AlterationDescription: "split city and state",
+			FieldChanges: []permissivecsv.FieldChange{
+				{Index: 1, OriginalIndex: 1, Original: "Austin TX", Resulting: "Austin"},
+				{Index: 2, OriginalIndex: -1, Resulting: "TX"},
+			},
+		},
+		&permissivecsv.Alteration{
+			RecordOrdinal:         3,
+			ByteOffset:            30,
+			OriginalData:          "Bob,Reno",
+			ResultingRecord:       []string{"Bob", "Reno", ""},
+			Kind:                  permissivecsv.AlterationPaddedRecord,
+			AlterationDescription: permissivecsv.AltPaddedRecord,
+			FieldChanges: []permissivecsv.FieldChange{
+				{Index: 2, OriginalIndex: -1},
+			},
+		},
+		&permissivecsv.Alteration{
+			RecordOrdinal:         4,
+			ByteOffset:            39,
+			OriginalData:          "Smith, Jr,Waco,TX",
+			ResultingRecord:       []string{"Smith, Jr", "Waco", "TX"},
+			AlternateRecord:       []string{"Smith", " Jr", "Waco"},
+			Kind:                  permissivecsv.AlterationMergedFields,
+			AlterationDescription: permissivecsv.AltMergedFields,
+			FieldChanges: []permissivecsv.FieldChange{
+				{Index: 0, OriginalIndex: 0, Original: "Smith", Resulting: "Smith, Jr"},
+				{Index: -1, OriginalIndex: 1, Original: " Jr"},
+			},
+		},
+	}
+	diff := deep.Equal(expAlterations, s.Summary().Alterations)
+	if diff != nil {
+		t.Error(diff)
+	}
+}
+
+func Test_RepairChain(t *testing.T) {
+	never := func(raw string, parsed []string, expected int) ([]string, permissivecsv.AlterationKind, bool) {
+		return nil, permissivecsv.AlterationNone, false
+	}
+	first := func(raw string, parsed []string, expected int) ([]string, permissivecsv.AlterationKind, bool) {
+		return []string{"first"}, permissivecsv.AlterationPaddedRecord, true
+	}
+	second := func(raw string, parsed []string, expected int) ([]string, permissivecsv.AlterationKind, bool) {
+		return []string{"second"}, permissivecsv.AlterationTruncatedRecord, true
+	}
+
+	chain := permissivecsv.RepairChain{never, first, second}
+	repaired, kind, ok := chain.Repair("a", []string{"a"}, 2)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"first"}, repaired)
+	assert.Equal(t, permissivecsv.AlterationPaddedRecord, kind)
+
+	_, _, ok = permissivecsv.RepairChain{never}.Repair("a", []string{"a"}, 2)
+	assert.False(t, ok)
+}
+
+func Test_RegisterAlterationKind(t *testing.T) {
+	kind := permissivecsv.RegisterAlterationKind("rejoined address")
+	assert.Equal(t, "rejoined address", kind.String())
+	assert.NotEqual(t, altSplitCityState, kind)
+	assert.Equal(t, "split city and state", altSplitCityState.String())
+}
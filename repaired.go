@@ -0,0 +1,100 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// RepairedReader is an io.Reader that produces the records of a Scanner as
+// standards-compliant CSV (as written by Normalize), scanning the input as the
+// output is read. This allows repaired data to be passed directly to APIs that
+// consume a reader, such as an HTTP request body or an object store upload.
+type RepairedReader struct {
+	scanner *Scanner
+	encoder *recordEncoder
+	buffer  bytes.Buffer
+	writer  *bufio.Writer
+	done    bool
+}
+
+// Repaired returns a RepairedReader that repairs the data read from src. The
+// Scanner assumes that the first record is a header (so options such as
+// WithNormalizedHeader apply to it), though the header is written to the
+// output like any other record. To use a different HeaderCheck, use the
+// Repaired method of a Scanner.
+func Repaired(src io.Reader, opts ...Option) *RepairedReader {
+	return NewScanner(src, HeaderCheckAssumeHeaderExists, opts...).Repaired()
+}
+
+// Repaired returns a RepairedReader that produces the remaining records of the
+// Scanner. The Scanner should not be used directly while the RepairedReader is
+// in use.
+func (s *Scanner) Repaired() *RepairedReader {
+	encoder, _ := newRecordEncoder(Dialect{})
+	r := &RepairedReader{
+		scanner: s,
+		encoder: encoder,
+	}
+	r.writer = bufio.NewWriter(&r.buffer)
+	return r
+}
+
+// Read reads repaired CSV into p. Once all records have been read, Read
+// returns io.EOF, or the error returned by the underlaying reader, if any.
+func (r *RepairedReader) Read(p []byte) (int, error) {
+	for r.buffer.Len() < len(p) && !r.done {
+		if !r.scanner.Scan() {
+			r.done = true
+			break
+		}
+		record, _ := r.scanner.outputRecord(nil)
+		r.encoder.write(r.writer, record, nil)
+		r.writer.Flush()
+	}
+	if r.buffer.Len() > 0 || len(p) == 0 {
+		return r.buffer.Read(p)
+	}
+	if err := r.scanner.Summary().Err; err != nil {
+		return 0, err
+	}
+	return 0, io.EOF
+}
+
+// WriteTo writes the remaining repaired CSV to w, and returns the number of
+// bytes written. WriteTo returns the first error returned by w, or otherwise
+// the error returned by the underlaying reader, if any. Implementing
+// io.WriterTo allows io.Copy to stream records directly to w, without an
+// intermediate buffer.
+func (r *RepairedReader) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	if r.buffer.Len() > 0 {
+		_, err := r.buffer.WriteTo(cw)
+		if err != nil {
+			return cw.n, err
+		}
+	}
+	if r.done {
+		return cw.n, r.scanner.Summary().Err
+	}
+	r.done = true
+	_, err := r.scanner.rewrite(cw, normalizeOptions{})
+	return cw.n, err
+}
+
+// Summary returns the summary of the records that have been scanned so far.
+func (r *RepairedReader) Summary() *ScanSummary {
+	return r.scanner.Summary()
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,95 @@
+package permissivecsv_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Repaired(t *testing.T) {
+	data := "a,b\r\n1,\"x\ny\"\r\n2\n3,4,5\n\"bad\"quote,6"
+	exp := "a,b\n1,\"x\ny\"\n2,\n3,4\n,\n"
+
+	tests := []struct {
+		name string
+		read func(r *permissivecsv.RepairedReader) ([]byte, error)
+	}{
+		{
+			name: "ReadAll",
+			read: func(r *permissivecsv.RepairedReader) ([]byte, error) {
+				return ioutil.ReadAll(r)
+			},
+		},
+		{
+			name: "one byte at a time",
+			read: func(r *permissivecsv.RepairedReader) ([]byte, error) {
+				return ioutil.ReadAll(iotest.OneByteReader(r))
+			},
+		},
+		{
+			name: "WriteTo",
+			read: func(r *permissivecsv.RepairedReader) ([]byte, error) {
+				buf := new(bytes.Buffer)
+				n, err := r.WriteTo(buf)
+				assert.Equal(t, int64(buf.Len()), n)
+				return buf.Bytes(), err
+			},
+		},
+		{
+			name: "Read then WriteTo",
+			read: func(r *permissivecsv.RepairedReader) ([]byte, error) {
+				p := make([]byte, 3)
+				n, err := r.Read(p)
+				if err != nil {
+					return nil, err
+				}
+				buf := bytes.NewBuffer(p[:n])
+				_, err = r.WriteTo(buf)
+				return buf.Bytes(), err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			r := permissivecsv.Repaired(strings.NewReader(data))
+			output, err := test.read(r)
+			assert.NoError(t, err)
+			assert.Equal(t, exp, string(output))
+			summary := r.Summary()
+			assert.True(t, summary.EOF)
+			assert.Equal(t, 3, summary.AlterationCount)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_RepairedReaderError(t *testing.T) {
+	r := permissivecsv.Repaired(BadReader(strings.NewReader("a")))
+	_, err := ioutil.ReadAll(r)
+	assert.True(t, errors.Is(err, ErrReader), "got %v", err)
+
+	r = permissivecsv.Repaired(BadReader(strings.NewReader("a")))
+	_, err = r.WriteTo(ioutil.Discard)
+	assert.True(t, errors.Is(err, ErrReader), "got %v", err)
+
+	r = permissivecsv.Repaired(strings.NewReader("a\nb"))
+	n, err := io.Copy(&failingWriter{limit: 1}, r)
+	assert.Equal(t, ErrWriter, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func Test_ScannerRepaired(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("First Name\nalice"), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithNormalizedHeader())
+	output, err := ioutil.ReadAll(s.Repaired())
+	assert.NoError(t, err)
+	assert.Equal(t, "First Name\nalice\n", string(output))
+}
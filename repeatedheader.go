@@ -0,0 +1,38 @@
+package permissivecsv
+
+// WithSkipRepeatedHeaders instructs the Scanner to skip any record that is
+// byte-identical to the header (see RecordIsHeader), wherever it appears in
+// the input. Such records are common when files that each begin with a header
+// are appended to one another. Each skipped record is counted by
+// ScanSummary.RepeatedHeaderCount, and, like a dropped record (see
+// WithFieldCountTolerance), is included in RecordCount, so that record
+// ordinals continue to reflect the input. Records are only skipped if the
+// first record is a header. Records that merely contain the same fields (for
+// instance, with different quoting) are not skipped.
+func WithSkipRepeatedHeaders() Option {
+	return func(o *options) {
+		o.skipRepeatedHeaders = true
+	}
+}
+
+// resolveRepeatedHeader retains the header (without its terminator), so that
+// records that repeat it can be skipped (see WithSkipRepeatedHeaders).
+func (s *Scanner) resolveRepeatedHeader(trimmedRawRecord string) {
+	if trimmedRawRecord != "" {
+		s.repeatedHeader = &trimmedRawRecord
+	}
+}
+
+// skipRepeatedHeader skips the current record if it repeats the header, and
+// reports whether it did so. The record's bytes are left unclaimed, so that
+// Partition includes them in the enclosing segment.
+func (s *Scanner) skipRepeatedHeader(rawRecord, trimmedRawRecord string) bool {
+	if s.repeatedHeader == nil || trimmedRawRecord != *s.repeatedHeader {
+		return false
+	}
+	s.scanSummary.RepeatedHeaderCount++
+	s.recordsScanned++
+	s.unclaimBytes([]byte(rawRecord))
+	s.currentDropped = true
+	return true
+}
@@ -0,0 +1,104 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithSkipRepeatedHeaders(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		headerCheck    permissivecsv.HeaderCheck
+		expRecords     [][]string
+		expOrdinals    []int
+		expRepeated    int
+		expRecordCount int
+	}{
+		{
+			name:           "repeated header",
+			data:           "id,name\n1,a\nid,name\n2,b\n",
+			headerCheck:    permissivecsv.HeaderCheckAssumeHeaderExists,
+			expRecords:     [][]string{{"id", "name"}, {"1", "a"}, {"2", "b"}},
+			expOrdinals:    []int{1, 2, 4},
+			expRepeated:    1,
+			expRecordCount: 4,
+		},
+		{
+			name:           "several repeated headers",
+			data:           "id,name\r\nid,name\r\n1,a\r\nid,name",
+			headerCheck:    permissivecsv.HeaderCheckAssumeHeaderExists,
+			expRecords:     [][]string{{"id", "name"}, {"1", "a"}},
+			expOrdinals:    []int{1, 3},
+			expRepeated:    2,
+			expRecordCount: 4,
+		},
+		{
+			name:           "no header",
+			data:           "id,name\n1,a\nid,name\n",
+			headerCheck:    permissivecsv.HeaderCheckAssumeNoHeader,
+			expRecords:     [][]string{{"id", "name"}, {"1", "a"}, {"id", "name"}},
+			expOrdinals:    []int{1, 2, 3},
+			expRepeated:    0,
+			expRecordCount: 3,
+		},
+		{
+			name:           "different quoting",
+			data:           "id,name\n\"id\",name\n",
+			headerCheck:    permissivecsv.HeaderCheckAssumeHeaderExists,
+			expRecords:     [][]string{{"id", "name"}, {"id", "name"}},
+			expOrdinals:    []int{1, 2},
+			expRepeated:    0,
+			expRecordCount: 2,
+		},
+		{
+			name:           "different whitespace",
+			data:           "id,name\nid, name\n",
+			headerCheck:    permissivecsv.HeaderCheckAssumeHeaderExists,
+			expRecords:     [][]string{{"id", "name"}, {"id", " name"}},
+			expOrdinals:    []int{1, 2},
+			expRepeated:    0,
+			expRecordCount: 2,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck, permissivecsv.WithSkipRepeatedHeaders())
+			records := [][]string{}
+			ordinals := []int{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+				ordinals = append(ordinals, s.CurrentRecordInfo().Ordinal)
+			}
+			summary := s.Summary()
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expOrdinals, ordinals)
+			assert.Equal(t, test.expRepeated, summary.RepeatedHeaderCount)
+			assert.Equal(t, test.expRecordCount, summary.RecordCount)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithSkipRepeatedHeadersPartition(t *testing.T) {
+	data := "h1,h2\na,b\nh1,h2\nc,d\ne,f\n"
+	r := strings.NewReader(data)
+	s := permissivecsv.NewScanner(r, permissivecsv.HeaderCheckAssumeHeaderExists, permissivecsv.WithSkipRepeatedHeaders())
+	segments, err := s.Partition(2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), s.HeaderSegment().Length)
+	assert.Len(t, segments, 2)
+	assert.Equal(t, int64(6), segments[0].LowerOffset)
+	assert.Equal(t, int64(14), segments[0].Length, "the skipped header belongs to the first segment")
+	assert.Equal(t, int64(20), segments[1].LowerOffset)
+	assert.Equal(t, int64(4), segments[1].Length)
+	assert.Equal(t, int64(0), segments[0].SkippedEmptyRecords)
+	for _, segment := range segments {
+		section := strings.NewReader(data[segment.LowerOffset : segment.LowerOffset+segment.Length])
+		assert.NoError(t, segment.Verify(section))
+	}
+}
@@ -0,0 +1,197 @@
+package permissivecsv
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ErrInvalidReportFormat is returned by WriteReport if the supplied format is
+// not supported.
+var ErrInvalidReportFormat = fmt.Errorf("unsupported report format")
+
+// ReportFormat identifies the format of a report written by WriteReport.
+type ReportFormat int
+
+const (
+	// ReportCSV is a CSV file containing one row per alteration, preceded by a
+	// header. The columns are record_ordinal, byte_offset, code, description,
+	// original_data, and resulting_record (a JSON array).
+	ReportCSV ReportFormat = iota
+
+	// ReportHTML is a small, self-contained HTML page with tables summarizing
+	// the scan, alterations by kind, terminators, and each alteration. The
+	// page has no external dependencies, so it is suitable for emailing to
+	// the provider of a file.
+	ReportHTML
+)
+
+// WriteReport writes a report describing the summary to w in the supplied
+// format. If format is not supported, ErrInvalidReportFormat is returned.
+// Otherwise, WriteReport returns any error returned by w.
+func (s *ScanSummary) WriteReport(w io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportCSV:
+		return s.writeCSVReport(w)
+	case ReportHTML:
+		return s.writeHTMLReport(w)
+	default:
+		return ErrInvalidReportFormat
+	}
+}
+
+func (s *ScanSummary) writeCSVReport(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"record_ordinal",
+		"byte_offset",
+		"code",
+		"description",
+		"original_data",
+		"resulting_record",
+	})
+	for _, alteration := range s.Alterations {
+		code, _ := problemCodeAndTitle(alteration, nil)
+		resultingRecord, err := json.Marshal(alteration.ResultingRecord)
+		if err != nil {
+			return err
+		}
+		cw.Write([]string{
+			strconv.Itoa(alteration.RecordOrdinal),
+			strconv.FormatInt(alteration.ByteOffset, 10),
+			code,
+			alteration.AlterationDescription,
+			alteration.OriginalData,
+			string(resultingRecord),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// reportCount is a row of one of the count tables in an HTML report.
+type reportCount struct {
+	Name  string
+	Count int
+}
+
+type htmlReport struct {
+	Summary     *ScanSummary
+	Kinds       []reportCount
+	Terminators []reportCount
+	Alterations []htmlReportAlteration
+}
+
+type htmlReportAlteration struct {
+	*Alteration
+	Code            string
+	ResultingRecord string
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Scan Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.75em; text-align: left; vertical-align: top; }
+th { background: #eee; }
+td.data { font-family: monospace; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Scan Report</h1>
+<table>
+<tr><th>Records Scanned</th><td>{{.Summary.RecordCount}}</td></tr>
+<tr><th>Alterations Made</th><td>{{.Summary.AlterationCount}}</td></tr>
+<tr><th>Empty Records</th><td>{{.Summary.EmptyRecordCount}}</td></tr>
+<tr><th>EOF</th><td>{{.Summary.EOF}}</td></tr>
+<tr><th>Err</th><td>{{if .Summary.Err}}{{.Summary.Err}}{{else}}none{{end}}</td></tr>
+</table>
+<h2>Alterations by Kind</h2>
+<table>
+<tr><th>Kind</th><th>Count</th></tr>
+{{range .Kinds}}<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{else}}<tr><td colspan="2">none</td></tr>
+{{end}}</table>
+<h2>Terminators</h2>
+<table>
+<tr><th>Terminator</th><th>Records</th></tr>
+{{range .Terminators}}<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{else}}<tr><td colspan="2">none</td></tr>
+{{end}}</table>
+<h2>Alterations</h2>
+<table>
+<tr><th>Record</th><th>Byte Offset</th><th>Code</th><th>Original Data</th><th>Resulting Record</th></tr>
+{{range .Alterations}}<tr><td>{{.RecordOrdinal}}</td><td>{{.ByteOffset}}</td><td>{{.Code}}</td><td class="data">{{.OriginalData}}</td><td class="data">{{.ResultingRecord}}</td></tr>
+{{else}}<tr><td colspan="5">none</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func (s *ScanSummary) writeHTMLReport(w io.Writer) error {
+	report := htmlReport{
+		Summary:     s,
+		Kinds:       []reportCount{},
+		Terminators: []reportCount{},
+		Alterations: []htmlReportAlteration{},
+	}
+
+	kindCounts := map[string]int{}
+	for _, alteration := range s.Alterations {
+		code, _ := problemCodeAndTitle(alteration, nil)
+		kindCounts[code]++
+		resultingRecord, err := json.Marshal(alteration.ResultingRecord)
+		if err != nil {
+			return err
+		}
+		report.Alterations = append(report.Alterations, htmlReportAlteration{
+			Alteration:      alteration,
+			Code:            code,
+			ResultingRecord: string(resultingRecord),
+		})
+	}
+	for code, count := range kindCounts {
+		report.Kinds = append(report.Kinds, reportCount{Name: code, Count: count})
+	}
+	sort.Slice(report.Kinds, func(i, j int) bool {
+		return report.Kinds[i].Name < report.Kinds[j].Name
+	})
+
+	for _, terminator := range []Terminator{TerminatorDOS, TerminatorInvertedDOS, TerminatorUnix, TerminatorCarriageReturn, ""} {
+		count, ok := s.TerminatorCounts[terminator]
+		if ok && count > 0 {
+			report.Terminators = append(report.Terminators, reportCount{
+				Name:  terminatorName(terminator),
+				Count: count,
+			})
+		}
+	}
+
+	return htmlReportTemplate.Execute(w, report)
+}
+
+// terminatorName returns a human-readable name for terminator.
+func terminatorName(terminator Terminator) string {
+	switch terminator {
+	case TerminatorDOS:
+		return `DOS (\r\n)`
+	case TerminatorInvertedDOS:
+		return `Inverted DOS (\n\r)`
+	case TerminatorUnix:
+		return `Unix (\n)`
+	case TerminatorCarriageReturn:
+		return `Carriage Return (\r)`
+	case "":
+		return "None (unterminated)"
+	default:
+		return strconv.Quote(string(terminator))
+	}
+}
@@ -0,0 +1,53 @@
+package permissivecsv_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteReportCSV(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\r\nc\r\nd,e,f\n\"g"), permissivecsv.HeaderCheckAssumeNoHeader)
+	for s.Scan() {
+	}
+	buf := new(bytes.Buffer)
+	err := s.Summary().WriteReport(buf, permissivecsv.ReportCSV)
+	assert.NoError(t, err)
+	exp := "record_ordinal,byte_offset,code,description,original_data,resulting_record\n" +
+		"2,5,padded-record,padded record,c,\"[\"\"c\"\",\"\"\"\"]\"\n" +
+		"3,8,truncated-record,truncated record,\"d,e,f\",\"[\"\"d\"\",\"\"e\"\"]\"\n" +
+		"4,14,extraneous-quote,extraneous quote,\"\"\"g\",\"[\"\"\"\",\"\"\"\"]\"\n"
+	assert.Equal(t, exp, buf.String())
+}
+
+func Test_WriteReportHTML(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\r\nc\r\n<script>,x,y\n"), permissivecsv.HeaderCheckAssumeNoHeader)
+	for s.Scan() {
+	}
+	buf := new(bytes.Buffer)
+	err := s.Summary().WriteReport(buf, permissivecsv.ReportHTML)
+	assert.NoError(t, err)
+	report := buf.String()
+	assert.True(t, strings.HasPrefix(report, "<!DOCTYPE html>"))
+	assert.Contains(t, report, "<tr><th>Records Scanned</th><td>3</td></tr>")
+	assert.Contains(t, report, "<tr><td>padded-record</td><td>1</td></tr>")
+	assert.Contains(t, report, "<tr><td>truncated-record</td><td>1</td></tr>")
+	assert.Contains(t, report, `<tr><td>DOS (\r\n)</td><td>2</td></tr>`)
+	assert.Contains(t, report, `<tr><td>Unix (\n)</td><td>1</td></tr>`)
+	assert.Contains(t, report, "&lt;script&gt;,x,y", "original data is escaped")
+	assert.NotContains(t, report, "<script>")
+}
+
+func Test_WriteReportErrors(t *testing.T) {
+	summary := &permissivecsv.ScanSummary{}
+	err := summary.WriteReport(new(bytes.Buffer), permissivecsv.ReportFormat(99))
+	assert.Equal(t, permissivecsv.ErrInvalidReportFormat, err)
+
+	err = summary.WriteReport(&failingWriter{limit: 0}, permissivecsv.ReportHTML)
+	assert.Error(t, err)
+	err = summary.WriteReport(&failingWriter{limit: 0}, permissivecsv.ReportCSV)
+	assert.Error(t, err)
+}
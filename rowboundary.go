@@ -0,0 +1,114 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+)
+
+// recordSplitter locates the raw records of the input (see
+// linesplit.Splitter).
+type recordSplitter interface {
+	Split(data []byte, atEOF bool) (advance int, token []byte, err error)
+	CurrentTerminator() []byte
+	QuoteForceClosed() bool
+}
+
+// WithRowBoundary is a last resort for files with so many unquoted
+// terminators within their fields that records cannot be located by their
+// terminators alone. Instead, a terminator only ends a record if rowStart
+// reports that the line following it (without its terminator) begins a new
+// record, such as because it begins with a date or an id. Any other
+// terminators are considered part of the record, as if they had been quoted.
+//
+// Quotes are ignored when locating records, though fields are still parsed
+// with the usual quoting rules. DOS (\r\n), unix (\n), and carriage return
+// (\r) terminators are recognized, but inverted DOS terminators are not, and
+// WithTerminatorPriority, WithDisableInvertedDOS, and WithMaxQuotedFieldBytes
+// have no effect. The first line of the input always begins a record.
+// rowStart is also called for empty lines, which are otherwise considered part
+// of the preceding record. However, empty lines that precede the first record
+// or follow the last record are treated as empty records, as usual.
+func WithRowBoundary(rowStart func(line []byte) bool) Option {
+	return func(o *options) {
+		o.rowStart = rowStart
+	}
+}
+
+// WithRowBoundaryRegex is equivalent to WithRowBoundary, with a rowStart
+// function that reports whether pattern matches at the beginning of the line.
+// For instance, WithRowBoundaryRegex(regexp.MustCompile(`^\d{4}-\d{2}-\d{2},`))
+// ends records only at terminators that are followed by an ISO 8601 date.
+func WithRowBoundaryRegex(pattern *regexp.Regexp) Option {
+	return WithRowBoundary(func(line []byte) bool {
+		loc := pattern.FindIndex(line)
+		return loc != nil && loc[0] == 0
+	})
+}
+
+// rowBoundarySplitter is a recordSplitter that ends records only at
+// terminators that are followed by the start of a row (see WithRowBoundary).
+type rowBoundarySplitter struct {
+	rowStart          func(line []byte) bool
+	currentTerminator []byte
+}
+
+func (l *rowBoundarySplitter) CurrentTerminator() []byte {
+	return l.currentTerminator
+}
+
+func (l *rowBoundarySplitter) QuoteForceClosed() bool {
+	return false
+}
+
+// Split is a bufio.SplitFunc. If rowStart panics, the panic is returned as a
+// CallbackPanicError, which stops the bufio.Scanner.
+func (l *rowBoundarySplitter) Split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			advance, token = 0, nil
+			err = newCallbackPanicError("row boundary", value)
+		}
+	}()
+	l.currentTerminator = nil
+	for start := 0; ; {
+		index := bytes.IndexAny(data[start:], "\r\n")
+		if index == -1 {
+			break
+		}
+		index += start
+		terminatorLength := 1
+		if data[index] == '\r' {
+			if index+1 == len(data) && !atEOF {
+				return 0, nil, nil
+			}
+			if index+1 < len(data) && data[index+1] == '\n' {
+				terminatorLength = 2
+			}
+		}
+		next := index + terminatorLength
+		line := data[next:]
+		lineEnd := bytes.IndexAny(line, "\r\n")
+		if lineEnd == -1 && !atEOF {
+			// the following line must be complete before it can be
+			// evaluated.
+			return 0, nil, nil
+		}
+		if lineEnd != -1 {
+			line = line[:lineEnd]
+		}
+		if index == 0 || atEOF && len(bytes.Trim(data[next:], "\r\n")) == 0 || l.rowStart(line) {
+			l.currentTerminator = data[index:next]
+			return next, data[:next], nil
+		}
+		start = next
+	}
+
+	if !atEOF {
+		return 0, nil, nil
+	}
+	if data != nil {
+		l.currentTerminator = []byte{}
+	}
+	return len(data), data, bufio.ErrFinalToken
+}
@@ -0,0 +1,106 @@
+package permissivecsv_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithRowBoundary(t *testing.T) {
+	datePattern := regexp.MustCompile(`^\d{4}-\d{2}-\d{2},`)
+	tests := []struct {
+		name           string
+		data           string
+		opt            permissivecsv.Option
+		expRecords     [][]string
+		expTerminators []string
+		expEmpty       int
+	}{
+		{
+			name: "regex",
+			data: "date,note\n2020-01-01,line one\nline two\n2020-01-02,ok\r\n2020-01-03,\"quoted\nstill\"\n\n",
+			opt:  permissivecsv.WithRowBoundaryRegex(datePattern),
+			expRecords: [][]string{
+				{"date", "note"},
+				{"2020-01-01", "line one\nline two"},
+				{"2020-01-02", "ok"},
+				{"2020-01-03", "quoted\nstill"},
+			},
+			expTerminators: []string{"\n", "\n", "\r\n", "\n"},
+			expEmpty:       1,
+		},
+		{
+			name: "unbalanced quotes are ignored",
+			data: "date,note\r2020-01-01,\"open\r2020-01-02,closed",
+			opt:  permissivecsv.WithRowBoundaryRegex(datePattern),
+			expRecords: [][]string{
+				{"date", "note"},
+				{"", ""},
+				{"2020-01-02", "closed"},
+			},
+			expTerminators: []string{"\r", "\r", ""},
+			expEmpty:       0,
+		},
+		{
+			name: "regex must match at the start of the line",
+			data: "date,note\n2020-01-01,see 2020-01-02,x\n2020-01-03,y",
+			opt:  permissivecsv.WithRowBoundaryRegex(regexp.MustCompile(`\d{4}-\d{2}-\d{2},`)),
+			expRecords: [][]string{
+				{"date", "note"},
+				{"2020-01-01", "see 2020-01-02"},
+				{"2020-01-03", "y"},
+			},
+			expTerminators: []string{"\n", "\n", ""},
+			expEmpty:       0,
+		},
+		{
+			name: "callback ending records at empty lines",
+			data: "\nid,v\n1,a\nb\n\n2,c",
+			opt: permissivecsv.WithRowBoundary(func(line []byte) bool {
+				return len(line) == 0 || line[0] >= '0' && line[0] <= '9'
+			}),
+			expRecords: [][]string{
+				{"id", "v"},
+				{"1", "a\nb"},
+				{"2", "c"},
+			},
+			expTerminators: []string{"\n", "\n", ""},
+			expEmpty:       2,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists, test.opt)
+			records := [][]string{}
+			terminators := []string{}
+			length := int64(0)
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+				terminators = append(terminators, s.CurrentRecordInfo().Terminator)
+				length += s.CurrentRecordInfo().ByteLength
+			}
+			summary := s.Summary()
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expTerminators, terminators)
+			assert.Equal(t, test.expEmpty, summary.EmptyRecordCount)
+			assert.Equal(t, int64(len(test.data)-test.expEmpty), length, "records cover the input")
+			assert.True(t, summary.EOF)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithRowBoundaryPanic(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a\nb\nc"), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithRowBoundary(func(line []byte) bool {
+			panic("boom")
+		}))
+	assert.False(t, s.Scan())
+	assert.True(t, s.Summary().CallbackFailed())
+	assert.False(t, s.Summary().ReadFailed())
+	assert.Equal(t, permissivecsv.ScannerStateErrored, s.State())
+}
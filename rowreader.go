@@ -0,0 +1,55 @@
+package permissivecsv
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// rowSource supplies the records of a non-CSV input, such as a worksheet. next
+// returns io.EOF once there are no more records. A record with no fields is
+// written as an empty record.
+type rowSource interface {
+	next() ([]string, error)
+}
+
+// rowReader is an io.Reader that produces the records of a rowSource as CSV,
+// converting them as the output is read, so that other formats can be fed
+// through a Scanner. If the source is an io.Closer, it is closed once it
+// returns an error (including io.EOF).
+type rowReader struct {
+	source  rowSource
+	encoder *recordEncoder
+	buffer  bytes.Buffer
+	writer  *bufio.Writer
+	err     error
+}
+
+func newRowReader(source rowSource) *rowReader {
+	encoder, _ := newRecordEncoder(Dialect{})
+	r := &rowReader{
+		source:  source,
+		encoder: encoder,
+	}
+	r.writer = bufio.NewWriter(&r.buffer)
+	return r
+}
+
+func (r *rowReader) Read(p []byte) (int, error) {
+	for r.buffer.Len() < len(p) && r.err == nil {
+		var record []string
+		record, r.err = r.source.next()
+		if r.err != nil {
+			if c, ok := r.source.(io.Closer); ok {
+				c.Close()
+			}
+			break
+		}
+		r.encoder.write(r.writer, record, nil)
+		r.writer.Flush()
+	}
+	if r.buffer.Len() > 0 || len(p) == 0 {
+		return r.buffer.Read(p)
+	}
+	return 0, r.err
+}
@@ -0,0 +1,67 @@
+package permissivecsv
+
+import "math/rand"
+
+// WithAlterationSampling limits the number of alterations of each kind that
+// are retained in the summary's Alterations to the number given by limits.
+// Only the earliest alterations of each kind are retained, so that a storm of
+// common alterations (such as padded records) cannot crowd out rare but
+// important ones (such as extraneous quotes) when combined with
+// WithMemoryBudget. Kinds that are not in limits are not limited.
+// AlterationCount and AlterationKindCounts remain exact, and alterations that
+// are not retained are counted by DroppedAlterationCount.
+func WithAlterationSampling(limits map[AlterationKind]int) Option {
+	return func(o *options) {
+		o.alterationSampling = make(map[AlterationKind]int, len(limits))
+		for kind, limit := range limits {
+			o.alterationSampling[kind] = limit
+		}
+	}
+}
+
+// sampleAlteration reports whether another alteration of kind can be retained
+// within the sampling limit for its kind.
+func (s *Scanner) sampleAlteration(kind AlterationKind) bool {
+	limit, ok := s.opts.alterationSampling[kind]
+	return !ok || s.retainedAlterations[kind] < limit
+}
+
+// WithSampleRate instructs Scan to return a random sample of records, each of
+// which is returned with probability p, which suits profiling very large
+// files for which a full scan is unnecessary. The sample is drawn from a
+// source seeded with seed, so the same input, options, and seed always
+// produce the same sample (including after Reset).
+//
+// Records that are not sampled are still read and processed, so the summary
+// reflects the entire input: they are included in RecordCount, any
+// alterations made to them are reported, and they are counted by
+// UnsampledRecordCount. The first record is always returned, so that the
+// header remains available, and records that are not returned by Scan do not
+// count toward the limit set by WithMaxRecords. Partition ignores the sample
+// rate, since segments must cover every record.
+//
+// A p of 1 or more returns every record (the default), and a p of 0 or less
+// returns only the first record.
+func WithSampleRate(p float64, seed int64) Option {
+	return func(o *options) {
+		o.sampleRate = p
+		o.sampleSeed = seed
+		o.sampling = p < 1
+	}
+}
+
+// skipUnsampled skips the current record if it was not drawn in the sample
+// (see WithSampleRate), and reports whether it did so.
+func (s *Scanner) skipUnsampled() bool {
+	if !s.opts.sampling || s.segmentHash != nil || s.recordsScanned <= 1 {
+		return false
+	}
+	if s.sampler == nil {
+		s.sampler = rand.New(rand.NewSource(s.opts.sampleSeed))
+	}
+	if s.sampler.Float64() < s.opts.sampleRate {
+		return false
+	}
+	s.scanSummary.UnsampledRecordCount++
+	return true
+}
@@ -0,0 +1,123 @@
+package permissivecsv_test
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithAlterationSampling(t *testing.T) {
+	data := "a,b\n" + strings.Repeat("1\n", 50) + "\"x\"y,z\n" + strings.Repeat("1,2,3\n", 10) + "c\"d,e"
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithAlterationSampling(map[permissivecsv.AlterationKind]int{
+			permissivecsv.AlterationPaddedRecord:    3,
+			permissivecsv.AlterationTruncatedRecord: 0,
+		}))
+	for s.Scan() {
+	}
+	summary := s.Summary()
+
+	assert.Equal(t, 62, summary.AlterationCount)
+	assert.Equal(t, map[permissivecsv.AlterationKind]int{
+		permissivecsv.AlterationPaddedRecord:    50,
+		permissivecsv.AlterationExtraneousQuote: 1,
+		permissivecsv.AlterationTruncatedRecord: 10,
+		permissivecsv.AlterationBareQuote:       1,
+	}, summary.AlterationKindCounts)
+	assert.Equal(t, 57, summary.DroppedAlterationCount)
+
+	kinds := []permissivecsv.AlterationKind{}
+	ordinals := []int{}
+	for _, alteration := range summary.Alterations {
+		kinds = append(kinds, alteration.Kind)
+		ordinals = append(ordinals, alteration.RecordOrdinal)
+	}
+	assert.Equal(t, []permissivecsv.AlterationKind{
+		permissivecsv.AlterationPaddedRecord,
+		permissivecsv.AlterationPaddedRecord,
+		permissivecsv.AlterationPaddedRecord,
+		permissivecsv.AlterationExtraneousQuote,
+		permissivecsv.AlterationBareQuote,
+	}, kinds)
+	assert.Equal(t, []int{2, 3, 4, 52, 63}, ordinals)
+}
+
+func Test_WithSampleRate(t *testing.T) {
+	data := "id,value\n"
+	for i := 1; i <= 1000; i++ {
+		data += strconv.Itoa(i) + ",x\n"
+	}
+	data += "1001\n"
+	scan := func(opts ...permissivecsv.Option) ([]string, *permissivecsv.ScanSummary) {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists, opts...)
+		ids := []string{}
+		for s.Scan() {
+			ids = append(ids, s.CurrentRecord()[0])
+		}
+		return ids, s.Summary()
+	}
+
+	t.Run("sample", func(t *testing.T) {
+		ids, summary := scan(permissivecsv.WithSampleRate(0.1, 42))
+		assert.Equal(t, "id", ids[0], "the header is always returned")
+		assert.InDelta(t, 100, len(ids)-1, 40)
+		assert.Equal(t, 1002, summary.RecordCount)
+		assert.Equal(t, 1002-len(ids), summary.UnsampledRecordCount)
+		assert.Equal(t, 1, summary.AlterationCount, "unsampled records are still processed")
+		assert.True(t, summary.EOF)
+	})
+
+	t.Run("reproducible", func(t *testing.T) {
+		a, _ := scan(permissivecsv.WithSampleRate(0.1, 42))
+		b, _ := scan(permissivecsv.WithSampleRate(0.1, 42))
+		c, _ := scan(permissivecsv.WithSampleRate(0.1, 7))
+		assert.Equal(t, a, b)
+		assert.NotEqual(t, a, c)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		r := strings.NewReader(data)
+		s := permissivecsv.NewScanner(r, permissivecsv.HeaderCheckAssumeHeaderExists, permissivecsv.WithSampleRate(0.1, 42))
+		first := []string{}
+		for s.Scan() {
+			first = append(first, s.CurrentRecord()[0])
+		}
+		r.Seek(0, io.SeekStart)
+		s.Reset()
+		second := []string{}
+		for s.Scan() {
+			second = append(second, s.CurrentRecord()[0])
+		}
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("rates", func(t *testing.T) {
+		ids, summary := scan(permissivecsv.WithSampleRate(1, 42))
+		assert.Len(t, ids, 1002)
+		assert.Equal(t, 0, summary.UnsampledRecordCount)
+		ids, summary = scan(permissivecsv.WithSampleRate(0, 42))
+		assert.Equal(t, []string{"id"}, ids)
+		assert.Equal(t, 1001, summary.UnsampledRecordCount)
+	})
+
+	t.Run("max records", func(t *testing.T) {
+		ids, _ := scan(permissivecsv.WithSampleRate(0.5, 42), permissivecsv.WithMaxRecords(10))
+		assert.Len(t, ids, 10)
+	})
+
+	t.Run("partition", func(t *testing.T) {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists,
+			permissivecsv.WithSampleRate(0.1, 42))
+		segments, err := s.Partition(100, true)
+		assert.NoError(t, err)
+		total := int64(0)
+		for _, segment := range segments {
+			total += segment.RecordCount
+		}
+		assert.Equal(t, int64(1001), total)
+	})
+}
@@ -0,0 +1,37 @@
+package permissivecsv
+
+import "io"
+
+// Terminator is a record terminator, as returned by ScanRaw. Its value is one
+// of TerminatorDOS, TerminatorInvertedDOS, TerminatorUnix, or
+// TerminatorCarriageReturn, or empty if the record is the last in the input
+// and is not terminated.
+type Terminator string
+
+// ScanRaw advances the Scanner to the next record, and returns the record's
+// bytes (without its terminator) and its terminator, without splitting the
+// record into fields. ScanRaw identifies the same records as Scan, so it
+// suits indexers and splitters that only need record boundaries, and would
+// otherwise pay for csv parsing that they do not use.
+//
+// Once there are no more records, ScanRaw returns io.EOF, or the error
+// returned by the underlaying reader. ScanRaw also returns io.EOF if scanning
+// stopped early because of a limit (see WithMaxRecords and WithDeadline), in
+// which case the Summary reports why scanning stopped.
+//
+// Since records are not parsed, the Summary does not report alterations or
+// field counts for records returned by ScanRaw, and CurrentRecord is not
+// meaningful. ScanRaw is not intended to be mixed with calls to Scan.
+func (s *Scanner) ScanRaw() ([]byte, Terminator, error) {
+	s.scanningRaw = true
+	defer func() {
+		s.scanningRaw = false
+	}()
+	if !s.Scan() {
+		if s.state == ScannerStateErrored {
+			return nil, "", s.scanSummary.Err
+		}
+		return nil, "", io.EOF
+	}
+	return []byte(s.currentRawFields), Terminator(s.currentTerminator), nil
+}
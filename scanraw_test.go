@@ -0,0 +1,113 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScanRaw(t *testing.T) {
+	tests := []struct {
+		name           string
+		reader         io.Reader
+		opts           []permissivecsv.Option
+		expRecords     []string
+		expTerminators []permissivecsv.Terminator
+		expErr         error
+	}{
+		{
+			name:   "mixed terminators",
+			reader: strings.NewReader("a,b\r\nc,\"d\ne\"\n\n\"f\nunterminated"),
+			expRecords: []string{
+				"a,b",
+				"c,\"d\ne\"",
+				"\"f\nunterminated",
+			},
+			expTerminators: []permissivecsv.Terminator{
+				permissivecsv.TerminatorDOS,
+				permissivecsv.TerminatorUnix,
+				"",
+			},
+			expErr: io.EOF,
+		},
+		{
+			name:   "keep empty records",
+			reader: strings.NewReader("a\n\nb\n"),
+			opts:   []permissivecsv.Option{permissivecsv.WithKeepEmptyRecords()},
+			expRecords: []string{
+				"a",
+				"",
+				"b",
+			},
+			expTerminators: []permissivecsv.Terminator{
+				permissivecsv.TerminatorUnix,
+				permissivecsv.TerminatorUnix,
+				permissivecsv.TerminatorUnix,
+			},
+			expErr: io.EOF,
+		},
+		{
+			name:           "record limit",
+			reader:         strings.NewReader("a\nb\nc"),
+			opts:           []permissivecsv.Option{permissivecsv.WithMaxRecords(1)},
+			expRecords:     []string{"a"},
+			expTerminators: []permissivecsv.Terminator{permissivecsv.TerminatorUnix},
+			expErr:         io.EOF,
+		},
+		{
+			name:           "reader error",
+			reader:         BadReader(strings.NewReader("a")),
+			expRecords:     []string{},
+			expTerminators: []permissivecsv.Terminator{},
+			expErr:         ErrReader,
+		},
+		{
+			name:           "nil reader",
+			reader:         nil,
+			expRecords:     []string{},
+			expTerminators: []permissivecsv.Terminator{},
+			expErr:         permissivecsv.ErrReaderIsNil,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(test.reader, permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			records := []string{}
+			terminators := []permissivecsv.Terminator{}
+			var err error
+			for {
+				var record []byte
+				var terminator permissivecsv.Terminator
+				record, terminator, err = s.ScanRaw()
+				if err != nil {
+					break
+				}
+				records = append(records, string(record))
+				terminators = append(terminators, terminator)
+			}
+			assert.True(t, errors.Is(err, test.expErr), "got %v", err)
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expTerminators, terminators)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Benchmark_ScanRaw(b *testing.B) {
+	data := strings.Repeat("alice,\"smith, jr\",30,raleigh\n", 10000)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+		for {
+			_, _, err := s.ScanRaw()
+			if err != nil {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package permissivecsv
+
+import "strings"
+
+// WithFieldsPerRecordFromHeaderNames supplies the names of the columns that the
+// file is expected to contain. The number of names is used as the expected
+// field count for every record (rather than the number of fields in the first
+// record), so records are padded or truncated to that width. Additionally, if
+// the first record is a header (see RecordIsHeader), it is compared to names,
+// and any differences are reported by the HeaderMismatch field of the
+// ScanSummary. Names are compared after both are normalized by
+// NormalizeColumnName, so "First Name" matches first_name.
+func WithFieldsPerRecordFromHeaderNames(names ...string) Option {
+	return func(o *options) {
+		o.expectedHeader = append([]string{}, names...)
+	}
+}
+
+// HeaderMismatch describes the differences between the header of a file and
+// the column names supplied to WithFieldsPerRecordFromHeaderNames.
+//
+// Missing contains the expected names that are not in the header, and Extra
+// contains the header's names that were not expected. Reordered contains the
+// expected names that are in the header, but not in the expected position
+// relative to the other names that are in both. Names in Missing and Reordered
+// are as supplied to WithFieldsPerRecordFromHeaderNames, and names in Extra are
+// as they appear in the header.
+type HeaderMismatch struct {
+	Missing   []string
+	Extra     []string
+	Reordered []string
+}
+
+// compareHeader compares header to expected, and returns a *HeaderMismatch
+// describing any differences, or nil if there are none.
+func compareHeader(header, expected []string) *HeaderMismatch {
+	headerPositions := make(map[string]int, len(header))
+	for i, name := range header {
+		key := NormalizeColumnName(name)
+		if _, exists := headerPositions[key]; !exists {
+			headerPositions[key] = i
+		}
+	}
+	expectedNames := make(map[string]bool, len(expected))
+	for _, name := range expected {
+		expectedNames[NormalizeColumnName(name)] = true
+	}
+
+	mismatch := &HeaderMismatch{}
+	common := []string{}
+	for _, name := range expected {
+		if _, ok := headerPositions[NormalizeColumnName(name)]; ok {
+			common = append(common, name)
+		} else {
+			mismatch.Missing = append(mismatch.Missing, name)
+		}
+	}
+	for _, name := range header {
+		if !expectedNames[NormalizeColumnName(name)] {
+			mismatch.Extra = append(mismatch.Extra, strings.TrimPrefix(name, byteOrderMark))
+		}
+	}
+
+	// Of the names in both, those that are in the header in the expected
+	// order are the longest increasing subsequence of their header positions.
+	// The remainder are reordered.
+	positions := make([]int, len(common))
+	for i, name := range common {
+		positions[i] = headerPositions[NormalizeColumnName(name)]
+	}
+	for i, inOrder := range longestIncreasingSubsequence(positions) {
+		if !inOrder {
+			mismatch.Reordered = append(mismatch.Reordered, common[i])
+		}
+	}
+
+	if mismatch.Missing == nil && mismatch.Extra == nil && mismatch.Reordered == nil {
+		return nil
+	}
+	return mismatch
+}
+
+// longestIncreasingSubsequence reports, for each element of values, whether it
+// is part of a longest strictly increasing subsequence of values.
+func longestIncreasingSubsequence(values []int) []bool {
+	lengths := make([]int, len(values))
+	previous := make([]int, len(values))
+	last := -1
+	for i := range values {
+		lengths[i] = 1
+		previous[i] = -1
+		for j := 0; j < i; j++ {
+			if values[j] < values[i] && lengths[j]+1 > lengths[i] {
+				lengths[i] = lengths[j] + 1
+				previous[i] = j
+			}
+		}
+		if last < 0 || lengths[i] > lengths[last] {
+			last = i
+		}
+	}
+	members := make([]bool, len(values))
+	for i := last; i >= 0; i = previous[i] {
+		members[i] = true
+	}
+	return members
+}
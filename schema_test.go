@@ -0,0 +1,89 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithFieldsPerRecordFromHeaderNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		headerCheck   permissivecsv.HeaderCheck
+		expRecords    [][]string
+		expMismatch   *permissivecsv.HeaderMismatch
+		expAlteration int
+	}{
+		{
+			name:        "matching header",
+			data:        "\ufeffID,First Name,city\n1,a,b",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expRecords: [][]string{
+				[]string{"\ufeffID", "First Name", "city"},
+				[]string{"1", "a", "b"},
+			},
+			expMismatch:   nil,
+			expAlteration: 0,
+		},
+		{
+			name:        "missing and extra columns",
+			data:        "id,first_name,state,zip\n1,a,NC,27601,x",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expRecords: [][]string{
+				[]string{"id", "first_name", "state"},
+				[]string{"1", "a", "NC"},
+			},
+			expMismatch: &permissivecsv.HeaderMismatch{
+				Missing: []string{"City"},
+				Extra:   []string{"state", "zip"},
+			},
+			expAlteration: 2,
+		},
+		{
+			name:        "reordered columns",
+			data:        "city,id,first_name\nb,1",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expRecords: [][]string{
+				[]string{"city", "id", "first_name"},
+				[]string{"b", "1", ""},
+			},
+			expMismatch: &permissivecsv.HeaderMismatch{
+				Reordered: []string{"City"},
+			},
+			expAlteration: 1,
+		},
+		{
+			name:        "no header",
+			data:        "1,a\n2,b,c,d",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expRecords: [][]string{
+				[]string{"1", "a", ""},
+				[]string{"2", "b", "c"},
+			},
+			expMismatch:   nil,
+			expAlteration: 2,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck,
+				permissivecsv.WithFieldsPerRecordFromHeaderNames("id", "First Name", "City"))
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, test.expRecords, records)
+			summary := s.Summary()
+			if diff := deep.Equal(test.expMismatch, summary.HeaderMismatch); diff != nil {
+				t.Error(diff)
+			}
+			assert.Equal(t, test.expAlteration, summary.AlterationCount)
+		}
+		t.Run(test.name, testFn)
+	}
+}
@@ -0,0 +1,187 @@
+package permissivecsv
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+	"runtime"
+	"sync"
+)
+
+var (
+	// ErrChecksumMismatch is returned by Segment.Verify if the bytes read do
+	// not match the segment's checksum.
+	ErrChecksumMismatch = fmt.Errorf("segment checksum mismatch")
+
+	// ErrRecordCountMismatch is reported by Segments.Validate if a segment
+	// does not contain the number of records recorded by Partition.
+	ErrRecordCountMismatch = fmt.Errorf("segment record count mismatch")
+
+	// ErrSegmentStartsMidRecord is reported by Segments.Validate if a segment
+	// does not begin at the start of a record.
+	ErrSegmentStartsMidRecord = fmt.Errorf("segment starts mid-record")
+)
+
+var segmentChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Verify reads the segment's bytes from r, and reports whether they match the
+// segment's checksum. r must be positioned at the segment's LowerOffset, as is
+// the case for a RangeReader, or a file after seeking to LowerOffset. Exactly
+// Length bytes are read from r.
+//
+// Verify returns ErrChecksumMismatch if the bytes do not match, which
+// indicates that the file changed between partitioning and processing. If r
+// contains fewer than Length bytes, io.ErrUnexpectedEOF is returned.
+func (seg *Segment) Verify(r io.Reader) error {
+	h := crc32.New(segmentChecksumTable)
+	_, err := io.CopyN(h, r, seg.Length)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	if h.Sum32() != seg.Checksum {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// HeaderSegment returns the byte range of the header that was excluded by the
+// most recent call to Partition, so that workers that need the header (to
+// build a column map, for instance) can fetch it without scanning from the
+// top of the file. The segment's Ordinal is 0, and its range begins at offset
+// 0 (including any terminators that precede the header), and ends where the
+// first partition begins.
+//
+// HeaderSegment returns nil if Partition has not been called, if
+// excludeHeader was false, or if no header was detected.
+func (s *Scanner) HeaderSegment() *Segment {
+	return s.headerSegment
+}
+
+// WithEmptySegments instructs Partition to place any bytes that follow the
+// last record (such as dangling terminators, or a run of empty records at the
+// end of the input) in a segment of their own, with a RecordCount of zero,
+// rather than in the last segment that contains records. This ensures that
+// every byte of the input is covered by a segment (including input that
+// contains no records), and that the segments that contain records are
+// unaffected by trailing terminators, which some producers add
+// inconsistently.
+func WithEmptySegments() Option {
+	return func(o *options) {
+		o.emptySegments = true
+	}
+}
+
+// Segments is a list of segments, as returned by Partition.
+type Segments []*Segment
+
+// SegmentCheck is the result of validating a Segment (see Segments.Validate).
+// RecordCount is the number of records that were scanned from the segment,
+// and Err reports every problem that was found with the segment, joined by
+// errors.Join, or is nil if the segment is valid.
+type SegmentCheck struct {
+	Segment     *Segment
+	RecordCount int64
+	Err         error
+}
+
+// SegmentReport is the result of validating segments (see Segments.Validate).
+// Checks holds the result for each segment, in the order in which the
+// segments were supplied.
+type SegmentReport struct {
+	Checks []*SegmentCheck
+}
+
+// OK reports whether every segment is valid.
+func (r *SegmentReport) OK() bool {
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate reads each segment from ra, and checks that its bytes match its
+// checksum (see Verify), that it begins at the start of a record, and that
+// scanning it returns RecordCount records. It is intended as a cheap safety
+// check before distributing segments, for instance to confirm that the file
+// did not change after it was partitioned, or that segments were not mangled
+// in transit (see MarshalSegments).
+//
+// Segments are validated concurrently, so ra must support concurrent calls to
+// ReadAt, as os.File does. A segment begins at the start of a record if it
+// begins at offset 0, or immediately after a terminator. Each segment is
+// scanned from the top with opts, which should be those that were supplied to
+// the Scanner that partitioned the input, since options such as
+// WithKeepEmptyRecords change the number of records that Scan returns.
+// However, each segment is scanned independently of the records that precede
+// it, so options that depend on the header (such as WithSkipRepeatedHeaders)
+// can cause the record counts of segments to differ.
+//
+// Problems with a segment are reported via its SegmentCheck, and match (see
+// errors.Is) ErrChecksumMismatch, ErrSegmentStartsMidRecord, or
+// ErrRecordCountMismatch, or are the error returned by ra.
+func (segments Segments) Validate(ra io.ReaderAt, opts ...Option) *SegmentReport {
+	report := &SegmentReport{
+		Checks: make([]*SegmentCheck, len(segments)),
+	}
+	workers := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, segment := range segments {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int, segment *Segment) {
+			defer wg.Done()
+			report.Checks[i] = validateSegment(ra, segment, opts)
+			<-workers
+		}(i, segment)
+	}
+	wg.Wait()
+	return report
+}
+
+// validateSegment validates a single segment (see Segments.Validate).
+func validateSegment(ra io.ReaderAt, segment *Segment, opts []Option) *SegmentCheck {
+	check := &SegmentCheck{Segment: segment}
+	err := segment.Verify(io.NewSectionReader(ra, segment.LowerOffset, segment.Length))
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	if segment.LowerOffset > 0 {
+		previous := make([]byte, 1)
+		_, err := ra.ReadAt(previous, segment.LowerOffset-1)
+		if err != nil {
+			check.Err = err
+			return check
+		}
+		if previous[0] != '\n' && previous[0] != '\r' {
+			check.Err = joinErrs(check.Err, ErrSegmentStartsMidRecord)
+		}
+	}
+	s := NewScanner(io.NewSectionReader(ra, segment.LowerOffset, segment.Length), HeaderCheckAssumeNoHeader, opts...)
+	for s.Scan() {
+		check.RecordCount++
+	}
+	check.Err = joinErrs(check.Err, s.Summary().Err)
+	if s.opts.keepEmptyRecords {
+		// Empty records at either end of the segment are ignored when it is
+		// scanned alone, but were kept when the input was partitioned, unless
+		// they are also at the corresponding end of the input.
+		if segment.LowerOffset > 0 {
+			check.RecordCount += int64(s.leadingEmptyRecords)
+		}
+		next := make([]byte, 1)
+		if n, _ := ra.ReadAt(next, segment.LowerOffset+segment.Length); n > 0 {
+			check.RecordCount += int64(s.danglingEmptyRecords)
+		}
+	}
+	if check.RecordCount != segment.RecordCount {
+		check.Err = joinErrs(check.Err, fmt.Errorf("%w: expected %d records, scanned %d",
+			ErrRecordCountMismatch, segment.RecordCount, check.RecordCount))
+	}
+	return check
+}
@@ -0,0 +1,226 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"hash/crc32"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SegmentVerify(t *testing.T) {
+	data := "h\na,b\nc,d\ne,f"
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists)
+	segments, err := s.Partition(2, true)
+	assert.NoError(t, err)
+	assert.Len(t, segments, 2)
+	segment := segments[0]
+
+	tests := []struct {
+		name   string
+		data   string
+		expErr error
+	}{
+		{
+			name:   "unchanged",
+			data:   data,
+			expErr: nil,
+		},
+		{
+			name:   "changed",
+			data:   "h\na,b\nc,x\ne,f",
+			expErr: permissivecsv.ErrChecksumMismatch,
+		},
+		{
+			name:   "truncated",
+			data:   "h\na,b",
+			expErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			r := strings.NewReader(test.data)
+			r.Seek(segment.LowerOffset, io.SeekStart)
+			err := segment.Verify(r)
+			assert.Equal(t, test.expErr, err)
+		}
+		t.Run(test.name, testFn)
+	}
+
+	r := strings.NewReader(data)
+	r.Seek(segments[1].LowerOffset, io.SeekStart)
+	assert.NoError(t, segments[1].Verify(r))
+	assert.Equal(t, ErrReader, segment.Verify(BadReader(nil)))
+}
+
+func Test_HeaderSegment(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		headerCheck   permissivecsv.HeaderCheck
+		excludeHeader bool
+		expHeader     string
+	}{
+		{
+			name:          "header excluded",
+			data:          "h1,h2\r\na,b\r\nc,d",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			excludeHeader: true,
+			expHeader:     "h1,h2\r\n",
+		},
+		{
+			name:          "leading terminators",
+			data:          "\n\nh1,h2\n\na,b",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			excludeHeader: true,
+			expHeader:     "\n\nh1,h2\n",
+		},
+		{
+			name:          "header only",
+			data:          "h1,h2",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			excludeHeader: true,
+			expHeader:     "h1,h2",
+		},
+		{
+			name:          "header not excluded",
+			data:          "h1,h2\na,b",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			excludeHeader: false,
+		},
+		{
+			name:          "no header",
+			data:          "a,b\nc,d",
+			headerCheck:   permissivecsv.HeaderCheckAssumeNoHeader,
+			excludeHeader: true,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck)
+			assert.Nil(t, s.HeaderSegment())
+			segments, err := s.Partition(1, test.excludeHeader)
+			assert.NoError(t, err)
+			header := s.HeaderSegment()
+			if test.expHeader == "" {
+				assert.Nil(t, header)
+				return
+			}
+			if !assert.NotNil(t, header) {
+				return
+			}
+			assert.Equal(t, int64(0), header.Ordinal)
+			assert.Equal(t, test.expHeader, test.data[header.LowerOffset:header.LowerOffset+header.Length])
+			assert.NoError(t, header.Verify(strings.NewReader(test.data)))
+			if len(segments) > 0 {
+				assert.Equal(t, header.Length, segments[0].LowerOffset)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_SegmentsValidate(t *testing.T) {
+	data := "h\na,b\n\nc,d\ne,f\ng,h\ni,j\n"
+	partition := func() permissivecsv.Segments {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists)
+		segments, err := s.Partition(2, true)
+		assert.NoError(t, err)
+		return segments
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		alter   func(segments permissivecsv.Segments)
+		opts    []permissivecsv.Option
+		expErrs []error
+	}{
+		{
+			name:    "valid",
+			data:    data,
+			expErrs: []error{nil, nil, nil},
+		},
+		{
+			name:    "file changed",
+			data:    "h\na,b\n\nc,d\ne,F\ng,h\ni,j\n",
+			expErrs: []error{nil, permissivecsv.ErrChecksumMismatch, nil},
+		},
+		{
+			name: "record count",
+			data: data,
+			alter: func(segments permissivecsv.Segments) {
+				segments[2].RecordCount = 3
+			},
+			expErrs: []error{nil, nil, permissivecsv.ErrRecordCountMismatch},
+		},
+		{
+			name:    "options change record count",
+			data:    data,
+			opts:    []permissivecsv.Option{permissivecsv.WithKeepEmptyRecords()},
+			expErrs: []error{permissivecsv.ErrRecordCountMismatch, nil, nil},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			segments := partition()
+			if test.alter != nil {
+				test.alter(segments)
+			}
+			report := segments.Validate(strings.NewReader(test.data), test.opts...)
+			if !assert.Len(t, report.Checks, len(test.expErrs)) {
+				return
+			}
+			ok := true
+			for i, check := range report.Checks {
+				assert.Equal(t, segments[i], check.Segment)
+				if test.expErrs[i] == nil {
+					assert.NoError(t, check.Err)
+					assert.Equal(t, segments[i].RecordCount, check.RecordCount)
+					continue
+				}
+				ok = false
+				assert.True(t, errors.Is(check.Err, test.expErrs[i]), "segment %d: %v", i, check.Err)
+			}
+			assert.Equal(t, ok, report.OK())
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_SegmentsValidateMidRecord(t *testing.T) {
+	data := "a,b\nc,d\ne,f\n"
+	segments := permissivecsv.Segments{
+		{Ordinal: 1, LowerOffset: 0, Length: 6, RecordCount: 2},
+		{Ordinal: 2, LowerOffset: 6, Length: 6, RecordCount: 2},
+	}
+	for _, segment := range segments {
+		b := data[segment.LowerOffset : segment.LowerOffset+segment.Length]
+		segment.Checksum = crc32.Checksum([]byte(b), crc32.MakeTable(crc32.Castagnoli))
+	}
+	report := segments.Validate(strings.NewReader(data))
+	assert.False(t, report.OK())
+	assert.True(t, errors.Is(report.Checks[1].Err, permissivecsv.ErrSegmentStartsMidRecord))
+	assert.False(t, errors.Is(report.Checks[1].Err, permissivecsv.ErrRecordCountMismatch))
+}
+
+func Test_SegmentsValidateKeptEmptyRecords(t *testing.T) {
+	for _, data := range []string{"a\n\nb\n", "\na\n\n\nb\n\n", "a\n\n"} {
+		for _, n := range []int{1, 2} {
+			s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader,
+				permissivecsv.WithKeepEmptyRecords())
+			segments, err := s.Partition(n, false)
+			assert.NoError(t, err)
+			report := permissivecsv.Segments(segments).Validate(strings.NewReader(data), permissivecsv.WithKeepEmptyRecords())
+			for _, check := range report.Checks {
+				assert.NoError(t, check.Err, "%q, %d records per segment", data, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package permissivecsv
+
+import "sync"
+
+// Snapshot returns a copy of the summary that does not share any of its
+// mutable state, so it can be read while scanning continues. Unlike the
+// summary itself, which is updated by each call to Scan, Snapshot may be
+// called from any goroutine, including while another goroutine is calling
+// Scan (for instance, to report progress from a status endpoint). The summary
+// must first be obtained from the scanning goroutine (see Summary) and handed
+// to the goroutine that calls Snapshot.
+//
+// While Scan is blocked reading from the underlaying reader, or is calling a
+// function supplied to the Scanner, Snapshot does not wait for it to return.
+// A snapshot may therefore reflect part of a record that has yet to be
+// returned by Scan, such as its RecordCount, but never a partially updated
+// field of the summary.
+func (s *ScanSummary) Snapshot() *ScanSummary {
+	if s == nil {
+		return nil
+	}
+	if s.mu != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	return s.clone()
+}
+
+// summaryLock returns the mutex that guards the Scanner's summary against
+// concurrent calls to Snapshot.
+func (s *Scanner) summaryLock() *sync.Mutex {
+	if s.summaryMu == nil {
+		s.summaryMu = &sync.Mutex{}
+	}
+	return s.summaryMu
+}
+
+// holdSummary locks the summary (see Snapshot) until the returned function is
+// called, unless it is already held.
+func (s *Scanner) holdSummary() func() {
+	if s.summaryHeld {
+		return func() {}
+	}
+	mu := s.summaryLock()
+	mu.Lock()
+	s.summaryHeld = true
+	return func() {
+		s.summaryHeld = false
+		mu.Unlock()
+	}
+}
+
+// releaseSummary unlocks the summary (if it is held) until the returned
+// function is called, so that Snapshot does not wait for operations that may
+// block, such as reading from the underlaying reader, or calling a function
+// supplied to the Scanner.
+func (s *Scanner) releaseSummary() func() {
+	if !s.summaryHeld {
+		return func() {}
+	}
+	mu := s.summaryLock()
+	s.summaryHeld = false
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		s.summaryHeld = true
+	}
+}
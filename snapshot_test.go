@@ -0,0 +1,79 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Snapshot(t *testing.T) {
+	t.Run("not modified by subsequent scans", func(t *testing.T) {
+		s := permissivecsv.NewScanner(strings.NewReader("a,b\nc\nd\n"), permissivecsv.HeaderCheckAssumeNoHeader)
+		s.Scan()
+		s.Scan()
+		snapshot := s.Summary().Snapshot()
+		for s.Scan() {
+		}
+		assert.Equal(t, 2, snapshot.RecordCount)
+		assert.Equal(t, 1, snapshot.AlterationCount)
+		assert.Len(t, snapshot.Alterations, 1)
+		assert.Len(t, snapshot.FieldCountRuns, 2)
+		assert.Equal(t, 2, snapshot.FieldCountRuns[1].LastRecordOrdinal)
+		assert.False(t, snapshot.EOF)
+		assert.Equal(t, 3, s.Summary().RecordCount)
+		assert.Len(t, s.Summary().Alterations, 2)
+		assert.Equal(t, 3, s.Summary().FieldCountRuns[1].LastRecordOrdinal)
+		assert.True(t, s.Summary().EOF)
+	})
+
+	t.Run("concurrent with scan", func(t *testing.T) {
+		data := strings.Repeat("a,b\nc\nd,e,f\n", 1000)
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+		s.Scan()
+		summary := s.Summary()
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := 0
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				snapshot := summary.Snapshot()
+				assert.True(t, snapshot.RecordCount >= last)
+				assert.True(t, len(snapshot.Alterations) <= snapshot.AlterationCount)
+				last = snapshot.RecordCount
+			}
+		}()
+		for s.Scan() {
+		}
+		close(done)
+		wg.Wait()
+		assert.Equal(t, 3000, summary.Snapshot().RecordCount)
+	})
+
+	t.Run("within a callback", func(t *testing.T) {
+		var snapshots []*permissivecsv.ScanSummary
+		s := permissivecsv.NewScanner(strings.NewReader("a,b\nc,d\ne,f\n"), permissivecsv.HeaderCheckAssumeNoHeader,
+			permissivecsv.WithCheckpointEvery(1, func(cp permissivecsv.Checkpoint, summary *permissivecsv.ScanSummary) {
+				snapshots = append(snapshots, summary.Snapshot())
+			}))
+		for s.Scan() {
+		}
+		if assert.NotEmpty(t, snapshots) {
+			assert.Equal(t, 3, snapshots[len(snapshots)-1].RecordCount)
+		}
+	})
+
+	t.Run("nil summary", func(t *testing.T) {
+		var summary *permissivecsv.ScanSummary
+		assert.Nil(t, summary.Snapshot())
+	})
+}
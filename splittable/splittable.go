@@ -0,0 +1,277 @@
+// Package splittable adapts the Segments produced by permissivecsv's Partition
+// method to the splittable source model used by Apache Beam's splittable DoFns
+// and similar data processing frameworks.
+//
+// A Restriction is a range of segment indexes. A Source creates the initial
+// restriction for a file, splits restrictions into smaller restrictions that
+// can be processed in parallel, and processes a restriction by scanning the
+// segments it claims from a Tracker. Tracker has the same method set as the
+// Beam Go SDK's sdf.RTracker, so it can be returned directly from a DoFn's
+// CreateTracker method without this package depending on Beam:
+//
+//	func (fn *csvFn) CreateInitialRestriction(path string) splittable.Restriction {
+//	    return fn.source.InitialRestriction()
+//	}
+//
+//	func (fn *csvFn) CreateTracker(r splittable.Restriction) *sdf.LockRTracker {
+//	    return sdf.NewLockRTracker(splittable.NewTracker(r))
+//	}
+//
+//	func (fn *csvFn) ProcessElement(ctx context.Context, rt *sdf.LockRTracker, path string, emit func([]string)) error {
+//	    return fn.source.Process(ctx, rt, func(record []string) error {
+//	        emit(record)
+//	        return nil
+//	    })
+//	}
+package splittable
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/eltorocorp/permissivecsv"
+)
+
+// Restriction is a half-open range [Start, End) of indexes into the segments
+// of a Source.
+type Restriction struct {
+	Start int64
+	End   int64
+}
+
+// Size returns the number of segments in the restriction.
+func (r Restriction) Size() int64 {
+	if r.End < r.Start {
+		return 0
+	}
+	return r.End - r.Start
+}
+
+// Source scans the segments of a partitioned file (see
+// permissivecsv.Scanner.Partition), fetching each segment's bytes from a
+// RangeReader. A Source holds no state while processing, so it can be shared
+// by every worker that processes the file.
+type Source struct {
+	segments []*permissivecsv.Segment
+	rr       permissivecsv.RangeReader
+	opts     []permissivecsv.Option
+}
+
+// NewSource returns a Source for segments, whose bytes are read from rr.
+// Each segment is scanned by a Scanner that assumes there is no header (the
+// header should be excluded when partitioning), with opts applied.
+func NewSource(segments []*permissivecsv.Segment, rr permissivecsv.RangeReader, opts ...permissivecsv.Option) *Source {
+	return &Source{
+		segments: segments,
+		rr:       rr,
+		opts:     opts,
+	}
+}
+
+// InitialRestriction returns the restriction that covers every segment.
+func (s *Source) InitialRestriction() Restriction {
+	return Restriction{Start: 0, End: int64(len(s.segments))}
+}
+
+// SplitRestriction divides r into at most n contiguous restrictions of (as
+// nearly as possible) equal numbers of segments. r is returned unchanged if n
+// is less than 2.
+func (s *Source) SplitRestriction(r Restriction, n int) []Restriction {
+	size := r.Size()
+	if n < 2 || size < 2 {
+		return []Restriction{r}
+	}
+	if int64(n) > size {
+		n = int(size)
+	}
+	restrictions := make([]Restriction, 0, n)
+	start := r.Start
+	for i := 0; i < n; i++ {
+		end := r.Start + size*int64(i+1)/int64(n)
+		restrictions = append(restrictions, Restriction{Start: start, End: end})
+		start = end
+	}
+	return restrictions
+}
+
+// RestrictionSize returns the number of bytes in the segments covered by r,
+// which frameworks use to balance work between workers.
+func (s *Source) RestrictionSize(r Restriction) float64 {
+	var size int64
+	for i := r.Start; i < r.End && i < int64(len(s.segments)); i++ {
+		if i >= 0 {
+			size += s.segments[i].Length
+		}
+	}
+	return float64(size)
+}
+
+// Claimer is implemented by Tracker, and by the trackers of frameworks that
+// wrap it (such as the Beam Go SDK's sdf.LockRTracker).
+type Claimer interface {
+	TryClaim(position interface{}) bool
+	GetRestriction() interface{}
+}
+
+// Process scans each segment that it successfully claims from tracker,
+// starting with the first segment of the tracker's restriction, and passes
+// each record to emit. Processing stops when a claim fails (because the
+// restriction is exhausted, or has been split), or if emit returns an error,
+// reading a segment fails, or ctx is canceled, in which case the error is
+// returned.
+func (s *Source) Process(ctx context.Context, tracker Claimer, emit func(record []string) error) error {
+	r, ok := tracker.GetRestriction().(Restriction)
+	if !ok {
+		return fmt.Errorf("splittable: unexpected restriction type %T", tracker.GetRestriction())
+	}
+	for i := r.Start; tracker.TryClaim(i); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if i < 0 || i >= int64(len(s.segments)) {
+			return fmt.Errorf("splittable: segment %d is out of range", i)
+		}
+		if err := s.processSegment(ctx, s.segments[i], emit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processSegment scans a single segment, passing each record to emit.
+func (s *Source) processSegment(ctx context.Context, segment *permissivecsv.Segment, emit func(record []string) error) error {
+	body, err := segment.Open(ctx, s.rr)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	scanner := permissivecsv.NewScanner(body, permissivecsv.HeaderCheckAssumeNoHeader, s.opts...)
+	for scanner.Scan() {
+		if err := emit(scanner.CurrentRecord()); err != nil {
+			return err
+		}
+	}
+	return scanner.Summary().Err
+}
+
+// Tracker tracks the segments of a Restriction that have been claimed for
+// processing. Tracker is safe for concurrent use, and its methods match those
+// of the Beam Go SDK's sdf.RTracker.
+type Tracker struct {
+	mu        sync.Mutex
+	rest      Restriction
+	claimed   int64
+	attempted int64
+	stopped   bool
+	err       error
+}
+
+// NewTracker returns a Tracker for r.
+func NewTracker(r Restriction) *Tracker {
+	return &Tracker{
+		rest:      r,
+		claimed:   r.Start - 1,
+		attempted: r.Start - 1,
+	}
+}
+
+// TryClaim attempts to claim the segment at position (an int64), and reports
+// whether it may be processed. Positions must be claimed in increasing order.
+// Once a claim fails, the restriction is done, and no further claims succeed.
+func (t *Tracker) TryClaim(position interface{}) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || t.err != nil {
+		return false
+	}
+	i, ok := position.(int64)
+	if !ok {
+		t.err = fmt.Errorf("splittable: position must be an int64, got %T", position)
+		return false
+	}
+	if i <= t.attempted {
+		t.err = fmt.Errorf("splittable: position %d claimed after position %d", i, t.attempted)
+		return false
+	}
+	t.attempted = i
+	if i < t.rest.Start || i >= t.rest.End {
+		t.stopped = true
+		return false
+	}
+	t.claimed = i
+	return true
+}
+
+// GetError returns the error (if any) that caused a claim to fail.
+func (t *Tracker) GetError() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// TrySplit splits the unclaimed part of the restriction, keeping (roughly)
+// fraction of the unclaimed segments in the primary restriction, and returning
+// the rest as the residual, which can be processed by another worker. A
+// fraction of 0 returns every unclaimed segment as the residual (a
+// checkpoint). The residual is nil if the remaining segments cannot be split.
+func (t *Tracker) TrySplit(fraction float64) (primary, residual interface{}, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || t.err != nil || fraction < 0 || fraction >= 1 {
+		return t.rest, nil, nil
+	}
+	next := t.attempted + 1
+	if next < t.rest.Start {
+		next = t.rest.Start
+	}
+	unclaimed := t.rest.End - next
+	if unclaimed <= 0 {
+		return t.rest, nil, nil
+	}
+	split := next + int64(math.Ceil(fraction*float64(unclaimed)))
+	if split >= t.rest.End {
+		return t.rest, nil, nil
+	}
+	residualRestriction := Restriction{Start: split, End: t.rest.End}
+	t.rest.End = split
+	return t.rest, residualRestriction, nil
+}
+
+// GetProgress returns the number of segments that have been claimed, and the
+// number that remain.
+func (t *Tracker) GetProgress() (done, remaining float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	done = float64(t.claimed - t.rest.Start + 1)
+	if done < 0 {
+		done = 0
+	}
+	remaining = float64(t.rest.End - t.claimed - 1)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return done, remaining
+}
+
+// IsDone reports whether every segment of the restriction has been claimed
+// (or a claim beyond the restriction has failed).
+func (t *Tracker) IsDone() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err == nil && (t.stopped || t.claimed >= t.rest.End-1)
+}
+
+// GetRestriction returns the tracker's current Restriction, which may have been
+// narrowed by TrySplit.
+func (t *Tracker) GetRestriction() interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rest
+}
+
+// IsBounded reports true, since the segments of a file are bounded.
+func (t *Tracker) IsBounded() bool {
+	return true
+}
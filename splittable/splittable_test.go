@@ -0,0 +1,186 @@
+package splittable_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/eltorocorp/permissivecsv/splittable"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSource partitions a file of n records (after the header) into segments
+// of two records each.
+func newSource(n int) (*splittable.Source, [][]string) {
+	var b strings.Builder
+	b.WriteString("id,name\r\n")
+	expRecords := [][]string{}
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%d,name %d\n", i, i)
+		expRecords = append(expRecords, []string{fmt.Sprint(i), fmt.Sprintf("name %d", i)})
+	}
+	data := strings.NewReader(b.String())
+	segments, err := permissivecsv.NewScanner(data, permissivecsv.HeaderCheckAssumeHeaderExists).Partition(2, true)
+	if err != nil {
+		panic(err)
+	}
+	return splittable.NewSource(segments, permissivecsv.ReaderAtRangeReader(data)), expRecords
+}
+
+func process(t *testing.T, source *splittable.Source, tracker splittable.Claimer) [][]string {
+	records := [][]string{}
+	err := source.Process(context.Background(), tracker, func(record []string) error {
+		records = append(records, record)
+		return nil
+	})
+	assert.NoError(t, err)
+	return records
+}
+
+func Test_SplitRestriction(t *testing.T) {
+	source, expRecords := newSource(9)
+	initial := source.InitialRestriction()
+	assert.Equal(t, splittable.Restriction{Start: 0, End: 5}, initial)
+	assert.Equal(t, float64(len("1,name 1\n")*9), source.RestrictionSize(initial))
+
+	tests := []struct {
+		name            string
+		n               int
+		expRestrictions []splittable.Restriction
+	}{
+		{
+			name:            "no split",
+			n:               1,
+			expRestrictions: []splittable.Restriction{initial},
+		},
+		{
+			name: "uneven split",
+			n:    2,
+			expRestrictions: []splittable.Restriction{
+				splittable.Restriction{Start: 0, End: 2},
+				splittable.Restriction{Start: 2, End: 5},
+			},
+		},
+		{
+			name: "more splits than segments",
+			n:    10,
+			expRestrictions: []splittable.Restriction{
+				splittable.Restriction{Start: 0, End: 1},
+				splittable.Restriction{Start: 1, End: 2},
+				splittable.Restriction{Start: 2, End: 3},
+				splittable.Restriction{Start: 3, End: 4},
+				splittable.Restriction{Start: 4, End: 5},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			restrictions := source.SplitRestriction(initial, test.n)
+			assert.Equal(t, test.expRestrictions, restrictions)
+			records := [][]string{}
+			for _, r := range restrictions {
+				records = append(records, process(t, source, splittable.NewTracker(r))...)
+			}
+			assert.Equal(t, expRecords, records)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_TrackerTrySplit(t *testing.T) {
+	source, expRecords := newSource(10)
+	tracker := splittable.NewTracker(source.InitialRestriction())
+
+	var residual interface{}
+	records := [][]string{}
+	err := source.Process(context.Background(), tracker, func(record []string) error {
+		if residual == nil {
+			// Split while the first segment is being processed.
+			var primary interface{}
+			var err error
+			primary, residual, err = tracker.TrySplit(0.5)
+			assert.NoError(t, err)
+			assert.Equal(t, splittable.Restriction{Start: 0, End: 3}, primary)
+			assert.Equal(t, splittable.Restriction{Start: 3, End: 5}, residual)
+		}
+		records = append(records, record)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, tracker.IsDone())
+	assert.NoError(t, tracker.GetError())
+
+	residualRestriction := residual.(splittable.Restriction)
+	records = append(records, process(t, source, splittable.NewTracker(residualRestriction))...)
+	assert.Equal(t, expRecords, records)
+}
+
+func Test_Tracker(t *testing.T) {
+	tracker := splittable.NewTracker(splittable.Restriction{Start: 2, End: 4})
+	assert.True(t, tracker.IsBounded())
+	assert.False(t, tracker.IsDone())
+	done, remaining := tracker.GetProgress()
+	assert.Equal(t, 0.0, done)
+	assert.Equal(t, 2.0, remaining)
+
+	assert.True(t, tracker.TryClaim(int64(2)))
+	done, remaining = tracker.GetProgress()
+	assert.Equal(t, 1.0, done)
+	assert.Equal(t, 1.0, remaining)
+
+	_, residual, err := tracker.TrySplit(0.5)
+	assert.NoError(t, err)
+	assert.Nil(t, residual, "a single remaining segment cannot be split")
+	primary, residual, err := tracker.TrySplit(0)
+	assert.NoError(t, err)
+	assert.Equal(t, splittable.Restriction{Start: 2, End: 3}, primary)
+	assert.Equal(t, splittable.Restriction{Start: 3, End: 4}, residual, "checkpoint")
+	assert.True(t, tracker.IsDone())
+	assert.False(t, tracker.TryClaim(int64(3)))
+	assert.NoError(t, tracker.GetError())
+
+	tracker = splittable.NewTracker(splittable.Restriction{Start: 2, End: 4})
+	assert.True(t, tracker.TryClaim(int64(2)))
+
+	assert.True(t, tracker.TryClaim(int64(3)))
+	assert.True(t, tracker.IsDone())
+	assert.False(t, tracker.TryClaim(int64(4)))
+	assert.NoError(t, tracker.GetError())
+
+	tracker = splittable.NewTracker(splittable.Restriction{Start: 0, End: 4})
+	assert.True(t, tracker.TryClaim(int64(1)))
+	assert.False(t, tracker.TryClaim(int64(1)))
+	assert.Error(t, tracker.GetError())
+	assert.False(t, tracker.IsDone())
+
+	tracker = splittable.NewTracker(splittable.Restriction{Start: 0, End: 4})
+	assert.False(t, tracker.TryClaim(1))
+	assert.Error(t, tracker.GetError())
+
+	assert.True(t, splittable.NewTracker(splittable.Restriction{Start: 3, End: 3}).IsDone())
+}
+
+func Test_ProcessErrors(t *testing.T) {
+	source, _ := newSource(4)
+
+	errEmit := fmt.Errorf("emit failed")
+	err := source.Process(context.Background(), splittable.NewTracker(source.InitialRestriction()), func(record []string) error {
+		return errEmit
+	})
+	assert.Equal(t, errEmit, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = source.Process(ctx, splittable.NewTracker(source.InitialRestriction()), func(record []string) error {
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+
+	err = source.Process(context.Background(), splittable.NewTracker(splittable.Restriction{Start: 5, End: 6}), func(record []string) error {
+		return nil
+	})
+	assert.Error(t, err)
+}
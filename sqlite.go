@@ -0,0 +1,276 @@
+package permissivecsv
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// SQLiteOption configures the behavior of LoadIntoSQLite and LoadIntoDB.
+type SQLiteOption func(*sqliteOptions)
+
+type sqliteOptions struct {
+	driver     string
+	batchSize  int
+	sampleSize int
+}
+
+// WithSQLiteDriver sets the name of the database/sql driver that
+// LoadIntoSQLite uses to open the database. permissivecsv does not include a
+// SQLite driver, so one must be imported by the caller, such as
+// github.com/mattn/go-sqlite3 (which registers "sqlite3", the default) or
+// modernc.org/sqlite (which registers "sqlite").
+func WithSQLiteDriver(name string) SQLiteOption {
+	return func(o *sqliteOptions) {
+		o.driver = name
+	}
+}
+
+// WithSQLiteBatchSize sets the number of records inserted in each transaction.
+// The default is 1000. A value of zero or less leaves the default in place.
+func WithSQLiteBatchSize(n int) SQLiteOption {
+	return func(o *sqliteOptions) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithSQLiteSampleSize sets the number of records that are examined to infer
+// the type of each column. The default is 100. A value of zero or less leaves
+// the default in place.
+func WithSQLiteSampleSize(n int) SQLiteOption {
+	return func(o *sqliteOptions) {
+		if n > 0 {
+			o.sampleSize = n
+		}
+	}
+}
+
+// LoadIntoSQLite opens the SQLite database at path (see WithSQLiteDriver), and
+// loads the remainder of the input into table, as by LoadIntoDB. This is an
+// easy way to analyze a messy file locally with SQL.
+func (s *Scanner) LoadIntoSQLite(path, table string, opts ...SQLiteOption) (*ScanSummary, error) {
+	o := newSQLiteOptions(opts)
+	db, err := sql.Open(o.driver, path)
+	if err != nil {
+		return s.Summary(), err
+	}
+	summary, err := s.LoadIntoDB(context.Background(), db, table, opts...)
+	closeErr := db.Close()
+	if err == nil {
+		err = closeErr
+	}
+	return summary, err
+}
+
+// LoadIntoDB scans the remainder of the input, and inserts each (possibly
+// altered) record into table, which is created if it does not exist. Records
+// are inserted in transactions of WithSQLiteBatchSize records.
+//
+// Column names are taken from the header (normalized with
+// NormalizeColumnNames), or are column_1, column_2, and so on if there is no
+// header (see RecordIsHeader). The type of each column is inferred from the
+// first WithSQLiteSampleSize records: INTEGER if every value is an integer,
+// REAL if every value is a number, and TEXT otherwise. Fields that were
+// missing from the input (see FieldMissing) are inserted as NULL, as are empty
+// fields of INTEGER and REAL columns.
+//
+// Statements are written for SQLite, though any database that accepts double
+// quoted identifiers and ? placeholders can be used. If a statement fails,
+// LoadIntoDB rolls back the current transaction, stops scanning, and returns
+// the error. Otherwise, LoadIntoDB returns any error returned by the
+// underlaying reader (which is also available via the summary).
+func (s *Scanner) LoadIntoDB(ctx context.Context, db *sql.DB, table string, opts ...SQLiteOption) (*ScanSummary, error) {
+	o := newSQLiteOptions(opts)
+
+	var (
+		header  []string
+		sample  [][]string
+		samples [][]FieldState
+	)
+	for len(sample) < o.sampleSize && s.Scan() {
+		if s.recordsScanned == 1 && s.RecordIsHeader() {
+			header = s.CurrentRecord()
+			continue
+		}
+		sample = append(sample, append([]string{}, s.currentRecord...))
+		samples = append(samples, s.CurrentFieldStates())
+	}
+	if header == nil && len(sample) == 0 {
+		summary := s.Summary()
+		return summary, summary.Err
+	}
+
+	columnCount := s.expectedFieldCount
+	if header != nil {
+		columnCount = len(header)
+	}
+	names := make([]string, columnCount)
+	for i := range names {
+		if i < len(header) {
+			names[i] = header[i]
+		}
+	}
+	names = NormalizeColumnNames(names)
+	types := inferSQLTypes(columnCount, sample, samples)
+
+	_, err := db.ExecContext(ctx, createTableStatement(table, names, types))
+	if err != nil {
+		return s.Summary(), err
+	}
+
+	loader := &sqlLoader{
+		db:     db,
+		insert: insertStatement(table, names),
+		types:  types,
+		size:   o.batchSize,
+	}
+	for i := range sample {
+		if err := loader.add(ctx, sample[i], samples[i]); err != nil {
+			return s.Summary(), err
+		}
+	}
+	for s.Scan() {
+		if err := loader.add(ctx, s.currentRecord, s.CurrentFieldStates()); err != nil {
+			return s.Summary(), err
+		}
+	}
+	if err := loader.commit(); err != nil {
+		return s.Summary(), err
+	}
+	summary := s.Summary()
+	return summary, summary.Err
+}
+
+func newSQLiteOptions(opts []SQLiteOption) sqliteOptions {
+	o := sqliteOptions{
+		driver:     "sqlite3",
+		batchSize:  1000,
+		sampleSize: 100,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// SQL column types inferred by LoadIntoDB.
+const (
+	sqlInteger = "INTEGER"
+	sqlReal    = "REAL"
+	sqlText    = "TEXT"
+)
+
+// inferSQLTypes returns the narrowest type that can hold every value of each
+// of n columns of records. Columns with no values are TEXT.
+func inferSQLTypes(n int, records [][]string, states [][]FieldState) []string {
+	types := make([]string, n)
+	for i := range types {
+		types[i] = sqlInteger
+		seen := false
+		for j, record := range records {
+			if i >= len(record) || states[j][i] == FieldMissing || record[i] == "" {
+				continue
+			}
+			seen = true
+			value := strings.TrimSpace(record[i])
+			if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+				continue
+			}
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				types[i] = sqlReal
+				continue
+			}
+			types[i] = sqlText
+			break
+		}
+		if !seen {
+			types[i] = sqlText
+		}
+	}
+	return types
+}
+
+// quoteIdentifier quotes name for use as an SQL identifier.
+func quoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+func createTableStatement(table string, names, types []string) string {
+	columns := make([]string, len(names))
+	for i, name := range names {
+		columns[i] = quoteIdentifier(name) + " " + types[i]
+	}
+	return "CREATE TABLE IF NOT EXISTS " + quoteIdentifier(table) + " (" + strings.Join(columns, ", ") + ")"
+}
+
+func insertStatement(table string, names []string) string {
+	columns := make([]string, len(names))
+	placeholders := make([]string, len(names))
+	for i, name := range names {
+		columns[i] = quoteIdentifier(name)
+		placeholders[i] = "?"
+	}
+	return "INSERT INTO " + quoteIdentifier(table) + " (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+}
+
+// sqlLoader inserts records in transactions of size records.
+type sqlLoader struct {
+	db     *sql.DB
+	insert string
+	types  []string
+	size   int
+
+	tx      *sql.Tx
+	stmt    *sql.Stmt
+	pending int
+}
+
+// add inserts record, beginning a transaction if necessary, and committing it
+// once it holds size records.
+func (l *sqlLoader) add(ctx context.Context, record []string, states []FieldState) error {
+	if l.tx == nil {
+		tx, err := l.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		stmt, err := tx.PrepareContext(ctx, l.insert)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		l.tx, l.stmt = tx, stmt
+	}
+	args := make([]interface{}, len(l.types))
+	for i := range args {
+		if i >= len(record) || states[i] == FieldMissing || (record[i] == "" && l.types[i] != sqlText) {
+			continue
+		}
+		args[i] = record[i]
+	}
+	_, err := l.stmt.ExecContext(ctx, args...)
+	if err != nil {
+		l.stmt.Close()
+		l.tx.Rollback()
+		l.tx, l.stmt = nil, nil
+		return err
+	}
+	l.pending++
+	if l.pending >= l.size {
+		return l.commit()
+	}
+	return nil
+}
+
+// commit commits the current transaction, if any.
+func (l *sqlLoader) commit() error {
+	if l.tx == nil {
+		return nil
+	}
+	l.stmt.Close()
+	err := l.tx.Commit()
+	l.tx, l.stmt, l.pending = nil, nil, 0
+	return err
+}
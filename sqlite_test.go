@@ -0,0 +1,205 @@
+package permissivecsv_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDB records the statements executed through the fakesql driver. Each
+// entry of log is a statement, followed by its arguments (if any). Statements
+// fail if they contain failOn.
+type fakeDB struct {
+	mu     sync.Mutex
+	log    []string
+	failOn string
+}
+
+var fakeDBs = map[string]*fakeDB{}
+
+func init() {
+	sql.Register("fakesql", fakeDriver{})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	db, ok := fakeDBs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown database %q", name)
+	}
+	return &fakeConn{db: db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) record(entry string) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.log = append(c.db.log, entry)
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.record("BEGIN")
+	return fakeTx{conn: c}, nil
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+func (tx fakeTx) Commit() error {
+	tx.conn.record("COMMIT")
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.conn.record("ROLLBACK")
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	entry := s.query
+	if len(args) > 0 {
+		values := make([]string, len(args))
+		for i, arg := range args {
+			if arg == nil {
+				values[i] = "NULL"
+			} else {
+				values[i] = fmt.Sprintf("%q", arg)
+			}
+		}
+		entry = strings.Join(values, " ")
+	}
+	if s.conn.db.failOn != "" && strings.Contains(entry, s.conn.db.failOn) {
+		return nil, fmt.Errorf("failed: %s", entry)
+	}
+	s.conn.record(entry)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func Test_LoadIntoSQLite(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		headerCheck permissivecsv.HeaderCheck
+		opts        []permissivecsv.SQLiteOption
+		failOn      string
+		expLog      []string
+		expErr      error
+	}{
+		{
+			name:        "inferred schema",
+			data:        "ID,Price,Name,Notes\n1,2.50,widget,\n2,3,\"gadget, large\"\n,,\"\"\"quoted\"\"\",x\n3,,4",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expLog: []string{
+				`CREATE TABLE IF NOT EXISTS "items" ("id" INTEGER, "price" REAL, "name" TEXT, "notes" TEXT)`,
+				"BEGIN",
+				`"1" "2.50" "widget" ""`,
+				`"2" "3" "gadget, large" NULL`,
+				`NULL NULL "\"quoted\"" "x"`,
+				`"3" NULL "4" NULL`,
+				"COMMIT",
+			},
+		},
+		{
+			name:        "no header, batches, and sampling",
+			data:        "1,a\n2,b\n3,4.5\nx,c",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			opts: []permissivecsv.SQLiteOption{
+				permissivecsv.WithSQLiteBatchSize(3),
+				permissivecsv.WithSQLiteSampleSize(2),
+			},
+			expLog: []string{
+				`CREATE TABLE IF NOT EXISTS "items" ("column_1" INTEGER, "column_2" TEXT)`,
+				"BEGIN",
+				`"1" "a"`,
+				`"2" "b"`,
+				`"3" "4.5"`,
+				"COMMIT",
+				"BEGIN",
+				`"x" "c"`,
+				"COMMIT",
+			},
+		},
+		{
+			name:        "empty input",
+			data:        "",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expLog:      nil,
+		},
+		{
+			name:        "header only",
+			data:        "a,b",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expLog: []string{
+				`CREATE TABLE IF NOT EXISTS "items" ("a" TEXT, "b" TEXT)`,
+			},
+		},
+		{
+			name:        "insert fails",
+			data:        "a\nb\nc",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			failOn:      `"b"`,
+			expLog: []string{
+				`CREATE TABLE IF NOT EXISTS "items" ("column_1" TEXT)`,
+				"BEGIN",
+				`"a"`,
+				"ROLLBACK",
+			},
+			expErr: fmt.Errorf(`failed: "b"`),
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			db := &fakeDB{failOn: test.failOn}
+			fakeDBs[t.Name()] = db
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck)
+			opts := append([]permissivecsv.SQLiteOption{permissivecsv.WithSQLiteDriver("fakesql")}, test.opts...)
+			summary, err := s.LoadIntoSQLite(t.Name(), "items", opts...)
+			assert.Equal(t, test.expErr, err)
+			assert.NotNil(t, summary)
+			assert.Equal(t, test.expLog, db.log)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_LoadIntoSQLiteErrors(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a"), permissivecsv.HeaderCheckAssumeNoHeader)
+	_, err := s.LoadIntoSQLite("db", "items", permissivecsv.WithSQLiteDriver("unregistered"))
+	assert.Error(t, err)
+
+	fakeDBs["reader error"] = &fakeDB{}
+	s = permissivecsv.NewScanner(BadReader(strings.NewReader("a\nb")), permissivecsv.HeaderCheckAssumeNoHeader)
+	_, err = s.LoadIntoSQLite("reader error", "items", permissivecsv.WithSQLiteDriver("fakesql"))
+	assert.True(t, errors.Is(err, ErrReader), "got %v", err)
+}
@@ -0,0 +1,88 @@
+package permissivecsv
+
+// ScannerState describes whether further calls to Scan can return records.
+//
+// A Scanner begins in ScannerStateScanning, and remains there until Scan
+// returns false, at which point it moves to one of the other states. Each of
+// those states is final, except that Reset (and methods that reset the
+// Scanner, such as Partition) return the Scanner to ScannerStateScanning.
+//
+//	               Scan returns false
+//	ScannerStateScanning ---+---> ScannerStateEOF      (end of input)
+//	                        +---> ScannerStateErrored  (reader error)
+//	                        +---> ScannerStateLimited  (limit, deadline, or Close)
+type ScannerState int
+
+const (
+	// ScannerStateScanning indicates that Scan may return further records.
+	ScannerStateScanning ScannerState = iota
+
+	// ScannerStateEOF indicates that the end of the input was reached. This
+	// includes scanning stopped by WithStopAtBlankRun, since the remainder of
+	// the input is read (and ignored) in that case.
+	ScannerStateEOF
+
+	// ScannerStateErrored indicates that scanning stopped because of an error
+	// (see ScanSummary.Err).
+	ScannerStateErrored
+
+	// ScannerStateLimited indicates that scanning stopped before the end of
+	// the input because a limit set by WithMaxRecords or WithMaxBytes was
+	// reached, or because the deadline set by WithDeadline or WithTimeout
+	// passed, or because the Scanner was closed (see Close). Scanning can be
+	// resumed from ScanSummary.ResumeOffset using a new Scanner.
+	ScannerStateLimited
+)
+
+func (st ScannerState) String() string {
+	switch st {
+	case ScannerStateScanning:
+		return "scanning"
+	case ScannerStateEOF:
+		return "EOF"
+	case ScannerStateErrored:
+		return "errored"
+	case ScannerStateLimited:
+		return "limited"
+	default:
+		return "unknown"
+	}
+}
+
+// State returns the current state of the Scanner. Once State returns a value
+// other than ScannerStateScanning, further calls to Scan will return false.
+// This allows wrappers (such as those that retry or pool Scanners) to decide
+// whether a Scanner is worth scanning further without inspecting its Summary.
+func (s *Scanner) State() ScannerState {
+	return s.state
+}
+
+// Done reports whether Scan has returned false. Once Done returns true, Scan
+// will continue to return false until the Scanner is Reset, and CurrentRecord
+// returns the last record that Scan returned (or an empty slice, see
+// WithClearRecordOnDone). This suits drain loops that stop scanning part way
+// through, and later need to know whether any records remain.
+func (s *Scanner) Done() bool {
+	return s.done
+}
+
+// WithClearRecordOnDone instructs CurrentRecord to return an empty slice once
+// Scan has returned false (see Done). By default, CurrentRecord continues to
+// return the last record that Scan returned, or an empty slice if Scan never
+// returned a record. In either case, CurrentRecord never returns nil once
+// Scan has returned false.
+func WithClearRecordOnDone() Option {
+	return func(o *options) {
+		o.clearRecordOnDone = true
+	}
+}
+
+// finish marks the Scanner as done, and makes the current record the last
+// record that Scan returned (see Done).
+func (s *Scanner) finish() {
+	s.done = true
+	s.currentRecord = s.lastRecord
+	if s.currentRecord == nil || s.opts.clearRecordOnDone {
+		s.currentRecord = []string{}
+	}
+}
@@ -0,0 +1,158 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_State(t *testing.T) {
+	tests := []struct {
+		name     string
+		scanner  func() *permissivecsv.Scanner
+		expState permissivecsv.ScannerState
+	}{
+		{
+			name: "EOF",
+			scanner: func() *permissivecsv.Scanner {
+				return permissivecsv.NewScanner(strings.NewReader("a\nb"), permissivecsv.HeaderCheckAssumeNoHeader)
+			},
+			expState: permissivecsv.ScannerStateEOF,
+		},
+		{
+			name: "stopped at blank run",
+			scanner: func() *permissivecsv.Scanner {
+				return permissivecsv.NewScanner(strings.NewReader("a\n\n\nb"), permissivecsv.HeaderCheckAssumeNoHeader,
+					permissivecsv.WithStopAtBlankRun(2))
+			},
+			expState: permissivecsv.ScannerStateEOF,
+		},
+		{
+			name: "reader error",
+			scanner: func() *permissivecsv.Scanner {
+				return permissivecsv.NewScanner(BadReader(strings.NewReader("a")), permissivecsv.HeaderCheckAssumeNoHeader)
+			},
+			expState: permissivecsv.ScannerStateErrored,
+		},
+		{
+			name: "nil reader",
+			scanner: func() *permissivecsv.Scanner {
+				return permissivecsv.NewScanner(nil, permissivecsv.HeaderCheckAssumeNoHeader)
+			},
+			expState: permissivecsv.ScannerStateErrored,
+		},
+		{
+			name: "record limit",
+			scanner: func() *permissivecsv.Scanner {
+				return permissivecsv.NewScanner(strings.NewReader("a\nb\nc"), permissivecsv.HeaderCheckAssumeNoHeader,
+					permissivecsv.WithMaxRecords(2))
+			},
+			expState: permissivecsv.ScannerStateLimited,
+		},
+		{
+			name: "deadline",
+			scanner: func() *permissivecsv.Scanner {
+				return permissivecsv.NewScanner(strings.NewReader("a\nb\nc"), permissivecsv.HeaderCheckAssumeNoHeader,
+					permissivecsv.WithDeadline(time.Now().Add(-time.Second)))
+			},
+			expState: permissivecsv.ScannerStateLimited,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := test.scanner()
+			assert.Equal(t, permissivecsv.ScannerStateScanning, s.State())
+			for s.Scan() {
+				assert.Equal(t, permissivecsv.ScannerStateScanning, s.State())
+			}
+			assert.Equal(t, test.expState, s.State())
+			assert.False(t, s.Scan(), "Scan returns false once scanning has stopped")
+			assert.Equal(t, test.expState, s.State())
+
+			s.Reset()
+			assert.Equal(t, permissivecsv.ScannerStateScanning, s.State())
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_ScannerStateString(t *testing.T) {
+	assert.Equal(t, "scanning", permissivecsv.ScannerStateScanning.String())
+	assert.Equal(t, "EOF", permissivecsv.ScannerStateEOF.String())
+	assert.Equal(t, "errored", permissivecsv.ScannerStateErrored.String())
+	assert.Equal(t, "limited", permissivecsv.ScannerStateLimited.String())
+	assert.Equal(t, "unknown", permissivecsv.ScannerState(-1).String())
+}
+
+func Test_Done(t *testing.T) {
+	tests := []struct {
+		name      string
+		reader    func() *strings.Reader
+		nilReader bool
+		opts      []permissivecsv.Option
+		expRecord []string
+	}{
+		{
+			name:      "retains last record",
+			reader:    func() *strings.Reader { return strings.NewReader("a,b\nc,d\n\n") },
+			expRecord: []string{"c", "d"},
+		},
+		{
+			name:      "retains last returned record when the limit is reached",
+			reader:    func() *strings.Reader { return strings.NewReader("a,b\nc,d\ne,f\n") },
+			opts:      []permissivecsv.Option{permissivecsv.WithMaxRecords(1)},
+			expRecord: []string{"a", "b"},
+		},
+		{
+			name:   "retains last returned record when trailing records are dropped",
+			reader: func() *strings.Reader { return strings.NewReader("a,b\nc,d\ne\n") },
+			opts: []permissivecsv.Option{
+				permissivecsv.WithFieldCountTolerance(0, permissivecsv.ToleranceDrop),
+			},
+			expRecord: []string{"c", "d"},
+		},
+		{
+			name:      "clear record on done",
+			reader:    func() *strings.Reader { return strings.NewReader("a,b\nc,d\n") },
+			opts:      []permissivecsv.Option{permissivecsv.WithClearRecordOnDone()},
+			expRecord: []string{},
+		},
+		{
+			name:      "no records",
+			reader:    func() *strings.Reader { return strings.NewReader("\n\n") },
+			expRecord: []string{},
+		},
+		{
+			name:      "nil reader",
+			nilReader: true,
+			expRecord: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			var s *permissivecsv.Scanner
+			if test.nilReader {
+				s = permissivecsv.NewScanner(nil, permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			} else {
+				s = permissivecsv.NewScanner(test.reader(), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			}
+			assert.Nil(t, s.CurrentRecord())
+			for s.Scan() {
+				assert.False(t, s.Done())
+			}
+			assert.True(t, s.Done())
+			assert.Equal(t, test.expRecord, s.CurrentRecord())
+			assert.False(t, s.Scan())
+			assert.Equal(t, test.expRecord, s.CurrentRecord())
+
+			s.Reset()
+			assert.False(t, s.Done())
+		}
+		t.Run(test.name, testFn)
+	}
+}
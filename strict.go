@@ -0,0 +1,148 @@
+package permissivecsv
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DeviationKind identifies a way in which a record deviates from RFC 4180.
+type DeviationKind int
+
+const (
+	// DeviationMixedTerminators indicates that a record was terminated
+	// differently from the first terminated record of the input.
+	DeviationMixedTerminators DeviationKind = iota + 1
+
+	// DeviationBareCarriageReturn indicates that a record was terminated by a
+	// carriage return that was not followed by a newline (including inverted
+	// DOS terminators).
+	DeviationBareCarriageReturn
+
+	// DeviationBareQuote indicates that a record contained a quote within an
+	// unquoted field, or data outside of a quoted field.
+	DeviationBareQuote
+
+	// DeviationExtraneousQuote indicates that a record contained an unclosed
+	// or unescaped quote.
+	DeviationExtraneousQuote
+
+	// DeviationRaggedRecord indicates that a record did not have the same
+	// number of fields as the first record.
+	DeviationRaggedRecord
+
+	// DeviationEmptyRecord indicates that the input contained an empty line.
+	DeviationEmptyRecord
+)
+
+func (k DeviationKind) String() string {
+	switch k {
+	case DeviationMixedTerminators:
+		return "mixed terminators"
+	case DeviationBareCarriageReturn:
+		return "bare carriage return"
+	case DeviationBareQuote:
+		return "bare quote"
+	case DeviationExtraneousQuote:
+		return "extraneous quote"
+	case DeviationRaggedRecord:
+		return "ragged record"
+	case DeviationEmptyRecord:
+		return "empty record"
+	default:
+		return "unknown"
+	}
+}
+
+// Deviation describes a way in which the input deviates from RFC 4180 (see
+// WithStrictRFC4180). RecordOrdinal and ByteOffset locate the record that
+// deviates. The RecordOrdinal of an empty record that Scan skipped (see
+// WithKeepEmptyRecords) is zero, since such records are not counted. Detail
+// describes the deviation.
+type Deviation struct {
+	Kind          DeviationKind
+	RecordOrdinal int
+	ByteOffset    int64
+	Detail        string
+}
+
+// WithStrictRFC4180 instructs the Scanner to report every way in which the
+// input deviates from RFC 4180 via the Deviations field of the ScanSummary.
+// The input is still scanned permissively, and Scan returns the same records
+// it otherwise would, but deviations that the Scanner would otherwise accept
+// without comment (such as mixed or bare carriage return terminators, and
+// empty lines) are reported alongside those that it alters records to
+// accommodate (such as ragged records and ambiguous quotes). Together, the
+// Deviations form a lint of the input.
+//
+// Newline (\n) terminators are accepted as equivalent to the DOS (\r\n)
+// terminators required by RFC 4180, as they are by most producers and
+// consumers, so they are only reported if they are mixed with other
+// terminators. A ragged record is reported even if it is repaired (see
+// WithRepairStrategies) or is within the tolerance set by
+// WithFieldCountTolerance.
+func WithStrictRFC4180() Option {
+	return func(o *options) {
+		o.strictRFC4180 = true
+	}
+}
+
+// deviate records a deviation of the record at ordinal and offset.
+func (s *Scanner) deviate(kind DeviationKind, ordinal int, offset int64, detail string) {
+	s.scanSummary.Deviations = append(s.scanSummary.Deviations, &Deviation{
+		Kind:          kind,
+		RecordOrdinal: ordinal,
+		ByteOffset:    offset,
+		Detail:        detail,
+	})
+}
+
+// checkStrictTerminator reports any deviation of terminator, which terminates
+// the record at ordinal and offset.
+func (s *Scanner) checkStrictTerminator(terminator []byte, ordinal int, offset int64) {
+	if !s.opts.strictRFC4180 || len(terminator) == 0 {
+		return
+	}
+	if s.strictTerminator == nil {
+		s.strictTerminator = terminator
+	} else if !bytes.Equal(terminator, s.strictTerminator) {
+		s.deviate(DeviationMixedTerminators, ordinal, offset,
+			fmt.Sprintf("terminator %q differs from first terminator %q", terminator, s.strictTerminator))
+	}
+	if string(terminator) == TerminatorCarriageReturn || string(terminator) == TerminatorInvertedDOS {
+		s.deviate(DeviationBareCarriageReturn, ordinal, offset,
+			fmt.Sprintf("terminator %q contains a bare carriage return", terminator))
+	}
+}
+
+// checkStrictEmptyRecord reports the empty record terminated by terminator at
+// offset. kept is true if the record will be emitted by Scan.
+func (s *Scanner) checkStrictEmptyRecord(terminator []byte, offset int64, kept bool) {
+	if !s.opts.strictRFC4180 {
+		return
+	}
+	ordinal := 0
+	if kept {
+		ordinal = s.scanSummary.RecordCount + len(s.pendingEmptyRecords)
+	}
+	s.deviate(DeviationEmptyRecord, ordinal, offset, "empty line")
+	s.checkStrictTerminator(terminator, ordinal, offset)
+}
+
+// checkStrictFields reports any deviation of the fields parsed from the
+// current record with a comma delimiter. fieldCount is the number of fields
+// parsed, and is ignored if either quote error was encountered.
+func (s *Scanner) checkStrictFields(fieldCount int, extraneousQuote, bareQuote bool) {
+	if !s.opts.strictRFC4180 {
+		return
+	}
+	ordinal := s.scanSummary.RecordCount
+	switch {
+	case extraneousQuote:
+		s.deviate(DeviationExtraneousQuote, ordinal, s.recordOffset, "unclosed or unescaped quote")
+	case bareQuote:
+		s.deviate(DeviationBareQuote, ordinal, s.recordOffset, "quote in unquoted field")
+	case s.recordsScanned > 0 && fieldCount != s.expectedFieldCount:
+		s.deviate(DeviationRaggedRecord, ordinal, s.recordOffset,
+			fmt.Sprintf("expected %d fields, found %d", s.expectedFieldCount, fieldCount))
+	}
+}
@@ -0,0 +1,122 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithStrictRFC4180(t *testing.T) {
+	type deviation struct {
+		kind    permissivecsv.DeviationKind
+		ordinal int
+		offset  int64
+	}
+	tests := []struct {
+		name          string
+		data          string
+		opts          []permissivecsv.Option
+		expDeviations []deviation
+	}{
+		{
+			name:          "compliant",
+			data:          "a,b\r\nc,d\r\n",
+			expDeviations: []deviation{},
+		},
+		{
+			name:          "newlines are accepted",
+			data:          "a,b\nc,d\n",
+			expDeviations: []deviation{},
+		},
+		{
+			name: "mixed terminators",
+			data: "a,b\r\nc,d\ne,f\r\n",
+			expDeviations: []deviation{
+				{permissivecsv.DeviationMixedTerminators, 2, 5},
+			},
+		},
+		{
+			name: "bare carriage return",
+			data: "a,b\rc,d\r",
+			expDeviations: []deviation{
+				{permissivecsv.DeviationBareCarriageReturn, 1, 0},
+				{permissivecsv.DeviationBareCarriageReturn, 2, 4},
+			},
+		},
+		{
+			name: "quotes",
+			data: "a,b\nc,d\"\"\n\"e,f\n",
+			expDeviations: []deviation{
+				{permissivecsv.DeviationBareQuote, 2, 4},
+				{permissivecsv.DeviationExtraneousQuote, 3, 10},
+			},
+		},
+		{
+			name: "ragged",
+			data: "a,b\nc\nd,e,f\n",
+			expDeviations: []deviation{
+				{permissivecsv.DeviationRaggedRecord, 2, 4},
+				{permissivecsv.DeviationRaggedRecord, 3, 6},
+			},
+		},
+		{
+			name: "repaired ragged record",
+			data: "a,b\nc,d,e\n",
+			opts: []permissivecsv.Option{permissivecsv.WithRaggedRight()},
+			expDeviations: []deviation{
+				{permissivecsv.DeviationRaggedRecord, 2, 4},
+			},
+		},
+		{
+			name: "skipped empty record",
+			data: "a\n\nb\n",
+			expDeviations: []deviation{
+				{permissivecsv.DeviationEmptyRecord, 0, 2},
+			},
+		},
+		{
+			name: "kept empty record",
+			data: "a\n\nb\n",
+			opts: []permissivecsv.Option{permissivecsv.WithKeepEmptyRecords()},
+			expDeviations: []deviation{
+				{permissivecsv.DeviationEmptyRecord, 2, 2},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			opts := append([]permissivecsv.Option{permissivecsv.WithStrictRFC4180()}, test.opts...)
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, opts...)
+			for s.Scan() {
+			}
+			deviations := []deviation{}
+			for _, d := range s.Summary().Deviations {
+				deviations = append(deviations, deviation{d.Kind, d.RecordOrdinal, d.ByteOffset})
+				assert.NotEmpty(t, d.Detail)
+			}
+			assert.Equal(t, test.expDeviations, deviations)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithStrictRFC4180Records(t *testing.T) {
+	data := "a,b\r\nc\n\nd,e\r"
+	records := func(opts ...permissivecsv.Option) [][]string {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader, opts...)
+		result := [][]string{}
+		for s.Scan() {
+			result = append(result, s.CurrentRecord())
+		}
+		return result
+	}
+	assert.Equal(t, records(), records(permissivecsv.WithStrictRFC4180()))
+
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+	for s.Scan() {
+	}
+	assert.Nil(t, s.Summary().Deviations)
+}
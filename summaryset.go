@@ -0,0 +1,195 @@
+package permissivecsv
+
+import (
+	"sort"
+	"sync"
+)
+
+// Merge appends the results summarized by other to s, as though the records
+// summarized by other immediately followed the records summarized by s.
+//
+// The RecordOrdinal of each of other's alterations is rebased by s's
+// RecordCount, so that the ordinals in the merged summary are relative to the
+// first record summarized by s. The ordinals of other's DuplicateKeys are
+// rebased in the same way, although keys that are duplicated across the two
+// summaries are not detected, as are the ordinals of other's Deviations and
+// Warnings.
+// Alteration and Deviation ByteOffsets are not adjusted, since a summary does
+// not record where its scanner started reading. Use a SummarySet to merge the
+// summaries of segment scanners with both ordinals and byte offsets rebased.
+//
+// Adjacent field count runs that share the same field count are joined, as are
+// adjacent AlterationGroups that share the same kind and shape.
+//
+// The merged summary reports EOF if other reports EOF, and its Err joins the
+// errors of both summaries. Counts (such as EmptyRecordCount,
+// IgnoredBytes, and TerminatorCounts) are summed, and flags (such as
+// LimitReached) are set if they are set in either summary. other is not
+// modified.
+func (s *ScanSummary) Merge(other *ScanSummary) {
+	s.merge(other, 0)
+}
+
+func (s *ScanSummary) merge(other *ScanSummary, byteOffset int64) {
+	if other == nil {
+		return
+	}
+	recordOffset := nonNegative(s.RecordCount)
+	for _, alteration := range other.Alterations {
+		rebased := *alteration
+		rebased.RecordOrdinal += recordOffset
+		rebased.ByteOffset += byteOffset
+		s.Alterations = append(s.Alterations, &rebased)
+	}
+	for _, duplicate := range other.DuplicateKeys {
+		if duplicate.FirstRecordOrdinal > 0 {
+			duplicate.FirstRecordOrdinal += recordOffset
+		}
+		duplicate.RecordOrdinal += recordOffset
+		s.DuplicateKeys = append(s.DuplicateKeys, duplicate)
+	}
+	for _, deviation := range other.Deviations {
+		rebased := *deviation
+		if rebased.RecordOrdinal > 0 {
+			rebased.RecordOrdinal += recordOffset
+		}
+		rebased.ByteOffset += byteOffset
+		s.Deviations = append(s.Deviations, &rebased)
+	}
+	for _, warning := range other.Warnings {
+		rebased := *warning
+		rebased.RecordOrdinal += recordOffset
+		s.Warnings = append(s.Warnings, &rebased)
+	}
+	for _, run := range other.FieldCountRuns {
+		n := len(s.FieldCountRuns)
+		if n > 0 && s.FieldCountRuns[n-1].FieldCount == run.FieldCount {
+			s.FieldCountRuns[n-1] = &FieldCountRun{
+				FirstRecordOrdinal: s.FieldCountRuns[n-1].FirstRecordOrdinal,
+				LastRecordOrdinal:  run.LastRecordOrdinal + recordOffset,
+				FieldCount:         run.FieldCount,
+			}
+			continue
+		}
+		s.FieldCountRuns = append(s.FieldCountRuns, &FieldCountRun{
+			FirstRecordOrdinal: run.FirstRecordOrdinal + recordOffset,
+			LastRecordOrdinal:  run.LastRecordOrdinal + recordOffset,
+			FieldCount:         run.FieldCount,
+		})
+	}
+	for _, group := range other.AlterationGroups {
+		rebased := *group
+		rebased.FirstRecordOrdinal += recordOffset
+		rebased.LastRecordOrdinal += recordOffset
+		n := len(s.AlterationGroups)
+		if n > 0 && s.AlterationGroups[n-1].Kind == group.Kind &&
+			s.AlterationGroups[n-1].OriginalFieldCount == group.OriginalFieldCount &&
+			s.AlterationGroups[n-1].ResultingFieldCount == group.ResultingFieldCount {
+			rebased.FirstRecordOrdinal = s.AlterationGroups[n-1].FirstRecordOrdinal
+			rebased.Count += s.AlterationGroups[n-1].Count
+			s.AlterationGroups[n-1] = &rebased
+			continue
+		}
+		s.AlterationGroups = append(s.AlterationGroups, &rebased)
+	}
+	s.HeaderOnly = (s.HeaderOnly && other.RecordCount <= 0) || (s.RecordCount <= 0 && other.HeaderOnly)
+	s.RecordCount = recordOffset + nonNegative(other.RecordCount)
+	s.AlterationCount = nonNegative(s.AlterationCount) + nonNegative(other.AlterationCount)
+	s.EmptyRecordCount = nonNegative(s.EmptyRecordCount) + nonNegative(other.EmptyRecordCount)
+	s.IgnoredBytes += other.IgnoredBytes
+	s.IgnoredRecords += other.IgnoredRecords
+	s.InvalidUTF8Count += other.InvalidUTF8Count
+	s.DroppedAlterationCount += other.DroppedAlterationCount
+	s.InfoAlterationCount += other.InfoAlterationCount
+	s.DroppedRecordCount += other.DroppedRecordCount
+	s.ForceClosedQuoteCount += other.ForceClosedQuoteCount
+	s.RepeatedHeaderCount += other.RepeatedHeaderCount
+	s.UnsampledRecordCount += other.UnsampledRecordCount
+	s.StoppedAtBlankRun = s.StoppedAtBlankRun || other.StoppedAtBlankRun
+	s.LimitReached = s.LimitReached || other.LimitReached
+	s.DeadlineExceeded = s.DeadlineExceeded || other.DeadlineExceeded
+	if len(other.TerminatorCounts) > 0 && s.TerminatorCounts == nil {
+		s.TerminatorCounts = map[Terminator]int{}
+	}
+	for terminator, count := range other.TerminatorCounts {
+		s.TerminatorCounts[terminator] += count
+	}
+	if len(other.AlterationKindCounts) > 0 && s.AlterationKindCounts == nil {
+		s.AlterationKindCounts = map[AlterationKind]int{}
+	}
+	for kind, count := range other.AlterationKindCounts {
+		s.AlterationKindCounts[kind] += count
+	}
+	if len(other.RedactionCounts) > 0 && s.RedactionCounts == nil {
+		s.RedactionCounts = map[string]int{}
+	}
+	for column, count := range other.RedactionCounts {
+		s.RedactionCounts[column] += count
+	}
+	s.EOF = other.EOF
+	s.addErr(other.Err)
+	if s.HeaderMismatch == nil {
+		s.HeaderMismatch = other.HeaderMismatch
+	}
+}
+
+func nonNegative(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// SummarySet collects the summaries of scanners that each read a single
+// Segment, and combines them into one summary for the entire file. SummarySet
+// is safe for concurrent use, so each scanner can add its summary as soon as it
+// finishes, in any order.
+//
+// The zero value is an empty set ready to use.
+type SummarySet struct {
+	mu      sync.Mutex
+	entries []summarySetEntry
+}
+
+type summarySetEntry struct {
+	segment *Segment
+	summary *ScanSummary
+}
+
+// Add records the summary produced by scanning segment.
+func (ss *SummarySet) Add(segment *Segment, summary *ScanSummary) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.entries = append(ss.entries, summarySetEntry{segment, summary})
+}
+
+// Len returns the number of summaries that have been added to the set.
+func (ss *SummarySet) Len() int {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return len(ss.entries)
+}
+
+// Summary merges the summaries in the set, in segment ordinal order, into a
+// single summary. Record ordinals are rebased so that they are relative to the
+// first record of the first segment, and alteration byte offsets are rebased
+// by each segment's LowerOffset so that they are relative to the start of the
+// file. Summary does not modify any of the summaries that were added.
+func (ss *SummarySet) Summary() *ScanSummary {
+	ss.mu.Lock()
+	entries := make([]summarySetEntry, len(ss.entries))
+	copy(entries, ss.entries)
+	ss.mu.Unlock()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].segment.Ordinal < entries[j].segment.Ordinal
+	})
+	merged := &ScanSummary{
+		Alterations:    []*Alteration{},
+		FieldCountRuns: []*FieldCountRun{},
+	}
+	for _, entry := range entries {
+		merged.merge(entry.summary, entry.segment.LowerOffset)
+	}
+	return merged
+}
@@ -0,0 +1,120 @@
+package permissivecsv_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SummaryMerge(t *testing.T) {
+	first := &permissivecsv.ScanSummary{
+		RecordCount:     3,
+		AlterationCount: 1,
+		Alterations: []*permissivecsv.Alteration{
+			&permissivecsv.Alteration{
+				RecordOrdinal:         2,
+				ByteOffset:            4,
+				OriginalData:          "c",
+				ResultingRecord:       []string{"c", ""},
+				Kind:                  permissivecsv.AlterationPaddedRecord,
+				AlterationDescription: permissivecsv.AltPaddedRecord,
+			},
+		},
+		EOF: false,
+	}
+	second := &permissivecsv.ScanSummary{
+		RecordCount:     2,
+		AlterationCount: 1,
+		Alterations: []*permissivecsv.Alteration{
+			&permissivecsv.Alteration{
+				RecordOrdinal:         2,
+				ByteOffset:            4,
+				OriginalData:          "g,h,i",
+				ResultingRecord:       []string{"g", "h"},
+				Kind:                  permissivecsv.AlterationTruncatedRecord,
+				AlterationDescription: permissivecsv.AltTruncatedRecord,
+			},
+		},
+		EmptyRecordCount: 2,
+		LimitReached:     true,
+		EOF:              true,
+		Err:              ErrReader,
+	}
+
+	first.Merge(second)
+	expected := &permissivecsv.ScanSummary{
+		RecordCount:     5,
+		AlterationCount: 2,
+		Alterations: []*permissivecsv.Alteration{
+			&permissivecsv.Alteration{
+				RecordOrdinal:         2,
+				ByteOffset:            4,
+				OriginalData:          "c",
+				ResultingRecord:       []string{"c", ""},
+				Kind:                  permissivecsv.AlterationPaddedRecord,
+				AlterationDescription: permissivecsv.AltPaddedRecord,
+			},
+			&permissivecsv.Alteration{
+				RecordOrdinal:         5,
+				ByteOffset:            4,
+				OriginalData:          "g,h,i",
+				ResultingRecord:       []string{"g", "h"},
+				Kind:                  permissivecsv.AlterationTruncatedRecord,
+				AlterationDescription: permissivecsv.AltTruncatedRecord,
+			},
+		},
+		EmptyRecordCount: 2,
+		LimitReached:     true,
+		EOF:              true,
+		Err:              ErrReader,
+	}
+	diff := deep.Equal(expected, first)
+	if diff != nil {
+		t.Error(diff)
+	}
+	assert.Equal(t, 2, second.Alterations[0].RecordOrdinal, "other must not be modified")
+}
+
+func Test_SummarySet(t *testing.T) {
+	data := strings.NewReader("a,b\nc\nd,e\nf,g\nh,i,j\nk,l\nm,n\no")
+
+	full := permissivecsv.NewScanner(data, permissivecsv.HeaderCheckAssumeNoHeader)
+	for full.Scan() {
+	}
+	expected := full.Summary()
+
+	data.Seek(0, 0)
+	segments, err := permissivecsv.NewScanner(data, permissivecsv.HeaderCheckAssumeNoHeader).Partition(3, false)
+	assert.NoError(t, err)
+	rr := permissivecsv.ReaderAtRangeReader(data)
+	set := new(permissivecsv.SummarySet)
+	wg := sync.WaitGroup{}
+	for _, segment := range segments {
+		wg.Add(1)
+		go func(segment *permissivecsv.Segment) {
+			defer wg.Done()
+			body, err := segment.Open(context.Background(), rr)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer body.Close()
+			s := permissivecsv.NewScanner(body, permissivecsv.HeaderCheckAssumeNoHeader)
+			for s.Scan() {
+			}
+			set.Add(segment, s.Summary())
+		}(segment)
+	}
+	wg.Wait()
+
+	assert.Equal(t, len(segments), set.Len())
+	diff := deep.Equal(expected, set.Summary())
+	if diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,135 @@
+package permissivecsv
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SummaryStringOption configures the representation of a summary returned by
+// ScanSummary.Describe.
+type SummaryStringOption func(*summaryStringOptions)
+
+type summaryStringOptions struct {
+	compact        bool
+	maxAlterations int
+}
+
+// WithSummaryCompact describes the summary on a single line, with the number
+// of alterations of each kind (identified by AlterationKind.Code) rather than
+// the alterations themselves, which is better suited to logs. By default, the
+// summary is described over several lines, with the details of each retained
+// alteration.
+func WithSummaryCompact() SummaryStringOption {
+	return func(o *summaryStringOptions) {
+		o.compact = true
+	}
+}
+
+// WithSummaryMaxAlterations limits the number of alterations (or alteration
+// groups, see WithDedupedAlterationGrouping) that are described to n. If the
+// summary holds more, the remainder are counted rather than described. A
+// value of zero (the default) describes every alteration.
+func WithSummaryMaxAlterations(n int) SummaryStringOption {
+	return func(o *summaryStringOptions) {
+		o.maxAlterations = n
+	}
+}
+
+// String returns a prettified representation of the summary. It is
+// equivalent to Describe with no options.
+func (s *ScanSummary) String() string {
+	return s.Describe()
+}
+
+// Describe returns a representation of the summary configured by opts. The
+// representation depends only on the contents of the summary, so it is
+// suitable for comparison in tests.
+func (s *ScanSummary) Describe(opts ...SummaryStringOption) string {
+	o := summaryStringOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	b := &strings.Builder{}
+	if o.compact {
+		s.describeCompact(b)
+	} else {
+		s.describeVerbose(b, o.maxAlterations)
+	}
+	return b.String()
+}
+
+func (s *ScanSummary) describeVerbose(b *strings.Builder, maxAlterations int) {
+	b.WriteString("Scan Summary\n")
+	b.WriteString("---------------------------------------\n")
+	b.WriteString("  Records Scanned:    " + strconv.Itoa(s.RecordCount) + "\n")
+	b.WriteString("  Alterations Made:   " + strconv.Itoa(s.AlterationCount) + "\n")
+	b.WriteString("  EOF:                " + strconv.FormatBool(s.EOF) + "\n")
+	b.WriteString("  Err:                " + s.errString() + "\n")
+	if s.AlterationGroups != nil {
+		s.describeGroups(b, maxAlterations)
+		return
+	}
+	b.WriteString("  Alterations:")
+	if len(s.Alterations) == 0 {
+		b.WriteString("        none")
+		return
+	}
+	for i, alteration := range s.Alterations {
+		if maxAlterations > 0 && i == maxAlterations {
+			b.WriteString("    ... " + strconv.Itoa(len(s.Alterations)-i) + " more\n")
+			break
+		}
+		record, _ := json.Marshal(alteration.ResultingRecord)
+		b.WriteString("\n    Record Number:    " + strconv.Itoa(alteration.RecordOrdinal))
+		b.WriteString("\n    Alteration:       " + alteration.AlterationDescription)
+		b.WriteString("\n    Original Data:    " + alteration.OriginalData)
+		b.WriteString("\n    Resulting Record: " + string(record) + "\n")
+	}
+}
+
+func (s *ScanSummary) describeGroups(b *strings.Builder, maxGroups int) {
+	b.WriteString("  Alteration Groups:")
+	if len(s.AlterationGroups) == 0 {
+		b.WriteString("  none")
+		return
+	}
+	for i, group := range s.AlterationGroups {
+		if maxGroups > 0 && i == maxGroups {
+			b.WriteString("\n    ... " + strconv.Itoa(len(s.AlterationGroups)-i) + " more")
+			break
+		}
+		b.WriteString("\n    " + group.String())
+	}
+	b.WriteString("\n")
+}
+
+func (s *ScanSummary) describeCompact(b *strings.Builder) {
+	b.WriteString("records=" + strconv.Itoa(s.RecordCount))
+	b.WriteString(" alterations=" + strconv.Itoa(s.AlterationCount))
+	b.WriteString(" eof=" + strconv.FormatBool(s.EOF))
+	if s.Err == nil {
+		b.WriteString(" err=none")
+	} else {
+		b.WriteString(" err=" + strconv.Quote(s.Err.Error()))
+	}
+
+	kinds := make([]AlterationKind, 0, len(s.AlterationKindCounts))
+	for kind, count := range s.AlterationKindCounts {
+		if count > 0 {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	for _, kind := range kinds {
+		b.WriteString(" " + kind.Code() + "=" + strconv.Itoa(s.AlterationKindCounts[kind]))
+	}
+}
+
+func (s *ScanSummary) errString() string {
+	if s.Err == nil {
+		return "none"
+	}
+	return s.Err.Error()
+}
@@ -0,0 +1,94 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SummaryDescribe(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		opts      []permissivecsv.SummaryStringOption
+		expString string
+	}{
+		{
+			name: "verbose without alterations",
+			data: "a,b\nc,d",
+			opts: nil,
+			expString: "Scan Summary\n" +
+				"---------------------------------------\n" +
+				"  Records Scanned:    2\n" +
+				"  Alterations Made:   0\n" +
+				"  EOF:                true\n" +
+				"  Err:                none\n" +
+				"  Alterations:        none",
+		},
+		{
+			name: "verbose with alterations",
+			data: "a,b\nc\nd,e,f",
+			opts: nil,
+			expString: "Scan Summary\n" +
+				"---------------------------------------\n" +
+				"  Records Scanned:    3\n" +
+				"  Alterations Made:   2\n" +
+				"  EOF:                true\n" +
+				"  Err:                none\n" +
+				"  Alterations:\n" +
+				"    Record Number:    2\n" +
+				"    Alteration:       padded record\n" +
+				"    Original Data:    c\n" +
+				"    Resulting Record: [\"c\",\"\"]\n" +
+				"\n" +
+				"    Record Number:    3\n" +
+				"    Alteration:       truncated record\n" +
+				"    Original Data:    d,e,f\n" +
+				"    Resulting Record: [\"d\",\"e\"]\n",
+		},
+		{
+			name: "max alterations",
+			data: "a,b\nc\nd,e,f\ng",
+			opts: []permissivecsv.SummaryStringOption{permissivecsv.WithSummaryMaxAlterations(1)},
+			expString: "Scan Summary\n" +
+				"---------------------------------------\n" +
+				"  Records Scanned:    4\n" +
+				"  Alterations Made:   3\n" +
+				"  EOF:                true\n" +
+				"  Err:                none\n" +
+				"  Alterations:\n" +
+				"    Record Number:    2\n" +
+				"    Alteration:       padded record\n" +
+				"    Original Data:    c\n" +
+				"    Resulting Record: [\"c\",\"\"]\n" +
+				"    ... 2 more\n",
+		},
+		{
+			name:      "compact",
+			data:      "a,b\nc\nd,e,f\ng\n\"x\"y,z",
+			opts:      []permissivecsv.SummaryStringOption{permissivecsv.WithSummaryCompact()},
+			expString: "records=5 alterations=4 eof=true err=none extraneous-quote=1 truncated-record=1 padded-record=2",
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeHeaderExists)
+			for s.Scan() {
+			}
+			assert.Equal(t, test.expString, s.Summary().Describe(test.opts...))
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_SummaryDescribeCompactError(t *testing.T) {
+	s := permissivecsv.NewScanner(BadReader(strings.NewReader("a,b")), permissivecsv.HeaderCheckAssumeHeaderExists)
+	for s.Scan() {
+	}
+	assert.Equal(t, "records=0 alterations=0 eof=false err=\"arbitrary reader error\"",
+		s.Summary().Describe(permissivecsv.WithSummaryCompact()))
+	assert.Contains(t, s.Summary().String(), "  Err:                arbitrary reader error\n")
+}
@@ -0,0 +1,114 @@
+package permissivecsv
+
+import (
+	"io"
+	"sync"
+)
+
+// WithSafeRecords instructs the Scanner to return a copy of the current record
+// from each call to CurrentRecord. By default, CurrentRecord returns the
+// Scanner's own slice, which is also referenced by the header and by any
+// alteration retained in the summary, so modifying it (or appending to a
+// slice that shares its backing array) would corrupt them. Copying costs an
+// allocation per call, so it is best suited to consumers that pass records to
+// other goroutines, or that modify them in place.
+func WithSafeRecords() Option {
+	return func(o *options) {
+		o.safeRecords = true
+	}
+}
+
+// SyncScanner is a Scanner that is safe for concurrent use by multiple
+// goroutines, such as a pool of workers that each pull records from a single
+// input. Each call to Next scans a record and returns a copy of it atomically,
+// so each record is returned to exactly one caller, which owns it.
+//
+// Methods of a Scanner that operate on the current record are not available,
+// since the current record of a shared Scanner may change at any time. Records
+// are returned in the order in which they were scanned, but goroutines may
+// process them in any order; use the Ordinal of the RecordInfo returned by
+// Next to restore their order if necessary.
+type SyncScanner struct {
+	mu      sync.Mutex
+	scanner *Scanner
+}
+
+// NewSyncScanner returns a SyncScanner that reads from r. headerCheck and opts
+// are applied as by NewScanner.
+func NewSyncScanner(r io.Reader, headerCheck HeaderCheck, opts ...Option) *SyncScanner {
+	return &SyncScanner{
+		scanner: NewScanner(r, headerCheck, opts...),
+	}
+}
+
+// Next scans the next record, and returns a copy of it, along with its
+// provenance (see CurrentRecordInfo) and whether it was identified as the
+// header (see RecordIsHeader). ok is false once there are no more records, in
+// which case Summary reports why scanning stopped.
+func (s *SyncScanner) Next() (record []string, info *RecordInfo, isHeader bool, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.scanner.Scan() {
+		return nil, nil, false, false
+	}
+	isHeader = s.scanner.recordsScanned == 1 && s.scanner.RecordIsHeader()
+	return s.scanner.CopyCurrentRecord(), s.scanner.CurrentRecordInfo(), isHeader, true
+}
+
+// Summary returns a copy of the summary of the records that have been scanned
+// so far, which is not modified by subsequent calls to Next.
+func (s *SyncScanner) Summary() *ScanSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scanner.Summary().clone()
+}
+
+// clone returns a copy of s that does not share any of its mutable state.
+func (s *ScanSummary) clone() *ScanSummary {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.mu = nil
+	c.Alterations = append([]*Alteration{}, s.Alterations...)
+	c.FieldCountRuns = make([]*FieldCountRun, len(s.FieldCountRuns))
+	for i, run := range s.FieldCountRuns {
+		copied := *run
+		c.FieldCountRuns[i] = &copied
+	}
+	if s.DuplicateKeys != nil {
+		c.DuplicateKeys = append([]DuplicateKey{}, s.DuplicateKeys...)
+	}
+	if s.Deviations != nil {
+		c.Deviations = append([]*Deviation{}, s.Deviations...)
+	}
+	if s.Warnings != nil {
+		c.Warnings = append([]*Warning{}, s.Warnings...)
+	}
+	if s.AlterationGroups != nil {
+		c.AlterationGroups = append([]*AlterationGroup{}, s.AlterationGroups...)
+	}
+	if s.Trailer != nil {
+		trailer := *s.Trailer
+		c.Trailer = &trailer
+	}
+	if s.TerminatorCounts != nil {
+		c.TerminatorCounts = make(map[Terminator]int, len(s.TerminatorCounts))
+		for terminator, count := range s.TerminatorCounts {
+			c.TerminatorCounts[terminator] = count
+		}
+	}
+	if s.AlterationKindCounts != nil {
+		c.AlterationKindCounts = make(map[AlterationKind]int, len(s.AlterationKindCounts))
+		for kind, count := range s.AlterationKindCounts {
+			c.AlterationKindCounts[kind] = count
+		}
+	}
+	if s.RedactionCounts != nil {
+		c.RedactionCounts = make(map[string]int, len(s.RedactionCounts))
+		for column, count := range s.RedactionCounts {
+			c.RedactionCounts[column] = count
+		}
+	}
+	return &c
+}
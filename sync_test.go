@@ -0,0 +1,128 @@
+package permissivecsv_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SyncScanner(t *testing.T) {
+	const n = 1000
+	var b strings.Builder
+	b.WriteString("id,value\n")
+	for i := 1; i <= n; i++ {
+		if i%100 == 0 {
+			fmt.Fprintf(&b, "%d\n", i)
+			continue
+		}
+		fmt.Fprintf(&b, "%d,v%d\n", i, i)
+	}
+
+	s := permissivecsv.NewSyncScanner(strings.NewReader(b.String()), permissivecsv.HeaderCheckAssumeHeaderExists)
+	var (
+		mu      sync.Mutex
+		records = map[int][]string{}
+		headers = 0
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				record, info, isHeader, ok := s.Next()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				if isHeader {
+					headers++
+				}
+				records[info.Ordinal] = record
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, headers)
+	assert.Len(t, records, n+1)
+	assert.Equal(t, []string{"id", "value"}, records[1])
+	ordinals := []int{}
+	for ordinal := range records {
+		ordinals = append(ordinals, ordinal)
+	}
+	sort.Ints(ordinals)
+	for _, ordinal := range ordinals[1:] {
+		expected := []string{fmt.Sprint(ordinal - 1), fmt.Sprintf("v%d", ordinal-1)}
+		if (ordinal-1)%100 == 0 {
+			expected[1] = ""
+		}
+		assert.Equal(t, expected, records[ordinal])
+	}
+
+	summary := s.Summary()
+	assert.True(t, summary.EOF)
+	assert.Equal(t, n+1, summary.RecordCount)
+	assert.Equal(t, n/100, summary.AlterationCount)
+	assert.Equal(t, n/100, summary.AlterationKindCounts[permissivecsv.AlterationPaddedRecord])
+}
+
+func Test_SyncScannerSummaryIsSnapshot(t *testing.T) {
+	s := permissivecsv.NewSyncScanner(strings.NewReader("a,b\nc\nd\ne,f"), permissivecsv.HeaderCheckAssumeNoHeader)
+	s.Next()
+	s.Next()
+	snapshot := s.Summary()
+	for {
+		if _, _, _, ok := s.Next(); !ok {
+			break
+		}
+	}
+	assert.Equal(t, 2, snapshot.RecordCount)
+	assert.Equal(t, 1, snapshot.AlterationCount)
+	assert.Len(t, snapshot.Alterations, 1)
+	assert.Equal(t, 2, snapshot.FieldCountRuns[1].LastRecordOrdinal)
+	assert.Equal(t, 1, snapshot.AlterationKindCounts[permissivecsv.AlterationPaddedRecord])
+	assert.False(t, snapshot.EOF)
+
+	summary := s.Summary()
+	assert.Equal(t, 4, summary.RecordCount)
+	assert.Equal(t, 2, summary.AlterationCount)
+	assert.True(t, summary.EOF)
+}
+
+func Test_WithSafeRecords(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []permissivecsv.Option
+		expResulted []string
+	}{
+		{
+			name:        "shared by default",
+			opts:        nil,
+			expResulted: []string{"modified", ""},
+		},
+		{
+			name:        "copied with safe records",
+			opts:        []permissivecsv.Option{permissivecsv.WithSafeRecords()},
+			expResulted: []string{"c", ""},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader("a,b\nc"), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			s.Scan()
+			s.Scan()
+			record := s.CurrentRecord()
+			record[0] = "modified"
+			assert.Equal(t, test.expResulted, s.Summary().Alterations[0].ResultingRecord)
+		}
+		t.Run(test.name, testFn)
+	}
+}
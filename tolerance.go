@@ -0,0 +1,82 @@
+package permissivecsv
+
+// Severity distinguishes alterations that warrant attention from those that
+// are expected, and can be safely ignored (see WithFieldCountTolerance).
+type Severity int
+
+const (
+	// SeverityError indicates an alteration that may have lost or misplaced
+	// data. This is the severity of every alteration unless
+	// WithFieldCountTolerance is supplied.
+	SeverityError Severity = iota
+
+	// SeverityInfo indicates an alteration that is within tolerance, such as
+	// a record missing a single trailing field.
+	SeverityInfo
+)
+
+func (sev Severity) String() string {
+	switch sev {
+	case SeverityError:
+		return "error"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "unknown"
+	}
+}
+
+// TolerancePolicy determines how the Scanner handles records whose field count
+// is outside the tolerance set by WithFieldCountTolerance.
+type TolerancePolicy int
+
+const (
+	// ToleranceFlag pads or truncates the record as usual, and reports the
+	// alteration with SeverityError. This is the default.
+	ToleranceFlag TolerancePolicy = iota
+
+	// ToleranceDrop skips the record, so that it is not returned by Scan, and
+	// reports an AlterationDroppedRecord alteration with SeverityError.
+	ToleranceDrop
+)
+
+// WithFieldCountTolerance sets the number of fields by which a record may
+// differ from the expected field count before it is considered structurally
+// broken. Records that differ by up to n fields are padded or truncated as
+// usual, but the alteration is reported with SeverityInfo (and counted by
+// ScanSummary.InfoAlterationCount), so that minor raggedness (such as
+// omitted trailing blank fields) can be ignored by alerting. Records that
+// differ by more than n fields are handled according to policy.
+//
+// Only padding and truncation are subject to the tolerance. Other alterations
+// (such as those caused by quote ambiguities) always have SeverityError. The
+// default tolerance is zero, with ToleranceFlag.
+func WithFieldCountTolerance(n int, policy TolerancePolicy) Option {
+	return func(o *options) {
+		o.fieldCountTolerance = n
+		o.tolerancePolicy = policy
+	}
+}
+
+// fieldCountWithinTolerance reports whether a record of n fields is within
+// the tolerance set by WithFieldCountTolerance.
+func (s *Scanner) fieldCountWithinTolerance(n int) bool {
+	deviation := n - s.expectedFieldCount
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	return deviation <= s.opts.fieldCountTolerance
+}
+
+// dropRecord reports that the current record was dropped, so that Scan
+// continues to the next record. The record's bytes are left unclaimed, so
+// that Partition includes them in the enclosing segment.
+func (s *Scanner) dropRecord(rawRecord, originalData string) {
+	s.unclaimBytes([]byte(rawRecord))
+	if len(s.redactedColumns) > 0 {
+		originalData = ""
+	}
+	s.appendAlteration(originalData, nil, nil, AlterationDroppedRecord)
+	s.scanSummary.DroppedRecordCount++
+	s.currentDropped = true
+}
@@ -0,0 +1,151 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithFieldCountTolerance(t *testing.T) {
+	const data = "a,b,c,d\n1,2,3\n1\n1,2,3,4,5\n1,2,3,4,5,6,7\n\"x\"y,2,3,4"
+	tests := []struct {
+		name          string
+		opts          []permissivecsv.Option
+		expRecords    [][]string
+		expKinds      []permissivecsv.AlterationKind
+		expSeverities []permissivecsv.Severity
+		expInfo       int
+		expDropped    int
+	}{
+		{
+			name: "default",
+			opts: nil,
+			expRecords: [][]string{
+				{"a", "b", "c", "d"},
+				{"1", "2", "3", ""},
+				{"1", "", "", ""},
+				{"1", "2", "3", "4"},
+				{"1", "2", "3", "4"},
+				{"", "", "", ""},
+			},
+			expKinds: []permissivecsv.AlterationKind{
+				permissivecsv.AlterationPaddedRecord,
+				permissivecsv.AlterationPaddedRecord,
+				permissivecsv.AlterationTruncatedRecord,
+				permissivecsv.AlterationTruncatedRecord,
+				permissivecsv.AlterationExtraneousQuote,
+			},
+			expSeverities: []permissivecsv.Severity{
+				permissivecsv.SeverityError,
+				permissivecsv.SeverityError,
+				permissivecsv.SeverityError,
+				permissivecsv.SeverityError,
+				permissivecsv.SeverityError,
+			},
+			expInfo:    0,
+			expDropped: 0,
+		},
+		{
+			name: "flag outside tolerance",
+			opts: []permissivecsv.Option{permissivecsv.WithFieldCountTolerance(1, permissivecsv.ToleranceFlag)},
+			expRecords: [][]string{
+				{"a", "b", "c", "d"},
+				{"1", "2", "3", ""},
+				{"1", "", "", ""},
+				{"1", "2", "3", "4"},
+				{"1", "2", "3", "4"},
+				{"", "", "", ""},
+			},
+			expKinds: []permissivecsv.AlterationKind{
+				permissivecsv.AlterationPaddedRecord,
+				permissivecsv.AlterationPaddedRecord,
+				permissivecsv.AlterationTruncatedRecord,
+				permissivecsv.AlterationTruncatedRecord,
+				permissivecsv.AlterationExtraneousQuote,
+			},
+			expSeverities: []permissivecsv.Severity{
+				permissivecsv.SeverityInfo,
+				permissivecsv.SeverityError,
+				permissivecsv.SeverityInfo,
+				permissivecsv.SeverityError,
+				permissivecsv.SeverityError,
+			},
+			expInfo:    2,
+			expDropped: 0,
+		},
+		{
+			name: "drop outside tolerance",
+			opts: []permissivecsv.Option{permissivecsv.WithFieldCountTolerance(1, permissivecsv.ToleranceDrop)},
+			expRecords: [][]string{
+				{"a", "b", "c", "d"},
+				{"1", "2", "3", ""},
+				{"1", "2", "3", "4"},
+				{"", "", "", ""},
+			},
+			expKinds: []permissivecsv.AlterationKind{
+				permissivecsv.AlterationPaddedRecord,
+				permissivecsv.AlterationDroppedRecord,
+				permissivecsv.AlterationTruncatedRecord,
+				permissivecsv.AlterationDroppedRecord,
+				permissivecsv.AlterationExtraneousQuote,
+			},
+			expSeverities: []permissivecsv.Severity{
+				permissivecsv.SeverityInfo,
+				permissivecsv.SeverityError,
+				permissivecsv.SeverityInfo,
+				permissivecsv.SeverityError,
+				permissivecsv.SeverityError,
+			},
+			expInfo:    2,
+			expDropped: 2,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeHeaderExists, test.opts...)
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			assert.Equal(t, test.expRecords, records)
+
+			summary := s.Summary()
+			kinds := []permissivecsv.AlterationKind{}
+			severities := []permissivecsv.Severity{}
+			for _, alteration := range summary.Alterations {
+				kinds = append(kinds, alteration.Kind)
+				severities = append(severities, alteration.Severity)
+			}
+			assert.Equal(t, test.expKinds, kinds)
+			assert.Equal(t, test.expSeverities, severities)
+			assert.Equal(t, test.expInfo, summary.InfoAlterationCount)
+			assert.Equal(t, test.expDropped, summary.DroppedRecordCount)
+			assert.Equal(t, 6, summary.RecordCount)
+			assert.True(t, summary.EOF)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_DroppedRecordAlteration(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b,c\n1\n2\n3,4,5"), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithFieldCountTolerance(0, permissivecsv.ToleranceDrop))
+	records := [][]string{}
+	for s.Scan() {
+		records = append(records, s.CurrentRecord())
+		assert.False(t, s.CurrentRecordInfo().Altered)
+	}
+	assert.Equal(t, [][]string{{"a", "b", "c"}, {"3", "4", "5"}}, records)
+
+	alteration := s.Summary().Alterations[1]
+	assert.Equal(t, 3, alteration.RecordOrdinal)
+	assert.Equal(t, int64(8), alteration.ByteOffset)
+	assert.Equal(t, "2", alteration.OriginalData)
+	assert.Nil(t, alteration.ResultingRecord)
+	assert.Equal(t, permissivecsv.AltDroppedRecord, alteration.AlterationDescription)
+	assert.Equal(t, "dropped-record", alteration.Kind.Code())
+	assert.Equal(t, "info", permissivecsv.SeverityInfo.String())
+}
@@ -0,0 +1,123 @@
+package permissivecsv
+
+// Tracer starts the spans that instrument a Scanner (see WithTracer). Its
+// methods mirror those of OpenTelemetry's trace.Tracer and trace.Span, so an
+// OpenTelemetry tracer can be supplied via a small adapter, without
+// permissivecsv depending on OpenTelemetry:
+//
+//	type otelTracer struct {
+//		ctx    context.Context
+//		tracer trace.Tracer
+//	}
+//
+//	func (t otelTracer) Start(name string, attrs ...permissivecsv.Attribute) permissivecsv.Span {
+//		_, span := t.tracer.Start(t.ctx, name, trace.WithAttributes(otelAttrs(attrs)...))
+//		return otelSpan{span}
+//	}
+//
+// where otelSpan wraps the trace.Span, and otelAttrs converts each Attribute
+// to an attribute.KeyValue according to the type of its Value.
+type Tracer interface {
+	Start(name string, attrs ...Attribute) Span
+}
+
+// Span is a span started by a Tracer. AddEvent records an event within the
+// span, SetAttributes adds attributes to the span, RecordError records an
+// error that ended the span, and End ends the span.
+type Span interface {
+	AddEvent(name string, attrs ...Attribute)
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a key-value pair that describes a Span or an event. Value is a
+// string, int, int64, or bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Names of the spans and events created by a Scanner with a Tracer.
+const (
+	SpanScan             = "permissivecsv.scan"
+	EventAlteration      = "permissivecsv.alteration"
+	AttrRecordCount      = "permissivecsv.record_count"
+	AttrAlterationCount  = "permissivecsv.alteration_count"
+	AttrBytesConsumed    = "permissivecsv.bytes_consumed"
+	AttrState            = "permissivecsv.state"
+	AttrAlterationKind   = "permissivecsv.alteration.kind"
+	AttrAlterationCode   = "permissivecsv.alteration.code"
+	AttrRecordOrdinal    = "permissivecsv.record.ordinal"
+	AttrRecordByteOffset = "permissivecsv.record.byte_offset"
+)
+
+// WithTracer instructs the Scanner to instrument scanning with tracer. A span
+// named SpanScan is started by the first call to Scan, and is ended once
+// scanning stops (or the Scanner is Reset), with attributes reporting the
+// number of records and alterations, the number of bytes consumed, and the
+// final State. If scanning stopped because of an error, the summary's Err is
+// recorded on the span. Each alteration adds an event named EventAlteration
+// to the span, with attributes identifying its kind and locating the record,
+// so that a trace shows where in the input problems are concentrated.
+// Alterations are reported whether or not they are retained by the summary
+// (see WithAlterationSampling and WithMemoryBudget).
+//
+// As with other functions supplied to the Scanner, a panic in the tracer is
+// recovered and stops scanning (see CallbackPanicError).
+func WithTracer(tracer Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// startSpan starts the scan span, if there is a tracer and the span has not
+// yet been started.
+func (s *Scanner) startSpan() {
+	if s.opts.tracer == nil || s.spanStarted || s.state != ScannerStateScanning {
+		return
+	}
+	s.spanStarted = true
+	defer s.recoverCallback("Tracer")
+	defer s.releaseSummary()()
+	s.span = s.opts.tracer.Start(SpanScan)
+}
+
+// traceAlteration adds an event for alteration to the scan span.
+func (s *Scanner) traceAlteration(alteration *Alteration) {
+	if s.span == nil {
+		return
+	}
+	defer s.recoverCallback("Tracer")
+	defer s.releaseSummary()()
+	s.span.AddEvent(EventAlteration,
+		Attribute{Key: AttrAlterationKind, Value: alteration.Kind.String()},
+		Attribute{Key: AttrAlterationCode, Value: alteration.Kind.Code()},
+		Attribute{Key: AttrRecordOrdinal, Value: alteration.RecordOrdinal},
+		Attribute{Key: AttrRecordByteOffset, Value: alteration.ByteOffset},
+	)
+}
+
+// endSpan ends the scan span (if it is in progress) with the totals from the
+// summary.
+func (s *Scanner) endSpan() {
+	span := s.span
+	if span == nil {
+		return
+	}
+	s.span = nil
+	defer s.recoverCallback("Tracer")
+	defer s.releaseSummary()()
+	if s.scanSummary != nil {
+		span.SetAttributes(
+			Attribute{Key: AttrRecordCount, Value: s.scanSummary.RecordCount},
+			Attribute{Key: AttrAlterationCount, Value: s.scanSummary.AlterationCount},
+			Attribute{Key: AttrBytesConsumed, Value: s.bytesConsumed},
+			Attribute{Key: AttrState, Value: s.state.String()},
+		)
+		if s.scanSummary.Err != nil {
+			span.RecordError(s.scanSummary.Err)
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,145 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTracer struct {
+	spans []*fakeSpan
+	panic bool
+}
+
+func (t *fakeTracer) Start(name string, attrs ...permissivecsv.Attribute) permissivecsv.Span {
+	if t.panic {
+		panic("tracer unavailable")
+	}
+	span := &fakeSpan{name: name, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return span
+}
+
+type fakeEvent struct {
+	name  string
+	attrs map[string]interface{}
+}
+
+type fakeSpan struct {
+	name   string
+	attrs  map[string]interface{}
+	events []fakeEvent
+	err    error
+	ended  int
+}
+
+func (s *fakeSpan) AddEvent(name string, attrs ...permissivecsv.Attribute) {
+	event := fakeEvent{name: name, attrs: map[string]interface{}{}}
+	for _, attr := range attrs {
+		event.attrs[attr.Key] = attr.Value
+	}
+	s.events = append(s.events, event)
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...permissivecsv.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *fakeSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *fakeSpan) End() {
+	s.ended++
+}
+
+func Test_WithTracer(t *testing.T) {
+	t.Run("scan session", func(t *testing.T) {
+		tracer := &fakeTracer{}
+		s := permissivecsv.NewScanner(strings.NewReader("a,b\nc\nd,e\nf,g,h\n"), permissivecsv.HeaderCheckAssumeNoHeader,
+			permissivecsv.WithTracer(tracer))
+		for s.Scan() {
+			assert.Len(t, tracer.spans, 1)
+			assert.Equal(t, 0, tracer.spans[0].ended)
+		}
+		assert.False(t, s.Scan())
+		if !assert.Len(t, tracer.spans, 1) {
+			return
+		}
+		span := tracer.spans[0]
+		assert.Equal(t, permissivecsv.SpanScan, span.name)
+		assert.Equal(t, 1, span.ended)
+		assert.Nil(t, span.err)
+		assert.Equal(t, map[string]interface{}{
+			permissivecsv.AttrRecordCount:     4,
+			permissivecsv.AttrAlterationCount: 2,
+			permissivecsv.AttrBytesConsumed:   int64(16),
+			permissivecsv.AttrState:           permissivecsv.ScannerStateEOF.String(),
+		}, span.attrs)
+		assert.Equal(t, []fakeEvent{
+			{
+				name: permissivecsv.EventAlteration,
+				attrs: map[string]interface{}{
+					permissivecsv.AttrAlterationKind:   permissivecsv.AlterationPaddedRecord.String(),
+					permissivecsv.AttrAlterationCode:   permissivecsv.AlterationPaddedRecord.Code(),
+					permissivecsv.AttrRecordOrdinal:    2,
+					permissivecsv.AttrRecordByteOffset: int64(4),
+				},
+			},
+			{
+				name: permissivecsv.EventAlteration,
+				attrs: map[string]interface{}{
+					permissivecsv.AttrAlterationKind:   permissivecsv.AlterationTruncatedRecord.String(),
+					permissivecsv.AttrAlterationCode:   permissivecsv.AlterationTruncatedRecord.Code(),
+					permissivecsv.AttrRecordOrdinal:    4,
+					permissivecsv.AttrRecordByteOffset: int64(10),
+				},
+			},
+		}, span.events)
+	})
+
+	t.Run("error recorded", func(t *testing.T) {
+		tracer := &fakeTracer{}
+		s := permissivecsv.NewScanner(BadReader(nil), permissivecsv.HeaderCheckAssumeNoHeader,
+			permissivecsv.WithTracer(tracer))
+		for s.Scan() {
+		}
+		if !assert.Len(t, tracer.spans, 1) {
+			return
+		}
+		span := tracer.spans[0]
+		assert.Equal(t, 1, span.ended)
+		assert.True(t, errors.Is(span.err, ErrReader))
+		assert.Equal(t, permissivecsv.ScannerStateErrored.String(), span.attrs[permissivecsv.AttrState])
+	})
+
+	t.Run("reset ends span", func(t *testing.T) {
+		tracer := &fakeTracer{}
+		s := permissivecsv.NewScanner(strings.NewReader("a,b\nc,d\n"), permissivecsv.HeaderCheckAssumeNoHeader,
+			permissivecsv.WithTracer(tracer))
+		s.Scan()
+		s.Reset()
+		if !assert.Len(t, tracer.spans, 1) {
+			return
+		}
+		assert.Equal(t, 1, tracer.spans[0].ended)
+		s.Scan()
+		assert.Len(t, tracer.spans, 2)
+	})
+
+	t.Run("tracer panics", func(t *testing.T) {
+		tracer := &fakeTracer{panic: true}
+		s := permissivecsv.NewScanner(strings.NewReader("a,b\nc,d\n"), permissivecsv.HeaderCheckAssumeNoHeader,
+			permissivecsv.WithTracer(tracer))
+		assert.False(t, s.Scan())
+		var panicErr *permissivecsv.CallbackPanicError
+		if assert.True(t, errors.As(s.Summary().Err, &panicErr)) {
+			assert.Equal(t, "Tracer", panicErr.Callback)
+		}
+	})
+}
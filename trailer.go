@@ -0,0 +1,78 @@
+package permissivecsv
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// DefaultTrailerPattern matches trailer records such as "TRAILER,12345", in
+// which the second field is the number of data records in the file. The match
+// is case insensitive, and the trailer may contain further fields.
+var DefaultTrailerPattern = regexp.MustCompile(`(?i)^trailer,\s*(\d+)\s*(,|$)`)
+
+// TrailerReconciliation reports whether the number of records in a file
+// matches the count declared by its trailer (see WithTrailerReconciliation).
+//
+// Found is true if the last record matched the trailer pattern, in which case
+// Expected is the count that it declared. Actual is the number of data
+// records, which excludes the header (if any) and the trailer (if found), but
+// includes any records dropped by WithFieldCountTolerance. Match is true if the
+// trailer was found, and Expected equals Actual.
+type TrailerReconciliation struct {
+	Found    bool
+	Expected int
+	Actual   int
+	Match    bool
+}
+
+// WithTrailerReconciliation instructs the Scanner to reconcile the number of
+// records in the input with the count declared by a trailer record once the
+// end of the input is reached, and to report the result via
+// ScanSummary.Trailer. The last record (as it appeared in the input, without
+// its terminator) is matched against pattern, and the first capturing group
+// of the match is parsed as the expected number of data records. If pattern
+// is nil, DefaultTrailerPattern is used.
+//
+// A mismatch (or missing trailer) does not stop scanning. To reject such
+// input, supply a function to WithOnEOFValidate that inspects the summary's
+// Trailer, which is populated before that function is called. The trailer is
+// returned by Scan like any other record.
+func WithTrailerReconciliation(pattern *regexp.Regexp) Option {
+	return func(o *options) {
+		if pattern == nil {
+			pattern = DefaultTrailerPattern
+		}
+		o.trailerPattern = pattern
+	}
+}
+
+// reconcileTrailer populates the summary's Trailer, if trailer reconciliation
+// was requested.
+func (s *Scanner) reconcileTrailer() {
+	if s.opts.trailerPattern == nil || s.scanningRaw {
+		return
+	}
+	reconciliation := &TrailerReconciliation{}
+	s.scanSummary.Trailer = reconciliation
+	if s.scanSummary.RecordCount == 0 {
+		return
+	}
+
+	s.resolveHeader()
+	reconciliation.Actual = s.scanSummary.RecordCount
+	if s.header != nil {
+		reconciliation.Actual--
+	}
+	match := s.opts.trailerPattern.FindStringSubmatch(s.currentRawFields)
+	if len(match) < 2 || s.scanSummary.RecordCount == 1 && s.header != nil {
+		return
+	}
+	expected, err := strconv.Atoi(match[1])
+	if err != nil {
+		return
+	}
+	reconciliation.Found = true
+	reconciliation.Expected = expected
+	reconciliation.Actual--
+	reconciliation.Match = expected == reconciliation.Actual
+}
@@ -0,0 +1,99 @@
+package permissivecsv_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithTrailerReconciliation(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		headerCheck permissivecsv.HeaderCheck
+		pattern     *regexp.Regexp
+		expTrailer  *permissivecsv.TrailerReconciliation
+	}{
+		{
+			name:        "match",
+			data:        "id,name\n1,a\n2,b\nTRAILER,2\n",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expTrailer:  &permissivecsv.TrailerReconciliation{Found: true, Expected: 2, Actual: 2, Match: true},
+		},
+		{
+			name:        "mismatch",
+			data:        "id,name\n1,a\n2,b\ntrailer, 3,extra\n",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expTrailer:  &permissivecsv.TrailerReconciliation{Found: true, Expected: 3, Actual: 2, Match: false},
+		},
+		{
+			name:        "no header",
+			data:        "1,a\n2,b\nTRAILER,2",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expTrailer:  &permissivecsv.TrailerReconciliation{Found: true, Expected: 2, Actual: 2, Match: true},
+		},
+		{
+			name:        "missing trailer",
+			data:        "id,name\n1,a\n2,b\n",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expTrailer:  &permissivecsv.TrailerReconciliation{Found: false, Expected: 0, Actual: 2, Match: false},
+		},
+		{
+			name:        "malformed trailer",
+			data:        "id,name\n1,a\nTRAILER,two\n",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expTrailer:  &permissivecsv.TrailerReconciliation{Found: false, Expected: 0, Actual: 2, Match: false},
+		},
+		{
+			name:        "empty input",
+			data:        "",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expTrailer:  &permissivecsv.TrailerReconciliation{},
+		},
+		{
+			name:        "custom pattern",
+			data:        "id,name\n1,a\n2,b\n3,c\nEOF|records=3\n",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			pattern:     regexp.MustCompile(`^EOF\|records=(\d+)$`),
+			expTrailer:  &permissivecsv.TrailerReconciliation{Found: true, Expected: 3, Actual: 3, Match: true},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck,
+				permissivecsv.WithTrailerReconciliation(test.pattern))
+			for s.Scan() {
+			}
+			assert.True(t, s.Summary().EOF)
+			assert.Equal(t, test.expTrailer, s.Summary().Trailer)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_TrailerReconciliationWithValidation(t *testing.T) {
+	errMismatch := errors.New("record count mismatch")
+	s := permissivecsv.NewScanner(strings.NewReader("id\n1\n2\nTRAILER,5"), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithTrailerReconciliation(nil),
+		permissivecsv.WithOnEOFValidate(func(lastRecord []string, sum *permissivecsv.ScanSummary) error {
+			if !sum.Trailer.Match {
+				return errMismatch
+			}
+			return nil
+		}))
+	for s.Scan() {
+	}
+	assert.True(t, errors.Is(s.Summary().Err, errMismatch))
+	assert.Equal(t, 5, s.Summary().Trailer.Expected)
+	assert.Equal(t, 2, s.Summary().Trailer.Actual)
+
+	s = permissivecsv.NewScanner(strings.NewReader("id\n1\n2\nTRAILER,2"), permissivecsv.HeaderCheckAssumeHeaderExists)
+	for s.Scan() {
+	}
+	assert.Nil(t, s.Summary().Trailer)
+}
@@ -0,0 +1,167 @@
+package permissivecsv
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// DuplicateKey identifies a record whose key (see WithUniqueColumns) matched
+// that of an earlier record. RecordOrdinal is the ordinal of the duplicate,
+// and FirstRecordOrdinal is the ordinal of the first record with the same
+// key. FirstRecordOrdinal is zero if keys are tracked with a bloom filter
+// (see WithUniqueColumnsBloomFilter), which does not record ordinals.
+type DuplicateKey struct {
+	FirstRecordOrdinal int
+	RecordOrdinal      int
+}
+
+// WithUniqueColumns instructs the Scanner to check that the values of the
+// named columns, taken together, are unique across all records, as a primary
+// key would be. Each duplicate is reported by the DuplicateKeys field of the
+// ScanSummary, but does not otherwise affect scanning.
+//
+// Columns are matched to the header's column names after both are normalized
+// by NormalizeColumnName, so keys are only checked if the first record is a
+// header (see RecordIsHeader) that contains every named column. Records whose
+// fields could not be parsed due to quote ambiguities are not checked.
+//
+// Rather than the keys themselves, a 64-bit hash of each key is retained,
+// along with the ordinal of the first record with that key, so memory use
+// grows with the number of distinct keys. For very large files, supply
+// WithUniqueColumnsBloomFilter as well.
+func WithUniqueColumns(columns ...string) Option {
+	return func(o *options) {
+		o.uniqueColumns = columns
+	}
+}
+
+// WithUniqueColumnsBloomFilter instructs the Scanner to track the keys
+// checked by WithUniqueColumns with a bloom filter sized for expectedKeys
+// keys with the supplied false positive rate (such as 0.001), so that memory
+// use is constant regardless of the size of the input. A bloom filter cannot
+// identify which record a key was first seen in, so the FirstRecordOrdinal of
+// each DuplicateKey is zero. It may also report keys that are not in fact
+// duplicated (at approximately the false positive rate, if no more than
+// expectedKeys keys are checked), so each duplicate that it reports should be
+// confirmed before it is acted upon.
+func WithUniqueColumnsBloomFilter(expectedKeys int, falsePositiveRate float64) Option {
+	return func(o *options) {
+		o.uniqueBloomKeys = expectedKeys
+		o.uniqueBloomRate = falsePositiveRate
+	}
+}
+
+// keyTracker records the keys that have been seen, and reports whether a key
+// has been seen before, along with the ordinal at which it was first seen (if
+// known).
+type keyTracker interface {
+	observe(key uint64, ordinal int) (firstOrdinal int, seen bool)
+}
+
+// exactKeyTracker tracks keys with a map.
+type exactKeyTracker map[uint64]int
+
+func (t exactKeyTracker) observe(key uint64, ordinal int) (int, bool) {
+	if first, seen := t[key]; seen {
+		return first, true
+	}
+	t[key] = ordinal
+	return 0, false
+}
+
+// bloomKeyTracker tracks keys with a bloom filter.
+type bloomKeyTracker struct {
+	bits   []uint64
+	size   uint64
+	hashes int
+}
+
+func newBloomKeyTracker(expectedKeys int, falsePositiveRate float64) *bloomKeyTracker {
+	if expectedKeys < 1 {
+		expectedKeys = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+	size := uint64(math.Ceil(-float64(expectedKeys) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if size < 64 {
+		size = 64
+	}
+	hashes := int(math.Round(float64(size) / float64(expectedKeys) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	return &bloomKeyTracker{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+func (t *bloomKeyTracker) observe(key uint64, ordinal int) (int, bool) {
+	// The bit positions are derived from the two halves of the key by double
+	// hashing.
+	h1, h2 := key&math.MaxUint32, key>>32|1
+	seen := true
+	for i := 0; i < t.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % t.size
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if t.bits[word]&mask == 0 {
+			seen = false
+			t.bits[word] |= mask
+		}
+	}
+	return 0, seen
+}
+
+// resolveUniqueColumns matches the columns supplied to WithUniqueColumns to
+// the header's columns. Keys are not checked unless every column is matched.
+func (s *Scanner) resolveUniqueColumns(header []string) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = NormalizeColumnName(name)
+		if _, exists := columns[name]; !exists {
+			columns[name] = i
+		}
+	}
+	indexes := make([]int, 0, len(s.opts.uniqueColumns))
+	for _, column := range s.opts.uniqueColumns {
+		index, ok := columns[NormalizeColumnName(column)]
+		if !ok {
+			return
+		}
+		indexes = append(indexes, index)
+	}
+	s.uniqueIndexes = indexes
+	if s.opts.uniqueBloomKeys > 0 {
+		s.uniqueKeys = newBloomKeyTracker(s.opts.uniqueBloomKeys, s.opts.uniqueBloomRate)
+	} else {
+		s.uniqueKeys = exactKeyTracker{}
+	}
+	s.scanSummary.DuplicateKeys = []DuplicateKey{}
+}
+
+// checkUniqueKey reports the current record via the summary if its key
+// duplicates that of an earlier record.
+func (s *Scanner) checkUniqueKey(record []string) {
+	h := fnv.New64a()
+	var length [binary.MaxVarintLen64]byte
+	for _, index := range s.uniqueIndexes {
+		field := ""
+		if index < len(record) {
+			field = record[index]
+		}
+		// Each field is prefixed with its length, so that keys whose fields
+		// differ only in where they are split do not collide.
+		h.Write(length[:binary.PutUvarint(length[:], uint64(len(field)))])
+		h.Write([]byte(field))
+	}
+	ordinal := s.scanSummary.RecordCount
+	if first, seen := s.uniqueKeys.observe(h.Sum64(), ordinal); seen {
+		s.scanSummary.DuplicateKeys = append(s.scanSummary.DuplicateKeys, DuplicateKey{
+			FirstRecordOrdinal: first,
+			RecordOrdinal:      ordinal,
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package permissivecsv_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithUniqueColumns(t *testing.T) {
+	const data = "ID,Region,Name\n1,east,a\n2,east,b\n1,west,c\n1,east,d\n2,east,e\n\"x\"y,east,f\n\"x\"y,east,g\n"
+	tests := []struct {
+		name          string
+		headerCheck   permissivecsv.HeaderCheck
+		opts          []permissivecsv.Option
+		expDuplicates []permissivecsv.DuplicateKey
+	}{
+		{
+			name:        "single column",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:        []permissivecsv.Option{permissivecsv.WithUniqueColumns("id")},
+			expDuplicates: []permissivecsv.DuplicateKey{
+				{FirstRecordOrdinal: 2, RecordOrdinal: 4},
+				{FirstRecordOrdinal: 2, RecordOrdinal: 5},
+				{FirstRecordOrdinal: 3, RecordOrdinal: 6},
+			},
+		},
+		{
+			name:        "composite key",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:        []permissivecsv.Option{permissivecsv.WithUniqueColumns("id", "region")},
+			expDuplicates: []permissivecsv.DuplicateKey{
+				{FirstRecordOrdinal: 2, RecordOrdinal: 5},
+				{FirstRecordOrdinal: 3, RecordOrdinal: 6},
+			},
+		},
+		{
+			name:          "unique",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:          []permissivecsv.Option{permissivecsv.WithUniqueColumns("name")},
+			expDuplicates: []permissivecsv.DuplicateKey{},
+		},
+		{
+			name:          "unmatched column",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:          []permissivecsv.Option{permissivecsv.WithUniqueColumns("id", "missing")},
+			expDuplicates: nil,
+		},
+		{
+			name:          "no header",
+			headerCheck:   permissivecsv.HeaderCheckAssumeNoHeader,
+			opts:          []permissivecsv.Option{permissivecsv.WithUniqueColumns("id")},
+			expDuplicates: nil,
+		},
+		{
+			name:          "not requested",
+			headerCheck:   permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:          nil,
+			expDuplicates: nil,
+		},
+		{
+			name:        "bloom filter",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.Option{
+				permissivecsv.WithUniqueColumns("id", "region"),
+				permissivecsv.WithUniqueColumnsBloomFilter(100, 0.001),
+			},
+			expDuplicates: []permissivecsv.DuplicateKey{
+				{FirstRecordOrdinal: 0, RecordOrdinal: 5},
+				{FirstRecordOrdinal: 0, RecordOrdinal: 6},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(data), test.headerCheck, test.opts...)
+			for s.Scan() {
+			}
+			assert.Equal(t, test.expDuplicates, s.Summary().DuplicateKeys)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_UniqueColumnsFieldBoundaries(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\nx,yz\nxy,z\n"), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithUniqueColumns("a", "b"))
+	for s.Scan() {
+	}
+	assert.Empty(t, s.Summary().DuplicateKeys)
+}
+
+func Test_UniqueColumnsBloomFilterFalsePositives(t *testing.T) {
+	const keys = 10000
+	var b strings.Builder
+	b.WriteString("id\n")
+	for i := 0; i < keys; i++ {
+		fmt.Fprintf(&b, "%d\n", i)
+	}
+	s := permissivecsv.NewScanner(strings.NewReader(b.String()), permissivecsv.HeaderCheckAssumeHeaderExists,
+		permissivecsv.WithUniqueColumns("id"),
+		permissivecsv.WithUniqueColumnsBloomFilter(keys, 0.01))
+	for s.Scan() {
+	}
+	// every key is unique, so any duplicates are false positives.
+	assert.True(t, len(s.Summary().DuplicateKeys) < keys/50, "%d false positives", len(s.Summary().DuplicateKeys))
+}
@@ -0,0 +1,39 @@
+package permissivecsv
+
+import "strings"
+
+// Validate scans the remainder of the input and returns the resulting summary,
+// without returning any records. Validate is intended for pre-flight checks,
+// such as deciding whether a file is clean enough to import.
+//
+// Validate produces the same summary as calling Scan until it returns false,
+// but is considerably cheaper, as records that do not require alteration are
+// not split into fields. Once Validate returns, CurrentRecord is not
+// meaningful.
+func (s *Scanner) Validate() *ScanSummary {
+	s.validating = true
+	defer func() {
+		s.validating = false
+	}()
+	for s.Scan() {
+	}
+	return s.Summary()
+}
+
+// isWellFormed reports whether raw can be determined to have the expected
+// number of fields without parsing it. This is only the case for records that
+// contain no quotes, since their fields are delimited by every comma. The first
+// record is never considered well formed, since it determines the expected
+// field count, and is needed for header detection. Nor is any record if its
+// fields must be inspected to build the summary, as they are to detect
+// duplicate keys (see WithUniqueColumns) and to count redactions (see
+// WithRedaction).
+func (s *Scanner) isWellFormed(raw string) bool {
+	if s.recordsScanned == 0 || raw == "" || s.opts.uniqueColumns != nil || s.opts.redactions != nil {
+		return false
+	}
+	if strings.IndexByte(raw, '"') >= 0 {
+		return false
+	}
+	return strings.Count(raw, ",")+1 == s.expectedFieldCount
+}
@@ -0,0 +1,116 @@
+package permissivecsv_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/go-test/deep"
+)
+
+func Test_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		headerCheck permissivecsv.HeaderCheck
+		opts        []permissivecsv.Option
+	}{
+		{
+			name: "clean",
+			data: "a,b\nc,d\r\ne,f",
+		},
+		{
+			name: "alterations",
+			data: "a,b\nc\n\"d\",e,f\ng\"g\"g,h\n\ni,j\n\"k,l",
+		},
+		{
+			name: "repairs and kept empty records",
+			data: "a,b\nSmith, Jr,c\n\nd,e",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithMergeSplitFields(),
+				permissivecsv.WithKeepEmptyRecords(),
+			},
+		},
+		{
+			name: "empty",
+			data: "",
+		},
+		{
+			name:        "duplicate keys",
+			data:        "id,name\n1,a\n2,b\n1,c\n",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts:        []permissivecsv.Option{permissivecsv.WithUniqueColumns("id")},
+		},
+		{
+			name:        "redactions",
+			data:        "id,name\n1,a\n2,b\n3,c\n",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			opts: []permissivecsv.Option{permissivecsv.WithRedaction([]string{"name"}, func(string) string {
+				return "***"
+			})},
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			headerCheck := test.headerCheck
+			if headerCheck == nil {
+				headerCheck = permissivecsv.HeaderCheckAssumeNoHeader
+			}
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), headerCheck, test.opts...)
+			for s.Scan() {
+			}
+			expected := s.Summary()
+
+			s = permissivecsv.NewScanner(strings.NewReader(test.data), headerCheck, test.opts...)
+			diff := deep.Equal(expected, s.Validate())
+			if diff != nil {
+				t.Error(diff)
+			}
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Benchmark_Validate(b *testing.B) {
+	data := strings.Repeat("alpha,bravo,charlie,delta\n", 10000)
+	for i := 0; i < b.N; i++ {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+		s.Validate()
+	}
+}
+
+func Benchmark_Scan(b *testing.B) {
+	data := strings.Repeat("alpha,bravo,charlie,delta\n", 10000)
+	for i := 0; i < b.N; i++ {
+		s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader)
+		for s.Scan() {
+		}
+	}
+}
+
+func Test_ValidateIntegration(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(testFileLocation, "*.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := permissivecsv.NewScanner(f, permissivecsv.HeaderCheckAssumeHeaderExists)
+		for s.Scan() {
+		}
+		expected := s.Summary()
+		f.Seek(0, 0)
+		s = permissivecsv.NewScanner(f, permissivecsv.HeaderCheckAssumeHeaderExists)
+		diff := deep.Equal(expected, s.Validate())
+		if diff != nil {
+			t.Error(file, diff)
+		}
+		f.Close()
+	}
+}
@@ -0,0 +1,87 @@
+package permissivecsv
+
+import "fmt"
+
+// WarningKind identifies an assumption that the Scanner made which may not
+// reflect the author's intent.
+type WarningKind int
+
+const (
+	// WarningHeaderWidthMismatch indicates that the first record was
+	// identified as a header, but has a different number of fields from the
+	// record that follows it, so either the header or the data is likely to be
+	// misaligned.
+	WarningHeaderWidthMismatch WarningKind = iota + 1
+
+	// WarningHeaderCheckPanicked indicates that the HeaderCheck panicked
+	// (see CallbackPanicError), so the first record was not identified as a
+	// header.
+	WarningHeaderCheckPanicked
+
+	// WarningEmptyFirstRecordSkipped indicates that a record consisting of a
+	// single empty field preceded the first record that contained data, and
+	// was skipped (see WithSkipEmptyFirstRecords).
+	WarningEmptyFirstRecordSkipped
+)
+
+func (k WarningKind) String() string {
+	switch k {
+	case WarningHeaderWidthMismatch:
+		return "header width mismatch"
+	case WarningHeaderCheckPanicked:
+		return "header check panicked"
+	case WarningEmptyFirstRecordSkipped:
+		return "empty first record skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// Warning describes an assumption that the Scanner made which may not reflect
+// the author's intent, and which the caller may want to review (see
+// ScanSummary.Warnings). Unlike an Alteration, a Warning does not indicate
+// that a record was changed. RecordOrdinal is the ordinal of the record that
+// the warning pertains to, and Detail describes the warning.
+type Warning struct {
+	Kind          WarningKind
+	RecordOrdinal int
+	Detail        string
+}
+
+func (w *Warning) String() string {
+	return fmt.Sprintf("record %d: %s: %s", w.RecordOrdinal, w.Kind, w.Detail)
+}
+
+// warn records a warning pertaining to the record at ordinal.
+func (s *Scanner) warn(kind WarningKind, ordinal int, detail string) {
+	if s.scanSummary == nil {
+		s.scanSummary = s.newScanSummary()
+	}
+	s.scanSummary.Warnings = append(s.scanSummary.Warnings, &Warning{
+		Kind:          kind,
+		RecordOrdinal: ordinal,
+		Detail:        detail,
+	})
+}
+
+// checkHeaderWidth warns if header has a different number of fields from the
+// dataWidth fields of the record that follows it. A negative dataWidth
+// indicates that the width is unknown.
+func (s *Scanner) checkHeaderWidth(header []string, dataWidth int) {
+	if dataWidth < 0 || len(header) == dataWidth {
+		return
+	}
+	s.warn(WarningHeaderWidthMismatch, 1,
+		fmt.Sprintf("header has %d fields, but the first data record has %d", len(header), dataWidth))
+}
+
+// fieldCountAt returns the number of fields observed in the record at
+// ordinal (see FieldCountRuns), or -1 if the record was not observed.
+func (s *ScanSummary) fieldCountAt(ordinal int) int {
+	for _, run := range s.FieldCountRuns {
+		if ordinal >= run.FirstRecordOrdinal && ordinal <= run.LastRecordOrdinal {
+			return run.FieldCount
+		}
+	}
+	return -1
+}
@@ -0,0 +1,114 @@
+package permissivecsv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Warnings(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		headerCheck permissivecsv.HeaderCheck
+		// resolveLate resolves the header after the file has been scanned
+		// (via HeaderFingerprint), rather than while the first record is
+		// current (via RecordIsHeader).
+		resolveLate bool
+		expWarnings []*permissivecsv.Warning
+	}{
+		{
+			name:        "header wider than data",
+			data:        "a,b,c\n1,2\n3,4",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expWarnings: []*permissivecsv.Warning{
+				{
+					Kind:          permissivecsv.WarningHeaderWidthMismatch,
+					RecordOrdinal: 1,
+					Detail:        "header has 3 fields, but the first data record has 2",
+				},
+			},
+		},
+		{
+			name:        "header narrower than data, resolved late",
+			data:        "a\n1,2\n3,4",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			resolveLate: true,
+			expWarnings: []*permissivecsv.Warning{
+				{
+					Kind:          permissivecsv.WarningHeaderWidthMismatch,
+					RecordOrdinal: 1,
+					Detail:        "header has 1 fields, but the first data record has 2",
+				},
+			},
+		},
+		{
+			name:        "header matches data",
+			data:        "a,b\n1,2",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expWarnings: nil,
+		},
+		{
+			name:        "no header",
+			data:        "a,b,c\n1,2",
+			headerCheck: permissivecsv.HeaderCheckAssumeNoHeader,
+			expWarnings: nil,
+		},
+		{
+			name:        "header without data",
+			data:        "a,b,c\n",
+			headerCheck: permissivecsv.HeaderCheckAssumeHeaderExists,
+			expWarnings: nil,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), test.headerCheck)
+			for s.Scan() {
+				if !test.resolveLate {
+					s.RecordIsHeader()
+				}
+			}
+			if test.resolveLate {
+				s.HeaderFingerprint()
+			}
+			assert.Equal(t, test.expWarnings, s.Summary().Warnings)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WarningsHeaderCheckPanic(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\n1,2"),
+		func(firstRecord, secondRecord []string) bool {
+			panic("boom")
+		})
+	assert.True(t, s.Scan())
+	assert.False(t, s.RecordIsHeader())
+	assert.False(t, s.RecordIsHeader())
+	assert.False(t, s.Scan())
+
+	summary := s.Summary()
+	assert.True(t, summary.CallbackFailed())
+	if assert.Len(t, summary.Warnings, 1) {
+		assert.Equal(t, permissivecsv.WarningHeaderCheckPanicked, summary.Warnings[0].Kind)
+		assert.Equal(t, "record 1: header check panicked: HeaderCheck panicked: boom", summary.Warnings[0].String())
+	}
+}
+
+func Test_WarningsMerge(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b,c\n1,2"), permissivecsv.HeaderCheckAssumeHeaderExists)
+	s.Scan()
+	s.RecordIsHeader()
+	for s.Scan() {
+	}
+	merged := &permissivecsv.ScanSummary{RecordCount: 5}
+	merged.Merge(s.Summary())
+	if assert.Len(t, merged.Warnings, 1) {
+		assert.Equal(t, 6, merged.Warnings[0].RecordOrdinal)
+	}
+	assert.Equal(t, 1, s.Summary().Warnings[0].RecordOrdinal, "other is not modified")
+}
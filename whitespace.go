@@ -0,0 +1,32 @@
+package permissivecsv
+
+import "strings"
+
+// WithWhitespaceRecordsAsEmpty instructs the Scanner to treat records that
+// consist solely of spaces and tabs as empty records. By default, such a
+// record is parsed as a record with a single field, which is then padded (or
+// sets the expected field count, if it is the first record). With this option,
+// it is instead skipped and counted by ScanSummary.EmptyRecordCount, like any
+// other empty record, so it is also subject to WithKeepEmptyRecords and
+// WithStopAtBlankRun. A kept whitespace record is emitted as a record of empty
+// fields, although ScanRaw returns its whitespace.
+func WithWhitespaceRecordsAsEmpty() Option {
+	return func(o *options) {
+		o.whitespaceRecordsAsEmpty = true
+	}
+}
+
+// isEmptyToken reports whether text, which is terminated by terminator, is an
+// empty record. This is the case if text consists solely of its terminator,
+// or, with WithWhitespaceRecordsAsEmpty, of spaces and tabs followed by its
+// terminator (if any).
+func (s *Scanner) isEmptyToken(text string, terminator []byte) bool {
+	if len(terminator) > 0 && text == string(terminator) {
+		return true
+	}
+	if !s.opts.whitespaceRecordsAsEmpty {
+		return false
+	}
+	fields := strings.TrimSuffix(text, string(terminator))
+	return fields != "" && strings.Trim(fields, " \t") == ""
+}
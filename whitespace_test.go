@@ -0,0 +1,124 @@
+package permissivecsv_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithWhitespaceRecordsAsEmpty(t *testing.T) {
+	tests := []struct {
+		name           string
+		data           string
+		opts           []permissivecsv.Option
+		expRecords     [][]string
+		expEmptyCount  int
+		expAlterations int
+	}{
+		{
+			name:           "disabled",
+			data:           "a,b\n  \nc,d\n",
+			expRecords:     [][]string{{"a", "b"}, {"  ", ""}, {"c", "d"}},
+			expAlterations: 1,
+		},
+		{
+			name:          "spaces and tabs",
+			data:          "a,b\n  \n\t \r\nc,d\n",
+			opts:          []permissivecsv.Option{permissivecsv.WithWhitespaceRecordsAsEmpty()},
+			expRecords:    [][]string{{"a", "b"}, {"c", "d"}},
+			expEmptyCount: 2,
+		},
+		{
+			name:          "leading whitespace record",
+			data:          " \na,b\nc,d\n",
+			opts:          []permissivecsv.Option{permissivecsv.WithWhitespaceRecordsAsEmpty()},
+			expRecords:    [][]string{{"a", "b"}, {"c", "d"}},
+			expEmptyCount: 1,
+		},
+		{
+			name:          "unterminated final whitespace record",
+			data:          "a,b\nc,d\n\t",
+			opts:          []permissivecsv.Option{permissivecsv.WithWhitespaceRecordsAsEmpty()},
+			expRecords:    [][]string{{"a", "b"}, {"c", "d"}},
+			expEmptyCount: 1,
+		},
+		{
+			name: "kept",
+			data: "a,b\n \t\nc,d\n",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithWhitespaceRecordsAsEmpty(),
+				permissivecsv.WithKeepEmptyRecords(),
+			},
+			expRecords:    [][]string{{"a", "b"}, {"", ""}, {"c", "d"}},
+			expEmptyCount: 1,
+		},
+		{
+			name: "blank run",
+			data: "a,b\n \n\t\nfooter\n",
+			opts: []permissivecsv.Option{
+				permissivecsv.WithWhitespaceRecordsAsEmpty(),
+				permissivecsv.WithStopAtBlankRun(2),
+			},
+			expRecords:    [][]string{{"a", "b"}},
+			expEmptyCount: 2,
+		},
+		{
+			name:           "whitespace within fields",
+			data:           "a,b\n , \nc,d\n",
+			opts:           []permissivecsv.Option{permissivecsv.WithWhitespaceRecordsAsEmpty()},
+			expRecords:     [][]string{{"a", "b"}, {" ", " "}, {"c", "d"}},
+			expAlterations: 0,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			s := permissivecsv.NewScanner(strings.NewReader(test.data), permissivecsv.HeaderCheckAssumeNoHeader, test.opts...)
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			summary := s.Summary()
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expEmptyCount, summary.EmptyRecordCount)
+			assert.Equal(t, test.expAlterations, summary.AlterationCount)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_WithWhitespaceRecordsAsEmptyScanRaw(t *testing.T) {
+	s := permissivecsv.NewScanner(strings.NewReader("a,b\n \t\nc,d\n"), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithWhitespaceRecordsAsEmpty(), permissivecsv.WithKeepEmptyRecords())
+	raw := []string{}
+	for {
+		record, _, err := s.ScanRaw()
+		if err == io.EOF {
+			break
+		}
+		raw = append(raw, string(record))
+	}
+	assert.Equal(t, []string{"a,b", " \t", "c,d"}, raw)
+}
+
+func Test_WithWhitespaceRecordsAsEmptyPartition(t *testing.T) {
+	data := " \na,b\n\t\nc,d\n  \ne,f\n \t"
+	s := permissivecsv.NewScanner(strings.NewReader(data), permissivecsv.HeaderCheckAssumeNoHeader,
+		permissivecsv.WithWhitespaceRecordsAsEmpty())
+	segments, err := s.Partition(1, false)
+	if !assert.NoError(t, err) || !assert.Len(t, segments, 3) {
+		return
+	}
+	lower := int64(0)
+	for _, segment := range segments {
+		assert.Equal(t, lower, segment.LowerOffset)
+		assert.Equal(t, int64(1), segment.RecordCount)
+		lower = segment.LowerOffset + segment.Length
+	}
+	assert.Equal(t, int64(len(data)), lower, "segments cover the entire input")
+	report := permissivecsv.Segments(segments).Validate(strings.NewReader(data), permissivecsv.WithWhitespaceRecordsAsEmpty())
+	assert.True(t, report.OK())
+}
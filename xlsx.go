@@ -0,0 +1,369 @@
+package permissivecsv
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrSheetNotFound is returned by ScanXLSX if the workbook does not
+	// contain the requested worksheet.
+	ErrSheetNotFound = fmt.Errorf("worksheet not found")
+
+	// ErrInvalidWorkbook is returned by ScanXLSX and XLSXSheets if the input
+	// is a zip archive, but not an Excel workbook.
+	ErrInvalidWorkbook = fmt.Errorf("invalid xlsx workbook")
+)
+
+// maxXLSXColumns is the number of columns in an Excel worksheet (A to XFD).
+const maxXLSXColumns = 16384
+
+// ScanXLSX returns a Scanner that reads the values of the named worksheet of
+// the Excel (.xlsx) workbook in r, which is size bytes long. If sheet is
+// empty, the first worksheet is read. This allows workbooks that have been
+// passed off as CSV files to be scanned, repaired, and summarized like any
+// other input.
+//
+// The worksheet is converted to CSV as it is scanned, one row per record, so
+// rows with trailing empty cells are shorter than the rest of the sheet, and
+// are padded (see AlterationPadded) like any short record. Rows that contain
+// no cells are empty records. Only cell values are read; formatting is
+// ignored. Formulas are represented by their last calculated value, booleans
+// as TRUE or FALSE, and numbers as they are stored in the workbook, so dates
+// appear as serial day numbers.
+//
+// The workbook's structure and shared strings are read before ScanXLSX
+// returns, so invalid workbooks and missing worksheets are reported
+// immediately via the returned error. Errors encountered while reading the
+// worksheet itself are reported via Summary().Err. Use XLSXSheets to list the
+// worksheets in a workbook.
+func ScanXLSX(r io.ReaderAt, size int64, sheet string, headerCheck HeaderCheck, opts ...Option) (*Scanner, error) {
+	wb, err := openWorkbook(r, size)
+	if err != nil {
+		return nil, err
+	}
+	var target string
+	for _, s := range wb.sheets {
+		if sheet == "" || s.name == sheet {
+			target = s.path
+			break
+		}
+	}
+	if target == "" {
+		return nil, fmt.Errorf("%w: %q", ErrSheetNotFound, sheet)
+	}
+	f, ok := wb.files[target]
+	if !ok {
+		return nil, ErrInvalidWorkbook
+	}
+	shared, err := wb.sharedStrings()
+	if err != nil {
+		return nil, err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	rows := &xlsxRows{
+		body:    rc,
+		decoder: xml.NewDecoder(rc),
+		shared:  shared,
+	}
+	return NewScanner(newRowReader(rows), headerCheck, opts...), nil
+}
+
+// XLSXSheets returns the names of the worksheets in the Excel (.xlsx) workbook
+// in r, which is size bytes long, in the order in which they appear in the
+// workbook.
+func XLSXSheets(r io.ReaderAt, size int64) ([]string, error) {
+	wb, err := openWorkbook(r, size)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(wb.sheets))
+	for i, s := range wb.sheets {
+		names[i] = s.name
+	}
+	return names, nil
+}
+
+// workbook describes the parts of an xlsx archive.
+type workbook struct {
+	files             map[string]*zip.File
+	sheets            []workbookSheet
+	sharedStringsPath string
+}
+
+type workbookSheet struct {
+	name string
+	path string
+}
+
+// xlsxRelationships is a package relationships (.rels) part.
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxWorkbook is the workbook part. Sheets are matched to their parts by the
+// relationship id attribute, whose namespace differs between the transitional
+// and strict variants of the format.
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name  string     `xml:"name,attr"`
+		Attrs []xml.Attr `xml:",any,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxText is a shared string, or the content of an inline string cell, which
+// is either plain text or a sequence of formatted runs.
+type xlsxText struct {
+	T *string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t *xlsxText) String() string {
+	if t.T != nil {
+		return *t.T
+	}
+	var b strings.Builder
+	for _, run := range t.R {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type xlsxRow struct {
+	R     int `xml:"r,attr"`
+	Cells []struct {
+		R  string    `xml:"r,attr"`
+		T  string    `xml:"t,attr"`
+		V  string    `xml:"v"`
+		Is *xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+// openWorkbook locates the workbook part of the archive in r, and reads the
+// list of worksheets.
+func openWorkbook(r io.ReaderAt, size int64) (*workbook, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	wb := &workbook{files: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		wb.files[f.Name] = f
+	}
+
+	workbookPath := "xl/workbook.xml"
+	var rootRels xlsxRelationships
+	if err := wb.decode("_rels/.rels", &rootRels); err == nil {
+		for _, rel := range rootRels.Relationships {
+			if strings.HasSuffix(rel.Type, "/officeDocument") {
+				workbookPath = resolvePart("", rel.Target)
+			}
+		}
+	}
+
+	var xwb xlsxWorkbook
+	if err := wb.decode(workbookPath, &xwb); err != nil {
+		return nil, err
+	}
+	var rels xlsxRelationships
+	relsPath := path.Join(path.Dir(workbookPath), "_rels", path.Base(workbookPath)+".rels")
+	if err := wb.decode(relsPath, &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		target := resolvePart(path.Dir(workbookPath), rel.Target)
+		targets[rel.ID] = target
+		if strings.HasSuffix(rel.Type, "/sharedStrings") {
+			wb.sharedStringsPath = target
+		}
+	}
+	for _, sheet := range xwb.Sheets {
+		for _, attr := range sheet.Attrs {
+			if attr.Name.Local == "id" && targets[attr.Value] != "" {
+				wb.sheets = append(wb.sheets, workbookSheet{
+					name: sheet.Name,
+					path: targets[attr.Value],
+				})
+			}
+		}
+	}
+	return wb, nil
+}
+
+// resolvePart returns the archive path of a relationship target, which is
+// either absolute, or relative to dir.
+func resolvePart(dir, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(dir, target)
+}
+
+// decode unmarshals the named part of the archive into v.
+func (wb *workbook) decode(name string, v interface{}) error {
+	f, ok := wb.files[name]
+	if !ok {
+		return ErrInvalidWorkbook
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// sharedStrings returns the workbook's shared string table, if any.
+func (wb *workbook) sharedStrings() ([]string, error) {
+	if wb.sharedStringsPath == "" {
+		return nil, nil
+	}
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if err := wb.decode(wb.sharedStringsPath, &sst); err != nil {
+		return nil, err
+	}
+	shared := make([]string, len(sst.Items))
+	for i := range sst.Items {
+		shared[i] = sst.Items[i].String()
+	}
+	return shared, nil
+}
+
+// xlsxRows is a rowSource that decodes the rows of a worksheet.
+type xlsxRows struct {
+	body    io.ReadCloser
+	decoder *xml.Decoder
+	shared  []string
+
+	// row is the number of the last row returned, and pending holds a row
+	// that follows rows skipped by the worksheet, which are returned as empty
+	// records.
+	row     int
+	pending *xlsxRow
+}
+
+func (r *xlsxRows) Close() error {
+	return r.body.Close()
+}
+
+func (r *xlsxRows) next() ([]string, error) {
+	if r.pending == nil {
+		row, err := r.nextRow()
+		if err != nil {
+			return nil, err
+		}
+		if row.R <= r.row {
+			row.R = r.row + 1
+		}
+		r.pending = row
+	}
+	r.row++
+	if r.row < r.pending.R {
+		return []string{}, nil
+	}
+	row := r.pending
+	r.pending = nil
+
+	record := []string{}
+	for _, cell := range row.Cells {
+		column := len(record)
+		if cell.R != "" {
+			var err error
+			column, err = xlsxColumn(cell.R)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for len(record) <= column {
+			record = append(record, "")
+		}
+		value, err := r.value(cell.T, cell.V, cell.Is)
+		if err != nil {
+			return nil, err
+		}
+		record[column] = value
+	}
+	return record, nil
+}
+
+// nextRow decodes the next row element of the worksheet.
+func (r *xlsxRows) nextRow() (*xlsxRow, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		row := &xlsxRow{}
+		if err := r.decoder.DecodeElement(row, &start); err != nil {
+			return nil, err
+		}
+		return row, nil
+	}
+}
+
+// value returns the value of a cell of type t.
+func (r *xlsxRows) value(t, v string, is *xlsxText) (string, error) {
+	switch t {
+	case "s":
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 || i >= len(r.shared) {
+			return "", fmt.Errorf("%w: invalid shared string %q", ErrInvalidWorkbook, v)
+		}
+		return r.shared[i], nil
+	case "inlineStr":
+		if is == nil {
+			return "", nil
+		}
+		return is.String(), nil
+	case "b":
+		if v == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	}
+	return v, nil
+}
+
+// xlsxColumn returns the zero based column index of a cell reference, such as
+// B7.
+func xlsxColumn(ref string) (int, error) {
+	column := 0
+	for i := 0; i < len(ref); i++ {
+		c := ref[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c < 'A' || c > 'Z' {
+			if i == 0 {
+				break
+			}
+			return column - 1, nil
+		}
+		column = column*26 + int(c-'A') + 1
+		if column > maxXLSXColumns {
+			break
+		}
+	}
+	return 0, fmt.Errorf("%w: invalid cell reference %q", ErrInvalidWorkbook, ref)
+}
@@ -0,0 +1,137 @@
+package permissivecsv_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/eltorocorp/permissivecsv"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Summary" sheetId="1" r:id="rId1"/><sheet name="Data" sheetId="2" r:id="rId2"/></sheets></workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/></Relationships>`
+
+	xlsxSharedStrings = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="5" uniqueCount="5"><si><t>id</t></si><si><t>name</t></si><si><t>active</t></si><si><r><t>carol, </t></r><r><rPr><b/></rPr><t>jr</t></r></si><si><t xml:space="preserve">line
+break</t></si></sst>`
+
+	xlsxSheet1 = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>total</t></is></c><c r="B1"><v>3</v></c></row></sheetData></worksheet>`
+
+	xlsxSheet2 = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>` +
+		`<row r="2"><c r="A2"><v>1</v></c><c r="B2" t="s"><v>3</v></c><c r="C2" t="b"><v>1</v></c></row>` +
+		`<row r="4"><c r="A4"><f>A2+1</f><v>2</v></c><c r="C4" t="b"><v>0</v></c></row>` +
+		`<row r="5"><c r="A5"><v>3.5</v></c><c r="B5" t="s"><v>4</v></c></row>` +
+		`</sheetData></worksheet>`
+)
+
+// newXLSX returns an xlsx archive containing the supplied parts.
+func newXLSX(t *testing.T, parts map[string]string) *bytes.Reader {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		w.Write([]byte(content))
+	}
+	assert.NoError(t, zw.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func newTestWorkbook(t *testing.T, sheet2 string) *bytes.Reader {
+	return newXLSX(t, map[string]string{
+		"_rels/.rels":                xlsxRootRels,
+		"xl/workbook.xml":            xlsxWorkbook,
+		"xl/_rels/workbook.xml.rels": xlsxWorkbookRels,
+		"xl/sharedStrings.xml":       xlsxSharedStrings,
+		"xl/worksheets/sheet1.xml":   xlsxSheet1,
+		"xl/worksheets/sheet2.xml":   sheet2,
+	})
+}
+
+func Test_ScanXLSX(t *testing.T) {
+	tests := []struct {
+		name       string
+		sheet      string
+		expRecords [][]string
+		expAltered int
+	}{
+		{
+			name:  "first sheet",
+			sheet: "",
+			expRecords: [][]string{
+				[]string{"total", "3"},
+			},
+		},
+		{
+			name:  "named sheet",
+			sheet: "Data",
+			expRecords: [][]string{
+				[]string{"id", "name", "active"},
+				[]string{"1", "carol, jr", "TRUE"},
+				[]string{"2", "", "FALSE"},
+				[]string{"3.5", "line\nbreak", ""},
+			},
+			expAltered: 1,
+		},
+	}
+
+	for _, test := range tests {
+		testFn := func(t *testing.T) {
+			r := newTestWorkbook(t, xlsxSheet2)
+			s, err := permissivecsv.ScanXLSX(r, r.Size(), test.sheet, permissivecsv.HeaderCheckAssumeHeaderExists)
+			assert.NoError(t, err)
+			records := [][]string{}
+			for s.Scan() {
+				records = append(records, s.CurrentRecord())
+			}
+			summary := s.Summary()
+			assert.NoError(t, summary.Err)
+			assert.Equal(t, test.expRecords, records)
+			assert.Equal(t, test.expAltered, summary.AlterationCount)
+		}
+		t.Run(test.name, testFn)
+	}
+}
+
+func Test_XLSXSheets(t *testing.T) {
+	r := newTestWorkbook(t, xlsxSheet2)
+	sheets, err := permissivecsv.XLSXSheets(r, r.Size())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Summary", "Data"}, sheets)
+}
+
+func Test_ScanXLSXErrors(t *testing.T) {
+	r := newTestWorkbook(t, xlsxSheet2)
+	_, err := permissivecsv.ScanXLSX(r, r.Size(), "Missing", permissivecsv.HeaderCheckAssumeNoHeader)
+	assert.True(t, errors.Is(err, permissivecsv.ErrSheetNotFound))
+
+	r = newXLSX(t, map[string]string{"word/document.xml": "<document/>"})
+	_, err = permissivecsv.ScanXLSX(r, r.Size(), "", permissivecsv.HeaderCheckAssumeNoHeader)
+	assert.Equal(t, permissivecsv.ErrInvalidWorkbook, err)
+
+	notZip := bytes.NewReader([]byte("a,b\nc,d"))
+	_, err = permissivecsv.ScanXLSX(notZip, notZip.Size(), "", permissivecsv.HeaderCheckAssumeNoHeader)
+	assert.Equal(t, zip.ErrFormat, err)
+
+	sheet := `<worksheet><sheetData><row r="1"><c r="A1"><v>1</v></c></row><row r="2"><c r="A2" t="s"><v>99</v></c></row></sheetData></worksheet>`
+	r = newTestWorkbook(t, sheet)
+	s, err := permissivecsv.ScanXLSX(r, r.Size(), "Data", permissivecsv.HeaderCheckAssumeNoHeader)
+	assert.NoError(t, err)
+	for s.Scan() {
+	}
+	summary := s.Summary()
+	assert.True(t, errors.Is(summary.Err, permissivecsv.ErrInvalidWorkbook))
+	assert.Equal(t, 1, summary.RecordCount)
+}